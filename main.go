@@ -53,6 +53,7 @@ var (
 			&patchCmd,
 			&awaitCmd,
 			&serveCmd,
+			&sidecarCmd,
 			&reportCmd,
 			&seinetwork.Cmd,
 			&seinode.Cmd,
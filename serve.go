@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -38,10 +41,7 @@ var serveCmd = cli.Command{
 		ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
 		defer stop()
 
-		homeDir := destinations.home
-		if homeDir == "" {
-			homeDir = "/sei"
-		}
+		homeDir := sidecarHome()
 		port := cmd.String("port")
 		chainID := os.Getenv("SEI_CHAIN_ID")
 		genesisBucket := os.Getenv("SEI_GENESIS_BUCKET")
@@ -80,6 +80,17 @@ var serveCmd = cli.Command{
 			snapshotUploadInterval = parsed
 		}
 
+		// Running tasks are uncapped unless SEI_SIDECAR_MAX_WORKERS sets a
+		// cap; 0 is the same as unset.
+		var maxWorkers int
+		if raw := os.Getenv("SEI_SIDECAR_MAX_WORKERS"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 0 {
+				return fmt.Errorf("invalid SEI_SIDECAR_MAX_WORKERS %q: must be a non-negative integer", raw)
+			}
+			maxWorkers = parsed
+		}
+
 		var snapshotUploadTimeout time.Duration
 		if raw := os.Getenv("SEI_SNAPSHOT_UPLOAD_TIMEOUT"); raw != "" {
 			parsed, err := time.ParseDuration(raw)
@@ -89,6 +100,24 @@ var serveCmd = cli.Command{
 			snapshotUploadTimeout = parsed
 		}
 
+		retention, err := retentionFromEnv()
+		if err != nil {
+			return err
+		}
+
+		drainTimeout, err := drainOnSigtermFromEnv()
+		if err != nil {
+			return err
+		}
+		// With drain-on-SIGTERM the engine and server outlive the signal:
+		// they run on runCtx, which is cancelled once the drain ends.
+		runCtx := ctx
+		cancelRun := func() {}
+		if drainTimeout > 0 {
+			runCtx, cancelRun = context.WithCancel(context.WithoutCancel(ctx))
+		}
+		defer cancelRun()
+
 		execCfg, err := buildExecutionConfig(homeDir)
 		if err != nil {
 			return err
@@ -98,7 +127,7 @@ var serveCmd = cli.Command{
 			return fmt.Errorf("home directory init failed: %w", err)
 		}
 
-		store, err := engine.NewSQLiteStore(filepath.Join(homeDir, "sidecar.db"))
+		store, err := openResultStore(homeDir)
 		if err != nil {
 			return fmt.Errorf("open result store: %w", err)
 		}
@@ -117,17 +146,19 @@ var serveCmd = cli.Command{
 		}
 		snapshotUploader.EmitStartupMetrics()
 
-		handlers := map[engine.TaskType]engine.TaskHandler{
+		handlers := map[engine.TaskType]engine.Handler{
 			engine.TaskSnapshotRestore:          snapshotRestorer.Handler(),
 			engine.TaskConfigPatch:              tasks.NewConfigPatcher(homeDir).Handler(),
 			engine.TaskConfigApply:              tasks.NewConfigApplier(homeDir).Handler(),
 			engine.TaskConfigValidate:           tasks.NewConfigValidator(homeDir).Handler(),
-			engine.TaskConfigReload:             tasks.NewConfigReloader(homeDir).Handler(),
+			engine.TaskConfigReload:             tasks.NewConfigReloader(homeDir, os.Getenv("SEI_SEID_CONFIG_URL")).Handler(),
 			engine.TaskMarkReady:                tasks.MarkReadyHandler(),
 			engine.TaskMarkNotReady:             tasks.NewMarkNotReadier(store).Handler(),
 			engine.TaskRestartSeid:              tasks.NewRestartSeider().Handler(),
 			engine.TaskStopSeid:                 tasks.NewStopSeider().Handler(),
 			engine.TaskResetData:                tasks.NewResetDataer(homeDir).Handler(),
+			engine.TaskPrepareUpgrade:           tasks.NewUpgradePreparer(homeDir, genesisRegion, nil, nil).Handler(),
+			engine.TaskApplyUpgrade:             tasks.NewUpgradeApplier(homeDir).Handler(),
 			engine.TaskConfigureGenesis:         tasks.NewGenesisFetcher(homeDir, chainID, genesisBucket, genesisRegion, nil).Handler(),
 			engine.TaskConfigureStateSync:       tasks.NewStateSyncConfigurer(homeDir, nil).Handler(),
 			engine.TaskSnapshotUpload:           snapshotUploader.Handler(),
@@ -145,11 +176,118 @@ var serveCmd = cli.Command{
 			engine.TaskEvmLogicalDigest:         tasks.NewEvmLogicalDigester(nil).Handler(),
 		}
 
-		eng := engine.NewEngine(ctx, handlers, store)
+		// Automatic retry for idempotent bootstrap tasks whose failures are
+		// dominated by transient S3 / network errors. Sign-tx and lifecycle
+		// tasks are deliberately absent: a failed gov submit or seid restart
+		// is re-driven by the controller, not the engine.
+		retryPolicies := map[engine.TaskType]engine.RetryPolicy{
+			engine.TaskSnapshotRestore:          {MaxAttempts: 3, InitialBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute, Jitter: 0.2, MaxElapsed: 2 * time.Hour},
+			engine.TaskConfigureGenesis:         {MaxAttempts: 5, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute, Jitter: 0.2, MaxElapsed: 15 * time.Minute},
+			engine.TaskConfigureStateSync:       {MaxAttempts: 5, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute, Jitter: 0.2, MaxElapsed: 15 * time.Minute},
+			engine.TaskUploadGenesisArtifacts:   {MaxAttempts: 5, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute, Jitter: 0.2, MaxElapsed: 15 * time.Minute},
+			engine.TaskAssembleAndUploadGenesis: {MaxAttempts: 5, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute, Jitter: 0.2, MaxElapsed: 15 * time.Minute},
+			engine.TaskSetGenesisPeers:          {MaxAttempts: 5, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute, Jitter: 0.2, MaxElapsed: 15 * time.Minute},
+			engine.TaskPrepareUpgrade:           {MaxAttempts: 5, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute, Jitter: 0.2, MaxElapsed: 30 * time.Minute},
+		}
+
+		// Default execution deadlines, applied when a submission carries no
+		// timeout. Each sits above the type's retry MaxElapsed so the policy,
+		// not the deadline, bounds a flapping bootstrap task. await-condition
+		// gets a generous backstop against a seid that never comes up;
+		// callers waiting on a far-off height pass their own timeout. Snapshot uploads
+		// carry their own bounds and the sign-tx family is absent so a
+		// broadcast is never cut short mid-flight. The readiness gates are
+		// absent too: a deadline is kept across restarts, so a mark-ready
+		// stranded by one would be failed on rehydration without running,
+		// defeating its crash recovery.
+		timeouts := map[engine.TaskType]time.Duration{
+			engine.TaskSnapshotRestore:          3 * time.Hour,
+			engine.TaskConfigPatch:              5 * time.Minute,
+			engine.TaskConfigApply:              5 * time.Minute,
+			engine.TaskConfigValidate:           5 * time.Minute,
+			engine.TaskConfigReload:             5 * time.Minute,
+			engine.TaskRestartSeid:              10 * time.Minute,
+			engine.TaskStopSeid:                 10 * time.Minute,
+			engine.TaskResetData:                30 * time.Minute,
+			engine.TaskConfigureGenesis:         30 * time.Minute,
+			engine.TaskConfigureStateSync:       30 * time.Minute,
+			engine.TaskGenerateIdentity:         5 * time.Minute,
+			engine.TaskGenerateGentx:            5 * time.Minute,
+			engine.TaskUploadGenesisArtifacts:   30 * time.Minute,
+			engine.TaskAssembleAndUploadGenesis: 30 * time.Minute,
+			engine.TaskSetGenesisPeers:          30 * time.Minute,
+			engine.TaskAwaitCondition:           24 * time.Hour,
+			engine.TaskPrepareUpgrade:           45 * time.Minute,
+			engine.TaskApplyUpgrade:             15 * time.Minute,
+		}
+
+		// Exclusion groups serialize task types that touch the same process or
+		// files. Lifecycle and config writes queue behind one another, since
+		// the controller submits them as ordered steps. Wiping or replacing
+		// the data directory under a running restore or upload is never what
+		// the caller meant, so those conflicts are rejected outright.
+		// apply-upgrade restarts seid, so it takes seid-lifecycle too; it
+		// holds the group only for the switch and restart, since callers
+		// chain the wait for the halt height ahead of it as await-condition.
+		exclusions := map[string]engine.ExclusionGroup{
+			"seid-lifecycle": {
+				Types: []engine.TaskType{
+					engine.TaskRestartSeid, engine.TaskStopSeid, engine.TaskResetData,
+					engine.TaskSnapshotRestore, engine.TaskConfigReload, engine.TaskApplyUpgrade,
+				},
+				Policy: engine.ExclusionQueue,
+			},
+			"home-config-write": {
+				Types: []engine.TaskType{
+					engine.TaskConfigPatch, engine.TaskConfigApply, engine.TaskConfigureGenesis,
+					engine.TaskConfigureStateSync, engine.TaskSetGenesisPeers,
+					engine.TaskGenerateIdentity, engine.TaskGenerateGentx,
+				},
+				Policy: engine.ExclusionQueue,
+			},
+			"data-dir": {
+				Types: []engine.TaskType{
+					engine.TaskResetData, engine.TaskSnapshotRestore, engine.TaskSnapshotUploadOnce,
+				},
+				Policy: engine.ExclusionReject,
+			},
+		}
+
+		// Worker limits keep a burst of heavy tasks from saturating the
+		// validator's CPU, disk and S3 bandwidth. Each full-state walk or
+		// upload runs alone. The continuous uploader and await-condition live
+		// for hours and the readiness gates must never wait behind bulk work,
+		// so none of them take a worker.
+		concurrency := engine.ConcurrencyLimits{
+			MaxWorkers: maxWorkers,
+			PerType: map[engine.TaskType]int{
+				engine.TaskResultExport:       1,
+				engine.TaskEvmLogicalDigest:   1,
+				engine.TaskSnapshotUploadOnce: 1,
+			},
+			Unbounded: []engine.TaskType{
+				engine.TaskMarkReady, engine.TaskMarkNotReady,
+				engine.TaskSnapshotUpload, engine.TaskAwaitCondition,
+			},
+		}
+
+		eng := engine.NewEngine(runCtx, handlers, store)
 		eng.Config = execCfg
-		// Rehydrate after Config is installed so sign-tx handlers see
-		// the full dep set via the goroutine-spawn happens-before edge.
+		eng.RetryPolicies = retryPolicies
+		eng.Timeouts = timeouts
+		eng.Exclusions = exclusions
+		eng.Concurrency = concurrency
+		eng.Retention = retention
+		// A drain must still let the controller take the pod out of
+		// rotation.
+		eng.DrainExempt = []engine.TaskType{engine.TaskMarkNotReady}
+		// Rehydrate after Config, RetryPolicies, Timeouts, Exclusions and
+		// Concurrency are installed so sign-tx handlers see the full dep set
+		// via the goroutine-spawn happens-before edge, and stale tasks re-take
+		// their workers and exclusion groups.
 		eng.RehydrateStaleTasks()
+		eng.StartScheduler()
+		eng.StartCompactor()
 
 		authnMode, err := server.AuthnMode()
 		if err != nil {
@@ -157,12 +295,39 @@ var serveCmd = cli.Command{
 		}
 		bindAddr := server.BindAddress(port, authnMode)
 		logArgs := []any{"authnMode", authnMode, "bind", bindAddr}
-		if authnMode == server.AuthnModeTrustedHeader {
+		if authnMode != server.AuthnModeUnauthenticated {
 			logArgs = append(logArgs, "bypassPaths", server.BypassPaths())
 		}
+		policy, err := policyFromEnv(authnMode, handlers)
+		if err != nil {
+			return err
+		}
+		if policy != nil {
+			logArgs = append(logArgs, "policyRules", len(policy.Rules))
+		}
 		serveLog.Info("sidecar HTTP", logArgs...)
 		srv := server.NewServer(bindAddr, eng, homeDir, authnMode)
-		srvErr := srv.ListenAndServe(ctx)
+		srv.Policy = policy
+		if err := credentialsFromEnv(srv, authnMode); err != nil {
+			return err
+		}
+		if srv.UnixListener, err = unixSocketFromEnv(); err != nil {
+			return err
+		}
+		if srv.UnixListener != nil {
+			serveLog.Info("sidecar HTTP on unix socket", "path", srv.UnixListener.Addr().String())
+		}
+		if drainTimeout > 0 {
+			go func() {
+				<-ctx.Done()
+				// Restore default signal handling so a second SIGTERM
+				// kills the process mid-drain.
+				stop()
+				drainOnSignal(eng, drainTimeout)
+				cancelRun()
+			}()
+		}
+		srvErr := srv.ListenAndServe(runCtx)
 
 		if closeErr := store.Close(); closeErr != nil {
 			fmt.Fprintf(os.Stderr, "warn: result store close: %v\n", closeErr)
@@ -175,6 +340,222 @@ var serveCmd = cli.Command{
 	},
 }
 
+// retentionFromEnv reads the result retention policy:
+// SEI_SIDECAR_RETENTION_MAX_AGE and SEI_SIDECAR_RETENTION_MAX_PER_TYPE bound
+// what is kept (unset or 0 disables a bound), SEI_SIDECAR_RETENTION_INTERVAL
+// sets how often the compactor prunes, and SEI_SIDECAR_VACUUM_INTERVAL how
+// often at most it compacts the result store.
+//
+// Retention is off unless a bound is set. A pruned result no longer dedupes
+// its task ID, so a controller re-driving a pruned sign-tx or reset-data
+// submission would run it again; operators opt in knowing their controller's
+// resubmission window.
+func retentionFromEnv() (engine.RetentionPolicy, error) {
+	var p engine.RetentionPolicy
+	for _, d := range []struct {
+		name string
+		into *time.Duration
+	}{
+		{"SEI_SIDECAR_RETENTION_MAX_AGE", &p.MaxAge},
+		{"SEI_SIDECAR_RETENTION_INTERVAL", &p.Interval},
+		{"SEI_SIDECAR_VACUUM_INTERVAL", &p.VacuumInterval},
+	} {
+		if raw := os.Getenv(d.name); raw != "" {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil || parsed < 0 {
+				return p, fmt.Errorf("invalid %s %q: must be a non-negative duration", d.name, raw)
+			}
+			*d.into = parsed
+		}
+	}
+	if raw := os.Getenv("SEI_SIDECAR_RETENTION_MAX_PER_TYPE"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return p, fmt.Errorf("invalid SEI_SIDECAR_RETENTION_MAX_PER_TYPE %q: must be a non-negative integer", raw)
+		}
+		p.MaxPerType = parsed
+	}
+	return p, nil
+}
+
+// drainOnSigtermFromEnv reads SEI_SIDECAR_DRAIN_ON_SIGTERM, how long serve
+// drains on SIGTERM before shutting down. Unset or 0 shuts down at once.
+// The server's own graceful shutdown follows the drain, so the two must
+// fit within the pod's terminationGracePeriodSeconds.
+func drainOnSigtermFromEnv() (time.Duration, error) {
+	raw := os.Getenv("SEI_SIDECAR_DRAIN_ON_SIGTERM")
+	if raw == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid SEI_SIDECAR_DRAIN_ON_SIGTERM %q: must be a non-negative duration", raw)
+	}
+	return d, nil
+}
+
+// drainOnSignal drains eng for up to timeout, logging whether the running
+// tasks finished in time.
+func drainOnSignal(eng *engine.Engine, timeout time.Duration) {
+	st := eng.Drain(timeout)
+	serveLog.Info("shutdown signal received; draining", "running", st.Running, "timeout", timeout)
+	if err := eng.WaitDrained(context.Background()); err != nil {
+		serveLog.Warn("drain incomplete; shutting down", "err", err)
+		return
+	}
+	serveLog.Info("drained; shutting down", "since", st.Since)
+}
+
+// policyFromEnv loads the authorization policy SEI_SIDECAR_POLICY_FILE
+// names, or returns nil when it is unset. A policy needs caller
+// identities, so it is refused outside trusted-header and mtls mode, and one
+// naming a task type serve has no handler for is refused as a likely
+// typo that would leave the type unguarded.
+func policyFromEnv(authnMode string, handlers map[engine.TaskType]engine.Handler) (*server.Policy, error) {
+	path := os.Getenv("SEI_SIDECAR_POLICY_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	if authnMode != server.AuthnModeTrustedHeader && authnMode != server.AuthnModeMTLS {
+		return nil, fmt.Errorf("SEI_SIDECAR_POLICY_FILE requires SEI_SIDECAR_AUTHN_MODE=%s or %s; without it callers have no identity to authorize",
+			server.AuthnModeTrustedHeader, server.AuthnModeMTLS)
+	}
+	policy, err := server.LoadPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range policy.TaskTypes() {
+		if _, ok := handlers[engine.TaskType(t)]; !ok {
+			return nil, fmt.Errorf("policy %s names unknown task type %q", path, t)
+		}
+	}
+	return policy, nil
+}
+
+// credentialsFromEnv installs what token or mtls mode authenticates with.
+// Token mode takes the token from SEI_SIDECAR_AUTHN_TOKEN or, to rotate it
+// without a restart, the file SEI_SIDECAR_AUTHN_TOKEN_FILE names. Mtls
+// mode takes its key pair from SEI_SIDECAR_TLS_CERT_FILE and
+// SEI_SIDECAR_TLS_KEY_FILE and verifies clients against the bundle in
+// SEI_SIDECAR_TLS_CLIENT_CA_FILE; all three are reloaded when they change.
+func credentialsFromEnv(srv *server.Server, authnMode string) error {
+	var err error
+	switch authnMode {
+	case server.AuthnModeToken:
+		srv.Token, err = server.NewBearerToken(os.Getenv("SEI_SIDECAR_AUTHN_TOKEN"), os.Getenv("SEI_SIDECAR_AUTHN_TOKEN_FILE"))
+	case server.AuthnModeMTLS:
+		srv.TLS, err = server.NewCertReloader(
+			os.Getenv("SEI_SIDECAR_TLS_CERT_FILE"),
+			os.Getenv("SEI_SIDECAR_TLS_KEY_FILE"),
+			os.Getenv("SEI_SIDECAR_TLS_CLIENT_CA_FILE"),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("SEI_SIDECAR_AUTHN_MODE=%s: %w", authnMode, err)
+	}
+	return nil
+}
+
+// unixSocketFromEnv opens the Unix socket SEI_SIDECAR_UNIX_SOCKET names,
+// or returns nil when it is unset. SEI_SIDECAR_UNIX_SOCKET_MODE is its
+// octal file mode (default 0660) and SEI_SIDECAR_UNIX_SOCKET_OWNER its
+// numeric "uid", "uid:gid" or ":gid" owner (default: the sidecar's).
+func unixSocketFromEnv() (net.Listener, error) {
+	path := os.Getenv("SEI_SIDECAR_UNIX_SOCKET")
+	if path == "" {
+		return nil, nil
+	}
+	mode := os.FileMode(0o660)
+	if raw := os.Getenv("SEI_SIDECAR_UNIX_SOCKET_MODE"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 8, 32)
+		if err != nil || parsed > 0o777 {
+			return nil, fmt.Errorf("invalid SEI_SIDECAR_UNIX_SOCKET_MODE %q: must be octal permission bits such as 0660", raw)
+		}
+		mode = os.FileMode(parsed)
+	}
+	uid, gid := -1, -1
+	if raw := os.Getenv("SEI_SIDECAR_UNIX_SOCKET_OWNER"); raw != "" {
+		u, g, _ := strings.Cut(raw, ":")
+		for _, f := range []struct {
+			raw  string
+			into *int
+		}{{u, &uid}, {g, &gid}} {
+			if f.raw == "" {
+				continue
+			}
+			id, err := strconv.Atoi(f.raw)
+			if err != nil || id < 0 {
+				return nil, fmt.Errorf("invalid SEI_SIDECAR_UNIX_SOCKET_OWNER %q: must be numeric uid, uid:gid or :gid", raw)
+			}
+			*f.into = id
+		}
+	}
+	return server.ListenUnix(path, mode, uid, gid)
+}
+
+// resultStore is what serve needs from a store backend: the ResultStore
+// itself and the pre-broadcast checkpoint for sign-tx handlers.
+type resultStore interface {
+	engine.ResultStore
+	engine.Checkpointer
+}
+
+// openResultStore opens the store backend SEI_SIDECAR_STORE selects under
+// homeDir: "sqlite" (the default) keeps sidecar.db, which must sit on a
+// local or block-device volume; "journal" keeps sidecar.journal, an
+// append-only log safe on network filesystems such as NFS or EFS. A store
+// holding a partial ledger import is refused.
+func openResultStore(homeDir string) (resultStore, error) {
+	backend, err := storeBackend()
+	if err != nil {
+		return nil, err
+	}
+	path := storePath(homeDir, backend)
+	var s interface {
+		resultStore
+		engine.LedgerStore
+	}
+	switch backend {
+	case "journal":
+		s, err = engine.NewJournalStore(path)
+	default:
+		s, err = engine.NewSQLiteStore(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.PendingImport()
+	if err == nil && pending != "" {
+		err = fmt.Errorf("%s holds a partial ledger import (archive %s); rerun `seictl sidecar db import` to finish it", filepath.Base(path), pending)
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// storeBackend returns the store backend SEI_SIDECAR_STORE names, "sqlite"
+// when it is unset.
+func storeBackend() (string, error) {
+	switch backend := os.Getenv("SEI_SIDECAR_STORE"); backend {
+	case "", "sqlite":
+		return "sqlite", nil
+	case "journal":
+		return backend, nil
+	default:
+		return "", fmt.Errorf("invalid SEI_SIDECAR_STORE %q: must be sqlite or journal", backend)
+	}
+}
+
+// storePath is where backend keeps its file under homeDir.
+func storePath(homeDir, backend string) string {
+	if backend == "journal" {
+		return filepath.Join(homeDir, "sidecar.journal")
+	}
+	return filepath.Join(homeDir, "sidecar.db")
+}
+
 // buildExecutionConfig assembles the engine's runtime dependencies:
 // keyring (opened from SEI_KEYRING_BACKEND, or nil) and RPC client
 // (pointed at the local seid). Sign-tx tasks consume both; tasks that
@@ -2,8 +2,13 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+	"github.com/sei-protocol/seictl/sidecar/server"
 )
 
 // TestBuildExecutionConfig_UnsetReturnsZero verifies the Phase-1 default:
@@ -146,3 +151,218 @@ func withEnv(t *testing.T, kv map[string]string) {
 		})
 	}
 }
+
+func TestRetentionFromEnv(t *testing.T) {
+	withEnv(t, map[string]string{
+		"SEI_SIDECAR_RETENTION_MAX_AGE":      "",
+		"SEI_SIDECAR_RETENTION_MAX_PER_TYPE": "",
+		"SEI_SIDECAR_RETENTION_INTERVAL":     "",
+		"SEI_SIDECAR_VACUUM_INTERVAL":        "",
+	})
+	p, err := retentionFromEnv()
+	if err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+	if p != (engine.RetentionPolicy{}) {
+		t.Fatalf("defaults = %+v, want retention off", p)
+	}
+
+	withEnv(t, map[string]string{
+		"SEI_SIDECAR_RETENTION_MAX_AGE":      "0",
+		"SEI_SIDECAR_RETENTION_MAX_PER_TYPE": "50",
+		"SEI_SIDECAR_RETENTION_INTERVAL":     "10m",
+	})
+	p, err = retentionFromEnv()
+	if err != nil {
+		t.Fatalf("overrides: %v", err)
+	}
+	if p.MaxAge != 0 || p.MaxPerType != 50 || p.Interval.String() != "10m0s" {
+		t.Fatalf("overrides = %+v", p)
+	}
+
+	for _, kv := range [][2]string{
+		{"SEI_SIDECAR_RETENTION_MAX_AGE", "a month"},
+		{"SEI_SIDECAR_RETENTION_MAX_PER_TYPE", "-1"},
+		{"SEI_SIDECAR_VACUUM_INTERVAL", "-1h"},
+	} {
+		withEnv(t, map[string]string{kv[0]: kv[1]})
+		if _, err := retentionFromEnv(); err == nil || !strings.Contains(err.Error(), kv[0]) {
+			t.Errorf("%s=%q: err = %v, want one naming the variable", kv[0], kv[1], err)
+		}
+		withEnv(t, map[string]string{kv[0]: ""})
+	}
+}
+
+func TestDrainOnSigtermFromEnv(t *testing.T) {
+	for raw, want := range map[string]time.Duration{"": 0, "0": 0, "90s": 90 * time.Second} {
+		withEnv(t, map[string]string{"SEI_SIDECAR_DRAIN_ON_SIGTERM": raw})
+		if got, err := drainOnSigtermFromEnv(); err != nil || got != want {
+			t.Errorf("SEI_SIDECAR_DRAIN_ON_SIGTERM=%q: got %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"soon", "-1m"} {
+		withEnv(t, map[string]string{"SEI_SIDECAR_DRAIN_ON_SIGTERM": raw})
+		if _, err := drainOnSigtermFromEnv(); err == nil || !strings.Contains(err.Error(), "SEI_SIDECAR_DRAIN_ON_SIGTERM") {
+			t.Errorf("SEI_SIDECAR_DRAIN_ON_SIGTERM=%q: err = %v, want one naming the variable", raw, err)
+		}
+	}
+}
+
+func TestOpenResultStoreSelectsBackend(t *testing.T) {
+	for backend, file := range map[string]string{"": "sidecar.db", "sqlite": "sidecar.db", "journal": "sidecar.journal"} {
+		dir := t.TempDir()
+		withEnv(t, map[string]string{"SEI_SIDECAR_STORE": backend})
+		s, err := openResultStore(dir)
+		if err != nil {
+			t.Fatalf("SEI_SIDECAR_STORE=%q: %v", backend, err)
+		}
+		s.Close()
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("SEI_SIDECAR_STORE=%q: %s not created: %v", backend, file, err)
+		}
+	}
+
+	withEnv(t, map[string]string{"SEI_SIDECAR_STORE": "postgres"})
+	if _, err := openResultStore(t.TempDir()); err == nil || !strings.Contains(err.Error(), "SEI_SIDECAR_STORE") {
+		t.Errorf("unknown backend: err = %v, want one naming the variable", err)
+	}
+}
+
+func TestOpenResultStoreRefusesPartialImport(t *testing.T) {
+	dir := t.TempDir()
+	withEnv(t, map[string]string{"SEI_SIDECAR_STORE": ""})
+	s, err := engine.NewSQLiteStore(filepath.Join(dir, "sidecar.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Params that cannot be encoded fail the first batch after the import
+	// has begun, leaving it pending.
+	bad := &engine.Ledger{TaskResults: []engine.TaskResult{{ID: "x", Params: map[string]any{"ch": make(chan int)}}}}
+	if err := s.ImportLedger(bad, "sha256:partial"); err == nil {
+		t.Fatal("import of an invalid row succeeded")
+	}
+	s.Close()
+
+	if _, err := openResultStore(dir); err == nil || !strings.Contains(err.Error(), "partial ledger import") {
+		t.Errorf("err = %v, want serve to refuse the partial import", err)
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	handlers := map[engine.TaskType]engine.Handler{engine.TaskGovVote: nil}
+
+	withEnv(t, map[string]string{"SEI_SIDECAR_POLICY_FILE": ""})
+	if p, err := policyFromEnv(server.AuthnModeTrustedHeader, handlers); p != nil || err != nil {
+		t.Fatalf("unset: policy = %v, err = %v", p, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  - verbs: [write]\n    taskTypes: [gov-vote]\n    groups: [governance]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	withEnv(t, map[string]string{"SEI_SIDECAR_POLICY_FILE": path})
+	if p, err := policyFromEnv(server.AuthnModeTrustedHeader, handlers); err != nil || len(p.Rules) != 1 {
+		t.Fatalf("trusted-header: policy = %v, err = %v", p, err)
+	}
+	if _, err := policyFromEnv(server.AuthnModeMTLS, handlers); err != nil {
+		t.Errorf("mtls: %v", err)
+	}
+	if _, err := policyFromEnv(server.AuthnModeToken, handlers); err == nil || !strings.Contains(err.Error(), "SEI_SIDECAR_AUTHN_MODE") {
+		t.Errorf("token: err = %v, want one naming SEI_SIDECAR_AUTHN_MODE", err)
+	}
+	if _, err := policyFromEnv("", handlers); err == nil || !strings.Contains(err.Error(), "SEI_SIDECAR_AUTHN_MODE") {
+		t.Errorf("unauthenticated: err = %v, want one naming SEI_SIDECAR_AUTHN_MODE", err)
+	}
+	if _, err := policyFromEnv(server.AuthnModeTrustedHeader, nil); err == nil || !strings.Contains(err.Error(), "gov-vote") {
+		t.Errorf("no handler: err = %v, want one naming the task type", err)
+	}
+}
+
+func TestCredentialsFromEnv(t *testing.T) {
+	withEnv(t, map[string]string{
+		"SEI_SIDECAR_AUTHN_TOKEN":        "",
+		"SEI_SIDECAR_AUTHN_TOKEN_FILE":   "",
+		"SEI_SIDECAR_TLS_CERT_FILE":      "",
+		"SEI_SIDECAR_TLS_KEY_FILE":       "",
+		"SEI_SIDECAR_TLS_CLIENT_CA_FILE": "",
+	})
+	srv := server.NewServer(":0", nil, t.TempDir(), server.AuthnModeToken)
+	if err := credentialsFromEnv(srv, server.AuthnModeToken); err == nil || !strings.Contains(err.Error(), "token") {
+		t.Errorf("token mode without a token: err = %v", err)
+	}
+	if err := credentialsFromEnv(srv, server.AuthnModeMTLS); err == nil || !strings.Contains(err.Error(), "mtls") {
+		t.Errorf("mtls mode without certificates: err = %v", err)
+	}
+	if err := credentialsFromEnv(srv, server.AuthnModeUnauthenticated); err != nil {
+		t.Errorf("unauthenticated mode: %v", err)
+	}
+
+	withEnv(t, map[string]string{"SEI_SIDECAR_AUTHN_TOKEN": "s3cret"})
+	if err := credentialsFromEnv(srv, server.AuthnModeToken); err != nil || srv.Token == nil || !srv.Token.Verify("s3cret") {
+		t.Errorf("token mode: err = %v, token = %v", err, srv.Token)
+	}
+}
+
+func TestUnixSocketFromEnv(t *testing.T) {
+	withEnv(t, map[string]string{
+		"SEI_SIDECAR_UNIX_SOCKET":       "",
+		"SEI_SIDECAR_UNIX_SOCKET_MODE":  "",
+		"SEI_SIDECAR_UNIX_SOCKET_OWNER": "",
+	})
+	if ln, err := unixSocketFromEnv(); ln != nil || err != nil {
+		t.Fatalf("unset: listener = %v, err = %v", ln, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "sidecar.sock")
+	withEnv(t, map[string]string{"SEI_SIDECAR_UNIX_SOCKET": path, "SEI_SIDECAR_UNIX_SOCKET_MODE": "0640"})
+	ln, err := unixSocketFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o640 {
+		t.Fatalf("socket: %v, %v; want mode 0640", fi, err)
+	}
+
+	for _, kv := range [][2]string{
+		{"SEI_SIDECAR_UNIX_SOCKET_MODE", "rw-rw----"},
+		{"SEI_SIDECAR_UNIX_SOCKET_MODE", "01777"},
+		{"SEI_SIDECAR_UNIX_SOCKET_OWNER", "sei:sei"},
+	} {
+		withEnv(t, map[string]string{"SEI_SIDECAR_UNIX_SOCKET": filepath.Join(t.TempDir(), "s.sock"), kv[0]: kv[1]})
+		if _, err := unixSocketFromEnv(); err == nil || !strings.Contains(err.Error(), kv[0]) {
+			t.Errorf("%s=%q: err = %v, want one naming the variable", kv[0], kv[1], err)
+		}
+		withEnv(t, map[string]string{kv[0]: ""})
+	}
+}
+
+func TestOpenLedgerStoreFollowsBackend(t *testing.T) {
+	destinations.home = t.TempDir()
+	t.Cleanup(func() { destinations.home = "" })
+
+	withEnv(t, map[string]string{"SEI_SIDECAR_STORE": "journal"})
+	store, name, err := openLedgerStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.(*engine.JournalStore); !ok || name != "sidecar.journal" {
+		t.Errorf("store = %T %q, want the journal", store, name)
+	}
+
+	// The journal is locked while open, as by a running sidecar.
+	if _, _, err := openLedgerStore(); err == nil || !strings.Contains(err.Error(), "stop it first") {
+		t.Errorf("second open: err = %v, want a running-sidecar refusal", err)
+	}
+	store.Close()
+
+	withEnv(t, map[string]string{"SEI_SIDECAR_STORE": ""})
+	store, name, err = openLedgerStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if _, ok := store.(*engine.SQLiteStore); !ok || name != "sidecar.db" {
+		t.Errorf("store = %T %q, want sidecar.db", store, name)
+	}
+}
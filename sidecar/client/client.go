@@ -7,10 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/sei-protocol/seictl/sidecar/wire"
 )
 
 const DefaultPort int32 = 7777
@@ -18,20 +22,45 @@ const DefaultPort int32 = 7777
 // ErrNotFound is returned when the requested task does not exist (HTTP 404).
 var ErrNotFound = errors.New("sidecar: task not found")
 
+// ErrConflict is returned when a submission is rejected because a
+// conflicting task holds one of its exclusion groups (HTTP 409).
+var ErrConflict = errors.New("sidecar: conflicting task in progress")
+
+// ErrForbidden is returned when the sidecar's authorization policy denies
+// the caller the request (HTTP 403).
+var ErrForbidden = errors.New("sidecar: forbidden by authorization policy")
+
+// ErrUnsupported is returned when the sidecar predates the endpoint
+// called (HTTP 404 on a fixed path).
+var ErrUnsupported = errors.New("sidecar: endpoint not supported by this sidecar")
+
+// ErrTaskFinished is returned when a cancel targets a task that already
+// completed, failed, or was skipped (HTTP 409).
+var ErrTaskFinished = errors.New("sidecar: task already finished")
+
+// ErrDraining is returned when a submission is refused because the
+// sidecar is draining (HTTP 503). The sidecar accepts it again once the
+// drain ends; its Retry-After header advises when.
+var ErrDraining = errors.New("sidecar: draining, not accepting new tasks")
+
 // SidecarClient wraps the generated ClientWithResponses with a simpler,
 // error-oriented API.
 type SidecarClient struct {
-	inner   *ClientWithResponses
-	baseURL string
-	doer    HttpRequestDoer
+	inner    *ClientWithResponses
+	baseURL  string
+	doer     HttpRequestDoer
+	streamer HttpRequestDoer
 }
 
 // Option configures optional SidecarClient parameters.
 type Option func(*sidecarOpts)
 
 type sidecarOpts struct {
-	httpClient HttpRequestDoer
-	timeout    time.Duration
+	httpClient  HttpRequestDoer
+	streamDoer  HttpRequestDoer
+	timeout     time.Duration
+	bearerToken string
+	unixSocket  string
 }
 
 // WithHTTPDoer overrides the underlying HTTP transport.
@@ -39,11 +68,45 @@ func WithHTTPDoer(doer HttpRequestDoer) Option {
 	return func(o *sidecarOpts) { o.httpClient = doer }
 }
 
+// WithStreamDoer sets the HTTP transport for long-lived requests such as the
+// event stream behind Watch, which must not carry a per-request timeout.
+// Defaults to the WithHTTPDoer transport when one is given, otherwise to an
+// HTTP client without a timeout.
+func WithStreamDoer(doer HttpRequestDoer) Option {
+	return func(o *sidecarOpts) { o.streamDoer = doer }
+}
+
 // WithTimeout sets the HTTP client timeout. Defaults to 10s.
 func WithTimeout(d time.Duration) Option {
 	return func(o *sidecarOpts) { o.timeout = d }
 }
 
+// WithBearerToken sends token as Authorization: Bearer on every request,
+// for a sidecar serving SEI_SIDECAR_AUTHN_MODE=token.
+func WithBearerToken(token string) Option {
+	return func(o *sidecarOpts) { o.bearerToken = token }
+}
+
+// WithUnixSocket sends every request over the sidecar's Unix socket
+// (SEI_SIDECAR_UNIX_SOCKET) at path rather than TCP; the base URL's host
+// is then ignored, so "http://localhost" serves. It has no effect on
+// transports given with WithHTTPDoer or WithStreamDoer.
+func WithUnixSocket(path string) Option {
+	return func(o *sidecarOpts) { o.unixSocket = path }
+}
+
+// bearerDoer adds a bearer token to each request it sends.
+type bearerDoer struct {
+	next  HttpRequestDoer
+	token string
+}
+
+func (d bearerDoer) Do(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+d.token)
+	return d.next.Do(req)
+}
+
 // NewSidecarClient creates a client from an explicit base URL.
 func NewSidecarClient(baseURL string, opts ...Option) (*SidecarClient, error) {
 	o := sidecarOpts{timeout: 10 * time.Second}
@@ -51,16 +114,38 @@ func NewSidecarClient(baseURL string, opts ...Option) (*SidecarClient, error) {
 		fn(&o)
 	}
 
+	var transport http.RoundTripper
+	if o.unixSocket != "" {
+		transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", o.unixSocket)
+			},
+		}
+	}
+
 	httpClient := o.httpClient
 	if httpClient == nil {
-		httpClient = &http.Client{Timeout: o.timeout}
+		httpClient = &http.Client{Timeout: o.timeout, Transport: transport}
+	}
+
+	streamer := o.streamDoer
+	if streamer == nil {
+		streamer = o.httpClient
+	}
+	if streamer == nil {
+		streamer = &http.Client{Transport: transport}
+	}
+	if o.bearerToken != "" {
+		httpClient = bearerDoer{next: httpClient, token: o.bearerToken}
+		streamer = bearerDoer{next: streamer, token: o.bearerToken}
 	}
 
 	inner, err := NewClientWithResponses(baseURL, WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, err
 	}
-	return &SidecarClient{inner: inner, baseURL: baseURL, doer: httpClient}, nil
+	return &SidecarClient{inner: inner, baseURL: baseURL, doer: httpClient, streamer: streamer}, nil
 }
 
 // NewSidecarClientFromPodDNS builds a client targeting the sidecar via
@@ -90,6 +175,57 @@ func (c *SidecarClient) Status(ctx context.Context) (*StatusResponse, error) {
 	return resp.JSON200, nil
 }
 
+// Capabilities reports the sidecar's build and the task types it runs,
+// with their param and result schemas. A sidecar that predates the
+// endpoint yields ErrUnsupported.
+func (c *SidecarClient) Capabilities(ctx context.Context) (*CapabilitiesResponse, error) {
+	resp, err := c.inner.GetCapabilitiesWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querying sidecar capabilities: %w", err)
+	}
+	switch {
+	case resp.StatusCode() == http.StatusNotFound:
+		return nil, ErrUnsupported
+	case resp.StatusCode() != http.StatusOK:
+		return nil, fmt.Errorf("sidecar capabilities returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	case resp.JSON200 == nil:
+		return nil, fmt.Errorf("sidecar capabilities returned 200 but empty body")
+	}
+	return resp.JSON200, nil
+}
+
+// ValidateTask checks a submission against the capabilities: the task
+// type must be one the sidecar runs, and params must fit its schema,
+// naming no field the schema does not declare. Nil params are checked as
+// an empty object, as the sidecar decodes them.
+func (c *CapabilitiesResponse) ValidateTask(taskType string, params map[string]any) error {
+	for _, tc := range c.TaskTypes {
+		if tc.Type != taskType {
+			continue
+		}
+		if tc.Params == nil {
+			return nil
+		}
+		if params == nil {
+			params = map[string]any{}
+		}
+		raw, err := json.Marshal(tc.Params)
+		if err != nil {
+			return err
+		}
+		var schema wire.Schema
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return fmt.Errorf("sidecar published an unreadable schema for %s: %w", taskType, err)
+		}
+		return schema.Validate(params)
+	}
+	types := make([]string, len(c.TaskTypes))
+	for i, tc := range c.TaskTypes {
+		types[i] = tc.Type
+	}
+	return fmt.Errorf("sidecar %s does not run task type %q (supported: %s)", c.Version, taskType, strings.Join(types, ", "))
+}
+
 // SubmitTask sends a TaskRequest to the sidecar. This is the generic
 // submission path used internally by the typed Submit*Task methods and
 // by the controller for dynamic dispatch. Prefer the typed methods for
@@ -127,28 +263,67 @@ func (c *SidecarClient) SubmitTask(ctx context.Context, task TaskRequest) (uuid.
 		}
 		return uuid.Nil, fmt.Errorf("sidecar rejected %s task: %s", task.Type, bytes.TrimSpace(resp.Body))
 
+	case http.StatusForbidden:
+		msg := string(bytes.TrimSpace(resp.Body))
+		if resp.JSON403 != nil {
+			msg = resp.JSON403.Message
+		}
+		return uuid.Nil, fmt.Errorf("%w: %s", ErrForbidden, msg)
+
+	case http.StatusConflict:
+		msg := string(bytes.TrimSpace(resp.Body))
+		if resp.JSON409 != nil {
+			msg = resp.JSON409.Error
+		}
+		return uuid.Nil, fmt.Errorf("%w: sidecar returned 409 for %s task: %s", ErrConflict, task.Type, msg)
+
+	case http.StatusServiceUnavailable:
+		return uuid.Nil, unavailable(resp.HTTPResponse, fmt.Errorf("%w: %s task refused", ErrDraining, task.Type))
+
 	default:
 		return uuid.Nil, fmt.Errorf("sidecar %s task submission returned %d: %s", task.Type, resp.StatusCode(), bytes.TrimSpace(resp.Body))
 	}
 }
 
-// ListTasks returns recent task results.
+// ListTasks returns the most recent task results (one page of up to 100).
 func (c *SidecarClient) ListTasks(ctx context.Context) ([]TaskResult, error) {
-	resp, err := c.inner.ListTasksWithResponse(ctx)
+	results, _, err := c.ListTasksPage(ctx, ListTasksParams{})
+	return results, err
+}
+
+// ListTasksPage returns one page of task results matching params, newest
+// first, and the cursor for the next page, or "" on the last page. Pass the
+// cursor back as params.Cursor, with the same filters, to continue.
+func (c *SidecarClient) ListTasksPage(ctx context.Context, params ListTasksParams) ([]TaskResult, string, error) {
+	resp, err := c.inner.ListTasksWithResponse(ctx, &params)
 	if err != nil {
-		return nil, fmt.Errorf("listing sidecar tasks: %w", err)
+		return nil, "", fmt.Errorf("listing sidecar tasks: %w", err)
 	}
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("sidecar list tasks returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	switch resp.StatusCode() {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		if resp.JSON400 != nil {
+			return nil, "", fmt.Errorf("sidecar rejected task listing: %s", resp.JSON400.Error)
+		}
+		return nil, "", fmt.Errorf("sidecar rejected task listing: %s", bytes.TrimSpace(resp.Body))
+	default:
+		return nil, "", fmt.Errorf("sidecar list tasks returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
 	}
+	next := resp.HTTPResponse.Header.Get("X-Next-Cursor")
 	if resp.JSON200 == nil {
-		return []TaskResult{}, nil
+		return []TaskResult{}, next, nil
 	}
-	return *resp.JSON200, nil
+	return *resp.JSON200, next, nil
 }
 
-// GetTask retrieves a single task result by ID.
+// GetTask retrieves a single task result by ID. A transient failure the
+// sidecar answers with 503 and Retry-After is retried after the advised
+// delay.
 func (c *SidecarClient) GetTask(ctx context.Context, id uuid.UUID) (*TaskResult, error) {
+	return retryUnavailable(ctx, func() (*TaskResult, error) { return c.getTask(ctx, id) })
+}
+
+func (c *SidecarClient) getTask(ctx context.Context, id uuid.UUID) (*TaskResult, error) {
 	resp, err := c.inner.GetTaskWithResponse(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("getting sidecar task %s: %w", id, err)
@@ -161,13 +336,22 @@ func (c *SidecarClient) GetTask(ctx context.Context, id uuid.UUID) (*TaskResult,
 		return resp.JSON200, nil
 	case http.StatusNotFound:
 		return nil, ErrNotFound
+	case http.StatusServiceUnavailable:
+		return nil, unavailable(resp.HTTPResponse, fmt.Errorf("sidecar get task returned 503: %s", bytes.TrimSpace(resp.Body)))
 	default:
 		return nil, fmt.Errorf("sidecar get task returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
 	}
 }
 
-// DeleteTask removes a task result or cancels a running task.
+// DeleteTask removes a task result or cancels a running task. A transient
+// failure the sidecar answers with 503 and Retry-After is retried after the
+// advised delay.
 func (c *SidecarClient) DeleteTask(ctx context.Context, id uuid.UUID) error {
+	_, err := retryUnavailable(ctx, func() (struct{}, error) { return struct{}{}, c.deleteTask(ctx, id) })
+	return err
+}
+
+func (c *SidecarClient) deleteTask(ctx context.Context, id uuid.UUID) error {
 	resp, err := c.inner.DeleteTaskWithResponse(ctx, id)
 	if err != nil {
 		return fmt.Errorf("deleting sidecar task %s: %w", id, err)
@@ -177,11 +361,241 @@ func (c *SidecarClient) DeleteTask(ctx context.Context, id uuid.UUID) error {
 		return nil
 	case http.StatusNotFound:
 		return ErrNotFound
+	case http.StatusServiceUnavailable:
+		return unavailable(resp.HTTPResponse, fmt.Errorf("sidecar delete task returned 503: %s", bytes.TrimSpace(resp.Body)))
 	default:
 		return fmt.Errorf("sidecar delete task returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
 	}
 }
 
+// CancelTask stops a pending or running task and keeps its record with the
+// terminal status cancelled and the given reason. The returned result is
+// still running when the handler had not stopped by the time the sidecar
+// answered; poll GetTask until it settles. Cancelling an already-cancelled
+// task returns its record; a task that already finished otherwise yields
+// ErrTaskFinished.
+func (c *SidecarClient) CancelTask(ctx context.Context, id uuid.UUID, reason string) (*TaskResult, error) {
+	body := CancelTaskRequest{}
+	if reason != "" {
+		body.Reason = &reason
+	}
+	resp, err := c.inner.CancelTaskWithResponse(ctx, id, body)
+	if err != nil {
+		return nil, fmt.Errorf("cancelling sidecar task %s: %w", id, err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK, http.StatusAccepted:
+		result := resp.JSON200
+		if result == nil {
+			result = resp.JSON202
+		}
+		if result == nil {
+			return nil, fmt.Errorf("sidecar returned %d for cancel of task %s but empty body", resp.StatusCode(), id)
+		}
+		return result, nil
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	case http.StatusConflict:
+		msg := string(bytes.TrimSpace(resp.Body))
+		if resp.JSON409 != nil {
+			msg = resp.JSON409.Error
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTaskFinished, msg)
+	default:
+		return nil, fmt.Errorf("sidecar cancel task returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// SubmitTaskGraph sends a dependency graph of tasks to the sidecar and
+// returns the graph ID. Each node runs as an ordinary task once every node it
+// depends on has completed.
+func (c *SidecarClient) SubmitTaskGraph(ctx context.Context, graph TaskGraphRequest) (uuid.UUID, error) {
+	resp, err := c.inner.SubmitTaskGraphWithResponse(ctx, graph)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("submitting task graph to sidecar: %w", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusCreated:
+		if resp.JSON201 == nil || resp.JSON201.Id == uuid.Nil {
+			return uuid.Nil, fmt.Errorf("sidecar returned 201 but no task graph ID in response body")
+		}
+		return resp.JSON201.Id, nil
+	case http.StatusBadRequest:
+		if resp.JSON400 != nil {
+			return uuid.Nil, fmt.Errorf("sidecar rejected task graph: %s", resp.JSON400.Error)
+		}
+		return uuid.Nil, fmt.Errorf("sidecar rejected task graph: %s", bytes.TrimSpace(resp.Body))
+	case http.StatusServiceUnavailable:
+		return uuid.Nil, unavailable(resp.HTTPResponse, fmt.Errorf("%w: task graph refused", ErrDraining))
+	default:
+		return uuid.Nil, fmt.Errorf("sidecar task graph submission returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// GetTaskGraph retrieves a task graph's phase and per-node states.
+func (c *SidecarClient) GetTaskGraph(ctx context.Context, id uuid.UUID) (*TaskGraphStatus, error) {
+	resp, err := c.inner.GetTaskGraphWithResponse(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting sidecar task graph %s: %w", id, err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("sidecar returned 200 for task graph %s but empty body", id)
+		}
+		return resp.JSON200, nil
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("sidecar get task graph returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// CreateSchedule registers a recurring task schedule and returns its ID.
+// Recreating an existing schedule ID is a no-op that returns the same ID.
+func (c *SidecarClient) CreateSchedule(ctx context.Context, schedule ScheduleRequest) (uuid.UUID, error) {
+	resp, err := c.inner.CreateScheduleWithResponse(ctx, schedule)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("creating sidecar schedule: %w", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusCreated:
+		if resp.JSON201 == nil || resp.JSON201.Id == uuid.Nil {
+			return uuid.Nil, fmt.Errorf("sidecar returned 201 but no schedule ID in response body")
+		}
+		return resp.JSON201.Id, nil
+	case http.StatusBadRequest:
+		if resp.JSON400 != nil {
+			return uuid.Nil, fmt.Errorf("sidecar rejected schedule: %s", resp.JSON400.Error)
+		}
+		return uuid.Nil, fmt.Errorf("sidecar rejected schedule: %s", bytes.TrimSpace(resp.Body))
+	default:
+		return uuid.Nil, fmt.Errorf("sidecar schedule creation returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// ListSchedules returns every schedule, soonest next firing first.
+func (c *SidecarClient) ListSchedules(ctx context.Context) ([]Schedule, error) {
+	resp, err := c.inner.ListSchedulesWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing sidecar schedules: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("sidecar list schedules returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+	if resp.JSON200 == nil {
+		return []Schedule{}, nil
+	}
+	return *resp.JSON200, nil
+}
+
+// GetSchedule retrieves a schedule by ID.
+func (c *SidecarClient) GetSchedule(ctx context.Context, id uuid.UUID) (*Schedule, error) {
+	resp, err := c.inner.GetScheduleWithResponse(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting sidecar schedule %s: %w", id, err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("sidecar returned 200 for schedule %s but empty body", id)
+		}
+		return resp.JSON200, nil
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("sidecar get schedule returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// DeleteSchedule stops a schedule from firing again. Tasks it already
+// submitted are unaffected.
+func (c *SidecarClient) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	resp, err := c.inner.DeleteScheduleWithResponse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("deleting sidecar schedule %s: %w", id, err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		return fmt.Errorf("sidecar delete schedule returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// Drain puts the sidecar into drain mode: it refuses new submissions
+// (except types exempt from draining) while running tasks finish. A
+// non-zero timeout sets the drain's deadline. Poll Status for progress.
+func (c *SidecarClient) Drain(ctx context.Context, timeout time.Duration) (*DrainStatus, error) {
+	var body DrainRequest
+	if timeout > 0 {
+		t := timeout.String()
+		body.Timeout = &t
+	}
+	resp, err := c.inner.DrainWithResponse(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("draining sidecar: %w", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("sidecar drain returned 200 but empty body")
+		}
+		return resp.JSON200, nil
+	case http.StatusNotFound:
+		return nil, ErrUnsupported
+	default:
+		return nil, fmt.Errorf("sidecar drain returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// Undrain ends a drain, so the sidecar accepts submissions again. It
+// succeeds whether or not the sidecar was draining.
+func (c *SidecarClient) Undrain(ctx context.Context) error {
+	resp, err := c.inner.UndrainWithResponse(ctx)
+	if err != nil {
+		return fmt.Errorf("undraining sidecar: %w", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return ErrUnsupported
+	default:
+		return fmt.Errorf("sidecar undrain returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+}
+
+// ListAudit returns audit log entries with a seq greater than after,
+// oldest first; a limit of zero takes the server's default page size.
+// Page through the log by passing the last entry's Seq as after.
+func (c *SidecarClient) ListAudit(ctx context.Context, after int64, limit int) ([]AuditEntry, error) {
+	params := ListAuditParams{After: &after}
+	if limit > 0 {
+		params.Limit = &limit
+	}
+	resp, err := c.inner.ListAuditWithResponse(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("listing sidecar audit log: %w", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		if resp.JSON400 != nil {
+			return nil, fmt.Errorf("sidecar rejected audit listing: %s", resp.JSON400.Error)
+		}
+		return nil, fmt.Errorf("sidecar rejected audit listing: %s", bytes.TrimSpace(resp.Body))
+	default:
+		return nil, fmt.Errorf("sidecar list audit returned %d: %s", resp.StatusCode(), bytes.TrimSpace(resp.Body))
+	}
+	if resp.JSON200 == nil {
+		return []AuditEntry{}, nil
+	}
+	return *resp.JSON200, nil
+}
+
 // Healthz checks whether the sidecar is healthy.
 // Returns (true, nil) for 200, (false, nil) for 503, and (false, error)
 // for network failures or unexpected status codes.
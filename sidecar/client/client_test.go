@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -179,6 +182,54 @@ func TestSubmitTask_Conflict(t *testing.T) {
 	}
 }
 
+func TestSubmitTask_ConflictWrapsErrConflict(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: `exclusion group "data-dir" held`})
+	}))
+
+	_, err := c.SubmitTask(context.Background(), TaskRequest{Type: TaskTypeMarkReady})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("error = %v, want ErrConflict", err)
+	}
+	if !strings.Contains(err.Error(), "data-dir") {
+		t.Errorf("error = %v, expected to carry the server message", err)
+	}
+}
+
+func TestSubmitTask_DrainingWrapsErrDraining(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "engine is draining; not accepting new tasks"})
+	}))
+
+	if _, err := c.SubmitTask(context.Background(), TaskRequest{Type: TaskTypeMarkReady}); !errors.Is(err, ErrDraining) {
+		t.Fatalf("SubmitTask error = %v, want ErrDraining", err)
+	}
+	if _, err := c.SubmitTaskGraph(context.Background(), TaskGraphRequest{}); !errors.Is(err, ErrDraining) {
+		t.Fatalf("SubmitTaskGraph error = %v, want ErrDraining", err)
+	}
+}
+
+func TestSubmitTask_ForbiddenWrapsErrForbidden(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":"user \"alice\" cannot write task type \"gov-vote\"","reason":"Forbidden","code":403}`))
+	}))
+
+	_, err := c.SubmitTask(context.Background(), TaskRequest{Type: "gov-vote"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("error = %v, want ErrForbidden", err)
+	}
+	if !strings.Contains(err.Error(), "cannot write") {
+		t.Errorf("error = %v, expected to carry the status message", err)
+	}
+}
+
 func TestSubmitTask_BadRequest(t *testing.T) {
 	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -236,6 +287,48 @@ func TestListTasks_OK(t *testing.T) {
 	}
 }
 
+func TestListTasksPage_SendsFiltersAndReturnsCursor(t *testing.T) {
+	after := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		for k, want := range map[string]string{
+			"type": "gov-vote", "status": "failed", "submittedAfter": "2026-01-02T03:04:05Z",
+			"limit": "5", "cursor": "abc",
+		} {
+			if got := q.Get(k); got != want {
+				t.Errorf("%s = %q, want %q", k, got, want)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Next-Cursor", "def")
+		_ = json.NewEncoder(w).Encode([]TaskResult{{Id: uuid.New(), Type: "gov-vote"}})
+	}))
+
+	typ, status, limit, cursor := "gov-vote", ListTasksParamsStatusFailed, 5, "abc"
+	results, next, err := c.ListTasksPage(context.Background(), ListTasksParams{
+		Type: &typ, Status: &status, SubmittedAfter: &after, Limit: &limit, Cursor: &cursor,
+	})
+	if err != nil {
+		t.Fatalf("ListTasksPage() error = %v", err)
+	}
+	if len(results) != 1 || next != "def" {
+		t.Errorf("got %d results, next %q; want 1, \"def\"", len(results), next)
+	}
+}
+
+func TestListTasksPage_BadRequest(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid cursor"})
+	}))
+
+	_, _, err := c.ListTasksPage(context.Background(), ListTasksParams{})
+	if err == nil || !strings.Contains(err.Error(), "invalid cursor") {
+		t.Errorf("error = %v, want the server's message", err)
+	}
+}
+
 func TestGetTask_OK(t *testing.T) {
 	taskID := uuid.New()
 	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -488,3 +581,352 @@ func TestGetNodeID_EmptyNodeID(t *testing.T) {
 		t.Errorf("error = %v, expected to contain 'missing nodeId'", err)
 	}
 }
+
+func TestSubmitTaskGraph_HTTP201(t *testing.T) {
+	graphID := uuid.New()
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/task-graphs" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body TaskGraphRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		if len(body.Nodes) != 2 || body.Nodes[1].DependsOn == nil || (*body.Nodes[1].DependsOn)[0] != "a" {
+			t.Errorf("unexpected nodes: %+v", body.Nodes)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(TaskSubmitResponse{Id: graphID})
+	}))
+
+	id, err := c.SubmitTaskGraph(context.Background(), TaskGraphRequest{Nodes: []TaskGraphNode{
+		{Name: "a", Type: TaskTypeConfigPatch},
+		{Name: "b", Type: TaskTypeMarkReady, DependsOn: &[]string{"a"}},
+	}})
+	if err != nil {
+		t.Fatalf("SubmitTaskGraph() error = %v", err)
+	}
+	if id != graphID {
+		t.Errorf("id = %s, want %s", id, graphID)
+	}
+}
+
+func TestSubmitTaskGraph_BadRequest(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid task graph: dependency cycle"})
+	}))
+
+	_, err := c.SubmitTaskGraph(context.Background(), TaskGraphRequest{})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Fatalf("err = %v, want the server's rejection", err)
+	}
+}
+
+func TestGetTaskGraph_NotFound(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "task graph not found"})
+	}))
+
+	if _, err := c.GetTaskGraph(context.Background(), uuid.New()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCreateSchedule_HTTP201(t *testing.T) {
+	scheduleID := uuid.New()
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/schedules" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body ScheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		if body.Cron == nil || *body.Cron != "0 3 * * *" || body.Task.Type != TaskTypeConfigValidate {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(TaskSubmitResponse{Id: scheduleID})
+	}))
+
+	cron := "0 3 * * *"
+	id, err := c.CreateSchedule(context.Background(), ScheduleRequest{
+		Cron: &cron,
+		Task: ScheduleTask{Type: TaskTypeConfigValidate},
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	if id != scheduleID {
+		t.Errorf("id = %s, want %s", id, scheduleID)
+	}
+}
+
+func TestCreateSchedule_BadRequest(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid schedule: cron and interval are mutually exclusive"})
+	}))
+
+	_, err := c.CreateSchedule(context.Background(), ScheduleRequest{})
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("err = %v, want the server's rejection", err)
+	}
+}
+
+func TestListSchedules_OK(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]Schedule{{Id: uuid.New(), MissedPolicy: "skip", Task: ScheduleTask{Type: "config-validate"}}})
+	}))
+
+	schedules, err := c.ListSchedules(context.Background())
+	if err != nil {
+		t.Fatalf("ListSchedules() error = %v", err)
+	}
+	if len(schedules) != 1 || schedules[0].Task.Type != "config-validate" {
+		t.Errorf("schedules = %+v", schedules)
+	}
+}
+
+func TestGetSchedule_NotFound(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "schedule not found"})
+	}))
+
+	if _, err := c.GetSchedule(context.Background(), uuid.New()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeleteSchedule_OK(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || !strings.HasPrefix(r.URL.Path, "/v0/schedules/") {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if err := c.DeleteSchedule(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("DeleteSchedule() error = %v", err)
+	}
+}
+
+func TestCancelTask_OK(t *testing.T) {
+	taskID := uuid.New()
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v0/tasks/"+taskID.String()+":cancel" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body CancelTaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		if body.Reason == nil || *body.Reason != "stuck" {
+			t.Errorf("reason = %v, want stuck", body.Reason)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(TaskResult{Id: taskID, Type: "config-patch", Status: Cancelled})
+	}))
+
+	result, err := c.CancelTask(context.Background(), taskID, "stuck")
+	if err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	if result.Status != Cancelled {
+		t.Errorf("Status = %q, want cancelled", result.Status)
+	}
+}
+
+func TestCancelTask_Accepted(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(TaskResult{Id: uuid.New(), Type: "config-patch", Status: Running})
+	}))
+
+	result, err := c.CancelTask(context.Background(), uuid.New(), "")
+	if err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	if result.Status != Running {
+		t.Errorf("Status = %q, want running", result.Status)
+	}
+}
+
+func TestCancelTask_Finished(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "task already finished: task x is completed"})
+	}))
+
+	if _, err := c.CancelTask(context.Background(), uuid.New(), ""); !errors.Is(err, ErrTaskFinished) {
+		t.Fatalf("err = %v, want ErrTaskFinished", err)
+	}
+}
+
+func TestCancelTask_NotFound(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "task not found"})
+	}))
+
+	if _, err := c.CancelTask(context.Background(), uuid.New(), ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListAudit_SendsPagingParams(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/audit" || r.URL.Query().Get("after") != "7" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"seq":8,"at":"2026-01-01T00:00:00Z","caller":{"user":"alice"},"action":"submit","status":201}]`))
+	}))
+
+	entries, err := c.ListAudit(context.Background(), 7, 2)
+	if err != nil {
+		t.Fatalf("ListAudit() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Seq != 8 || entries[0].Caller.User == nil || *entries[0].Caller.User != "alice" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestDrain_SendsTimeout(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/admin/drain":
+			var req DrainRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Timeout == nil || *req.Timeout != "10m0s" {
+				t.Errorf("drain body = %+v, %v; want timeout 10m0s", req, err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"since":"2026-01-01T00:00:00Z","deadline":"2026-01-01T00:10:00Z","running":2,"drained":false}`))
+		case "/v0/admin/undrain":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+
+	st, err := c.Drain(context.Background(), 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if st.Running != 2 || st.Drained || st.Deadline == nil {
+		t.Errorf("drain status = %+v", st)
+	}
+	if err := c.Undrain(context.Background()); err != nil {
+		t.Fatalf("Undrain() error = %v", err)
+	}
+}
+
+func TestWithBearerToken_SetsAuthorization(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer s3cret" {
+			t.Errorf("Authorization = %q, want the bearer token", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(StatusResponse{Status: Ready})
+	}))
+	t.Cleanup(srv.Close)
+	c, err := NewSidecarClient(srv.URL, WithBearerToken("s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Status(context.Background()); err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+}
+
+func TestWithUnixSocket_DialsTheSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(StatusResponse{Status: Ready})
+	}))
+	srv.Listener = ln
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	c, err := NewSidecarClient("http://localhost", WithUnixSocket(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if resp.Status != Ready {
+		t.Errorf("Status = %q, want %q", resp.Status, Ready)
+	}
+}
+
+func TestCapabilities_OlderSidecarIsUnsupported(t *testing.T) {
+	c := newTestClient(t, http.NotFoundHandler())
+	if _, err := c.Capabilities(context.Background()); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestCapabilities_ValidateTask(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/capabilities" {
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"v1","storeSchemaVersion":17,"authnMode":"token","taskTypes":[
+			{"type":"gov-vote","params":{"type":"object","properties":{"proposalId":{"type":"integer","minimum":0}}}},
+			{"type":"list-only","params":{"type":"array"}},
+			{"type":"mark-ready"}]}`))
+	}))
+	caps, err := c.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.AuthnMode != Token {
+		t.Errorf("AuthnMode = %q", caps.AuthnMode)
+	}
+	if err := caps.ValidateTask("gov-vote", map[string]any{"proposalId": float64(7)}); err != nil {
+		t.Errorf("valid params: %v", err)
+	}
+	if err := caps.ValidateTask("gov-vote", map[string]any{"proposalId": "seven"}); err == nil || !strings.Contains(err.Error(), "params.proposalId") {
+		t.Errorf("mistyped param: err = %v", err)
+	}
+	if err := caps.ValidateTask("gov-vote", map[string]any{"proposalID": float64(7)}); err != nil {
+		t.Errorf("param in another case: %v", err)
+	}
+	if err := caps.ValidateTask("gov-vote", map[string]any{"proposalId": float64(7), "propsalId": float64(7)}); err == nil || !strings.Contains(err.Error(), "params.propsalId: unknown field") {
+		t.Errorf("misspelled param: err = %v", err)
+	}
+	// Nil params are checked as an empty object, not skipped.
+	if err := caps.ValidateTask("gov-vote", nil); err != nil {
+		t.Errorf("nil params for an object schema: %v", err)
+	}
+	if err := caps.ValidateTask("list-only", nil); err == nil {
+		t.Error("nil params passed a schema that does not accept an object")
+	}
+	if err := caps.ValidateTask("mark-ready", map[string]any{"anything": true}); err != nil {
+		t.Errorf("opaque params: %v", err)
+	}
+	if err := caps.ValidateTask("gov-vot", nil); err == nil || !strings.Contains(err.Error(), "gov-vote, list-only, mark-ready") {
+		t.Errorf("unknown type: err = %v, want the supported types listed", err)
+	}
+}
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrEventsUnsupported is returned by Watch when the sidecar predates the
+// event stream (HTTP 404 on /v0/events). Callers fall back to polling.
+var ErrEventsUnsupported = errors.New("sidecar: event stream not supported")
+
+// watchRetryDelay and watchMaxRetries bound how Watch reconnects a dropped
+// stream: a fixed pause between attempts, and a cap on consecutive attempts
+// that fail to connect or end without delivering an event.
+const (
+	watchRetryDelay = time.Second
+	watchMaxRetries = 5
+)
+
+// TaskEvent is one event from the sidecar's event stream. Type is one of
+// submitted, started, progress, completed, failed, cancelled, or skipped;
+// Task is the task's record as it stood right after the transition. ID is
+// the event's position in the sidecar's event log.
+type TaskEvent struct {
+	ID   string
+	Type string
+	Task TaskResult
+}
+
+// terminal reports whether ev settles its task.
+func (ev TaskEvent) terminal() bool {
+	switch ev.Type {
+	case "completed", "failed", "cancelled", "skipped":
+		return true
+	}
+	return false
+}
+
+// Watch follows task id over the sidecar's event stream until it reaches a
+// terminal status, calling onEvent (when non-nil) for each event, and returns
+// the settled record. A dropped stream is resumed from the last event seen.
+// A task that settled before the stream opened is returned without waiting.
+// Watch returns ErrNotFound when the task does not exist and
+// ErrEventsUnsupported when the sidecar has no event stream.
+func (c *SidecarClient) Watch(ctx context.Context, id uuid.UUID, onEvent func(TaskEvent)) (*TaskResult, error) {
+	var lastID string
+	failures := 0
+	for {
+		if failures > 0 {
+			if err := sleepCtx(ctx, watchRetryDelay); err != nil {
+				return nil, err
+			}
+		}
+		body, err := c.openEvents(ctx, id, lastID)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrEventsUnsupported) {
+				return nil, err
+			}
+			failures++
+			if failures >= watchMaxRetries {
+				return nil, err
+			}
+			continue
+		}
+
+		// The subscription is live once the headers arrive, so a task found
+		// settled here will not produce a terminal event later.
+		tr, err := c.GetTask(ctx, id)
+		if err != nil {
+			_ = body.Close()
+			return nil, err
+		}
+		if settled(tr.Status) {
+			_ = body.Close()
+			return tr, nil
+		}
+
+		received := false
+		tr, err = readEvents(body, func(ev TaskEvent) {
+			received = true
+			lastID = ev.ID
+			if onEvent != nil {
+				onEvent(ev)
+			}
+		})
+		_ = body.Close()
+		if tr != nil {
+			return tr, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// The stream dropped. One that delivered events is resumed straight
+		// away; repeated empty streams count against the retry budget.
+		if received {
+			failures = 0
+		}
+		failures++
+		if failures >= watchMaxRetries {
+			return nil, fmt.Errorf("event stream for task %s: %w", id, err)
+		}
+	}
+}
+
+// openEvents opens the event stream for one task, resuming after lastID when
+// it is set, and returns the response body positioned at the first event.
+func (c *SidecarClient) openEvents(ctx context.Context, id uuid.UUID, lastID string) (io.ReadCloser, error) {
+	params := &StreamEventsParams{Task: &id}
+	req, err := NewStreamEventsRequest(c.baseURL, params)
+	if err != nil {
+		return nil, fmt.Errorf("building event stream request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := c.streamer.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("opening event stream: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, ErrEventsUnsupported
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("sidecar event stream returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+}
+
+// readEvents parses a text/event-stream body, calling onEvent for each
+// event, until a terminal event arrives (whose task it returns) or the
+// stream ends.
+func readEvents(body io.Reader, onEvent func(TaskEvent)) (*TaskResult, error) {
+	r := bufio.NewReader(body)
+	var id, typ string
+	var data []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if len(data) > 0 {
+				ev := TaskEvent{ID: id, Type: typ}
+				if err := json.Unmarshal([]byte(strings.Join(data, "\n")), &ev.Task); err != nil {
+					return nil, fmt.Errorf("parsing %s event %s: %w", typ, id, err)
+				}
+				onEvent(ev)
+				if ev.terminal() {
+					return &ev.Task, nil
+				}
+			}
+			typ, data = "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			typ = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
+
+// settled reports whether a task with status s has stopped running.
+func settled(s TaskResultStatus) bool {
+	switch s {
+	case Completed, Failed, Cancelled, Skipped:
+		return true
+	}
+	return false
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
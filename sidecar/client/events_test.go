@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func writeSSE(t *testing.T, w http.ResponseWriter, id int, typ string, tr TaskResult) {
+	t.Helper()
+	data, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	_, _ = fmt.Fprintf(w, ": keepalive\n\nid: %d\nevent: %s\ndata: %s\n\n", id, typ, data)
+	w.(http.Flusher).Flush()
+}
+
+func writeTask(w http.ResponseWriter, tr TaskResult) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tr)
+}
+
+func TestWatch_FollowsToTerminalEvent(t *testing.T) {
+	id := uuid.New()
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/events":
+			if got := r.URL.Query().Get("task"); got != id.String() {
+				t.Errorf("task = %q, want %q", got, id)
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeSSE(t, w, 1, "started", TaskResult{Id: id, Status: Running})
+			writeSSE(t, w, 2, "completed", TaskResult{Id: id, Status: Completed})
+		case "/v0/tasks/" + id.String():
+			writeTask(w, TaskResult{Id: id, Status: Running})
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	var types []string
+	tr, err := c.Watch(context.Background(), id, func(ev TaskEvent) { types = append(types, ev.Type) })
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if tr.Status != Completed {
+		t.Errorf("Status = %q, want completed", tr.Status)
+	}
+	if len(types) != 2 || types[0] != "started" || types[1] != "completed" {
+		t.Errorf("events = %v, want [started completed]", types)
+	}
+}
+
+func TestWatch_ResumesFromLastEventID(t *testing.T) {
+	id := uuid.New()
+	var conns atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/events" {
+			writeTask(w, TaskResult{Id: id, Status: Running})
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		if conns.Add(1) == 1 {
+			writeSSE(t, w, 7, "started", TaskResult{Id: id, Status: Running})
+			return // drop the stream
+		}
+		if got := r.Header.Get("Last-Event-ID"); got != "7" {
+			t.Errorf("Last-Event-ID = %q, want 7", got)
+		}
+		writeSSE(t, w, 8, "failed", TaskResult{Id: id, Status: Failed})
+	}))
+
+	tr, err := c.Watch(context.Background(), id, nil)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if tr.Status != Failed {
+		t.Errorf("Status = %q, want failed", tr.Status)
+	}
+	if conns.Load() != 2 {
+		t.Errorf("connections = %d, want 2", conns.Load())
+	}
+}
+
+func TestWatch_AlreadySettled(t *testing.T) {
+	id := uuid.New()
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/events" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		writeTask(w, TaskResult{Id: id, Status: Cancelled})
+	}))
+
+	tr, err := c.Watch(context.Background(), id, nil)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if tr.Status != Cancelled {
+		t.Errorf("Status = %q, want cancelled", tr.Status)
+	}
+}
+
+func TestWatch_Unsupported(t *testing.T) {
+	c := newTestClient(t, http.NotFoundHandler())
+
+	_, err := c.Watch(context.Background(), uuid.New(), nil)
+	if !errors.Is(err, ErrEventsUnsupported) {
+		t.Errorf("err = %v, want ErrEventsUnsupported", err)
+	}
+}
+
+func TestWatch_TaskNotFound(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/events" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		http.NotFound(w, r)
+	}))
+
+	_, err := c.Watch(context.Background(), uuid.New(), nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// defaultFleetParallelism is how many nodes a FleetClient has in flight at
+// once when FleetOptions.Parallelism is unset.
+const defaultFleetParallelism = 10
+
+// FleetTarget is one node a FleetClient submits to. Name identifies the
+// node in the outcome and seeds its task ID, so it must be unique and
+// stable across runs, e.g. the SeiNode name.
+type FleetTarget struct {
+	Name   string
+	Client *SidecarClient
+}
+
+// FleetOptions shapes how a FleetClient rolls a task out.
+type FleetOptions struct {
+	// Parallelism bounds how many nodes are in flight at once; zero
+	// means 10.
+	Parallelism int
+
+	// Canary is how many nodes, from the front of the target list, make
+	// up a first stage of their own; zero skips the canary. BatchSize is
+	// how many of the remaining nodes make up each later stage; zero puts
+	// them all in one. A stage starts once every node of the one before
+	// it is done.
+	Canary    int
+	BatchSize int
+
+	// Wait, when set, follows each node's task to a terminal status as
+	// WaitForTask does before the node counts as done. Nil counts a node
+	// done once its sidecar accepts the task, which is only allowed for a
+	// single-stage rollout: a stage must not pass on a task that then fails
+	// at runtime, so NewFleetClient defaults Wait to zero WaitOptions when
+	// Canary or BatchSize is set.
+	Wait *WaitOptions
+
+	// ContinueOnFailure runs the later stages after a node fails. By
+	// default a failure halts the rollout once its stage is done, and the
+	// nodes of the later stages are reported NodeNotAttempted.
+	ContinueOnFailure bool
+}
+
+// NodeStatus is where one node of a fleet rollout ended up.
+type NodeStatus string
+
+const (
+	// NodeSubmitted is a node whose sidecar accepted the task, when the
+	// rollout does not wait on tasks.
+	NodeSubmitted NodeStatus = "submitted"
+	// NodeCompleted is a node whose task completed.
+	NodeCompleted NodeStatus = "completed"
+	// NodeFailed is a node whose submission failed, or whose task
+	// failed, was cancelled, or could not be followed.
+	NodeFailed NodeStatus = "failed"
+	// NodeNotAttempted is a node of a stage the rollout halted before.
+	NodeNotAttempted NodeStatus = "not-attempted"
+)
+
+// NodeOutcome is one node's row of a fleet rollout's outcome.
+type NodeOutcome struct {
+	Node   string     `json:"node"`
+	Stage  int        `json:"stage"`
+	ID     uuid.UUID  `json:"id"`
+	Status NodeStatus `json:"status"`
+	// Task is the node's settled task record, when the rollout waited and
+	// the task settled.
+	Task  *TaskResult `json:"task,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// FleetResult is the outcome of a fleet rollout, one row per target in
+// target order. ID is the base the per-node task IDs derive from; Halted
+// is set when a failure stopped the rollout before its last stage.
+type FleetResult struct {
+	ID     uuid.UUID     `json:"id"`
+	Nodes  []NodeOutcome `json:"nodes"`
+	Halted bool          `json:"halted,omitempty"`
+}
+
+// Succeeded reports whether every node was submitted or completed.
+func (r *FleetResult) Succeeded() bool {
+	for _, n := range r.Nodes {
+		if n.Status != NodeSubmitted && n.Status != NodeCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// FleetNodeID is the task ID a fleet rollout with base ID base submits to
+// node. It is deterministic, so rerunning a rollout with the same base
+// resubmits each node's task idempotently rather than starting it again.
+func FleetNodeID(base uuid.UUID, node string) uuid.UUID {
+	return uuid.NewSHA1(base, []byte(node))
+}
+
+// FleetClient submits one task to many nodes' sidecars, in stages with
+// bounded parallelism, and aggregates the per-node outcome.
+type FleetClient struct {
+	targets []FleetTarget
+	opts    FleetOptions
+}
+
+// NewFleetClient returns a FleetClient over targets, rolled out in target
+// order.
+func NewFleetClient(targets []FleetTarget, opts FleetOptions) (*FleetClient, error) {
+	if len(targets) == 0 {
+		return nil, errors.New("fleet has no targets")
+	}
+	seen := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		switch {
+		case t.Name == "":
+			return nil, errors.New("fleet target has no name")
+		case t.Client == nil:
+			return nil, fmt.Errorf("fleet target %s has no client", t.Name)
+		case seen[t.Name]:
+			return nil, fmt.Errorf("fleet target %s is listed twice", t.Name)
+		}
+		seen[t.Name] = true
+	}
+	if opts.Parallelism < 0 || opts.Canary < 0 || opts.BatchSize < 0 {
+		return nil, errors.New("fleet parallelism, canary and batch size must not be negative")
+	}
+	if opts.Parallelism == 0 {
+		opts.Parallelism = defaultFleetParallelism
+	}
+	if opts.Wait == nil && (opts.Canary > 0 || opts.BatchSize > 0) {
+		opts.Wait = &WaitOptions{}
+	}
+	return &FleetClient{targets: targets, opts: opts}, nil
+}
+
+// Submit rolls task out to every target, each under the ID FleetNodeID
+// derives from task's ID, or from a fresh base when task has none. Per-node
+// failures are reported in the result, not returned; the error is ctx's
+// when it ends the rollout early, and the result then holds the nodes
+// reached so far.
+func (f *FleetClient) Submit(ctx context.Context, task TaskRequest) (*FleetResult, error) {
+	base := uuid.New()
+	if task.Id != nil {
+		base = *task.Id
+	}
+	stages := f.stages()
+	res := &FleetResult{ID: base, Nodes: make([]NodeOutcome, len(f.targets))}
+	for stage, r := range stages {
+		for i := r[0]; i < r[1]; i++ {
+			res.Nodes[i] = NodeOutcome{
+				Node:   f.targets[i].Name,
+				Stage:  stage + 1,
+				ID:     FleetNodeID(base, f.targets[i].Name),
+				Status: NodeNotAttempted,
+			}
+		}
+	}
+
+	for stage, r := range stages {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		f.runStage(ctx, task, f.targets[r[0]:r[1]], res.Nodes[r[0]:r[1]])
+		if f.opts.ContinueOnFailure || stage == len(stages)-1 {
+			continue
+		}
+		for _, n := range res.Nodes[r[0]:r[1]] {
+			if n.Status == NodeFailed {
+				res.Halted = true
+				return res, nil
+			}
+		}
+	}
+	return res, ctx.Err()
+}
+
+// stages splits the targets into the half-open index ranges of the
+// rollout's stages.
+func (f *FleetClient) stages() [][2]int {
+	var stages [][2]int
+	lo := 0
+	if f.opts.Canary > 0 {
+		lo = min(f.opts.Canary, len(f.targets))
+		stages = append(stages, [2]int{0, lo})
+	}
+	for lo < len(f.targets) {
+		hi := len(f.targets)
+		if f.opts.BatchSize > 0 {
+			hi = min(lo+f.opts.BatchSize, hi)
+		}
+		stages = append(stages, [2]int{lo, hi})
+		lo = hi
+	}
+	return stages
+}
+
+// runStage submits task to each of targets, at most Parallelism at once,
+// filling in the matching outcomes.
+func (f *FleetClient) runStage(ctx context.Context, task TaskRequest, targets []FleetTarget, out []NodeOutcome) {
+	sem := make(chan struct{}, f.opts.Parallelism)
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			f.runNode(ctx, task, targets[i].Client, &out[i])
+		}()
+	}
+	wg.Wait()
+}
+
+func (f *FleetClient) runNode(ctx context.Context, task TaskRequest, c *SidecarClient, out *NodeOutcome) {
+	id := out.ID
+	task.Id = &id
+	if f.opts.Wait == nil {
+		if _, err := c.SubmitTask(ctx, task); err != nil {
+			out.Status, out.Error = NodeFailed, err.Error()
+			return
+		}
+		out.Status = NodeSubmitted
+		return
+	}
+	_, tr, err := SubmitAndWait[json.RawMessage](ctx, c, task, *f.opts.Wait)
+	out.Task = tr
+	if err != nil {
+		out.Status, out.Error = NodeFailed, err.Error()
+		return
+	}
+	out.Status = NodeCompleted
+}
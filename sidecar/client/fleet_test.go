@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fleetTestNode is one fake node's sidecar. It records the task IDs
+// submitted to it and, when reject is set, refuses every submission; when
+// fail is set, it accepts them and reports the task failed.
+type fleetTestNode struct {
+	reject bool
+	fail   bool
+	mu     sync.Mutex
+	ids    []uuid.UUID
+}
+
+func (n *fleetTestNode) handler(inFlight, peak *atomic.Int32) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v0/tasks", func(w http.ResponseWriter, r *http.Request) {
+		cur := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if cur <= p || peak.CompareAndSwap(p, cur) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var req TaskRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		n.mu.Lock()
+		n.ids = append(n.ids, *req.Id)
+		n.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if n.reject {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "disk full"})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(TaskSubmitResponse{Id: *req.Id})
+	})
+	mux.HandleFunc("GET /v0/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		tr := TaskResult{Id: uuid.MustParse(r.PathValue("id")), Type: TaskTypeConfigReload, Status: Completed}
+		if n.fail {
+			msg := "seid exited"
+			tr.Status, tr.Error = Failed, &msg
+		}
+		_ = json.NewEncoder(w).Encode(tr)
+	})
+	return mux
+}
+
+func newTestFleet(t *testing.T, names []string, reject map[string]bool) ([]FleetTarget, map[string]*fleetTestNode, *atomic.Int32) {
+	t.Helper()
+	return newTestFleetFailing(t, names, reject, nil)
+}
+
+func newTestFleetFailing(t *testing.T, names []string, reject, fail map[string]bool) ([]FleetTarget, map[string]*fleetTestNode, *atomic.Int32) {
+	t.Helper()
+	var inFlight, peak atomic.Int32
+	nodes := make(map[string]*fleetTestNode, len(names))
+	targets := make([]FleetTarget, len(names))
+	for i, name := range names {
+		nodes[name] = &fleetTestNode{reject: reject[name], fail: fail[name]}
+		targets[i] = FleetTarget{Name: name, Client: newTestClient(t, nodes[name].handler(&inFlight, &peak))}
+	}
+	return targets, nodes, &peak
+}
+
+func TestFleetSubmit_BatchesWithBoundedParallelism(t *testing.T) {
+	names := []string{"n0", "n1", "n2", "n3", "n4", "n5", "n6"}
+	targets, nodes, peak := newTestFleet(t, names, nil)
+	fc, err := NewFleetClient(targets, FleetOptions{Parallelism: 2, Canary: 1, BatchSize: 3, Wait: &WaitOptions{PollInterval: time.Millisecond}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := uuid.New()
+	res, err := fc.Submit(context.Background(), TaskRequest{Id: &base, Type: TaskTypeConfigReload})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if !res.Succeeded() || res.Halted || res.ID != base {
+		t.Fatalf("result = %+v, want every node completed under the given base", res)
+	}
+	wantStages := []int{1, 2, 2, 2, 3, 3, 3}
+	for i, n := range res.Nodes {
+		if n.Node != names[i] || n.Stage != wantStages[i] || n.Status != NodeCompleted || n.Task == nil {
+			t.Errorf("node %d = %+v, want %s completed in stage %d", i, n, names[i], wantStages[i])
+		}
+		if got := nodes[n.Node].ids; len(got) != 1 || got[0] != FleetNodeID(base, n.Node) || n.ID != got[0] {
+			t.Errorf("node %s submitted as %v, want its derived ID", n.Node, got)
+		}
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak in-flight submissions = %d, want at most 2", p)
+	}
+	if FleetNodeID(base, "n0") == FleetNodeID(base, "n1") || FleetNodeID(base, "n0") != FleetNodeID(base, "n0") {
+		t.Error("FleetNodeID is not deterministic per node")
+	}
+}
+
+func TestFleetSubmit_CanaryFailureHalts(t *testing.T) {
+	targets, nodes, _ := newTestFleet(t, []string{"canary", "a", "b"}, map[string]bool{"canary": true})
+	fc, err := NewFleetClient(targets, FleetOptions{Canary: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := fc.Submit(context.Background(), TaskRequest{Type: TaskTypeConfigReload})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if !res.Halted || res.Succeeded() {
+		t.Fatalf("result = %+v, want the rollout halted", res)
+	}
+	if n := res.Nodes[0]; n.Status != NodeFailed || n.Error == "" {
+		t.Errorf("canary = %+v, want failed with the sidecar's error", n)
+	}
+	for _, n := range res.Nodes[1:] {
+		if n.Status != NodeNotAttempted || len(nodes[n.Node].ids) != 0 {
+			t.Errorf("node %+v was attempted after the canary failed", n)
+		}
+	}
+
+	fc, _ = NewFleetClient(targets, FleetOptions{Canary: 1, ContinueOnFailure: true, Wait: &WaitOptions{PollInterval: time.Millisecond}})
+	res, _ = fc.Submit(context.Background(), TaskRequest{Type: TaskTypeConfigReload})
+	if res.Halted || res.Nodes[1].Status != NodeCompleted || res.Nodes[2].Status != NodeCompleted {
+		t.Errorf("result = %+v, want later stages run despite the failure", res)
+	}
+
+	if _, err := NewFleetClient(append(targets, targets[0]), FleetOptions{}); err == nil {
+		t.Error("NewFleetClient accepted a target listed twice")
+	}
+}
+
+// A staged rollout waits on tasks even when the caller set no Wait, so a
+// canary that is accepted but then fails still halts it.
+func TestFleetSubmit_StagedRolloutWaitsByDefault(t *testing.T) {
+	targets, nodes, _ := newTestFleetFailing(t, []string{"canary", "a"}, nil, map[string]bool{"canary": true})
+	fc, err := NewFleetClient(targets, FleetOptions{Canary: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := fc.Submit(context.Background(), TaskRequest{Type: TaskTypeConfigReload})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if n := res.Nodes[0]; n.Status != NodeFailed || n.Task == nil {
+		t.Errorf("canary = %+v, want failed on its settled task", n)
+	}
+	if !res.Halted || len(nodes["a"].ids) != 0 {
+		t.Errorf("result = %+v, want the rollout halted before node a", res)
+	}
+}
@@ -64,6 +64,9 @@ const (
 	TaskTypeMarkNotReady = string(wire.TaskMarkNotReady)
 	TaskTypeStopSeid     = string(wire.TaskStopSeid)
 	TaskTypeResetData    = string(wire.TaskResetData)
+
+	TaskTypePrepareUpgrade = string(wire.TaskPrepareUpgrade)
+	TaskTypeApplyUpgrade   = string(wire.TaskApplyUpgrade)
 )
 
 // Snapshot-upload outcome contract, re-exported from wire so CLI consumers
@@ -296,6 +299,76 @@ func (t ResetDataTask) ToTaskRequest() TaskRequest {
 	return TaskRequest{Type: t.TaskType()}
 }
 
+// PrepareUpgradeTask downloads a software-upgrade plan's seid binary, named
+// for the node's platform in the plan's cosmovisor-style info JSON, verifies
+// its sha256 checksum, and places it under cosmovisor/upgrades/<name>/bin.
+type PrepareUpgradeTask struct {
+	UpgradeName string
+	UpgradeInfo string
+}
+
+func (t PrepareUpgradeTask) TaskType() string { return TaskTypePrepareUpgrade }
+
+func (t PrepareUpgradeTask) Validate() error {
+	if t.UpgradeName == "" {
+		return fmt.Errorf("prepare-upgrade: UpgradeName is required")
+	}
+	if t.UpgradeInfo == "" {
+		return fmt.Errorf("prepare-upgrade: UpgradeInfo is required")
+	}
+	return nil
+}
+
+func (t PrepareUpgradeTask) ToTaskRequest() TaskRequest {
+	p := map[string]interface{}{
+		"upgradeName": t.UpgradeName,
+		"upgradeInfo": t.UpgradeInfo,
+	}
+	return TaskRequest{Type: t.TaskType(), Params: &p}
+}
+
+// ApplyUpgradeTask switches the active seid binary to the prepared upgrade
+// and restarts seid onto it. The sidecar waits until seid has reached the
+// halt height, the block before UpgradeHeight, before switching; submit it
+// with Graph, which chains it behind an await-condition on that height, so
+// the wait holds no exclusion group.
+type ApplyUpgradeTask struct {
+	UpgradeName   string
+	UpgradeHeight int64
+}
+
+func (t ApplyUpgradeTask) TaskType() string { return TaskTypeApplyUpgrade }
+
+func (t ApplyUpgradeTask) Validate() error {
+	if t.UpgradeName == "" {
+		return fmt.Errorf("apply-upgrade: UpgradeName is required")
+	}
+	if t.UpgradeHeight <= 1 {
+		return fmt.Errorf("apply-upgrade: UpgradeHeight must be > 1")
+	}
+	return nil
+}
+
+func (t ApplyUpgradeTask) ToTaskRequest() TaskRequest {
+	p := map[string]interface{}{
+		"upgradeName":   t.UpgradeName,
+		"upgradeHeight": t.UpgradeHeight,
+	}
+	return TaskRequest{Type: t.TaskType(), Params: &p}
+}
+
+// Graph returns a task graph that waits for the halt height and then
+// applies the upgrade, so the wait holds no exclusion group and the switch
+// and restart queue behind the node's other lifecycle tasks.
+func (t ApplyUpgradeTask) Graph() TaskGraphRequest {
+	await := AwaitConditionTask{Condition: ConditionHeight, TargetHeight: t.UpgradeHeight - 1}.ToTaskRequest()
+	apply := t.ToTaskRequest()
+	return TaskGraphRequest{Nodes: []TaskGraphNode{
+		{Name: "await-halt-height", Type: await.Type, Params: await.Params},
+		{Name: "apply-upgrade", Type: apply.Type, Params: apply.Params, DependsOn: &[]string{"await-halt-height"}},
+	}}
+}
+
 // SetGenesisPeersTask requests the sidecar to publish this node's peer
 // entry to the shared genesis peers list (S3 coordinates derived from
 // the sidecar environment).
@@ -354,8 +427,9 @@ func (t ConfigValidateTask) ToTaskRequest() TaskRequest {
 	return req
 }
 
-// ConfigReloadTask patches hot-reloadable fields on disk and signals seid
-// to re-read its configuration.
+// ConfigReloadTask patches hot-reloadable fields on disk and, when seid
+// serves its live config, signals it to re-read its configuration;
+// otherwise the result reports that a restart is required.
 type ConfigReloadTask struct {
 	Fields map[string]string
 }
@@ -635,3 +635,36 @@ func resultExportTaskFromParams(params map[string]interface{}) ResultExportTask
 		CanonicalRPC: s("canonicalRpc"),
 	}
 }
+
+func TestApplyUpgradeTaskWire(t *testing.T) {
+	task := ApplyUpgradeTask{UpgradeName: "v6.0.0", UpgradeHeight: 1000}
+	if err := task.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	req := task.ToTaskRequest()
+	if req.Type != TaskTypeApplyUpgrade || req.Params == nil {
+		t.Fatalf("request = %+v, want apply-upgrade with params", req)
+	}
+	if (*req.Params)["upgradeName"] != "v6.0.0" || (*req.Params)["upgradeHeight"] != int64(1000) {
+		t.Errorf("params = %v", *req.Params)
+	}
+	if err := (ApplyUpgradeTask{UpgradeName: "v6.0.0", UpgradeHeight: 1}).Validate(); err == nil {
+		t.Error("Validate() accepted an upgrade height of 1")
+	}
+}
+
+// The graph chains the halt-height wait ahead of the switch, so only the
+// switch and restart hold the lifecycle group.
+func TestApplyUpgradeTaskGraph(t *testing.T) {
+	g := ApplyUpgradeTask{UpgradeName: "v6.0.0", UpgradeHeight: 1000}.Graph()
+	if len(g.Nodes) != 2 {
+		t.Fatalf("nodes = %+v, want await then apply", g.Nodes)
+	}
+	await, apply := g.Nodes[0], g.Nodes[1]
+	if await.Type != TaskTypeAwaitCondition || (*await.Params)["targetHeight"] != int64(999) {
+		t.Errorf("first node = %+v, want await-condition on the halt height 999", await)
+	}
+	if apply.Type != TaskTypeApplyUpgrade || apply.DependsOn == nil || len(*apply.DependsOn) != 1 || (*apply.DependsOn)[0] != await.Name {
+		t.Errorf("second node = %+v, want apply-upgrade depending on %s", apply, await.Name)
+	}
+}
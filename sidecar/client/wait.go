@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultPollInterval is how often a wait polls a task when the sidecar's
+// event stream is unavailable and WaitOptions.PollInterval is unset.
+const defaultPollInterval = 2 * time.Second
+
+// maxUnavailableRetries bounds how many times GetTask and DeleteTask send a
+// request the sidecar answers with a 503 carrying Retry-After.
+const maxUnavailableRetries = 3
+
+// WaitOptions tunes how WaitForTask and SubmitAndWait follow a task.
+type WaitOptions struct {
+	// PollInterval is the first delay between GETs when the event stream
+	// is unavailable; zero means 2s. Each poll doubles it up to
+	// MaxPollInterval, which, when not above PollInterval, keeps the
+	// interval fixed.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+
+	// Timeout bounds the whole wait, including the submit for
+	// SubmitAndWait; zero leaves it to ctx. On expiry the wait returns an
+	// error wrapping context.DeadlineExceeded. The task itself may still
+	// be running.
+	Timeout time.Duration
+
+	// OnEvent, when set, is called for each event the stream delivers.
+	OnEvent func(TaskEvent)
+
+	// OnRetry, when set, is called with each transient failure the wait
+	// rides out: a dropped event stream, a failed GET, a submission the
+	// draining sidecar refused.
+	OnRetry func(error)
+}
+
+func (o WaitOptions) pollInterval() time.Duration {
+	if o.PollInterval > 0 {
+		return o.PollInterval
+	}
+	return defaultPollInterval
+}
+
+func (o WaitOptions) maxPollInterval() time.Duration {
+	return max(o.MaxPollInterval, o.pollInterval())
+}
+
+func (o WaitOptions) retried(err error) {
+	if o.OnRetry != nil {
+		o.OnRetry(err)
+	}
+}
+
+// TaskFailedError is returned by WaitForTask and SubmitAndWait when the task
+// settles without completing: it failed, was cancelled, or was skipped by
+// its task graph. Task is the settled record, carrying the error or cancel
+// reason and any result the handler persisted.
+type TaskFailedError struct {
+	Task *TaskResult
+}
+
+func (e *TaskFailedError) Error() string {
+	msg := fmt.Sprintf("sidecar: %s task %s %s", e.Task.Type, e.Task.Id, e.Task.Status)
+	switch {
+	case e.Task.Error != nil && *e.Task.Error != "":
+		msg += ": " + *e.Task.Error
+	case e.Task.CancelReason != nil && *e.Task.CancelReason != "":
+		msg += ": " + *e.Task.CancelReason
+	}
+	return msg
+}
+
+// SubmitAndWait submits task and waits for it as WaitForTask does. A task
+// without an ID is given a fresh one, so a submission the draining sidecar
+// refuses is resubmitted, after the Retry-After it advises, as the same
+// task until the sidecar accepts it or the wait's deadline passes.
+func SubmitAndWait[R any](ctx context.Context, c *SidecarClient, task TaskRequest, opts WaitOptions) (R, *TaskResult, error) {
+	var zero R
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+		opts.Timeout = 0
+	}
+	if task.Id == nil {
+		id := uuid.New()
+		task.Id = &id
+	}
+	for {
+		id, err := c.SubmitTask(ctx, task)
+		if err == nil {
+			return WaitForTask[R](ctx, c, id, opts)
+		}
+		var unavailable *unavailableError
+		if !errors.As(err, &unavailable) || ctx.Err() != nil {
+			return zero, nil, err
+		}
+		opts.retried(err)
+		delay := opts.pollInterval()
+		if unavailable.advised {
+			delay = unavailable.after
+		}
+		if err := sleepCtx(ctx, delay); err != nil {
+			return zero, nil, fmt.Errorf("submitting %s task %s: %w", task.Type, *task.Id, err)
+		}
+	}
+}
+
+// WaitForTask follows task id to a terminal status and decodes its result
+// into R. It follows the sidecar's event stream, falling back to polling
+// with backoff when the sidecar has none or the stream cannot be held; a
+// GET that fails transiently is retried on the next poll. A task that
+// fails, is cancelled, or is skipped yields a *TaskFailedError; one that
+// does not exist, or is deleted while waited on, an error wrapping
+// ErrNotFound. The settled record is returned whenever the task settled.
+func WaitForTask[R any](ctx context.Context, c *SidecarClient, id uuid.UUID, opts WaitOptions) (R, *TaskResult, error) {
+	var zero R
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	tr, err := c.awaitSettled(ctx, id, opts)
+	if err != nil {
+		return zero, nil, err
+	}
+	if tr.Status != Completed {
+		return zero, tr, &TaskFailedError{Task: tr}
+	}
+	if tr.Result == nil || string(*tr.Result) == "null" {
+		return zero, tr, nil
+	}
+	var r R
+	if err := json.Unmarshal(*tr.Result, &r); err != nil {
+		return zero, tr, fmt.Errorf("decoding result of %s task %s: %w", tr.Type, id, err)
+	}
+	return r, tr, nil
+}
+
+// awaitSettled follows task id over the event stream, falling back to
+// pollSettled.
+func (c *SidecarClient) awaitSettled(ctx context.Context, id uuid.UUID, opts WaitOptions) (*TaskResult, error) {
+	tr, err := c.Watch(ctx, id, opts.OnEvent)
+	switch {
+	case err == nil:
+		return tr, nil
+	case errors.Is(err, ErrNotFound):
+		return nil, fmt.Errorf("awaiting task %s: %w", id, ErrNotFound)
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case !errors.Is(err, ErrEventsUnsupported):
+		opts.retried(fmt.Errorf("watching task %s: %w; polling instead", id, err))
+	}
+	return c.pollSettled(ctx, id, opts)
+}
+
+// pollSettled GETs task id until it settles. Only a 404, which means the
+// task is gone and will never settle, or ctx ending stops it early.
+func (c *SidecarClient) pollSettled(ctx context.Context, id uuid.UUID, opts WaitOptions) (*TaskResult, error) {
+	interval := opts.pollInterval()
+	for {
+		tr, err := c.GetTask(ctx, id)
+		switch {
+		case err == nil && settled(tr.Status):
+			return tr, nil
+		case errors.Is(err, ErrNotFound):
+			return nil, fmt.Errorf("awaiting task %s: %w", id, ErrNotFound)
+		case err != nil && ctx.Err() == nil:
+			opts.retried(fmt.Errorf("polling task %s: %w", id, err))
+		}
+		if err := sleepCtx(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval = min(interval*2, opts.maxPollInterval())
+	}
+}
+
+// unavailableError is a 503 from the sidecar: a transient store failure on
+// GET or DELETE, or a submission refused while the sidecar drains. advised
+// is set when the response carried Retry-After, after being its delay.
+type unavailableError struct {
+	err     error
+	after   time.Duration
+	advised bool
+}
+
+func (e *unavailableError) Error() string { return e.err.Error() }
+func (e *unavailableError) Unwrap() error { return e.err }
+
+// unavailable wraps err, the error for a 503 response, with the response's
+// Retry-After.
+func unavailable(resp *http.Response, err error) error {
+	ue := &unavailableError{err: err}
+	if resp != nil {
+		if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && secs >= 0 {
+			ue.after, ue.advised = time.Duration(secs)*time.Second, true
+		}
+	}
+	return ue
+}
+
+// retryUnavailable calls fn until it returns something other than a 503
+// carrying Retry-After, waiting the advised delay between attempts, for at
+// most maxUnavailableRetries attempts.
+func retryUnavailable[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		v, err := fn()
+		var ue *unavailableError
+		if !errors.As(err, &ue) || !ue.advised || attempt >= maxUnavailableRetries {
+			return v, err
+		}
+		if err := sleepCtx(ctx, ue.after); err != nil {
+			return v, err
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type waitTestResult struct {
+	Height int64  `json:"height"`
+	Key    string `json:"key"`
+}
+
+// waitTestSidecar serves submit and GET for one task with no event stream.
+// The first refuse submits are turned away as by a draining sidecar; GETs
+// report running until the pending count runs out, then terminal.
+type waitTestSidecar struct {
+	refuse    int32
+	pending   int32
+	terminal  TaskResult
+	submitted atomic.Value
+	submits   atomic.Int32
+	gets      atomic.Int32
+}
+
+func (f *waitTestSidecar) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v0/tasks", func(w http.ResponseWriter, r *http.Request) {
+		var req TaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Id == nil {
+			t.Errorf("submit body = %+v, %v; want a task ID", req, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if prev, ok := f.submitted.Load().(uuid.UUID); ok && prev != *req.Id {
+			t.Errorf("resubmitted as %s, want the first ID %s", *req.Id, prev)
+		}
+		f.submitted.Store(*req.Id)
+		w.Header().Set("Content-Type", "application/json")
+		if f.submits.Add(1) <= f.refuse {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "engine is draining; not accepting new tasks"})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(TaskSubmitResponse{Id: *req.Id})
+	})
+	mux.HandleFunc("GET /v0/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		res := f.terminal
+		res.Id = uuid.MustParse(r.PathValue("id"))
+		if f.gets.Add(1) <= f.pending {
+			res.Status, res.Result = Running, nil
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	return mux
+}
+
+func TestSubmitAndWait_DecodesResult(t *testing.T) {
+	raw := json.RawMessage(`{"height":42,"key":"snap/42.tar"}`)
+	fake := &waitTestSidecar{refuse: 2, pending: 2, terminal: TaskResult{Type: TaskTypeSnapshotUploadOnce, Status: Completed, Result: &raw}}
+	c := newTestClient(t, fake.handler(t))
+
+	var retries int
+	got, tr, err := SubmitAndWait[waitTestResult](context.Background(), c, TaskRequest{Type: TaskTypeSnapshotUploadOnce},
+		WaitOptions{PollInterval: time.Millisecond, OnRetry: func(error) { retries++ }})
+	if err != nil {
+		t.Fatalf("SubmitAndWait() error = %v", err)
+	}
+	if got.Height != 42 || got.Key != "snap/42.tar" {
+		t.Errorf("result = %+v", got)
+	}
+	if tr == nil || tr.Status != Completed {
+		t.Errorf("task = %+v, want the completed record", tr)
+	}
+	if fake.submits.Load() != 3 || retries != 2 {
+		t.Errorf("submits = %d, retries = %d; want the two draining refusals retried", fake.submits.Load(), retries)
+	}
+}
+
+func TestWaitForTask_FailedCarriesRecord(t *testing.T) {
+	msg := "s3 put failed"
+	raw := json.RawMessage(`{"height":7}`)
+	fake := &waitTestSidecar{terminal: TaskResult{Type: TaskTypeSnapshotUploadOnce, Status: Failed, Error: &msg, Result: &raw}}
+	c := newTestClient(t, fake.handler(t))
+
+	_, tr, err := WaitForTask[waitTestResult](context.Background(), c, uuid.New(), WaitOptions{PollInterval: time.Millisecond})
+	var failed *TaskFailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("err = %v, want *TaskFailedError", err)
+	}
+	if failed.Task != tr || failed.Task.Error == nil || *failed.Task.Error != msg || failed.Task.Result == nil {
+		t.Errorf("failed task = %+v, want the persisted record", failed.Task)
+	}
+}
+
+func TestWaitForTask_TimeoutAndNotFound(t *testing.T) {
+	fake := &waitTestSidecar{pending: 1 << 30}
+	c := newTestClient(t, fake.handler(t))
+	_, _, err := WaitForTask[waitTestResult](context.Background(), c, uuid.New(), WaitOptions{PollInterval: time.Millisecond, Timeout: 30 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the deadline exceeded", err)
+	}
+
+	c = newTestClient(t, http.NotFoundHandler())
+	if _, _, err := WaitForTask[waitTestResult](context.Background(), c, uuid.New(), WaitOptions{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetAndDeleteTask_RetryAdvisedUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%2 == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(TaskResult{Status: Running})
+	}))
+
+	if _, err := c.GetTask(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("GetTask() error = %v, want the 503 retried", err)
+	}
+	if err := c.DeleteTask(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("DeleteTask() error = %v, want the 503 retried", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("calls = %d, want 4", got)
+	}
+
+	// A 503 without Retry-After, e.g. from a proxy, is returned as is.
+	c = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	if _, err := c.GetTask(context.Background(), uuid.New()); err == nil {
+		t.Fatal("GetTask() succeeded on a bare 503")
+	}
+}
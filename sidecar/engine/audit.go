@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"time"
+)
+
+// Caller is the authenticated identity behind an API request, as asserted
+// by a trusted front proxy. The zero Caller is an anonymous request.
+type Caller struct {
+	User   string              `json:"user,omitempty"`
+	Groups []string            `json:"groups,omitempty"`
+	Extra  map[string][]string `json:"extra,omitempty"`
+}
+
+// AuditEntry is one record of the append-only audit log: a mutating API
+// request, who made it, and how it was answered. Seq is assigned by the
+// store, starts at 1, and increases strictly. TaskID names the task,
+// graph or schedule acted on, once known. ParamsDigest is the
+// "sha256:<hex>" digest of the submitted params, so the log records what
+// was asked for without holding the params themselves.
+type AuditEntry struct {
+	Seq          int64     `json:"seq"`
+	At           time.Time `json:"at"`
+	RequestID    string    `json:"requestId,omitempty"`
+	RemoteAddr   string    `json:"remoteAddr,omitempty"`
+	Caller       Caller    `json:"caller"`
+	Action       string    `json:"action"`
+	TaskID       string    `json:"taskId,omitempty"`
+	TaskType     string    `json:"taskType,omitempty"`
+	ParamsDigest string    `json:"paramsDigest,omitempty"`
+	Status       int       `json:"status"`
+}
+
+// RecordAudit appends a to the audit log, stamping its time and assigning
+// its Seq.
+func (e *Engine) RecordAudit(a *AuditEntry) error {
+	a.At = time.Now().UTC()
+	return e.store.AppendAudit(a)
+}
+
+// ListAudit returns audit entries with Seq greater than after, oldest
+// first. limit defaults to DefaultListLimit and is capped at MaxListLimit.
+func (e *Engine) ListAudit(after int64, limit int) ([]AuditEntry, error) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+	entries, err := e.store.ListAudit(after, limit)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []AuditEntry{}
+	}
+	return entries, nil
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestRecordAndListAudit(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	if got, err := eng.ListAudit(0, 0); err != nil || got == nil || len(got) != 0 {
+		t.Fatalf("empty ListAudit = %#v, %v; want an empty slice", got, err)
+	}
+	for range MaxListLimit + 5 {
+		if err := eng.RecordAudit(&AuditEntry{Caller: Caller{User: "alice"}, Action: "submit", Status: 202}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := eng.ListAudit(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != DefaultListLimit || got[0].Seq != 1 || got[0].At.IsZero() || got[0].Caller.User != "alice" {
+		t.Fatalf("default page = %d entries starting %+v", len(got), got[0])
+	}
+	if got, _ := eng.ListAudit(0, 5000); len(got) != MaxListLimit {
+		t.Fatalf("capped page = %d entries, want %d", len(got), MaxListLimit)
+	}
+	if got, _ := eng.ListAudit(MaxListLimit, 10); len(got) != 5 || got[0].Seq != MaxListLimit+1 {
+		t.Fatalf("page after %d = %+v", MaxListLimit, got)
+	}
+}
+
+func TestSQLiteAuditLogIsAppendOnly(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.AppendAudit(&AuditEntry{Action: "submit", Status: 202}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`UPDATE audit_log SET status = 500`); err == nil {
+		t.Error("UPDATE audit_log succeeded, want it refused")
+	}
+	if _, err := s.db.Exec(`DELETE FROM audit_log`); err == nil {
+		t.Error("DELETE FROM audit_log succeeded, want it refused")
+	}
+	if got, _ := s.ListAudit(0, 10); len(got) != 1 || got[0].Status != 202 {
+		t.Fatalf("audit after refused writes = %+v", got)
+	}
+}
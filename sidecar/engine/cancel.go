@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTaskFinished is returned by CancelTask when the task already reached a
+// terminal state other than cancelled, so there is nothing left to stop.
+var ErrTaskFinished = errors.New("task already finished")
+
+// cancellation is an explicit CancelTask request: who asked, why, and when.
+type cancellation struct {
+	by     string
+	reason string
+	at     time.Time
+}
+
+// CancelTask stops a pending or running task and records it as terminal
+// TaskStatusCancelled, keeping the row — unlike RemoveResult, which erases
+// it. by is the caller identity and reason the caller's explanation; both
+// are persisted on the row.
+//
+// A Pending task is dropped from the wait queue and persisted Cancelled
+// immediately. A running task's context is cancelled and its run persists
+// the Cancelled row once the handler returns; CancelTask waits for that
+// until ctx ends and then returns the row as it stands, which is still
+// running if the handler has not yet honoured the cancellation. A handler
+// that completes successfully despite the cancellation is recorded
+// Completed, so a finished non-idempotent task is never misreported.
+//
+// It returns (nil, nil) when the task does not exist, the row unchanged when
+// it was already cancelled, and the row with ErrTaskFinished when it had
+// already completed, failed, or been skipped.
+func (e *Engine) CancelTask(ctx context.Context, id, by, reason string) (*TaskResult, error) {
+	c := &cancellation{by: by, reason: reason, at: time.Now().UTC()}
+
+	e.mu.Lock()
+	tr, err := e.store.Get(id)
+	if err != nil {
+		e.mu.Unlock()
+		return nil, fmt.Errorf("read task: %w", err)
+	}
+	if tr == nil {
+		e.mu.Unlock()
+		return nil, nil
+	}
+	if err := cancellable(tr); err != nil || tr.Status == TaskStatusCancelled {
+		e.mu.Unlock()
+		return tr, err
+	}
+
+	if entry, ok := e.cancels[id]; ok {
+		if entry.requested == nil {
+			entry.requested = c
+			e.cancels[id] = entry
+		}
+		entry.cancel()
+		e.mu.Unlock()
+		log.Info("task cancellation requested", "type", tr.Type, "id", id, "by", by, "reason", reason)
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+		}
+		tr, err := e.store.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("read task: %w", err)
+		}
+		if tr == nil {
+			return nil, nil
+		}
+		return tr, cancellable(tr)
+	}
+
+	// No live run: the task is queued, or is a row a previous process left
+	// behind that rehydration has not yet picked up. Either way nothing is
+	// executing, so the row can be settled here.
+	if e.dequeue(id) {
+		e.dispatchWaiters()
+	}
+	e.mu.Unlock()
+	if err := e.persistCancelled(tr, c); err != nil {
+		return nil, fmt.Errorf("persist cancellation: %w", err)
+	}
+	return tr, nil
+}
+
+// cancellable reports whether tr can still be cancelled: nil for pending,
+// running, and already-cancelled tasks, ErrTaskFinished otherwise.
+func cancellable(tr *TaskResult) error {
+	switch tr.Status {
+	case TaskStatusPending, TaskStatusRunning, TaskStatusCancelled:
+		return nil
+	default:
+		return fmt.Errorf("%w: task %s is %s", ErrTaskFinished, tr.ID, tr.Status)
+	}
+}
+
+// cancellationOf returns the CancelTask request recorded against the run
+// registered under (id, gen), or nil when the run was not explicitly
+// cancelled.
+func (e *Engine) cancellationOf(id string, gen int64) *cancellation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if entry, ok := e.cancels[id]; ok && entry.gen == gen {
+		return entry.requested
+	}
+	return nil
+}
+
+// persistCancelled stamps tr as Cancelled per c, persists it, and lets a
+// graph the task belongs to advance past it.
+func (e *Engine) persistCancelled(tr *TaskResult, c *cancellation) error {
+	at := c.at
+	tr.Status = TaskStatusCancelled
+	tr.CancelledBy = c.by
+	tr.CancelReason = c.reason
+	tr.CompletedAt = &at
+	tr.NextAttemptAt = nil
+	tr.Error = ""
+	if err := e.store.Save(tr); err != nil {
+		return err
+	}
+	taskCancellations.WithLabelValues(tr.Type).Inc()
+	log.Info("task cancelled", "type", tr.Type, "id", tr.ID, "by", c.by, "reason", c.reason)
+	e.publishSettled(tr)
+	e.graphTaskSettled(tr.ID)
+	return nil
+}
@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCancelRunningTaskKeepsRecord(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{})
+	before := testutil.ToFloat64(taskCancellations.WithLabelValues(string(TaskResultExport)))
+
+	id, err := eng.Submit(Task{Type: TaskResultExport, Params: step("run")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "run")
+
+	r, err := eng.CancelTask(context.Background(), id, "alice", "wrong height")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != TaskStatusCancelled || r.CancelledBy != "alice" || r.CancelReason != "wrong height" || r.CompletedAt == nil {
+		t.Fatalf("result = %+v, want cancelled by alice", r)
+	}
+	if got := eng.GetResult(id); got == nil || got.Status != TaskStatusCancelled {
+		t.Fatalf("stored result = %+v, want cancelled", got)
+	}
+	if n := cancelRegistrySize(eng); n != 0 {
+		t.Fatalf("cancel registry has %d entries after cancel, want 0", n)
+	}
+	if got := testutil.ToFloat64(taskCancellations.WithLabelValues(string(TaskResultExport))); got != before+1 {
+		t.Fatalf("cancellations = %v, want %v", got, before+1)
+	}
+}
+
+func TestCancelPendingTaskLeavesQueue(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{MaxWorkers: 1})
+
+	first, err := eng.Submit(Task{Type: TaskResultExport, Params: step("first")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "first")
+	queued, err := eng.Submit(Task{Type: TaskEvmLogicalDigest, Params: step("queued")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := eng.CancelTask(context.Background(), queued, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != TaskStatusCancelled {
+		t.Fatalf("status = %q, want cancelled", r.Status)
+	}
+	if got := testutil.ToFloat64(taskQueueDepth.WithLabelValues(string(TaskEvmLogicalDigest))); got != 0 {
+		t.Fatalf("queue depth = %v, want 0", got)
+	}
+
+	rec.open()
+	waitForResult(t, eng, first)
+	for _, s := range rec.ran() {
+		if s == "queued" {
+			t.Fatal("cancelled pending task ran")
+		}
+	}
+}
+
+func TestCancelFinishedOrMissingTask(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{})
+	rec.open()
+
+	id, err := eng.Submit(Task{Type: TaskResultExport, Params: step("done")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForResult(t, eng, id)
+
+	r, err := eng.CancelTask(context.Background(), id, "", "")
+	if !errors.Is(err, ErrTaskFinished) {
+		t.Fatalf("err = %v, want ErrTaskFinished", err)
+	}
+	if r == nil || r.Status != TaskStatusCompleted {
+		t.Fatalf("result = %+v, want the completed record", r)
+	}
+
+	if r, err := eng.CancelTask(context.Background(), "00000000-0000-0000-0000-000000000000", "", ""); r != nil || err != nil {
+		t.Fatalf("CancelTask(missing) = %+v, %v; want nil, nil", r, err)
+	}
+}
+
+func TestCancelIsIdempotentAndResubmitReruns(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{})
+
+	id, err := eng.Submit(Task{Type: TaskResultExport, Params: step("first")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "first")
+	if _, err := eng.CancelTask(context.Background(), id, "alice", "first"); err != nil {
+		t.Fatal(err)
+	}
+	r, err := eng.CancelTask(context.Background(), id, "bob", "second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.CancelledBy != "alice" || r.CancelReason != "first" {
+		t.Fatalf("second cancel rewrote the record: %+v", r)
+	}
+
+	rec.open()
+	if _, err := eng.Submit(Task{ID: id, Type: TaskResultExport, Params: step("again")}); err != nil {
+		t.Fatal(err)
+	}
+	r = waitForResult(t, eng, id)
+	if r.Status != TaskStatusCompleted || r.Run != 2 || r.CancelledBy != "" {
+		t.Fatalf("resubmitted result = %+v, want completed run 2", r)
+	}
+}
+
+// A handler that does not honour cancellation within the caller's wait leaves
+// the task running; its run still records the cancellation once it returns.
+func TestCancelWaitsOnlyUntilCallerDeadline(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskResultExport: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			close(started)
+			<-release
+			return nil, ctx.Err()
+		},
+	})
+
+	id, err := eng.Submit(Task{Type: TaskResultExport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r, err := eng.CancelTask(ctx, id, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != TaskStatusRunning {
+		t.Fatalf("status = %q, want running while the handler ignores cancellation", r.Status)
+	}
+
+	close(release)
+	if r := waitForResult(t, eng, id); r.Status != TaskStatusCancelled || r.CancelledBy != "alice" {
+		t.Fatalf("result = %+v, want cancelled by alice", r)
+	}
+}
+
+func TestCancelledGraphNodeSkipsDependents(t *testing.T) {
+	rec := newGatedRecorder()
+	t.Cleanup(rec.open)
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: rec.handler})
+
+	gid, err := eng.SubmitGraph(TaskGraph{Nodes: []GraphNode{
+		{Name: "a", Type: TaskConfigPatch, Params: step("a")},
+		{Name: "b", Type: TaskConfigPatch, Params: step("b"), DependsOn: []string{"a"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "a")
+
+	var aID string
+	for _, n := range eng.GetGraph(gid).Nodes {
+		if n.Name == "a" {
+			aID = n.TaskID
+		}
+	}
+	if _, err := eng.CancelTask(context.Background(), aID, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	gs := waitForGraph(t, eng, gid)
+	if gs.Phase != GraphPhaseFailed {
+		t.Fatalf("phase = %q, want failed", gs.Phase)
+	}
+	if got := nodeStatus(gs, "a"); got != TaskStatusCancelled {
+		t.Fatalf("node a = %q, want cancelled", got)
+	}
+	if got := nodeStatus(gs, "b"); got != TaskStatusSkipped {
+		t.Fatalf("node b = %q, want skipped", got)
+	}
+}
@@ -5,12 +5,12 @@ package engine
 // TaskResult.Result. It carries the signed tx bytes so a re-run re-broadcasts
 // the identical tx rather than re-signing (which risks a double submit).
 type TxMarker struct {
-	TaskID        string
-	TxHash        string
-	TxBytes       []byte
-	AccountNumber uint64
-	Sequence      uint64
-	ChainID       string
+	TaskID        string `json:"taskId"`
+	TxHash        string `json:"txHash"`
+	TxBytes       []byte `json:"txBytes"`
+	AccountNumber uint64 `json:"accountNumber"`
+	Sequence      uint64 `json:"sequence"`
+	ChainID       string `json:"chainId"`
 }
 
 // Checkpointer persists a TxMarker durably before broadcast and retrieves it on
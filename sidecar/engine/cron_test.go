@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronRejectsMalformed(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@fortnightly",
+	} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	for _, tc := range []struct {
+		expr, from, want string
+	}{
+		{"*/15 * * * *", "2026-03-01T10:07:30Z", "2026-03-01T10:15:00Z"},
+		{"*/15 * * * *", "2026-03-01T10:45:00Z", "2026-03-01T11:00:00Z"},
+		{"0 3 * * *", "2026-03-01T03:00:00Z", "2026-03-02T03:00:00Z"},
+		{"@hourly", "2026-03-01T10:59:59Z", "2026-03-01T11:00:00Z"},
+		{"30 2 1 * *", "2026-01-31T12:00:00Z", "2026-02-01T02:30:00Z"},
+		// Sunday as 7; 2026-03-01 is a Sunday.
+		{"0 0 * * 7", "2026-02-26T00:00:00Z", "2026-03-01T00:00:00Z"},
+		// Both day fields restricted: the 15th OR any Monday.
+		{"0 0 15 * 1", "2026-03-03T00:00:00Z", "2026-03-09T00:00:00Z"},
+		{"0 0 15 * 1", "2026-03-13T00:00:00Z", "2026-03-15T00:00:00Z"},
+		{"0 12 29 2 *", "2026-03-01T00:00:00Z", "2028-02-29T12:00:00Z"},
+		{"1-3,50 9-10 * 6 *", "2026-06-30T10:49:00Z", "2026-06-30T10:50:00Z"},
+	} {
+		spec, err := parseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tc.expr, err)
+		}
+		if got := spec.next(at(tc.from)); !got.Equal(at(tc.want)) {
+			t.Errorf("%q after %s = %s, want %s", tc.expr, tc.from, got.Format(time.RFC3339), tc.want)
+		}
+	}
+}
+
+func TestCronNextNeverMatches(t *testing.T) {
+	spec, err := parseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.next(time.Now()); !got.IsZero() {
+		t.Fatalf("next = %s, want zero for February 30th", got)
+	}
+}
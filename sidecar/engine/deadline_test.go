@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// blockUntilDone is a handler that runs until its context ends.
+func blockUntilDone(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestSubmitTimeoutFailsOverrunningTask(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: blockUntilDone})
+
+	id, err := eng.Submit(Task{Type: TaskConfigPatch, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusFailed {
+		t.Fatalf("status = %q, want failed", r.Status)
+	}
+	if r.Deadline == nil {
+		t.Fatal("Deadline not recorded on a task submitted with a timeout")
+	}
+	if !strings.Contains(r.Error, "deadline") || !strings.Contains(r.Error, "context deadline exceeded") {
+		t.Fatalf("error = %q, want a deadline TaskError", r.Error)
+	}
+}
+
+func TestTimeoutsTableAppliesDefault(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: blockUntilDone})
+	eng.Timeouts = map[TaskType]time.Duration{TaskConfigPatch: 50 * time.Millisecond}
+
+	before := time.Now()
+	id, err := eng.Submit(Task{Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusFailed {
+		t.Fatalf("status = %q, want failed", r.Status)
+	}
+	if r.Deadline == nil || r.Deadline.Before(before.Add(50*time.Millisecond)) {
+		t.Fatalf("Deadline = %v, want about submission + 50ms", r.Deadline)
+	}
+}
+
+func TestSubmitTimeoutOverridesDefault(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+				return nil, nil
+			}
+		},
+	})
+	eng.Timeouts = map[TaskType]time.Duration{TaskConfigPatch: 10 * time.Millisecond}
+
+	id, err := eng.Submit(Task{Type: TaskConfigPatch, Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := waitForResult(t, eng, id); r.Status != TaskStatusCompleted {
+		t.Fatalf("status = %q (err %q), want completed under the caller's longer timeout", r.Status, r.Error)
+	}
+}
+
+func TestNoTimeoutRunsUnbounded(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			if _, ok := ctx.Deadline(); ok {
+				return nil, errors.New("unexpected deadline on an unbounded task")
+			}
+			return nil, nil
+		},
+	})
+
+	id, err := eng.Submit(Task{Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusCompleted {
+		t.Fatalf("status = %q (err %q), want completed", r.Status, r.Error)
+	}
+	if r.Deadline != nil {
+		t.Fatalf("Deadline = %v, want nil", r.Deadline)
+	}
+}
+
+func TestSubmitRejectsNegativeTimeout(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: blockUntilDone})
+	if _, err := eng.Submit(Task{Type: TaskConfigPatch, Timeout: -time.Second}); !errors.Is(err, ErrInvalidTimeout) {
+		t.Fatalf("err = %v, want ErrInvalidTimeout", err)
+	}
+}
+
+func TestDeadlineFailureIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(ctx context.Context, p map[string]any) (json.RawMessage, error) {
+			calls.Add(1)
+			return blockUntilDone(ctx, p)
+		},
+	})
+	eng.RetryPolicies = map[TaskType]RetryPolicy{TaskConfigPatch: fastRetry}
+
+	id, err := eng.Submit(Task{Type: TaskConfigPatch, Timeout: 30 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusFailed {
+		t.Fatalf("status = %q, want failed", r.Status)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times, want 1 (a deadline failure is final)", n)
+	}
+}
+
+func TestRetryNotScheduledPastDeadline(t *testing.T) {
+	var calls atomic.Int32
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			calls.Add(1)
+			return nil, errors.New("transient")
+		},
+	})
+	eng.RetryPolicies = map[TaskType]RetryPolicy{
+		TaskConfigPatch: {MaxAttempts: 5, InitialBackoff: time.Hour},
+	}
+
+	id, err := eng.Submit(Task{Type: TaskConfigPatch, Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusFailed || r.Error != "transient" {
+		t.Fatalf("status = %q, error = %q; want failed with the handler's error", r.Status, r.Error)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times, want 1 (retry would start past the deadline)", n)
+	}
+}
+
+func TestRehydrateKeepsPersistedDeadline(t *testing.T) {
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	id := uuid.New().String()
+	expired := time.Now().Add(-time.Minute).UTC()
+	if err := store.Save(&TaskResult{
+		ID: id, Type: string(TaskConfigPatch), Status: TaskStatusRunning,
+		Run: 1, Attempt: 1, SubmittedAt: time.Now().Add(-time.Hour).UTC(), Deadline: &expired,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	eng := engineOver(t, store, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			calls.Add(1)
+			return nil, nil
+		},
+	})
+	eng.Timeouts = map[TaskType]time.Duration{TaskConfigPatch: time.Hour}
+	eng.RehydrateStaleTasks()
+
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusFailed {
+		t.Fatalf("status = %q, want failed (rehydration must not grant a fresh budget)", r.Status)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("handler ran %d times, want 0 for an already-expired deadline", n)
+	}
+	if !strings.Contains(r.Error, ": deadline: ") {
+		t.Fatalf("error = %q, want a deadline TaskError", r.Error)
+	}
+}
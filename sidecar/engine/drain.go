@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrDraining is returned by Submit and SubmitGraph while the engine
+// drains, for any task type not in DrainExempt.
+var ErrDraining = errors.New("engine is draining; not accepting new tasks")
+
+// DrainStatus reports a drain in progress. Running counts the tasks still
+// in flight; Drained is set once none is. Deadline is when whoever started
+// the drain stops waiting on them, if it gave one.
+type DrainStatus struct {
+	Since    time.Time  `json:"since"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Running  int        `json:"running"`
+	Drained  bool       `json:"drained"`
+}
+
+// drainState is a drain in progress. done is closed once no task is
+// running.
+type drainState struct {
+	since    time.Time
+	deadline time.Time
+	done     chan struct{}
+}
+
+// Drain stops the engine taking new work while letting in-flight tasks
+// finish: Submit and SubmitGraph refuse every type not in DrainExempt
+// with ErrDraining, queued tasks stay Pending, task graphs start no
+// further nodes, and scheduled firings are recorded as refused. A
+// non-zero timeout sets the drain's deadline, which WaitDrained honours;
+// draining an engine already draining keeps its start and replaces the
+// deadline. Drain returns at once with the drain's status.
+func (e *Engine) Drain(timeout time.Duration) DrainStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now().UTC()
+	if e.drain == nil {
+		e.drain = &drainState{since: now, done: make(chan struct{})}
+		log.Info("engine draining", "running", len(e.cancels), "queued", len(e.waiters), "timeout", timeout)
+	}
+	e.drain.deadline = time.Time{}
+	if timeout > 0 {
+		e.drain.deadline = now.Add(timeout)
+	}
+	e.checkDrained()
+	return e.drainStatus()
+}
+
+// Undrain ends a drain: submissions are accepted again, queued tasks start
+// as workers allow, and task graphs resume. It reports whether the engine
+// was draining.
+func (e *Engine) Undrain() bool {
+	e.mu.Lock()
+	if e.drain == nil {
+		e.mu.Unlock()
+		return false
+	}
+	e.drain = nil
+	log.Info("engine drain ended", "queued", len(e.waiters))
+	e.dispatchWaiters()
+	e.mu.Unlock()
+
+	e.graphMu.Lock()
+	graphs := make(map[string]bool)
+	for _, id := range e.graphOf {
+		graphs[id] = true
+	}
+	e.graphMu.Unlock()
+	for id := range graphs {
+		go e.advanceGraph(id)
+	}
+	return true
+}
+
+// WaitDrained blocks until a drain in progress has no task left running,
+// returning nil, or until the drain's deadline passes or ctx ends,
+// returning an error naming how many tasks are still running. It fails
+// at once when the engine is not draining.
+func (e *Engine) WaitDrained(ctx context.Context) error {
+	e.mu.Lock()
+	d := e.drain
+	e.mu.Unlock()
+	if d == nil {
+		return errors.New("engine is not draining")
+	}
+	if !d.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, d.deadline)
+		defer cancel()
+	}
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		e.mu.Lock()
+		running := len(e.cancels)
+		e.mu.Unlock()
+		return fmt.Errorf("drain: %d tasks still running: %w", running, ctx.Err())
+	}
+}
+
+// Draining returns the status of the drain in progress, or nil when the
+// engine is accepting work.
+func (e *Engine) Draining() *DrainStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.drain == nil {
+		return nil
+	}
+	s := e.drainStatus()
+	return &s
+}
+
+// drainStatus reports the drain in progress. Callers MUST hold e.mu and
+// have checked e.drain is set.
+func (e *Engine) drainStatus() DrainStatus {
+	s := DrainStatus{Since: e.drain.since, Running: len(e.cancels), Drained: len(e.cancels) == 0}
+	if !e.drain.deadline.IsZero() {
+		d := e.drain.deadline
+		s.Deadline = &d
+	}
+	return s
+}
+
+// checkDrained closes the drain's done channel once no task is running.
+// Every path that unregisters a running task calls it. Callers MUST hold
+// e.mu.
+func (e *Engine) checkDrained() {
+	if e.drain == nil || len(e.cancels) > 0 {
+		return
+	}
+	select {
+	case <-e.drain.done:
+	default:
+		log.Info("engine drained", "since", e.drain.since)
+		close(e.drain.done)
+	}
+}
+
+// drainHolds reports whether a drain in progress keeps a task of taskType
+// from starting. Callers MUST hold e.mu.
+func (e *Engine) drainHolds(taskType TaskType) bool {
+	return e.drain != nil && !slices.Contains(e.DrainExempt, taskType)
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDrainRefusesNewWorkAndWaitsForRunning(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{MaxWorkers: 1, Unbounded: []TaskType{TaskAwaitCondition}})
+	eng.DrainExempt = []TaskType{TaskAwaitCondition}
+
+	running, err := eng.Submit(Task{Type: TaskResultExport, Params: step("running")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "running")
+	queued, err := eng.Submit(Task{Type: TaskEvmLogicalDigest, Params: step("queued")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := eng.Drain(time.Hour)
+	if st.Running != 1 || st.Drained || st.Deadline == nil {
+		t.Fatalf("drain status = %+v, want one running task and a deadline", st)
+	}
+	if got := eng.Status().Drain; got == nil || got.Running != 1 {
+		t.Fatalf("status drain = %+v, want the drain reported", got)
+	}
+	if _, err := eng.Submit(Task{Type: TaskResultExport, Params: step("refused")}); !errors.Is(err, ErrDraining) {
+		t.Fatalf("submit while draining = %v, want ErrDraining", err)
+	}
+	if _, err := eng.Submit(Task{ID: running, Type: TaskResultExport, Params: step("running")}); err != nil {
+		t.Fatalf("idempotent resubmit while draining = %v, want its ID", err)
+	}
+	exempt, err := eng.Submit(Task{Type: TaskAwaitCondition, Params: step("exempt")})
+	if err != nil {
+		t.Fatalf("exempt submit while draining = %v", err)
+	}
+	rec.waitForStep(t, "exempt")
+
+	waited := make(chan error, 1)
+	go func() { waited <- eng.WaitDrained(context.Background()) }()
+	select {
+	case err := <-waited:
+		t.Fatalf("WaitDrained returned %v with tasks still running", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	rec.open()
+	if err := <-waited; err != nil {
+		t.Fatalf("WaitDrained = %v", err)
+	}
+	for _, id := range []string{running, exempt} {
+		if r := waitForResult(t, eng, id); r.Status != TaskStatusCompleted {
+			t.Fatalf("task %s status = %q, want completed", id, r.Status)
+		}
+	}
+	if r := eng.GetResult(queued); r.Status != TaskStatusPending {
+		t.Fatalf("queued task status = %q, want it held pending", r.Status)
+	}
+	if st := eng.Draining(); st == nil || !st.Drained || st.Running != 0 {
+		t.Fatalf("drain status = %+v, want drained", st)
+	}
+
+	if !eng.Undrain() || eng.Undrain() {
+		t.Fatal("Undrain should report true once, then false")
+	}
+	if r := waitForResult(t, eng, queued); r.Status != TaskStatusCompleted {
+		t.Fatalf("queued task status after undrain = %q, want completed", r.Status)
+	}
+	if eng.Status().Drain != nil {
+		t.Error("status still reports a drain after undrain")
+	}
+}
+
+func TestWaitDrainedStopsAtDeadline(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{})
+
+	if err := eng.WaitDrained(context.Background()); err == nil {
+		t.Fatal("WaitDrained without a drain should fail")
+	}
+	if _, err := eng.Submit(Task{Type: TaskResultExport, Params: step("stuck")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "stuck")
+
+	eng.Drain(20 * time.Millisecond)
+	err := eng.WaitDrained(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitDrained = %v, want the deadline exceeded", err)
+	}
+}
+
+func TestDrainHoldsTaskGraphs(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{})
+
+	id, err := eng.SubmitGraph(TaskGraph{Nodes: []GraphNode{
+		{Name: "first", Type: TaskResultExport, Params: step("first")},
+		{Name: "second", Type: TaskEvmLogicalDigest, Params: step("second"), DependsOn: []string{"first"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "first")
+
+	eng.Drain(0)
+	if _, err := eng.SubmitGraph(TaskGraph{Nodes: []GraphNode{{Name: "a", Type: TaskResultExport}}}); !errors.Is(err, ErrDraining) {
+		t.Fatalf("graph submit while draining = %v, want ErrDraining", err)
+	}
+	rec.open()
+	if err := eng.WaitDrained(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if g := eng.GetGraph(id); g == nil || g.Phase != GraphPhaseRunning {
+		t.Fatalf("graph = %+v, want it running with its second node held", g)
+	}
+
+	eng.Undrain()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if g := eng.GetGraph(id); g != nil && g.Phase == GraphPhaseCompleted {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("graph = %+v, want completed after undrain", eng.GetGraph(id))
+}
@@ -19,6 +19,9 @@ var log = seilog.NewLogger("seictl", "engine")
 // ErrInvalidTaskID is returned when a caller-provided task ID is not a valid UUID.
 var ErrInvalidTaskID = fmt.Errorf("task ID must be a valid UUID")
 
+// ErrInvalidTimeout is returned when a submission carries a negative timeout.
+var ErrInvalidTimeout = fmt.Errorf("task timeout must not be negative")
+
 // validateTaskID checks that a non-empty ID string is a valid UUID.
 func validateTaskID(id string) error {
 	if id == "" {
@@ -35,18 +38,19 @@ func validateTaskID(id string) error {
 // The engine context propagates to all handlers — on SIGTERM the
 // context is cancelled and handlers observe ctx.Done() to stop gracefully.
 type Engine struct {
-	handlers map[TaskType]TaskHandler
+	handlers map[TaskType]Handler
 	ctx      context.Context
 	ready    atomic.Bool
 	store    ResultStore
 	mu       sync.Mutex
 
 	// cancels holds the cancel func of every currently running task, keyed by
-	// task ID, so RemoveResult can stop a task's goroutine. Each entry carries
-	// the generation that registered it so cleanup is a compare-and-delete: a
-	// resubmit under the same ID registers a fresh entry with a newer generation
-	// and overwrites the prior one, and a superseded registration's late cleanup
-	// must not touch the newer entry. Guarded by mu.
+	// task ID, so RemoveResult and CancelTask can stop a task's goroutine. Each
+	// entry carries the generation that registered it so cleanup is a
+	// compare-and-delete: a resubmit under the same ID registers a fresh entry
+	// with a newer generation and overwrites the prior one, and a superseded
+	// registration's late cleanup must not touch the newer entry. Guarded by
+	// mu.
 	cancels map[string]cancelEntry
 
 	// gen mints a strictly-increasing, process-lifetime-unique generation for
@@ -57,36 +61,128 @@ type Engine struct {
 	// gen never repeats.
 	gen atomic.Int64
 
+	// graphMu serializes task-graph advancement so two nodes settling at
+	// once cannot both start a shared child. graphOf maps the task ID of
+	// every node of an active graph to its graph ID, so a settling task can
+	// find the graph to advance. Guarded by graphMu. Lock order: graphMu
+	// before mu.
+	graphMu sync.Mutex
+	graphOf map[string]string
+
+	// scheduleMu serializes schedule firing against create and delete, so a
+	// deleted schedule is never re-saved by a firing in flight. scheduleWake
+	// interrupts the scheduler's sleep when the schedule set changes. Lock
+	// order: scheduleMu before mu.
+	scheduleMu   sync.Mutex
+	scheduleWake chan struct{}
+
+	// eventsMu serializes appending to the event log with fan-out to
+	// subscribers, so a subscription's backlog and live feed neither overlap
+	// nor leave a gap. Guarded by eventsMu. Lock order: eventsMu after every
+	// other lock; nothing is acquired while holding it.
+	eventsMu    sync.Mutex
+	subscribers map[*subscriber]struct{}
+
+	// running maps the ID of every task holding a worker to its type;
+	// locks maps each held exclusion group to the ID of the task holding
+	// it; waiters is the queue of Pending tasks, in start order. All
+	// guarded by mu.
+	running map[string]TaskType
+	locks   map[string]string
+	waiters []waiter
+
 	// Config is set once during single-threaded startup before Submit
 	// is reachable; read-only thereafter. No synchronization.
 	Config ExecutionConfig
+
+	// RetryPolicies maps a task type to its automatic retry policy. Types
+	// absent from the map are never retried by the engine. Set once during
+	// startup alongside Config; read-only thereafter.
+	RetryPolicies map[TaskType]RetryPolicy
+
+	// Timeouts is the per-type default execution deadline, applied when a
+	// submission carries no Task.Timeout. Types absent from the map run
+	// without a deadline unless the caller sets one. Set once during
+	// startup alongside Config; read-only thereafter.
+	Timeouts map[TaskType]time.Duration
+
+	// Exclusions declares the mutual-exclusion groups, keyed by group name.
+	// A task runs only while it holds every group its type belongs to; a
+	// conflicting submission is queued or rejected per the group's Policy.
+	// Set once during startup alongside Config; read-only thereafter.
+	Exclusions map[string]ExclusionGroup
+
+	// Concurrency bounds how many tasks run at once. The zero value is
+	// unbounded. Set once during startup alongside Config; read-only
+	// thereafter.
+	Concurrency ConcurrencyLimits
+
+	// Retention bounds how many settled results the store keeps; the
+	// compactor started by StartCompactor applies it. The zero value keeps
+	// everything. Set once during startup alongside Config; read-only
+	// thereafter.
+	Retention RetentionPolicy
+
+	// DrainExempt lists the task types still accepted and started while
+	// the engine drains: readiness gates an operator needs mid-migration.
+	// Set once during startup alongside Config; read-only thereafter.
+	DrainExempt []TaskType
+
+	// drain is the drain in progress, nil while the engine accepts work.
+	// Guarded by mu.
+	drain *drainState
 }
 
 // cancelEntry is a registered task's cancel func tagged with the generation that
 // registered it, so clearCancel and RemoveResult can distinguish "my
 // registration's entry" from "a newer registration that overwrote mine".
+// requested is set by CancelTask so the run persists a Cancelled row rather
+// than treating the cancellation as shutdown; done is closed when the entry is
+// unregistered, which CancelTask waits on.
 type cancelEntry struct {
-	cancel context.CancelFunc
-	gen    int64
+	cancel    context.CancelFunc
+	gen       int64
+	requested *cancellation
+	done      chan struct{}
 }
 
 // NewEngine creates a new Engine. The engine runs until ctx is cancelled.
-// Callers MUST install handler dependencies on e.Config before
-// RehydrateStaleTasks, else a rehydrated handler races with the write.
-func NewEngine(ctx context.Context, handlers map[TaskType]TaskHandler, store ResultStore) *Engine {
+// Callers MUST install handler dependencies on e.Config (and any
+// e.RetryPolicies) before RehydrateStaleTasks, else a rehydrated handler
+// races with the write.
+//
+// handlers may be any map of Handler implementations, e.g. a
+// map[TaskType]TaskHandler; the engine keeps its own copy.
+func NewEngine[H Handler](ctx context.Context, handlers map[TaskType]H, store ResultStore) *Engine {
+	registered := make(map[TaskType]Handler, len(handlers))
+	for t, h := range handlers {
+		registered[t] = h
+	}
 	return &Engine{
-		handlers: handlers,
+		handlers: registered,
 		ctx:      ctx,
 		store:    store,
 		cancels:  make(map[string]cancelEntry),
+		graphOf:  make(map[string]string),
+		running:  make(map[string]TaskType),
+		locks:    make(map[string]string),
+
+		scheduleWake: make(chan struct{}, 1),
+		subscribers:  make(map[*subscriber]struct{}),
 	}
 }
 
 // RehydrateStaleTasks re-executes tasks left in "running" state by a
 // previous process that exited before completing them. Run count is
 // NOT incremented — rehydration is crash recovery of an incomplete
-// run, not a new run. Must be called only after Config is installed.
+// run, not a new run. Tasks left "pending" are re-queued in queue
+// order, and task graphs left running are
+// resumed once the stale tasks are dispatched. Must be called only
+// after Config, Exclusions and Concurrency are installed.
 func (e *Engine) RehydrateStaleTasks() {
+	graphs := e.rehydrateGraphs()
+	defer e.resumeGraphs(graphs)
+
 	stale, err := e.store.ListStaleTasks()
 	if err != nil {
 		log.Error("failed to list stale tasks", "err", err)
@@ -113,9 +209,9 @@ func (e *Engine) RehydrateStaleTasks() {
 			log.Info("rehydrating hold synchronously before other tasks",
 				"type", tr.Type, "id", tr.ID, "run", tr.Run)
 			e.mu.Lock()
-			ctx, gen := e.newTaskContext(tr.ID)
+			ctx, gen := e.newTaskContext(tr.ID, tr.Deadline)
 			e.mu.Unlock()
-			e.runTaskSync(ctx, tr.ID, TaskType(tr.Type), handler, tr.Params, tr.SubmittedAt, tr.Run, gen)
+			e.runTaskSync(ctx, tr, handler, gen)
 		}
 	}
 
@@ -129,6 +225,8 @@ func (e *Engine) RehydrateStaleTasks() {
 		}
 	}
 
+	// Stale running tasks held their workers and exclusion groups when the
+	// process died; they reclaim them before anything queued is considered.
 	for _, tr := range stale {
 		switch TaskType(tr.Type) {
 		case TaskMarkNotReady:
@@ -147,11 +245,36 @@ func (e *Engine) RehydrateStaleTasks() {
 		if handler, ok := e.resolveStaleHandler(tr); ok {
 			log.Info("rehydrating stale task", "type", tr.Type, "id", tr.ID, "run", tr.Run)
 			e.mu.Lock()
-			ctx, gen := e.newTaskContext(tr.ID)
+			e.acquire(tr.ID, TaskType(tr.Type), e.exclusionGroups(TaskType(tr.Type)))
+			ctx, gen := e.newTaskContext(tr.ID, tr.Deadline)
 			e.mu.Unlock()
-			e.runTask(ctx, tr.ID, TaskType(tr.Type), handler, tr.Params, tr.SubmittedAt, tr.Run, gen)
+			e.runTask(ctx, tr, handler, gen)
 		}
 	}
+
+	e.requeuePendingTasks()
+}
+
+// requeuePendingTasks restores the wait queue from the Pending rows a
+// previous process left behind, in queue order, and starts whichever of them
+// can run now. A task's deadline starts once it is dequeued.
+func (e *Engine) requeuePendingTasks() {
+	pending, err := e.store.ListPendingTasks()
+	if err != nil {
+		log.Error("failed to list pending tasks", "err", err)
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, tr := range pending {
+		handler, ok := e.resolveStaleHandler(tr)
+		if !ok {
+			continue
+		}
+		log.Info("re-queuing pending task", "type", tr.Type, "id", tr.ID, "run", tr.Run)
+		e.enqueue(waiter{tr: tr, handler: handler, groups: e.exclusionGroups(TaskType(tr.Type)), enqueuedAt: tr.SubmittedAt})
+	}
+	e.dispatchWaiters()
 }
 
 // markReadySuperseded reports whether a stranded mark-ready must not be
@@ -183,6 +306,8 @@ func (e *Engine) markReadySuperseded(mr TaskResult) bool {
 	mr.CompletedAt = &t
 	if err := e.store.Save(&mr); err != nil {
 		log.Error("failed to persist superseded mark-ready", "id", mr.ID, "err", err)
+	} else {
+		e.publishSettled(&mr)
 	}
 	return true
 }
@@ -191,8 +316,23 @@ func (e *Engine) markReadySuperseded(mr TaskResult) bool {
 //
 // The engine follows a cloud-API model for task lifecycle:
 //   - If no task with this ID exists, create and execute it (run 1).
-//   - If the task is running or completed, return its ID (idempotent no-op).
-//   - If the task failed, re-execute it with an incremented run counter.
+//   - If the task is pending, running or completed, return its ID
+//     (idempotent no-op).
+//   - If the task failed, was cancelled, or was skipped by its task graph,
+//     re-execute it with an incremented run counter.
+//
+// While the engine drains, a new run of any type not in DrainExempt is
+// refused with ErrDraining; see Drain.
+//
+// A task whose exclusion groups are busy is either queued or rejected with
+// ErrTaskConflict, per the groups' policy; a task with no free worker under
+// the concurrency limits is queued. A queued task is persisted Pending and
+// started, highest Priority first, once what it waits for frees. Its
+// deadline counts from when it starts, not from submission.
+//
+// Params a typed handler cannot decode, or that fail its request type's
+// Validate hook, are rejected with a *ParamsError before anything is
+// persisted.
 //
 // The caller submits a stable key and the engine owns the execution lifecycle.
 func (e *Engine) Submit(task Task) (string, error) {
@@ -200,11 +340,18 @@ func (e *Engine) Submit(task Task) (string, error) {
 	if !ok {
 		return "", fmt.Errorf("unknown task type: %s", task.Type)
 	}
+	if err := checkParams(handler, task.Params); err != nil {
+		return "", err
+	}
 
 	if err := validateTaskID(task.ID); err != nil {
 		return "", err
 	}
 
+	if task.Timeout < 0 {
+		return "", ErrInvalidTimeout
+	}
+
 	id := task.ID
 	if id == "" {
 		id = uuid.New().String()
@@ -216,12 +363,15 @@ func (e *Engine) Submit(task Task) (string, error) {
 	run := 1
 	if existing, _ := e.store.Get(id); existing != nil {
 		switch existing.Status {
-		case TaskStatusRunning, TaskStatusCompleted:
+		case TaskStatusPending, TaskStatusRunning, TaskStatusCompleted:
 			return id, nil
-		case TaskStatusFailed:
+		case TaskStatusFailed, TaskStatusSkipped, TaskStatusCancelled:
 			run = existing.Run + 1
 		}
 	}
+	if e.drainHolds(task.Type) {
+		return "", ErrDraining
+	}
 
 	now := time.Now().UTC()
 	tr := &TaskResult{
@@ -229,34 +379,86 @@ func (e *Engine) Submit(task Task) (string, error) {
 		Type:        string(task.Type),
 		Status:      TaskStatusRunning,
 		Run:         run,
+		Attempt:     1,
 		Params:      task.Params,
+		Priority:    task.Priority,
 		SubmittedAt: now,
+		SubmittedBy: task.SubmittedBy,
+	}
+	timeout := task.Timeout
+	if timeout == 0 {
+		timeout = e.Timeouts[task.Type]
+	}
+	tr.Timeout = Duration(timeout)
+
+	groups := e.exclusionGroups(task.Type)
+	group, policy := e.exclusionConflict(groups)
+	if group != "" && policy == ExclusionReject {
+		return "", e.conflictError(task.Type, group)
+	}
+	queued := group != "" || !e.workerFree(task.Type)
+	if queued {
+		tr.Status = TaskStatusPending
+	} else {
+		tr.startDeadline(now)
 	}
 
 	if err := e.store.Save(tr); err != nil {
 		return "", fmt.Errorf("persist task: %w", err)
 	}
+	taskSubmissions.WithLabelValues(string(task.Type)).Inc()
+	e.publish(EventSubmitted, tr)
+
+	if queued {
+		log.Info("task queued", "type", task.Type, "id", id, "run", run, "priority", task.Priority, "group", group)
+		e.enqueue(waiter{tr: *tr, handler: handler, groups: groups, enqueuedAt: now})
+		// A queued group may be claimed only by lower-priority waiters that
+		// this task now precedes.
+		e.dispatchWaiters()
+		return id, nil
+	}
 
 	log.Info("task submitted", "type", task.Type, "id", id, "run", run)
-	taskSubmissions.WithLabelValues(string(task.Type)).Inc()
-	ctx, gen := e.newTaskContext(id)
-	e.runTask(ctx, id, task.Type, handler, task.Params, now, run, gen)
+	e.acquire(id, task.Type, groups)
+	ctx, gen := e.newTaskContext(id, tr.Deadline)
+	e.runTask(ctx, *tr, handler, gen)
 
 	return id, nil
 }
 
+// startDeadline sets the deadline tr's Timeout gives a run starting at now.
+// A deadline already set is kept: a row persisted before Timeout was
+// recorded carries one counted from its submission.
+func (tr *TaskResult) startDeadline(now time.Time) {
+	if tr.Deadline != nil || tr.Timeout <= 0 {
+		return
+	}
+	deadline := now.Add(time.Duration(tr.Timeout))
+	tr.Deadline = &deadline
+}
+
 // newTaskContext derives a cancellable, task-tagged context from the engine
 // root and registers its cancel func under id, tagged with a freshly-minted
 // generation, so RemoveResult can stop the task and clearCancel can tell this
 // registration's entry from a newer one's. It returns the context and the
 // generation it minted; the caller threads that generation to clearCancel so
 // cleanup removes only its own entry. The task id is threaded through ctx for
-// handlers that need it (e.g. sign-tx memo tagging). Callers MUST hold e.mu;
+// handlers that need it (e.g. sign-tx memo tagging). A non-nil deadline is the
+// task's persisted absolute deadline; a rehydrated task passes the stored value
+// so a restart never grants it a fresh budget. Callers MUST hold e.mu;
 // clearCancel removes the entry when the task terminates.
-func (e *Engine) newTaskContext(id string) (context.Context, int64) {
+func (e *Engine) newTaskContext(id string, deadline *time.Time) (context.Context, int64) {
 	gen := e.gen.Add(1)
-	ctx, cancel := context.WithCancel(e.ctx)
-	e.cancels[id] = cancelEntry{cancel: cancel, gen: gen}
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if deadline != nil {
+		ctx, cancel = context.WithDeadline(e.ctx, *deadline)
+	} else {
+		ctx, cancel = context.WithCancel(e.ctx)
+	}
+	e.cancels[id] = cancelEntry{cancel: cancel, gen: gen, done: make(chan struct{})}
 	return WithTaskID(ctx, id), gen
 }
 
@@ -273,71 +475,206 @@ func (e *Engine) clearCancel(id string, gen int64) {
 	if entry, ok := e.cancels[id]; ok && entry.gen == gen {
 		entry.cancel()
 		delete(e.cancels, id)
+		close(entry.done)
+		e.checkDrained()
 	}
 }
 
 // runTask spawns a goroutine to run the handler and persist the result.
-func (e *Engine) runTask(ctx context.Context, id string, taskType TaskType, handler TaskHandler, params map[string]any, submittedAt time.Time, run int, gen int64) {
-	go e.runTaskSync(ctx, id, taskType, handler, params, submittedAt, run, gen)
+func (e *Engine) runTask(ctx context.Context, tr TaskResult, handler Handler, gen int64) {
+	go e.runTaskSync(ctx, tr, handler, gen)
 }
 
 // runTaskSync runs the handler and persists the result, blocking until done.
 // runTask wraps it in a goroutine; RehydrateStaleTasks calls it directly for a
 // stale mark-not-ready that must complete (purge + readiness flip) before any
 // other stale task is dispatched. ctx is the per-task cancellable context from
-// newTaskContext.
-func (e *Engine) runTaskSync(ctx context.Context, id string, taskType TaskType, handler TaskHandler, params map[string]any, submittedAt time.Time, run int, gen int64) {
-	defer e.clearCancel(id, gen)
-	result, err := e.executeRecovered(ctx, taskType, handler, params)
-
-	// The task's context was cancelled — either engine shutdown (e.ctx) or an
-	// explicit DELETE (RemoveResult cancelled this task's context). In both
-	// cases, leave the store untouched: on shutdown the row stays 'running' so
-	// RehydrateStaleTasks resumes it on restart (persisting a spurious Failed
-	// would strand an in-flight sign-tx); on DELETE the handler already removed
-	// the row, so writing nothing is the true-deletion outcome. Keying on a
-	// cancelled context (not only a context.Canceled-wrapping error) also
-	// suppresses the case where cancellation surfaces as an unrelated handler
-	// error, avoiding a confusing Failed row after the row is already gone. The
-	// guard matches only context.Canceled, never any ctx error: a
-	// context.DeadlineExceeded (e.g. a per-task timeout added to newTaskContext)
-	// falls through to the normal Failed-persistence path so a timed-out one-shot
-	// task reaches Failed rather than being stranded in 'running'. A run that
-	// SUCCEEDED (err == nil) is always persisted, even under cancellation, so a
-	// completed non-idempotent task is never re-run on restart.
-	if err != nil && (errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled)) {
-		log.Info("task cancelled; leaving store untouched",
-			"type", taskType, "id", id, "run", run)
+// newTaskContext; tr is the task's persisted row as of dispatch.
+//
+// A failure the task type's RetryPolicy permits is re-executed in this same
+// goroutine under the same context registration: the row is persisted
+// 'running' with an advanced Run and NextAttemptAt, the backoff elapses, and
+// the handler runs again. A row rehydrated with NextAttemptAt in the future
+// waits out the remainder of its persisted backoff first.
+//
+// The handler's ProgressReporter lives for the whole run, across retries; its
+// latest report is carried onto every row the run saves.
+func (e *Engine) runTaskSync(ctx context.Context, tr TaskResult, handler Handler, gen int64) {
+	defer e.clearCancel(tr.ID, gen)
+	taskType := TaskType(tr.Type)
+	attempt := max(tr.Attempt, 1)
+	progress := newTaskProgress(e, &tr)
+	ctx = WithProgressReporter(ctx, progress)
+
+	for {
+		var wait time.Duration
+		if tr.NextAttemptAt != nil {
+			wait = time.Until(*tr.NextAttemptAt)
+		}
+
+		// The handler runs only once any pending backoff has elapsed with the
+		// context still live. A context that ends first — cancelled, or past a
+		// deadline already expired at rehydration — is reported as the
+		// attempt's error without invoking the handler.
+		var (
+			result json.RawMessage
+			err    error
+		)
+		if sleepCtx(ctx, wait) {
+			e.publish(EventStarted, &tr)
+			result, err = e.executeRecovered(ctx, taskType, handler, tr.Params)
+		} else {
+			err = ctx.Err()
+		}
+
+		// A task that overran its deadline fails with a structured,
+		// non-retryable error naming the deadline; the handler's own error is
+		// kept as the cause.
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = deadlineError(taskType, tr.Deadline, err)
+		}
+
+		// The task's context was cancelled — engine shutdown (e.ctx), an
+		// explicit DELETE (RemoveResult cancelled this task's context), or
+		// CancelTask. CancelTask records its request on the registry entry and
+		// the run persists it as a terminal Cancelled row. In the other two
+		// cases, leave the store untouched: on shutdown the row stays 'running' so
+		// RehydrateStaleTasks resumes it on restart (persisting a spurious Failed
+		// would strand an in-flight sign-tx); on DELETE the handler already removed
+		// the row, so writing nothing is the true-deletion outcome. Keying on a
+		// cancelled context (not only a context.Canceled-wrapping error) also
+		// suppresses the case where cancellation surfaces as an unrelated handler
+		// error, avoiding a confusing Failed row after the row is already gone. The
+		// guard matches only context.Canceled, never any ctx error: a
+		// context.DeadlineExceeded (e.g. the per-task deadline newTaskContext applies)
+		// falls through to the normal Failed-persistence path so a timed-out one-shot
+		// task reaches Failed rather than being stranded in 'running'. A run that
+		// SUCCEEDED (err == nil) is always persisted, even under cancellation, so a
+		// completed non-idempotent task is never re-run on restart.
+		if err != nil && (errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled)) {
+			progress.close(&tr)
+			if c := e.cancellationOf(tr.ID, gen); c != nil {
+				e.release(tr.ID)
+				tr.Attempt = attempt
+				tr.Result = result
+				if storeErr := e.persistCancelled(&tr, c); storeErr != nil {
+					log.Error("failed to persist task cancellation", "id", tr.ID, "err", storeErr)
+				}
+				return
+			}
+			log.Info("task cancelled; leaving store untouched",
+				"type", taskType, "id", tr.ID, "run", tr.Run)
+			e.release(tr.ID)
+			return
+		}
+
+		progress.stamp(&tr)
+		if err != nil && e.scheduleRetry(&tr, attempt, result, err) {
+			attempt++
+			continue
+		}
+		progress.close(&tr)
+		e.release(tr.ID)
+
+		t := time.Now().UTC()
+		tr.Status = TaskStatusCompleted
+		tr.Attempt = attempt
+		tr.CompletedAt = &t
+		tr.NextAttemptAt = nil
+		// Stamp on both paths — a failed run may still carry a result (e.g. a
+		// tx hash); a panic yields nil, so nothing partial is stamped.
+		tr.Result = result
+		tr.Error = ""
+		if err != nil {
+			tr.Error = err.Error()
+			tr.Status = TaskStatusFailed
+		}
+
+		if storeErr := e.store.Save(&tr); storeErr != nil {
+			log.Error("failed to persist task result", "id", tr.ID, "err", storeErr)
+		} else {
+			e.publishSettled(&tr)
+		}
+		e.graphTaskSettled(tr.ID)
 		return
 	}
+}
 
-	t := time.Now().UTC()
-	tr := &TaskResult{
-		ID:          id,
-		Type:        string(taskType),
-		Run:         run,
-		Params:      params,
-		SubmittedAt: submittedAt,
-		CompletedAt: &t,
+// scheduleRetry decides whether a failed attempt is retried under the task
+// type's RetryPolicy. When it is, the row is advanced to the next run and
+// persisted 'running' with the failure and the next attempt's start time, and
+// scheduleRetry reports true. The persist precedes the backoff so a crash
+// during the wait resumes the same schedule on restart.
+func (e *Engine) scheduleRetry(tr *TaskResult, attempt int, result json.RawMessage, err error) bool {
+	policy, ok := e.RetryPolicies[TaskType(tr.Type)]
+	if !ok || !Retryable(err) {
+		return false
 	}
-	// Stamp on both paths — a failed run may still carry a result (e.g. a
-	// tx hash); a panic yields nil, so nothing partial is stamped.
-	tr.Result = result
-	if err != nil {
-		tr.Error = err.Error()
-		tr.Status = TaskStatusFailed
-	} else {
-		tr.Status = TaskStatusCompleted
+	next, ok := policy.nextAttempt(attempt, tr.SubmittedAt, time.Now())
+	if ok && tr.Deadline != nil && next.After(*tr.Deadline) {
+		ok = false
 	}
+	if !ok {
+		log.Warn("retry policy exhausted; failing task",
+			"type", tr.Type, "id", tr.ID, "run", tr.Run, "attempts", attempt)
+		return false
+	}
+	next = next.UTC()
 
+	tr.Run++
+	tr.Attempt = attempt + 1
+	tr.Status = TaskStatusRunning
+	tr.Result = result
+	tr.Error = err.Error()
+	tr.CompletedAt = nil
+	tr.NextAttemptAt = &next
 	if storeErr := e.store.Save(tr); storeErr != nil {
-		log.Error("failed to persist task result", "id", id, "err", storeErr)
+		log.Error("failed to persist pending retry", "id", tr.ID, "err", storeErr)
+	}
+
+	log.Info("task failed; retry scheduled",
+		"type", tr.Type, "id", tr.ID, "run", tr.Run, "attempt", tr.Attempt,
+		"after", time.Until(next).Round(time.Millisecond))
+	taskRetries.WithLabelValues(tr.Type).Inc()
+	return true
+}
+
+// deadlineError is the Failed-row error for a task whose context passed its
+// deadline. It is a non-retryable TaskError, so no retry policy re-runs it.
+func deadlineError(taskType TaskType, deadline *time.Time, cause error) *TaskError {
+	msg := context.DeadlineExceeded.Error()
+	if deadline != nil {
+		msg += " (deadline " + deadline.UTC().Format(time.RFC3339) + ")"
+	}
+	return &TaskError{
+		Task:      string(taskType),
+		Operation: "deadline",
+		Message:   msg,
+		Hint:      "resubmit with a longer timeout if the task needs more time",
+		Cause:     cause.Error(),
+	}
+}
+
+// sleepCtx blocks for d or until ctx is done, reporting whether the full
+// duration elapsed. A non-positive d returns true immediately unless ctx is
+// already done.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
 	}
 }
 
 // resolveStaleHandler returns the handler for a stale task. When no handler is
 // registered it marks the task failed, persists that, and returns ok=false.
-func (e *Engine) resolveStaleHandler(tr TaskResult) (TaskHandler, bool) {
+func (e *Engine) resolveStaleHandler(tr TaskResult) (Handler, bool) {
 	handler, ok := e.handlers[TaskType(tr.Type)]
 	if ok {
 		return handler, true
@@ -349,6 +686,8 @@ func (e *Engine) resolveStaleHandler(tr TaskResult) (TaskHandler, bool) {
 	tr.CompletedAt = &t
 	if err := e.store.Save(&tr); err != nil {
 		log.Error("failed to persist stale task failure", "id", tr.ID, "err", err)
+	} else {
+		e.publishSettled(&tr)
 	}
 	return nil, false
 }
@@ -357,7 +696,7 @@ func (e *Engine) resolveStaleHandler(tr TaskResult) (TaskHandler, bool) {
 // failed TaskResult instead of taking down the shared sidecar process. It
 // guards only the handler goroutine; a task that spawns its own goroutines must
 // recover within them (e.g. s3.streamGzip's writer).
-func (e *Engine) executeRecovered(ctx context.Context, taskType TaskType, handler TaskHandler, params map[string]any) (result json.RawMessage, err error) {
+func (e *Engine) executeRecovered(ctx context.Context, taskType TaskType, handler Handler, params map[string]any) (result json.RawMessage, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Error("task handler panicked", "type", taskType, "panic", r, "stack", string(debug.Stack()))
@@ -370,9 +709,9 @@ func (e *Engine) executeRecovered(ctx context.Context, taskType TaskType, handle
 }
 
 // execute runs a handler synchronously and logs the outcome.
-func (e *Engine) execute(ctx context.Context, taskType TaskType, handler TaskHandler, params map[string]any) (json.RawMessage, error) {
+func (e *Engine) execute(ctx context.Context, taskType TaskType, handler Handler, params map[string]any) (json.RawMessage, error) {
 	start := time.Now()
-	result, err := handler(ctx, params)
+	result, err := handler.Handle(ctx, params)
 	if err != nil {
 		elapsed := time.Since(start)
 		log.Error("task failed", "type", taskType, "elapsed", elapsed.Round(time.Millisecond), "err", err)
@@ -423,7 +762,7 @@ func (e *Engine) Status() StatusResponse {
 	if e.ready.Load() {
 		status = "Ready"
 	}
-	return StatusResponse{Status: status}
+	return StatusResponse{Status: status, Locks: e.exclusionStatus(), Drain: e.Draining()}
 }
 
 // RecentResults returns the most recent task results across all states.
@@ -459,13 +798,17 @@ func (e *Engine) GetResult(id string) *TaskResult {
 // process restart's RehydrateStaleTasks resumes the row. Either path recovers
 // it, so a failed delete never strands a task in 'running' with nothing able to
 // act on it. An already-terminal task has no registered cancel func, so this is
-// a plain delete.
+// a plain delete. A Pending task is dropped from its exclusion queue first; if
+// the delete then fails, the row stays Pending and is re-queued on restart.
 func (e *Engine) RemoveResult(id string) (bool, error) {
 	e.mu.Lock()
 	entry, hadEntry := e.cancels[id]
 	if hadEntry {
 		entry.cancel()
 	}
+	if e.dequeue(id) {
+		e.dispatchWaiters()
+	}
 	e.mu.Unlock()
 
 	deleted, err := e.store.Delete(id)
@@ -478,6 +821,8 @@ func (e *Engine) RemoveResult(id string) (bool, error) {
 		e.mu.Lock()
 		if cur, ok := e.cancels[id]; ok && cur.gen == entry.gen {
 			delete(e.cancels, id)
+			close(cur.done)
+			e.checkDrained()
 		}
 		e.mu.Unlock()
 	}
@@ -14,7 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
-func newTestEngine(t *testing.T, handlers map[TaskType]TaskHandler) *Engine {
+func newTestEngine[H Handler](t *testing.T, handlers map[TaskType]H) *Engine {
 	t.Helper()
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -649,12 +649,12 @@ func TestRemoveRehydratedTaskCancels(t *testing.T) {
 // survive. Driving newTaskContext/clearCancel directly makes the interleaving
 // deterministic under -race.
 func TestClearCancelIgnoresSupersededRun(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[TaskHandler](t, nil)
 	const id = "aaaaaaaa-1111-2222-3333-444444444444"
 
 	eng.mu.Lock()
-	_, gen1 := eng.newTaskContext(id)        // first registration
-	retryCtx, gen2 := eng.newTaskContext(id) // second overwrites the entry
+	_, gen1 := eng.newTaskContext(id, nil)        // first registration
+	retryCtx, gen2 := eng.newTaskContext(id, nil) // second overwrites the entry
 	eng.mu.Unlock()
 
 	eng.clearCancel(id, gen1) // the superseded registration's late defer must be a no-op
@@ -684,12 +684,12 @@ func TestClearCancelIgnoresSupersededRun(t *testing.T) {
 // intact. Driven through the real registry mutation path so the run-number
 // collision is genuine, not simulated.
 func TestClearCancelIgnoresSupersededRunAfterResubmit(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[TaskHandler](t, nil)
 	const id = "aaaaaaaa-1111-2222-3333-444444444444"
 
 	// Registration A. In a live run this is Submit's newTaskContext with run 1.
 	eng.mu.Lock()
-	ctxA, genA := eng.newTaskContext(id)
+	ctxA, genA := eng.newTaskContext(id, nil)
 	eng.mu.Unlock()
 
 	// RemoveResult cancels A's context and removes its entry on a successful
@@ -707,7 +707,7 @@ func TestClearCancelIgnoresSupersededRunAfterResubmit(t *testing.T) {
 	// A fresh resubmit lands under the same ID. run would again be 1 here (the
 	// row is gone), but the generation is strictly newer.
 	eng.mu.Lock()
-	ctxB, genB := eng.newTaskContext(id)
+	ctxB, genB := eng.newTaskContext(id, nil)
 	eng.mu.Unlock()
 	if genB <= genA {
 		t.Fatalf("generation must be strictly increasing: genA=%d genB=%d", genA, genB)
@@ -829,7 +829,8 @@ func TestRunTaskSyncSuppressesErrorUnderCancellation(t *testing.T) {
 	const id = "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // the task's context is already cancelled, as after RemoveResult
-	eng.runTaskSync(WithTaskID(ctx, id), id, TaskConfigPatch, eng.handlers[TaskConfigPatch], nil, time.Now().UTC(), 1, 1)
+	tr := TaskResult{ID: id, Type: string(TaskConfigPatch), Run: 1, SubmittedAt: time.Now().UTC()}
+	eng.runTaskSync(WithTaskID(ctx, id), tr, eng.handlers[TaskConfigPatch], 1)
 
 	if r := eng.GetResult(id); r != nil {
 		t.Fatalf("non-context error under a cancelled ctx must not persist; got %q row", r.Status)
@@ -870,7 +871,8 @@ func TestRunTaskSyncPersistsFailedOnDeadlineExceeded(t *testing.T) {
 			})
 
 			ctx := WithTaskID(tc.ctx(t), tc.id)
-			eng.runTaskSync(ctx, tc.id, TaskConfigPatch, eng.handlers[TaskConfigPatch], nil, time.Now().UTC(), 1, 1)
+			tr := TaskResult{ID: tc.id, Type: string(TaskConfigPatch), Run: 1, SubmittedAt: time.Now().UTC()}
+			eng.runTaskSync(ctx, tr, eng.handlers[TaskConfigPatch], 1)
 
 			r := eng.GetResult(tc.id)
 			if r == nil {
@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"time"
+)
+
+// maxEventLog is how many events the store keeps for Last-Event-ID resume;
+// older ones are pruned as new ones are appended.
+const maxEventLog = 10000
+
+// eventBuffer is how many live events a subscriber may fall behind by before
+// its feed is closed and it must resume from the log.
+const eventBuffer = 64
+
+// EventType names a task lifecycle transition on the event stream.
+type EventType string
+
+const (
+	// EventSubmitted: the task was accepted, running or queued.
+	EventSubmitted EventType = "submitted"
+
+	// EventStarted: the handler was invoked. A retried task emits one per
+	// attempt.
+	EventStarted EventType = "started"
+
+	// EventProgress: the handler's progress was persisted; at most one per
+	// task per progressSaveInterval.
+	EventProgress EventType = "progress"
+
+	// The terminal events, one per task run.
+	EventCompleted EventType = "completed"
+	EventFailed    EventType = "failed"
+	EventCancelled EventType = "cancelled"
+	EventSkipped   EventType = "skipped"
+)
+
+// TaskEvent is one entry of the persisted event log: a lifecycle transition
+// and the task's row as it stood right after it. Seq is assigned by the
+// store, starts at 1, and increases strictly, so it doubles as the SSE event
+// ID a client resumes from.
+type TaskEvent struct {
+	Seq  int64      `json:"seq"`
+	Type EventType  `json:"type"`
+	Task TaskResult `json:"task"`
+	At   time.Time  `json:"at"`
+}
+
+// EventSubscription is a feed of task events from SubscribeEvents.
+type EventSubscription struct {
+	// Backlog holds the logged events after the requested sequence, oldest
+	// first.
+	Backlog []TaskEvent
+
+	// Events delivers events published after SubscribeEvents returned. It is
+	// closed when the subscriber falls more than a small buffer behind;
+	// resubscribing after the last Seq seen resumes from the log without a
+	// gap.
+	Events <-chan TaskEvent
+
+	engine *Engine
+	sub    *subscriber
+}
+
+type subscriber struct {
+	taskID string
+	ch     chan TaskEvent
+}
+
+// Close stops the feed. It is safe to call more than once.
+func (s *EventSubscription) Close() {
+	e := s.engine
+	e.eventsMu.Lock()
+	defer e.eventsMu.Unlock()
+	if _, ok := e.subscribers[s.sub]; ok {
+		delete(e.subscribers, s.sub)
+		close(s.sub.ch)
+	}
+}
+
+// SubscribeEvents opens a feed of task events, limited to one task when
+// taskID is set. When after is positive the events logged after that
+// sequence are returned as the backlog, so a client that saw event N
+// resumes from N without a gap; after == 0 subscribes to live events only.
+// Events pruned from the log are not replayed.
+func (e *Engine) SubscribeEvents(taskID string, after int64) (*EventSubscription, error) {
+	e.eventsMu.Lock()
+	defer e.eventsMu.Unlock()
+
+	var backlog []TaskEvent
+	if after > 0 {
+		logged, err := e.store.ListEvents(after, maxEventLog)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range logged {
+			if taskID == "" || ev.Task.ID == taskID {
+				backlog = append(backlog, ev)
+			}
+		}
+	}
+
+	sub := &subscriber{taskID: taskID, ch: make(chan TaskEvent, eventBuffer)}
+	e.subscribers[sub] = struct{}{}
+	return &EventSubscription{Backlog: backlog, Events: sub.ch, engine: e, sub: sub}, nil
+}
+
+// publish logs a lifecycle event for tr and fans it out to subscribers. A
+// subscriber too far behind to take it is dropped; it resumes from the log.
+// A failure to log is reported and the event is not delivered, so the live
+// feed never carries an event a resuming client could not replay.
+func (e *Engine) publish(typ EventType, tr *TaskResult) {
+	ev := TaskEvent{Type: typ, Task: *tr, At: time.Now().UTC()}
+
+	e.eventsMu.Lock()
+	defer e.eventsMu.Unlock()
+	if err := e.store.AppendEvent(&ev, maxEventLog); err != nil {
+		log.Warn("failed to log task event", "type", typ, "id", tr.ID, "err", err)
+		return
+	}
+	for sub := range e.subscribers {
+		if sub.taskID != "" && sub.taskID != tr.ID {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			delete(e.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// publishSettled publishes the terminal event matching tr's status.
+func (e *Engine) publishSettled(tr *TaskResult) {
+	switch tr.Status {
+	case TaskStatusCompleted:
+		e.publish(EventCompleted, tr)
+	case TaskStatusFailed:
+		e.publish(EventFailed, tr)
+	case TaskStatusCancelled:
+		e.publish(EventCancelled, tr)
+	case TaskStatusSkipped:
+		e.publish(EventSkipped, tr)
+	}
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func nextEvent(t *testing.T, sub *EventSubscription) TaskEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-sub.Events:
+		if !ok {
+			t.Fatal("event feed closed")
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return TaskEvent{}
+}
+
+func TestSubscribeEventsLifecycle(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+		TaskMarkReady: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			return nil, errors.New("boom")
+		},
+	})
+	sub, err := eng.SubscribeEvents("", 0)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer sub.Close()
+
+	id, err := eng.Submit(Task{Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	var seqs []int64
+	for _, want := range []EventType{EventSubmitted, EventStarted, EventCompleted} {
+		ev := nextEvent(t, sub)
+		if ev.Type != want || ev.Task.ID != id {
+			t.Fatalf("event = %s for %s, want %s for %s", ev.Type, ev.Task.ID, want, id)
+		}
+		seqs = append(seqs, ev.Seq)
+	}
+	if seqs[0] >= seqs[1] || seqs[1] >= seqs[2] {
+		t.Errorf("seqs = %v, want strictly increasing", seqs)
+	}
+
+	failed, err := eng.Submit(Task{Type: TaskMarkReady})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForResult(t, eng, failed)
+	for _, want := range []EventType{EventSubmitted, EventStarted, EventFailed} {
+		if ev := nextEvent(t, sub); ev.Type != want {
+			t.Fatalf("event = %s, want %s", ev.Type, want)
+		}
+	}
+}
+
+func TestSubscribeEventsResumesFromLog(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	first, err := eng.Submit(Task{Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForResult(t, eng, first)
+	second, err := eng.Submit(Task{Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForResult(t, eng, second)
+
+	sub, err := eng.SubscribeEvents(second, 1)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer sub.Close()
+	if len(sub.Backlog) != 3 {
+		t.Fatalf("backlog = %d events, want the second task's 3", len(sub.Backlog))
+	}
+	for i, want := range []EventType{EventSubmitted, EventStarted, EventCompleted} {
+		if ev := sub.Backlog[i]; ev.Type != want || ev.Task.ID != second {
+			t.Errorf("backlog[%d] = %s for %s, want %s for %s", i, ev.Type, ev.Task.ID, want, second)
+		}
+	}
+	if sub.Backlog[2].Task.Status != TaskStatusCompleted {
+		t.Errorf("completed event carries status %q", sub.Backlog[2].Task.Status)
+	}
+}
+
+// A subscriber that stops reading is dropped once its buffer fills, after
+// the buffered events; resubscribing after the last one seen replays the
+// rest from the log.
+func TestSubscribeEventsLaggingSubscriberResumes(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	sub, err := eng.SubscribeEvents("", 0)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	total := eventBuffer + 10
+	for i := range total {
+		eng.publish(EventProgress, &TaskResult{ID: "lag", Run: i})
+	}
+
+	var last int64
+	for ev := range sub.Events {
+		last = ev.Seq
+	}
+	if last != eventBuffer {
+		t.Fatalf("last buffered seq = %d, want %d", last, eventBuffer)
+	}
+
+	sub, err = eng.SubscribeEvents("", last)
+	if err != nil {
+		t.Fatalf("resubscribe: %v", err)
+	}
+	defer sub.Close()
+	if len(sub.Backlog) != total-eventBuffer || sub.Backlog[0].Seq != last+1 {
+		t.Fatalf("backlog = %d events from %d, want %d from %d", len(sub.Backlog), sub.Backlog[0].Seq, total-eventBuffer, last+1)
+	}
+}
+
+func TestEventSubscriptionCloseIdempotent(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	sub, err := eng.SubscribeEvents("", 0)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	sub.Close()
+	sub.Close()
+	eng.publish(EventStarted, &TaskResult{ID: "closed"})
+	if _, ok := <-sub.Events; ok {
+		t.Error("closed subscription received an event")
+	}
+}
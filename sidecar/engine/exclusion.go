@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ErrTaskConflict is returned by Submit when a task belongs to an exclusion
+// group with ExclusionReject policy and a conflicting task holds (or is
+// queued for) that group.
+var ErrTaskConflict = errors.New("conflicting task in progress")
+
+// ExclusionPolicy decides what happens to a task submitted while a
+// conflicting task holds one of its exclusion groups.
+type ExclusionPolicy string
+
+const (
+	// ExclusionQueue persists the task as Pending and starts it, in queue
+	// order, once every group it needs is free.
+	ExclusionQueue ExclusionPolicy = "queue"
+	// ExclusionReject fails the submission with ErrTaskConflict.
+	ExclusionReject ExclusionPolicy = "reject"
+)
+
+// ExclusionGroup is a mutual-exclusion class: at most one task of any of
+// its Types runs at a time. A task type may belong to several groups and
+// runs only while it holds all of them; if any conflicting group rejects,
+// the submission is rejected, otherwise it queues.
+type ExclusionGroup struct {
+	Types []TaskType
+	// Policy defaults to ExclusionQueue when empty.
+	Policy ExclusionPolicy
+}
+
+// ExclusionLockStatus is one group's entry on /v0/status: the task holding
+// the group, if any, and the pending tasks queued for it in start order.
+type ExclusionLockStatus struct {
+	Group   string          `json:"group"`
+	Policy  ExclusionPolicy `json:"policy"`
+	Holder  string          `json:"holder,omitempty"`
+	Waiters []string        `json:"waiters,omitempty"`
+}
+
+// exclusionGroups returns the sorted names of the groups taskType belongs to.
+func (e *Engine) exclusionGroups(taskType TaskType) []string {
+	var groups []string
+	for name, g := range e.Exclusions {
+		if slices.Contains(g.Types, taskType) {
+			groups = append(groups, name)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// exclusionConflict reports the first of groups that is held or that an
+// earlier waiter is queued for, along with the policy the submission must
+// follow: ExclusionReject if any busy group rejects, else ExclusionQueue.
+// It returns "" when every group is free. Callers MUST hold e.mu.
+func (e *Engine) exclusionConflict(groups []string) (string, ExclusionPolicy) {
+	queued := make(map[string]bool)
+	for _, w := range e.waiters {
+		for _, g := range w.groups {
+			queued[g] = true
+		}
+	}
+	busy, policy := "", ExclusionQueue
+	for _, g := range groups {
+		if _, held := e.locks[g]; !held && !queued[g] {
+			continue
+		}
+		if busy == "" {
+			busy = g
+		}
+		if e.Exclusions[g].Policy == ExclusionReject {
+			return g, ExclusionReject
+		}
+	}
+	return busy, policy
+}
+
+// conflictError describes a rejected submission.
+func (e *Engine) conflictError(taskType TaskType, group string) error {
+	if holder, ok := e.locks[group]; ok {
+		return fmt.Errorf("%w: %s needs exclusion group %q, held by task %s", ErrTaskConflict, taskType, group, holder)
+	}
+	return fmt.Errorf("%w: %s needs exclusion group %q, which has queued tasks", ErrTaskConflict, taskType, group)
+}
+
+// exclusionStatus snapshots every configured group for /v0/status.
+func (e *Engine) exclusionStatus() []ExclusionLockStatus {
+	if len(e.Exclusions) == 0 {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, 0, len(e.Exclusions))
+	for name := range e.Exclusions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]ExclusionLockStatus, 0, len(names))
+	for _, name := range names {
+		policy := e.Exclusions[name].Policy
+		if policy == "" {
+			policy = ExclusionQueue
+		}
+		ls := ExclusionLockStatus{Group: name, Policy: policy, Holder: e.locks[name]}
+		for _, w := range e.waiters {
+			if slices.Contains(w.groups, name) {
+				ls.Waiters = append(ls.Waiters, w.tr.ID)
+			}
+		}
+		out = append(out, ls)
+	}
+	return out
+}
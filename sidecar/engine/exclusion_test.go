@@ -0,0 +1,263 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// gatedRecorder records the "step" param of every run, then blocks until
+// released, so a test can hold an exclusion group for as long as it needs.
+type gatedRecorder struct {
+	orderRecorder
+	release chan struct{}
+	once    sync.Once
+}
+
+func newGatedRecorder() *gatedRecorder {
+	return &gatedRecorder{release: make(chan struct{})}
+}
+
+func (g *gatedRecorder) handler(ctx context.Context, params map[string]any) (json.RawMessage, error) {
+	if _, err := g.orderRecorder.handler(ctx, params); err != nil {
+		return nil, err
+	}
+	select {
+	case <-g.release:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *gatedRecorder) open() { g.once.Do(func() { close(g.release) }) }
+
+// waitForStep polls until the recorder has seen step.
+func (g *gatedRecorder) waitForStep(t *testing.T, step string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if slices.Contains(g.ran(), step) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for step %q, ran %v", step, g.ran())
+}
+
+func exclusionTestEngine(t *testing.T, policy ExclusionPolicy) (*Engine, *gatedRecorder) {
+	t.Helper()
+	rec := newGatedRecorder()
+	t.Cleanup(rec.open)
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: rec.handler,
+		TaskConfigApply: rec.handler,
+	})
+	eng.Exclusions = map[string]ExclusionGroup{
+		"home-config-write": {Types: []TaskType{TaskConfigPatch, TaskConfigApply}, Policy: policy},
+	}
+	return eng, rec
+}
+
+func step(s string) map[string]any { return map[string]any{"step": s} }
+
+func TestExclusionQueueDefersConflictingTask(t *testing.T) {
+	eng, rec := exclusionTestEngine(t, ExclusionQueue)
+
+	first, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step("first")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "first")
+
+	second, err := eng.Submit(Task{Type: TaskConfigApply, Params: step("second")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := eng.GetResult(second); r == nil || r.Status != TaskStatusPending {
+		t.Fatalf("queued task = %+v, want status pending", r)
+	}
+	if again, err := eng.Submit(Task{ID: second, Type: TaskConfigApply}); err != nil || again != second {
+		t.Fatalf("resubmitting a pending task = (%s, %v), want idempotent no-op", again, err)
+	}
+
+	rec.open()
+	for _, id := range []string{first, second} {
+		if r := waitForResult(t, eng, id); r.Status != TaskStatusCompleted {
+			t.Fatalf("task %s status = %q, want completed", id, r.Status)
+		}
+	}
+	if ran := rec.ran(); !slices.Equal(ran, []string{"first", "second"}) {
+		t.Fatalf("run order = %v, want [first second]", ran)
+	}
+}
+
+func TestExclusionQueueIsFIFO(t *testing.T) {
+	eng, rec := exclusionTestEngine(t, ExclusionQueue)
+
+	if _, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step("holder")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "holder")
+	var ids []string
+	for _, s := range []string{"a", "b", "c"} {
+		id, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step(s)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	rec.open()
+	for _, id := range ids {
+		waitForResult(t, eng, id)
+	}
+	if ran := rec.ran(); !slices.Equal(ran, []string{"holder", "a", "b", "c"}) {
+		t.Fatalf("run order = %v, want submission order", ran)
+	}
+}
+
+func TestExclusionRejectReturnsConflict(t *testing.T) {
+	eng, rec := exclusionTestEngine(t, ExclusionReject)
+
+	holder, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step("holder")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "holder")
+
+	id := uuid.New().String()
+	if _, err := eng.Submit(Task{ID: id, Type: TaskConfigApply}); !errors.Is(err, ErrTaskConflict) {
+		t.Fatalf("err = %v, want ErrTaskConflict", err)
+	}
+	if r := eng.GetResult(id); r != nil {
+		t.Fatalf("rejected task persisted: %+v", r)
+	}
+
+	rec.open()
+	waitForResult(t, eng, holder)
+	if _, err := eng.Submit(Task{ID: id, Type: TaskConfigApply}); err != nil {
+		t.Fatalf("submit after holder finished: %v", err)
+	}
+}
+
+func TestExclusionStatusReportsHolderAndWaiters(t *testing.T) {
+	eng, rec := exclusionTestEngine(t, "")
+
+	holder, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step("holder")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "holder")
+	waiting, err := eng.Submit(Task{Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locks := eng.Status().Locks
+	if len(locks) != 1 {
+		t.Fatalf("locks = %+v, want one group", locks)
+	}
+	l := locks[0]
+	if l.Group != "home-config-write" || l.Policy != ExclusionQueue || l.Holder != holder {
+		t.Fatalf("lock = %+v, want home-config-write/queue held by %s", l, holder)
+	}
+	if !slices.Equal(l.Waiters, []string{waiting}) {
+		t.Fatalf("waiters = %v, want [%s]", l.Waiters, waiting)
+	}
+
+	rec.open()
+	waitForResult(t, eng, waiting)
+	if l := eng.Status().Locks[0]; l.Holder != "" || len(l.Waiters) != 0 {
+		t.Fatalf("lock after drain = %+v, want free", l)
+	}
+}
+
+func TestRemoveResultDropsPendingTaskFromQueue(t *testing.T) {
+	eng, rec := exclusionTestEngine(t, ExclusionQueue)
+
+	holder, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step("holder")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "holder")
+	queued, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step("queued")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := eng.RemoveResult(queued); err != nil || !ok {
+		t.Fatalf("RemoveResult = (%v, %v), want (true, nil)", ok, err)
+	}
+	if w := eng.Status().Locks[0].Waiters; len(w) != 0 {
+		t.Fatalf("waiters after remove = %v, want none", w)
+	}
+
+	rec.open()
+	waitForResult(t, eng, holder)
+	time.Sleep(20 * time.Millisecond)
+	if slices.Contains(rec.ran(), "queued") {
+		t.Fatal("removed pending task ran")
+	}
+}
+
+// A Pending row left by a previous process is re-queued on rehydration and
+// runs once the group is free.
+func TestRehydrateRequeuesPendingTasks(t *testing.T) {
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	id := uuid.New().String()
+	if err := store.Save(&TaskResult{
+		ID: id, Type: string(TaskConfigPatch), Status: TaskStatusPending,
+		Run: 1, Attempt: 1, Params: step("queued"), SubmittedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := newGatedRecorder()
+	rec.open()
+	eng := engineOver(t, store, map[TaskType]TaskHandler{TaskConfigPatch: rec.handler})
+	eng.Exclusions = map[string]ExclusionGroup{"home-config-write": {Types: []TaskType{TaskConfigPatch}}}
+	eng.RehydrateStaleTasks()
+
+	if r := waitForResult(t, eng, id); r.Status != TaskStatusCompleted {
+		t.Fatalf("status = %q, want completed", r.Status)
+	}
+}
+
+// A graph node queued behind an exclusion group stays pending without
+// stalling the graph, and the graph completes once the group frees.
+func TestSubmitGraphNodeQueuesBehindExclusion(t *testing.T) {
+	eng, rec := exclusionTestEngine(t, ExclusionQueue)
+
+	if _, err := eng.Submit(Task{Type: TaskConfigPatch, Params: step("holder")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "holder")
+
+	id, err := eng.SubmitGraph(TaskGraph{Nodes: []GraphNode{
+		{Name: "a", Type: TaskConfigPatch, Params: step("a")},
+		{Name: "b", Type: TaskConfigApply, Params: step("b"), DependsOn: []string{"a"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs := eng.GetGraph(id); nodeStatus(gs, "a") != TaskStatusPending {
+		t.Fatalf("node a status = %q, want pending", nodeStatus(gs, "a"))
+	}
+
+	rec.open()
+	if gs := waitForGraph(t, eng, id); gs.Phase != GraphPhaseCompleted {
+		t.Fatalf("phase = %q, want completed", gs.Phase)
+	}
+}
@@ -0,0 +1,486 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidGraph is returned by SubmitGraph when a graph is malformed:
+// empty, a duplicate or dangling node name, a cycle, or a node that would
+// itself be rejected by Submit.
+var ErrInvalidGraph = errors.New("invalid task graph")
+
+// GraphPhase is the aggregate lifecycle state of a TaskGraph.
+type GraphPhase string
+
+const (
+	// GraphPhaseRunning: at least one node has not reached a terminal state.
+	GraphPhaseRunning GraphPhase = "running"
+	// GraphPhaseCompleted: every node completed.
+	GraphPhaseCompleted GraphPhase = "completed"
+	// GraphPhaseFailed: every node is terminal and at least one failed, was
+	// cancelled, or was skipped.
+	GraphPhaseFailed GraphPhase = "failed"
+)
+
+// GraphNode is one task in a TaskGraph. Name is the node's key within the
+// graph and the target of other nodes' DependsOn edges; ID is the task ID the
+// node runs under (a UUID, generated when empty). The node is submitted as an
+// ordinary task once every node it depends on has completed.
+type GraphNode struct {
+	Name      string         `json:"name"`
+	ID        string         `json:"id,omitempty"`
+	Type      TaskType       `json:"type"`
+	Params    map[string]any `json:"params,omitempty"`
+	Timeout   time.Duration  `json:"timeout,omitempty"`
+	DependsOn []string       `json:"dependsOn,omitempty"`
+	// Started is set, and the graph saved, before the node's task is
+	// submitted. A started node whose task row has since been deleted or
+	// pruned counts as cancelled rather than unstarted, so it never runs
+	// twice.
+	Started bool `json:"started,omitempty"`
+}
+
+func (n GraphNode) task(submittedBy string) Task {
+	return Task{ID: n.ID, Type: n.Type, Params: n.Params, Timeout: n.Timeout, SubmittedBy: submittedBy}
+}
+
+// TaskGraph is a DAG of tasks submitted as one unit. The graph record holds
+// its shape, aggregate phase and which nodes have started; each node's
+// state is its task row, so a node is observable through the ordinary task
+// endpoints as well.
+type TaskGraph struct {
+	ID          string      `json:"id"`
+	Phase       GraphPhase  `json:"phase"`
+	Nodes       []GraphNode `json:"nodes"`
+	SubmittedAt time.Time   `json:"submittedAt"`
+	CompletedAt *time.Time  `json:"completedAt,omitempty"`
+	// SubmittedBy is who submitted the graph; its nodes' tasks carry it too.
+	SubmittedBy string `json:"submittedBy,omitempty"`
+}
+
+// GraphNodeStatus is a node's view in GraphStatus. Status is
+// TaskStatusPending until the node's dependencies complete and it starts.
+type GraphNodeStatus struct {
+	Name      string     `json:"name"`
+	TaskID    string     `json:"taskId"`
+	Type      TaskType   `json:"type"`
+	DependsOn []string   `json:"dependsOn,omitempty"`
+	Status    TaskStatus `json:"status"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// GraphStatus is the read view of a TaskGraph: its aggregate phase and the
+// current state of every node, in submission order.
+type GraphStatus struct {
+	ID          string            `json:"id"`
+	Phase       GraphPhase        `json:"phase"`
+	SubmittedAt time.Time         `json:"submittedAt"`
+	CompletedAt *time.Time        `json:"completedAt,omitempty"`
+	SubmittedBy string            `json:"submittedBy,omitempty"`
+	Nodes       []GraphNodeStatus `json:"nodes"`
+}
+
+// SubmitGraph validates and persists a task graph, starts every node with no
+// dependencies, and returns the graph ID. Later nodes start as their parents
+// complete; a node whose parent fails, is cancelled, or is skipped is
+// recorded Skipped, and the skip cascades to its own dependents. The call is
+// idempotent on the graph ID: resubmitting an existing graph returns its ID
+// unchanged. A new graph is refused with ErrDraining while the engine
+// drains, and a running graph starts no further nodes until it undrains.
+func (e *Engine) SubmitGraph(g TaskGraph) (string, error) {
+	if err := validateTaskID(g.ID); err != nil {
+		return "", err
+	}
+	if g.ID == "" {
+		g.ID = uuid.New().String()
+	}
+	nodes, err := e.validateGraph(g.Nodes)
+	if err != nil {
+		return "", err
+	}
+
+	e.graphMu.Lock()
+	defer e.graphMu.Unlock()
+
+	existing, err := e.store.GetGraph(g.ID)
+	if err != nil {
+		return "", fmt.Errorf("read task graph: %w", err)
+	}
+	if existing != nil {
+		return g.ID, nil
+	}
+	// A node ID that already names a task would read that task's row as the
+	// node's state: a Completed row satisfies dependents without the node
+	// ever running here.
+	for _, n := range nodes {
+		if _, ok := e.graphOf[n.ID]; ok {
+			return "", fmt.Errorf("%w: node %q: task ID %s belongs to another graph", ErrInvalidGraph, n.Name, n.ID)
+		}
+		r, err := e.store.Get(n.ID)
+		if err != nil {
+			return "", fmt.Errorf("read task %s: %w", n.ID, err)
+		}
+		if r != nil {
+			return "", fmt.Errorf("%w: node %q: task ID %s already exists", ErrInvalidGraph, n.Name, n.ID)
+		}
+	}
+	e.mu.Lock()
+	draining := e.drain != nil
+	e.mu.Unlock()
+	if draining {
+		return "", ErrDraining
+	}
+
+	graph := &TaskGraph{
+		ID:          g.ID,
+		Phase:       GraphPhaseRunning,
+		Nodes:       nodes,
+		SubmittedAt: time.Now().UTC(),
+		SubmittedBy: g.SubmittedBy,
+	}
+	if err := e.store.SaveGraph(graph); err != nil {
+		return "", fmt.Errorf("persist task graph: %w", err)
+	}
+	for _, n := range graph.Nodes {
+		e.graphOf[n.ID] = graph.ID
+	}
+
+	log.Info("task graph submitted", "id", graph.ID, "nodes", len(graph.Nodes))
+	e.advanceGraphLocked(graph)
+	return graph.ID, nil
+}
+
+// validateGraph checks a graph's shape and returns its nodes with task IDs
+// filled in. Every failure wraps ErrInvalidGraph.
+func (e *Engine) validateGraph(in []GraphNode) ([]GraphNode, error) {
+	if len(in) == 0 {
+		return nil, fmt.Errorf("%w: no nodes", ErrInvalidGraph)
+	}
+
+	nodes := make([]GraphNode, len(in))
+	byName := make(map[string]int, len(in))
+	taskIDs := make(map[string]string, len(in))
+	for i, n := range in {
+		if n.Name == "" {
+			return nil, fmt.Errorf("%w: node %d has no name", ErrInvalidGraph, i)
+		}
+		if _, dup := byName[n.Name]; dup {
+			return nil, fmt.Errorf("%w: duplicate node name %q", ErrInvalidGraph, n.Name)
+		}
+		handler, ok := e.handlers[n.Type]
+		if !ok {
+			return nil, fmt.Errorf("%w: node %q: unknown task type: %s", ErrInvalidGraph, n.Name, n.Type)
+		}
+		if err := checkParams(handler, n.Params); err != nil {
+			return nil, fmt.Errorf("%w: node %q: %w", ErrInvalidGraph, n.Name, err)
+		}
+		if err := validateTaskID(n.ID); err != nil {
+			return nil, fmt.Errorf("%w: node %q: %w", ErrInvalidGraph, n.Name, err)
+		}
+		if n.Timeout < 0 {
+			return nil, fmt.Errorf("%w: node %q: %w", ErrInvalidGraph, n.Name, ErrInvalidTimeout)
+		}
+		if n.ID == "" {
+			n.ID = uuid.New().String()
+		}
+		n.Started = false
+		if other, dup := taskIDs[n.ID]; dup {
+			return nil, fmt.Errorf("%w: nodes %q and %q share task ID %s", ErrInvalidGraph, other, n.Name, n.ID)
+		}
+		byName[n.Name] = i
+		taskIDs[n.ID] = n.Name
+		nodes[i] = n
+	}
+
+	// Kahn's algorithm: every node must be reachable from the roots, else
+	// the leftovers form a cycle.
+	indegree := make([]int, len(nodes))
+	children := make([][]int, len(nodes))
+	for i, n := range nodes {
+		for _, dep := range n.DependsOn {
+			p, ok := byName[dep]
+			if !ok {
+				return nil, fmt.Errorf("%w: node %q depends on unknown node %q", ErrInvalidGraph, n.Name, dep)
+			}
+			if p == i {
+				return nil, fmt.Errorf("%w: node %q depends on itself", ErrInvalidGraph, n.Name)
+			}
+			indegree[i]++
+			children[p] = append(children[p], i)
+		}
+	}
+	var queue []int
+	for i, d := range indegree {
+		if d == 0 {
+			queue = append(queue, i)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, c := range children[i] {
+			if indegree[c]--; indegree[c] == 0 {
+				queue = append(queue, c)
+			}
+		}
+	}
+	if visited != len(nodes) {
+		return nil, fmt.Errorf("%w: dependency cycle", ErrInvalidGraph)
+	}
+	return nodes, nil
+}
+
+// GetGraph returns a graph's current status, or nil when it does not exist
+// or cannot be read.
+func (e *Engine) GetGraph(id string) *GraphStatus {
+	g, err := e.store.GetGraph(id)
+	if err != nil {
+		log.Error("failed to get task graph", "id", id, "err", err)
+		return nil
+	}
+	if g == nil {
+		return nil
+	}
+	gs := &GraphStatus{
+		ID:          g.ID,
+		Phase:       g.Phase,
+		SubmittedAt: g.SubmittedAt,
+		CompletedAt: g.CompletedAt,
+		SubmittedBy: g.SubmittedBy,
+		Nodes:       make([]GraphNodeStatus, 0, len(g.Nodes)),
+	}
+	for _, n := range g.Nodes {
+		ns := GraphNodeStatus{Name: n.Name, TaskID: n.ID, Type: n.Type, DependsOn: n.DependsOn, Status: TaskStatusPending}
+		if r, err := e.store.Get(n.ID); err != nil {
+			log.Error("failed to get task graph node", "graph", g.ID, "node", n.Name, "err", err)
+		} else if r != nil {
+			ns.Status = r.Status
+			ns.Error = r.Error
+		} else if n.Started {
+			ns.Status = TaskStatusCancelled
+			ns.Error = graphNodeRowGone
+		}
+		gs.Nodes = append(gs.Nodes, ns)
+	}
+	return gs
+}
+
+// graphTaskSettled is called when a task reaches a terminal state. If the
+// task belongs to an active graph, the graph is advanced on a fresh goroutine
+// so the settling task's goroutine never waits on graphMu.
+func (e *Engine) graphTaskSettled(taskID string) {
+	e.graphMu.Lock()
+	graphID, ok := e.graphOf[taskID]
+	e.graphMu.Unlock()
+	if ok {
+		go e.advanceGraph(graphID)
+	}
+}
+
+// advanceGraph re-reads a graph from the store and advances it.
+func (e *Engine) advanceGraph(id string) {
+	e.graphMu.Lock()
+	defer e.graphMu.Unlock()
+
+	g, err := e.store.GetGraph(id)
+	if err != nil {
+		log.Error("failed to read task graph", "id", id, "err", err)
+		return
+	}
+	if g == nil || g.Phase != GraphPhaseRunning {
+		return
+	}
+	e.advanceGraphLocked(g)
+}
+
+// advanceGraphLocked starts every unstarted node whose dependencies have all
+// completed, skips every unstarted node with a failed or skipped dependency,
+// and repeats until no node changes. Once every node is terminal it records
+// the graph's final phase. Node state is read from the store each pass, so
+// the same call both drives a fresh graph and resumes a rehydrated one.
+// Callers MUST hold e.graphMu.
+func (e *Engine) advanceGraphLocked(g *TaskGraph) {
+	for {
+		states, ok := e.graphNodeStates(g)
+		if !ok {
+			return
+		}
+
+		progressed := false
+		for i, n := range g.Nodes {
+			if states[n.Name] != "" {
+				continue // already started, queued, or finished
+			}
+			ready, blockedBy := true, ""
+			for _, dep := range n.DependsOn {
+				switch states[dep] {
+				case TaskStatusCompleted:
+				case TaskStatusFailed, TaskStatusSkipped, TaskStatusCancelled:
+					blockedBy = dep
+				default:
+					ready = false
+				}
+			}
+			switch {
+			case blockedBy != "":
+				e.skipGraphNode(g, n, blockedBy)
+				states[n.Name] = TaskStatusSkipped
+				progressed = true
+			case ready && e.holdsGraphNode(n):
+				// Left unstarted; Undrain advances the graph again.
+			case ready:
+				if !e.startGraphNode(g, i) {
+					continue // left for the next settle to retry
+				}
+				if _, err := e.Submit(n.task(g.SubmittedBy)); err != nil {
+					log.Error("failed to start task graph node", "graph", g.ID, "node", n.Name, "err", err)
+					e.finishGraphNode(n, TaskStatusFailed, fmt.Sprintf("start node: %v", err))
+				}
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	states, ok := e.graphNodeStates(g)
+	if !ok {
+		return
+	}
+	phase := GraphPhaseCompleted
+	for _, st := range states {
+		switch st {
+		case TaskStatusCompleted:
+		case TaskStatusFailed, TaskStatusSkipped, TaskStatusCancelled:
+			phase = GraphPhaseFailed
+		default:
+			return // still in flight
+		}
+	}
+
+	t := time.Now().UTC()
+	g.Phase = phase
+	g.CompletedAt = &t
+	if err := e.store.SaveGraph(g); err != nil {
+		log.Error("failed to persist task graph phase", "id", g.ID, "err", err)
+		return
+	}
+	for _, n := range g.Nodes {
+		delete(e.graphOf, n.ID)
+	}
+	log.Info("task graph finished", "id", g.ID, "phase", phase)
+}
+
+// holdsGraphNode reports whether a drain in progress keeps n from
+// starting.
+func (e *Engine) holdsGraphNode(n GraphNode) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.drainHolds(n.Type)
+}
+
+// startGraphNode marks g's i'th node started and saves the graph, before
+// its task is submitted. It reports false, leaving the node unstarted, when
+// the save fails.
+func (e *Engine) startGraphNode(g *TaskGraph, i int) bool {
+	g.Nodes[i].Started = true
+	if err := e.store.SaveGraph(g); err != nil {
+		log.Error("failed to persist task graph node start", "graph", g.ID, "node", g.Nodes[i].Name, "err", err)
+		g.Nodes[i].Started = false
+		return false
+	}
+	return true
+}
+
+// graphNodeRowGone is the error GetGraph reports for a started node
+// whose task row no longer exists.
+const graphNodeRowGone = "task row removed after the node started"
+
+// graphNodeStates maps each node name to its task row's status, or "" when
+// the node has not been started and has no row yet. A started node with no
+// row maps to TaskStatusCancelled. ok is false on a store error, in which
+// case the graph is left for the next settle to advance.
+func (e *Engine) graphNodeStates(g *TaskGraph) (map[string]TaskStatus, bool) {
+	states := make(map[string]TaskStatus, len(g.Nodes))
+	for _, n := range g.Nodes {
+		r, err := e.store.Get(n.ID)
+		if err != nil {
+			log.Error("failed to read task graph node", "graph", g.ID, "node", n.Name, "err", err)
+			return nil, false
+		}
+		switch {
+		case r != nil:
+			states[n.Name] = r.Status
+		case n.Started:
+			states[n.Name] = TaskStatusCancelled
+		default:
+			states[n.Name] = ""
+		}
+	}
+	return states, true
+}
+
+// skipGraphNode records a node that will never run because dep failed, was
+// cancelled, or was skipped.
+func (e *Engine) skipGraphNode(g *TaskGraph, n GraphNode, dep string) {
+	log.Info("skipping task graph node", "graph", g.ID, "node", n.Name, "dependency", dep)
+	e.finishGraphNode(n, TaskStatusSkipped, fmt.Sprintf("dependency %q did not complete", dep))
+}
+
+// finishGraphNode persists a terminal row for a node the engine never ran.
+func (e *Engine) finishGraphNode(n GraphNode, status TaskStatus, msg string) {
+	t := time.Now().UTC()
+	tr := &TaskResult{
+		ID:          n.ID,
+		Type:        string(n.Type),
+		Status:      status,
+		Run:         1,
+		Attempt:     1,
+		Params:      n.Params,
+		Error:       msg,
+		SubmittedAt: t,
+		CompletedAt: &t,
+	}
+	if err := e.store.Save(tr); err != nil {
+		log.Error("failed to persist task graph node", "node", n.Name, "id", n.ID, "err", err)
+		return
+	}
+	e.publishSettled(tr)
+}
+
+// rehydrateGraphs re-registers the nodes of every graph a previous process
+// left running, so their tasks' completions advance the graph again. It runs
+// before stale tasks are re-dispatched; resumeGraphs then advances each graph
+// once to start nodes whose parents completed just before the crash.
+func (e *Engine) rehydrateGraphs() []string {
+	graphs, err := e.store.ListActiveGraphs()
+	if err != nil {
+		log.Error("failed to list active task graphs", "err", err)
+		return nil
+	}
+	e.graphMu.Lock()
+	defer e.graphMu.Unlock()
+	ids := make([]string, 0, len(graphs))
+	for _, g := range graphs {
+		for _, n := range g.Nodes {
+			e.graphOf[n.ID] = g.ID
+		}
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+// resumeGraphs advances each rehydrated graph once.
+func (e *Engine) resumeGraphs(ids []string) {
+	for _, id := range ids {
+		log.Info("resuming task graph", "id", id)
+		e.advanceGraph(id)
+	}
+}
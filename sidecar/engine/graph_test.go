@@ -0,0 +1,265 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// waitForGraph polls until the graph leaves GraphPhaseRunning.
+func waitForGraph(t *testing.T, eng *Engine, id string) *GraphStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if gs := eng.GetGraph(id); gs != nil && gs.Phase != GraphPhaseRunning {
+			return gs
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for graph %s", id)
+	return nil
+}
+
+func nodeStatus(gs *GraphStatus, name string) TaskStatus {
+	for _, n := range gs.Nodes {
+		if n.Name == name {
+			return n.Status
+		}
+	}
+	return ""
+}
+
+// orderRecorder is a handler that records the "step" param of every run.
+type orderRecorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (o *orderRecorder) handler(_ context.Context, params map[string]any) (json.RawMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	step, _ := params["step"].(string)
+	o.steps = append(o.steps, step)
+	if params["fail"] == true {
+		return nil, errors.New("boom")
+	}
+	return nil, nil
+}
+
+func (o *orderRecorder) ran() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.steps...)
+}
+
+func TestSubmitGraphRunsNodesInDependencyOrder(t *testing.T) {
+	rec := &orderRecorder{}
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: rec.handler})
+
+	id, err := eng.SubmitGraph(TaskGraph{SubmittedBy: "alice", Nodes: []GraphNode{
+		{Name: "apply", Type: TaskConfigPatch, Params: map[string]any{"step": "apply"}, DependsOn: []string{"genesis", "state-sync"}},
+		{Name: "restore", Type: TaskConfigPatch, Params: map[string]any{"step": "restore"}},
+		{Name: "genesis", Type: TaskConfigPatch, Params: map[string]any{"step": "genesis"}, DependsOn: []string{"restore"}},
+		{Name: "state-sync", Type: TaskConfigPatch, Params: map[string]any{"step": "state-sync"}, DependsOn: []string{"restore"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gs := waitForGraph(t, eng, id)
+	if gs.Phase != GraphPhaseCompleted {
+		t.Fatalf("phase = %q, want completed", gs.Phase)
+	}
+	if gs.SubmittedBy != "alice" {
+		t.Fatalf("graph submittedBy = %q, want alice", gs.SubmittedBy)
+	}
+	for _, n := range gs.Nodes {
+		if r := eng.GetResult(n.TaskID); r == nil || r.SubmittedBy != "alice" {
+			t.Fatalf("node %s result = %+v, want submittedBy alice", n.Name, r)
+		}
+	}
+	ran := rec.ran()
+	if len(ran) != 4 || ran[0] != "restore" || ran[3] != "apply" {
+		t.Fatalf("run order = %v, want restore first and apply last", ran)
+	}
+	if gs.CompletedAt == nil {
+		t.Fatal("CompletedAt not set on a finished graph")
+	}
+}
+
+func TestSubmitGraphFailureCascadesSkipped(t *testing.T) {
+	rec := &orderRecorder{}
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: rec.handler})
+
+	id, err := eng.SubmitGraph(TaskGraph{Nodes: []GraphNode{
+		{Name: "a", Type: TaskConfigPatch, Params: map[string]any{"step": "a", "fail": true}},
+		{Name: "b", Type: TaskConfigPatch, Params: map[string]any{"step": "b"}, DependsOn: []string{"a"}},
+		{Name: "c", Type: TaskConfigPatch, Params: map[string]any{"step": "c"}, DependsOn: []string{"b"}},
+		{Name: "d", Type: TaskConfigPatch, Params: map[string]any{"step": "d"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gs := waitForGraph(t, eng, id)
+	if gs.Phase != GraphPhaseFailed {
+		t.Fatalf("phase = %q, want failed", gs.Phase)
+	}
+	want := map[string]TaskStatus{"a": TaskStatusFailed, "b": TaskStatusSkipped, "c": TaskStatusSkipped, "d": TaskStatusCompleted}
+	for name, st := range want {
+		if got := nodeStatus(gs, name); got != st {
+			t.Errorf("node %s status = %q, want %q", name, got, st)
+		}
+	}
+	for _, step := range rec.ran() {
+		if step == "b" || step == "c" {
+			t.Fatalf("skipped node %s ran", step)
+		}
+	}
+}
+
+func TestSubmitGraphRejectsInvalidShapes(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: (&orderRecorder{}).handler})
+	cases := map[string][]GraphNode{
+		"empty":          nil,
+		"unnamed":        {{Type: TaskConfigPatch}},
+		"duplicate name": {{Name: "a", Type: TaskConfigPatch}, {Name: "a", Type: TaskConfigPatch}},
+		"unknown dep":    {{Name: "a", Type: TaskConfigPatch, DependsOn: []string{"x"}}},
+		"self dep":       {{Name: "a", Type: TaskConfigPatch, DependsOn: []string{"a"}}},
+		"cycle": {
+			{Name: "a", Type: TaskConfigPatch, DependsOn: []string{"b"}},
+			{Name: "b", Type: TaskConfigPatch, DependsOn: []string{"a"}},
+		},
+		"unknown type": {{Name: "a", Type: "nope"}},
+		"bad task id":  {{Name: "a", ID: "not-a-uuid", Type: TaskConfigPatch}},
+	}
+	for name, nodes := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := eng.SubmitGraph(TaskGraph{Nodes: nodes}); !errors.Is(err, ErrInvalidGraph) {
+				t.Fatalf("err = %v, want ErrInvalidGraph", err)
+			}
+		})
+	}
+}
+
+func TestSubmitGraphIsIdempotent(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: (&orderRecorder{}).handler})
+	g := TaskGraph{ID: uuid.New().String(), Nodes: []GraphNode{{Name: "a", Type: TaskConfigPatch}}}
+
+	first, err := eng.SubmitGraph(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForGraph(t, eng, first)
+	second, err := eng.SubmitGraph(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatalf("resubmit returned %s, want %s", second, first)
+	}
+}
+
+// A graph persisted mid-flight — parent completed, child never started — is
+// resumed by rehydration: the child starts and the graph completes.
+func TestRehydrateResumesHalfFinishedGraph(t *testing.T) {
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	parent, child := uuid.New().String(), uuid.New().String()
+	graph := &TaskGraph{
+		ID:          uuid.New().String(),
+		Phase:       GraphPhaseRunning,
+		SubmittedAt: time.Now().UTC(),
+		Nodes: []GraphNode{
+			{Name: "parent", ID: parent, Type: TaskConfigPatch},
+			{Name: "child", ID: child, Type: TaskConfigPatch, DependsOn: []string{"parent"}},
+		},
+	}
+	if err := store.SaveGraph(graph); err != nil {
+		t.Fatal(err)
+	}
+	done := time.Now().UTC()
+	if err := store.Save(&TaskResult{
+		ID: parent, Type: string(TaskConfigPatch), Status: TaskStatusCompleted,
+		Run: 1, Attempt: 1, SubmittedAt: done, CompletedAt: &done,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &orderRecorder{}
+	eng := engineOver(t, store, map[TaskType]TaskHandler{TaskConfigPatch: rec.handler})
+	eng.RehydrateStaleTasks()
+
+	gs := waitForGraph(t, eng, graph.ID)
+	if gs.Phase != GraphPhaseCompleted {
+		t.Fatalf("phase = %q, want completed", gs.Phase)
+	}
+	if n := len(rec.ran()); n != 1 {
+		t.Fatalf("handler ran %d times, want 1 (only the child)", n)
+	}
+}
+
+func TestSubmitGraphRejectsExistingTaskID(t *testing.T) {
+	eng := newTestEngine(t, map[TaskType]TaskHandler{TaskConfigPatch: (&orderRecorder{}).handler})
+	id, err := eng.Submit(Task{ID: uuid.New().String(), Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForResult(t, eng, id)
+
+	_, err = eng.SubmitGraph(TaskGraph{Nodes: []GraphNode{{Name: "a", ID: id, Type: TaskConfigPatch}}})
+	if !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("err = %v, want ErrInvalidGraph", err)
+	}
+}
+
+// A node recorded as started whose task row has since been removed is not
+// submitted again on resume: it counts as cancelled and its dependents are
+// skipped.
+func TestRehydrateDoesNotRerunStartedNodeWithoutRow(t *testing.T) {
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	graph := &TaskGraph{
+		ID:          uuid.New().String(),
+		Phase:       GraphPhaseRunning,
+		SubmittedAt: time.Now().UTC(),
+		Nodes: []GraphNode{
+			{Name: "parent", ID: uuid.New().String(), Type: TaskConfigPatch, Started: true},
+			{Name: "child", ID: uuid.New().String(), Type: TaskConfigPatch, DependsOn: []string{"parent"}},
+		},
+	}
+	if err := store.SaveGraph(graph); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &orderRecorder{}
+	eng := engineOver(t, store, map[TaskType]TaskHandler{TaskConfigPatch: rec.handler})
+	eng.RehydrateStaleTasks()
+
+	gs := waitForGraph(t, eng, graph.ID)
+	if gs.Phase != GraphPhaseFailed {
+		t.Fatalf("phase = %q, want failed", gs.Phase)
+	}
+	if got := nodeStatus(gs, "parent"); got != TaskStatusCancelled {
+		t.Fatalf("parent = %q, want cancelled", got)
+	}
+	if got := nodeStatus(gs, "child"); got != TaskStatusSkipped {
+		t.Fatalf("child = %q, want skipped", got)
+	}
+	if n := len(rec.ran()); n != 0 {
+		t.Fatalf("handler ran %d times, want 0", n)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package engine
+
+import (
+	"errors"
+	"os"
+)
+
+// lockJournal refuses to open the journal: flock is unix-only, and without
+// it nothing would stop a second process from appending to, or compacting,
+// a journal this one holds.
+func lockJournal(path string) (*os.File, error) {
+	return nil, errors.New("the journal store needs flock, which this platform lacks; use the sqlite store")
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockJournal takes an exclusive, non-blocking flock on path, creating it,
+// and returns the open lock file; closing it releases the lock. Linux NFS
+// clients implement flock with server-side locks, so the lock holds across
+// pods sharing a volume.
+func lockJournal(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w: %s", ErrJournalLocked, path)
+		}
+		return nil, err
+	}
+	return f, nil
+}
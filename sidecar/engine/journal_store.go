@@ -0,0 +1,752 @@
+package engine
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// journalVersion is the journal format this binary writes. Opening a
+// journal written by a newer format fails rather than misreading it.
+const journalVersion = 1
+
+// A journal is compacted once it holds at least journalCompactMinRecords
+// records and journalCompactFactor times as many records as live entries.
+const (
+	journalCompactMinRecords = 1000
+	journalCompactFactor     = 4
+)
+
+// journalAuditKeep is how many audit entries a journal keeps. The journal
+// is held in memory, so unlike SQLiteStore it cannot keep the whole audit
+// log; older entries are dropped as new ones are appended, as events are.
+const journalAuditKeep = 10000
+
+// ErrJournalLocked is returned by NewJournalStore when another process
+// holds the journal open.
+var ErrJournalLocked = errors.New("journal is locked by another process")
+
+type journalOp string
+
+const (
+	opHeader         journalOp = "header"
+	opResult         journalOp = "result"
+	opProgress       journalOp = "progress"
+	opDeleteResults  journalOp = "delete-results"
+	opGraph          journalOp = "graph"
+	opSchedule       journalOp = "schedule"
+	opDeleteSchedule journalOp = "delete-schedule"
+	opMarker         journalOp = "marker"
+	opEvent          journalOp = "event"
+	opAudit          journalOp = "audit"
+)
+
+// journalRecord is one line of the journal. Op selects which of the other
+// fields it carries.
+type journalRecord struct {
+	Op journalOp `json:"op"`
+
+	// Header fields; the header is always the first record.
+	Version      int   `json:"version,omitempty"`
+	LastSeq      int64 `json:"lastSeq,omitempty"`
+	LastAuditSeq int64 `json:"lastAuditSeq,omitempty"`
+
+	ID       string      `json:"id,omitempty"`
+	IDs      []string    `json:"ids,omitempty"`
+	Result   *TaskResult `json:"result,omitempty"`
+	Progress *Progress   `json:"progress,omitempty"`
+	Graph    *TaskGraph  `json:"graph,omitempty"`
+	Schedule *Schedule   `json:"schedule,omitempty"`
+	Marker   *TxMarker   `json:"marker,omitempty"`
+	Event    *TaskEvent  `json:"event,omitempty"`
+	Keep     int         `json:"keep,omitempty"`
+	Audit    *AuditEntry `json:"audit,omitempty"`
+}
+
+// JournalStore persists task results in an append-only JSON-lines journal.
+// Every mutation appends one record; opening the store replays the journal
+// into memory, and compaction rewrites it as a snapshot of the live state.
+//
+// Unlike SQLiteStore it needs no shared memory or locking within the
+// database file, only appends, fsync, an atomic rename and one whole-file
+// lock, so the journal may live on a network filesystem (NFS, EFS, Azure
+// Files). Only one process may open a journal at a time: NewJournalStore
+// holds an exclusive flock on a lock file beside it until Close, since
+// compaction renames the journal itself out from under any other writer.
+// The whole store is held in memory; a RetentionPolicy keeps it bounded.
+//
+// Results, graphs, schedules, markers, events, audit entries and deletions
+// are fsynced as they are written, so a crash never forgets that a task
+// completed, brings back a task an operator deleted, or reuses an event
+// sequence number a client has already seen. Progress and retention
+// pruning, which removes only settled results, are not: losing either
+// costs only a stale view.
+//
+// Only the newest journalAuditKeep audit entries are kept.
+type JournalStore struct {
+	path string
+	lock *os.File
+
+	// failed is set once a write could not be undone, after which the
+	// journal's tail is unknown and every write is refused.
+	mu        sync.Mutex
+	f         *os.File
+	failed    error
+	records   int
+	results   map[string]*TaskResult
+	graphs    map[string]*TaskGraph
+	schedules map[string]*Schedule
+	markers   map[string]*TxMarker
+	events    []TaskEvent
+	lastSeq   int64
+	audit     []AuditEntry
+
+	lastAuditSeq int64
+}
+
+// NewJournalStore locks and opens (or creates) the journal at path and
+// replays it. It fails with ErrJournalLocked while another process holds
+// the journal. A record torn by a crash mid-append is dropped. The journal
+// is then rewritten as a snapshot, so every open starts compact.
+func NewJournalStore(path string) (*JournalStore, error) {
+	lock, err := lockJournal(path + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("lock journal %s: %w", path, err)
+	}
+	s := &JournalStore{
+		path:      path,
+		lock:      lock,
+		results:   map[string]*TaskResult{},
+		graphs:    map[string]*TaskGraph{},
+		schedules: map[string]*Schedule{},
+		markers:   map[string]*TxMarker{},
+	}
+	if err := s.replay(); err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("replay journal %s: %w", path, err)
+	}
+	if err := s.compact(); err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("compact journal %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *JournalStore) replay() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(b) > 0 {
+				log.Warn("dropping torn journal record", "path", s.path, "line", line)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var rec journalRecord
+		if err := unmarshalStored(b, &rec); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if line == 1 {
+			if rec.Op != opHeader {
+				return fmt.Errorf("line 1 is %q, not a journal header", rec.Op)
+			}
+			if rec.Version > journalVersion {
+				return fmt.Errorf("journal format version %d is newer than supported version %d", rec.Version, journalVersion)
+			}
+		}
+		s.apply(&rec)
+		s.records++
+	}
+}
+
+// apply folds one record into the in-memory state. Records are applied
+// only after they are written, so memory never runs ahead of the journal.
+func (s *JournalStore) apply(rec *journalRecord) {
+	switch rec.Op {
+	case opHeader:
+		s.lastSeq = max(s.lastSeq, rec.LastSeq)
+		s.lastAuditSeq = max(s.lastAuditSeq, rec.LastAuditSeq)
+	case opResult:
+		s.results[rec.Result.ID] = rec.Result
+	case opProgress:
+		if r, ok := s.results[rec.ID]; ok && r.Status == TaskStatusRunning {
+			r.Progress = rec.Progress
+		}
+	case opDeleteResults:
+		for _, id := range rec.IDs {
+			delete(s.results, id)
+		}
+	case opGraph:
+		s.graphs[rec.Graph.ID] = rec.Graph
+	case opSchedule:
+		s.schedules[rec.Schedule.ID] = rec.Schedule
+	case opDeleteSchedule:
+		delete(s.schedules, rec.ID)
+	case opMarker:
+		s.markers[rec.Marker.TaskID] = rec.Marker
+	case opEvent:
+		s.events = append(s.events, *rec.Event)
+		s.lastSeq = max(s.lastSeq, rec.Event.Seq)
+		if rec.Keep > 0 {
+			s.events = slices.DeleteFunc(s.events, func(ev TaskEvent) bool {
+				return ev.Seq <= rec.Event.Seq-int64(rec.Keep)
+			})
+		}
+	case opAudit:
+		s.audit = append(s.audit, *rec.Audit)
+		s.lastAuditSeq = max(s.lastAuditSeq, rec.Audit.Seq)
+		if n := len(s.audit) - journalAuditKeep; n > 0 {
+			s.audit = slices.Delete(s.audit, 0, n)
+		}
+	}
+}
+
+// write appends rec to the journal, fsyncing it when sync is set, and
+// applies the record as it will read back on replay. It compacts the
+// journal once dead records dominate. Called with mu held.
+//
+// A failed append (ENOSPC, or EIO on a network filesystem) may leave part
+// of the record behind, which the next append would run on from and replay
+// would then reject, so the journal is truncated back to where the record
+// began. When that fails too, or an fsync fails and the kernel may have
+// dropped the written pages, the store is marked failed.
+func (s *JournalStore) write(rec journalRecord, sync bool) error {
+	if s.failed != nil {
+		return fmt.Errorf("journal %s failed: %w", s.path, s.failed)
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal journal record: %w", err)
+	}
+	info, err := s.f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := s.f.Write(append(b, '\n')); err != nil {
+		if terr := s.f.Truncate(info.Size()); terr != nil {
+			s.failed = fmt.Errorf("truncating torn record: %w (after append error: %v)", terr, err)
+			log.Error("journal failed; refusing further writes", "path", s.path, "err", s.failed)
+		}
+		return err
+	}
+	if sync {
+		if err := s.f.Sync(); err != nil {
+			s.failed = fmt.Errorf("fsync: %w", err)
+			log.Error("journal failed; refusing further writes", "path", s.path, "err", s.failed)
+			return err
+		}
+	}
+	// Decoding the written bytes, rather than keeping rec, leaves no state
+	// shared with the caller.
+	var applied journalRecord
+	if err := unmarshalStored(b, &applied); err != nil {
+		return fmt.Errorf("unmarshal journal record: %w", err)
+	}
+	s.apply(&applied)
+	s.records++
+
+	if s.records >= journalCompactMinRecords && s.records >= journalCompactFactor*s.live() {
+		if err := s.compact(); err != nil {
+			log.Warn("failed to compact journal", "path", s.path, "err", err)
+		}
+	}
+	return nil
+}
+
+func (s *JournalStore) live() int {
+	return 1 + len(s.results) + len(s.graphs) + len(s.schedules) + len(s.markers) + len(s.events) + len(s.audit)
+}
+
+// compact rewrites the journal as a header followed by one record per live
+// entry. The snapshot is written beside the journal, fsynced and renamed
+// over it, so a crash leaves either the old journal or the new one. Called
+// with mu held, or before the store is shared.
+func (s *JournalStore) compact() error {
+	recs := []journalRecord{{Op: opHeader, Version: journalVersion, LastSeq: s.lastSeq, LastAuditSeq: s.lastAuditSeq}}
+	for _, r := range sortedValues(s.results) {
+		recs = append(recs, journalRecord{Op: opResult, Result: r})
+	}
+	for _, g := range sortedValues(s.graphs) {
+		recs = append(recs, journalRecord{Op: opGraph, Graph: g})
+	}
+	for _, sc := range sortedValues(s.schedules) {
+		recs = append(recs, journalRecord{Op: opSchedule, Schedule: sc})
+	}
+	for _, m := range sortedValues(s.markers) {
+		recs = append(recs, journalRecord{Op: opMarker, Marker: m})
+	}
+	for i := range s.events {
+		recs = append(recs, journalRecord{Op: opEvent, Event: &s.events[i]})
+	}
+	for i := range s.audit {
+		recs = append(recs, journalRecord{Op: opAudit, Audit: &s.audit[i]})
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if err := syncDir(filepath.Dir(s.path)); err != nil {
+		return err
+	}
+
+	appendFile, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	if s.f != nil {
+		s.f.Close()
+	}
+	s.f, s.records = appendFile, len(recs)
+	return nil
+}
+
+// syncDir fsyncs a directory so a rename within it is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// sortedValues returns a map's values ordered by key, so snapshots are
+// deterministic.
+func sortedValues[T any](m map[string]*T) []*T {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	out := make([]*T, len(keys))
+	for i, k := range keys {
+		out[i] = m[k]
+	}
+	return out
+}
+
+// clone deep-copies a stored value through JSON, the same encoding the
+// journal holds, so callers never share state with the store.
+func clone[T any](v *T) (*T, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	out := new(T)
+	if err := unmarshalStored(b, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// cloneAll copies vs into a new slice, stopping after limit values when
+// limit is non-negative.
+func cloneAll[T any](vs []*T, limit int) ([]T, error) {
+	if limit >= 0 && len(vs) > limit {
+		vs = vs[:limit]
+	}
+	var out []T
+	for _, v := range vs {
+		c, err := clone(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+// selectResults returns the stored results keep accepts, newest first by
+// submission time with ties broken by descending ID.
+func (s *JournalStore) selectResults(keep func(r *TaskResult) bool) []*TaskResult {
+	var out []*TaskResult
+	for _, r := range s.results {
+		if keep(r) {
+			out = append(out, r)
+		}
+	}
+	slices.SortFunc(out, func(a, b *TaskResult) int {
+		if c := b.SubmittedAt.Compare(a.SubmittedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return out
+}
+
+func (s *JournalStore) Save(r *TaskResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(journalRecord{Op: opResult, Result: r}, true)
+}
+
+func (s *JournalStore) SaveProgress(id string, p *Progress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.results[id]; !ok || r.Status != TaskStatusRunning {
+		return nil
+	}
+	return s.write(journalRecord{Op: opProgress, ID: id, Progress: p}, false)
+}
+
+func (s *JournalStore) Get(id string) (*TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.results[id]
+	if !ok {
+		return nil, nil
+	}
+	return clone(r)
+}
+
+func (s *JournalStore) List(limit int) ([]TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneAll(s.selectResults(func(*TaskResult) bool { return true }), limit)
+}
+
+func (s *JournalStore) QueryResults(f ResultFilter) ([]TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneAll(s.selectResults(func(r *TaskResult) bool {
+		switch {
+		case f.Type != "" && r.Type != f.Type,
+			f.Status != "" && r.Status != f.Status,
+			!f.SubmittedAfter.IsZero() && r.SubmittedAt.Before(f.SubmittedAfter),
+			!f.SubmittedBefore.IsZero() && !r.SubmittedAt.Before(f.SubmittedBefore):
+			return false
+		case f.After != nil:
+			c := r.SubmittedAt.Compare(f.After.SubmittedAt)
+			return c < 0 || (c == 0 && r.ID < f.After.ID)
+		}
+		return true
+	}), f.Limit)
+}
+
+func (s *JournalStore) ListStaleTasks() ([]TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneAll(s.selectResults(func(r *TaskResult) bool { return r.Status == TaskStatusRunning }), -1)
+}
+
+func (s *JournalStore) ListPendingTasks() ([]TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.selectResults(func(r *TaskResult) bool { return r.Status == TaskStatusPending })
+	slices.SortStableFunc(pending, func(a, b *TaskResult) int {
+		if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+			return c
+		}
+		return a.SubmittedAt.Compare(b.SubmittedAt)
+	})
+	return cloneAll(pending, -1)
+}
+
+func (s *JournalStore) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.results[id]; !ok {
+		return false, nil
+	}
+	if err := s.write(journalRecord{Op: opDeleteResults, IDs: []string{id}}, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *JournalStore) DeleteByType(taskType string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id, r := range s.results {
+		if r.Type == taskType {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	slices.Sort(ids)
+	if err := s.write(journalRecord{Op: opDeleteResults, IDs: ids}, true); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+func (s *JournalStore) LatestByType(taskType string) (*TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matches := s.selectResults(func(r *TaskResult) bool { return r.Type == taskType })
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return clone(matches[0])
+}
+
+func (s *JournalStore) PruneResults(before time.Time, maxPerType int, keep []string) (PruneCounts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := PruneCounts{ByAge: map[string]int{}, ByCount: map[string]int{}}
+	pruned := map[string]bool{}
+	settled := s.selectResults(func(r *TaskResult) bool {
+		return r.Status != TaskStatusPending && r.Status != TaskStatusRunning && !slices.Contains(keep, r.ID)
+	})
+	if !before.IsZero() {
+		for _, r := range settled {
+			at := r.SubmittedAt
+			if r.CompletedAt != nil {
+				at = *r.CompletedAt
+			}
+			if at.Before(before) {
+				pruned[r.ID] = true
+				counts.ByAge[r.Type]++
+			}
+		}
+	}
+	if maxPerType > 0 {
+		kept := map[string]int{}
+		for _, r := range settled {
+			if pruned[r.ID] {
+				continue
+			}
+			if kept[r.Type]++; kept[r.Type] > maxPerType {
+				pruned[r.ID] = true
+				counts.ByCount[r.Type]++
+			}
+		}
+	}
+	if len(pruned) == 0 {
+		return counts, nil
+	}
+
+	ids := make([]string, 0, len(pruned))
+	for id := range pruned {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return counts, s.write(journalRecord{Op: opDeleteResults, IDs: ids}, false)
+}
+
+func (s *JournalStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compact()
+}
+
+func (s *JournalStore) SaveGraph(g *TaskGraph) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := journalRecord{Op: opGraph, Graph: g}
+	if existing, ok := s.graphs[g.ID]; ok {
+		// Nodes and submission time are fixed by the first save; only a
+		// node's Started flag can change, and never back to false.
+		merged := *existing
+		merged.Phase, merged.CompletedAt = g.Phase, g.CompletedAt
+		started := make(map[string]bool, len(g.Nodes))
+		for _, n := range g.Nodes {
+			started[n.Name] = n.Started
+		}
+		merged.Nodes = make([]GraphNode, len(existing.Nodes))
+		for i, n := range existing.Nodes {
+			n.Started = n.Started || started[n.Name]
+			merged.Nodes[i] = n
+		}
+		rec.Graph = &merged
+	}
+	return s.write(rec, true)
+}
+
+func (s *JournalStore) GetGraph(id string) (*TaskGraph, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.graphs[id]
+	if !ok {
+		return nil, nil
+	}
+	return clone(g)
+}
+
+func (s *JournalStore) ListActiveGraphs() ([]TaskGraph, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var active []*TaskGraph
+	for _, g := range sortedValues(s.graphs) {
+		if g.Phase == GraphPhaseRunning {
+			active = append(active, g)
+		}
+	}
+	slices.SortStableFunc(active, func(a, b *TaskGraph) int { return a.SubmittedAt.Compare(b.SubmittedAt) })
+	return cloneAll(active, -1)
+}
+
+func (s *JournalStore) SaveSchedule(sc *Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(journalRecord{Op: opSchedule, Schedule: sc}, true)
+}
+
+func (s *JournalStore) GetSchedule(id string) (*Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc, ok := s.schedules[id]
+	if !ok {
+		return nil, nil
+	}
+	return clone(sc)
+}
+
+func (s *JournalStore) ListSchedules() ([]Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedules := sortedValues(s.schedules)
+	slices.SortStableFunc(schedules, func(a, b *Schedule) int { return a.NextRunAt.Compare(b.NextRunAt) })
+	return cloneAll(schedules, -1)
+}
+
+func (s *JournalStore) DeleteSchedule(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[id]; !ok {
+		return false, nil
+	}
+	if err := s.write(journalRecord{Op: opDeleteSchedule, ID: id}, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SaveTxMarker persists a pre-broadcast marker and fsyncs the journal
+// before returning, so it survives a crash. Callers MUST let it return
+// before broadcasting.
+func (s *JournalStore) SaveTxMarker(m *TxMarker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(journalRecord{Op: opMarker, Marker: m}, true)
+}
+
+func (s *JournalStore) GetTxMarker(taskID string) (*TxMarker, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.markers[taskID]
+	if !ok {
+		return nil, nil
+	}
+	return clone(m)
+}
+
+func (s *JournalStore) AppendEvent(ev *TaskEvent, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logged := *ev
+	logged.Seq = s.lastSeq + 1
+	if err := s.write(journalRecord{Op: opEvent, Event: &logged, Keep: keep}, true); err != nil {
+		return err
+	}
+	ev.Seq = logged.Seq
+	return nil
+}
+
+func (s *JournalStore) ListEvents(after int64, limit int) ([]TaskEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*TaskEvent
+	for i := range s.events {
+		if s.events[i].Seq > after {
+			out = append(out, &s.events[i])
+		}
+	}
+	return cloneAll(out, limit)
+}
+
+// AppendAudit numbers entries after the highest sequence number ever
+// logged, which the compaction header carries once older entries are
+// dropped.
+func (s *JournalStore) AppendAudit(a *AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logged := *a
+	logged.Seq = s.lastAuditSeq + 1
+	if err := s.write(journalRecord{Op: opAudit, Audit: &logged}, true); err != nil {
+		return err
+	}
+	a.Seq = logged.Seq
+	return nil
+}
+
+func (s *JournalStore) ListAudit(after int64, limit int) ([]AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*AuditEntry
+	for i := range s.audit {
+		if s.audit[i].Seq > after {
+			out = append(out, &s.audit[i])
+		}
+	}
+	return cloneAll(out, limit)
+}
+
+func (s *JournalStore) Ping() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failed != nil {
+		return fmt.Errorf("journal %s failed: %w", s.path, s.failed)
+	}
+	_, err := s.f.Stat()
+	return err
+}
+
+// Close closes the journal and releases its lock.
+func (s *JournalStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.f.Close()
+	if lerr := s.lock.Close(); err == nil {
+		err = lerr
+	}
+	return err
+}
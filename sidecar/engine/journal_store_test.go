@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestJournal(t *testing.T, path string) *JournalStore {
+	t.Helper()
+	s, err := NewJournalStore(path)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestJournalStoreReplaysAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.journal")
+	s := openTestJournal(t, path)
+	now := time.Now().UTC()
+	for _, r := range []*TaskResult{
+		{ID: "kept", Type: "config-patch", Status: TaskStatusRunning, SubmittedAt: now},
+		{ID: "deleted", Type: "config-patch", Status: TaskStatusCompleted, SubmittedAt: now},
+	} {
+		if err := s.Save(r); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	if err := s.SaveProgress("kept", &Progress{Phase: "download"}); err != nil {
+		t.Fatalf("save progress: %v", err)
+	}
+	if _, err := s.Delete("deleted"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.SaveTxMarker(&TxMarker{TaskID: "kept", TxHash: "ABC"}); err != nil {
+		t.Fatalf("save marker: %v", err)
+	}
+	for range 3 {
+		if err := s.AppendEvent(&TaskEvent{Type: EventStarted, Task: TaskResult{ID: "kept"}, At: now}, 2); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	s = openTestJournal(t, path)
+	if got, _ := s.Get("kept"); got == nil || got.Progress == nil || got.Progress.Phase != "download" {
+		t.Errorf("kept = %+v, want it with its progress", got)
+	}
+	if got, _ := s.Get("deleted"); got != nil {
+		t.Errorf("deleted result came back after reopen")
+	}
+	if m, _ := s.GetTxMarker("kept"); m == nil || m.TxHash != "ABC" {
+		t.Errorf("marker = %+v", m)
+	}
+	ev := &TaskEvent{Type: EventCompleted, Task: TaskResult{ID: "kept"}, At: now}
+	if err := s.AppendEvent(ev, 2); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if events, _ := s.ListEvents(0, 10); ev.Seq != 4 || len(events) != 2 || events[0].Seq != 3 {
+		t.Errorf("after reopen: seq = %d, events = %+v; want seq 4 following 3", ev.Seq, events)
+	}
+}
+
+func TestJournalStoreDropsTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.journal")
+	s := openTestJournal(t, path)
+	if err := s.Save(&TaskResult{ID: "whole", Type: "config-patch", Status: TaskStatusCompleted, SubmittedAt: time.Now()}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	s.Close()
+
+	// A crash mid-append leaves a final line without its newline.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"op":"result","result":{"id":"torn"`)
+	f.Close()
+
+	s = openTestJournal(t, path)
+	if got, _ := s.Get("whole"); got == nil {
+		t.Error("record before the torn one was lost")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "torn") {
+		t.Error("torn record survived the rewrite on open")
+	}
+}
+
+func TestJournalStoreRejectsCorruptJournal(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"corrupt middle": `{"op":"header","version":1}` + "\nnot json\n" + `{"op":"delete-results","ids":["x"]}` + "\n",
+		"no header":      `{"op":"delete-results","ids":["x"]}` + "\n",
+		"newer version":  `{"op":"header","version":99}` + "\n",
+	} {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-"))
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if s, err := NewJournalStore(path); err == nil {
+			s.Close()
+			t.Errorf("%s: opened without error", name)
+		}
+	}
+}
+
+func TestJournalStoreCompactsWhenDeadRecordsDominate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.journal")
+	s := openTestJournal(t, path)
+	r := &TaskResult{ID: "churn", Type: "config-patch", Status: TaskStatusRunning, SubmittedAt: time.Now()}
+	for i := range journalCompactMinRecords {
+		r.Run = i
+		if err := s.Save(r); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	if s.records >= journalCompactMinRecords {
+		t.Fatalf("journal holds %d records, want it compacted", s.records)
+	}
+	if got, _ := s.Get("churn"); got == nil || got.Run != journalCompactMinRecords-1 {
+		t.Errorf("after compaction = %+v, want the last save", got)
+	}
+}
+
+func TestJournalStoreLocksOutSecondOpener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.journal")
+	s := openTestJournal(t, path)
+	if other, err := NewJournalStore(path); !errors.Is(err, ErrJournalLocked) {
+		if other != nil {
+			other.Close()
+		}
+		t.Fatalf("second open: err = %v, want ErrJournalLocked", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	openTestJournal(t, path)
+}
+
+// A write that fails and cannot be rolled back leaves the journal's tail
+// unknown, so the store refuses every write after it and reports unhealthy.
+func TestJournalStoreFailsClosedOnUnrecoverableWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.journal")
+	s := openTestJournal(t, path)
+	ro, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.f.Close()
+	s.f = ro
+
+	r := &TaskResult{ID: "a", Type: "config-patch", Status: TaskStatusCompleted, SubmittedAt: time.Now()}
+	if err := s.Save(r); err == nil {
+		t.Fatal("save to a read-only journal succeeded")
+	}
+	if err := s.Ping(); err == nil {
+		t.Error("Ping() = nil after the journal failed")
+	}
+	if err := s.Save(r); err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("save after failure: err = %v, want the store's failure", err)
+	}
+	if got, _ := s.Get("a"); got != nil {
+		t.Error("a failed save reached memory")
+	}
+}
+
+// The journal keeps a bounded audit log, and its sequence numbers carry on
+// past the dropped entries, across a reopen.
+func TestJournalStoreCapsAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.journal")
+	s := openTestJournal(t, path)
+	for range journalAuditKeep + 5 {
+		if err := s.AppendAudit(&AuditEntry{Action: "submit-task", Status: 202}); err != nil {
+			t.Fatalf("append audit: %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	s = openTestJournal(t, path)
+	entries, _ := s.ListAudit(0, journalAuditKeep+10)
+	if len(entries) != journalAuditKeep || entries[0].Seq != 6 {
+		t.Fatalf("kept %d entries from seq %d, want %d from 6", len(entries), entries[0].Seq, journalAuditKeep)
+	}
+	a := &AuditEntry{Action: "cancel-task", Status: 200}
+	if err := s.AppendAudit(a); err != nil {
+		t.Fatalf("append audit: %v", err)
+	}
+	if want := int64(journalAuditKeep + 6); a.Seq != want {
+		t.Errorf("seq after reopen = %d, want %d", a.Seq, want)
+	}
+}
@@ -0,0 +1,383 @@
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Ledger archive identity. The format version changes only when the
+// archive's own layout does; the store schema a ledger was exported from is
+// recorded separately.
+const (
+	ledgerFormat        = "seictl-sidecar-ledger"
+	LedgerFormatVersion = 1
+)
+
+// importBatchSize is how many rows ImportLedger writes per transaction.
+const importBatchSize = 500
+
+// ErrStoreNotEmpty is returned by ImportLedger when the store already holds
+// task results or tx markers outside an unfinished import.
+var ErrStoreNotEmpty = errors.New("store is not empty")
+
+// ErrSchemaTooNew is returned by ExportLedgerFile for a database a newer
+// binary migrated.
+var ErrSchemaTooNew = errors.New("store schema is newer than this binary's")
+
+// Ledger is the part of a sidecar store that must follow a node to a new
+// volume or host: its task history and the pre-broadcast tx markers that
+// keep sign-tx tasks idempotent across a crash.
+type Ledger struct {
+	TaskResults []TaskResult `json:"taskResults"`
+	TxMarkers   []TxMarker   `json:"txMarkers"`
+}
+
+// LedgerStore is a store whose Ledger can be exported and imported.
+// SQLiteStore and JournalStore both implement it.
+type LedgerStore interface {
+	ExportLedger() (*Ledger, error)
+	ImportLedger(l *Ledger, checksum string) error
+	// PendingImport returns the checksum of an import that started but
+	// did not finish, or "" when there is none.
+	PendingImport() (string, error)
+}
+
+// ledgerArchive is a Ledger's serialized form. Checksum is the SHA-256 of
+// the compacted Ledger JSON, so re-indenting an archive keeps it valid.
+type ledgerArchive struct {
+	Format        string          `json:"format"`
+	Version       int             `json:"version"`
+	SchemaVersion int             `json:"schemaVersion"`
+	ExportedAt    time.Time       `json:"exportedAt"`
+	Checksum      string          `json:"checksum"`
+	Ledger        json.RawMessage `json:"ledger"`
+}
+
+// LedgerInfo describes an archive read by ReadLedgerArchive.
+type LedgerInfo struct {
+	SchemaVersion int
+	ExportedAt    time.Time
+	Checksum      string
+}
+
+// WriteLedgerArchive writes l to w as a versioned, checksummed archive
+// recording the store schema version it was exported from.
+func WriteLedgerArchive(w io.Writer, l *Ledger, schemaVersion int) error {
+	payload, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("marshal ledger: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return json.NewEncoder(w).Encode(ledgerArchive{
+		Format:        ledgerFormat,
+		Version:       LedgerFormatVersion,
+		SchemaVersion: schemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Checksum:      "sha256:" + hex.EncodeToString(sum[:]),
+		Ledger:        payload,
+	})
+}
+
+// ReadLedgerArchive reads an archive written by WriteLedgerArchive. It
+// rejects archives of another format or a newer format version, archives
+// exported from a store schema newer than SchemaVersion, and archives
+// whose contents do not match their checksum.
+func ReadLedgerArchive(r io.Reader) (*Ledger, LedgerInfo, error) {
+	var a ledgerArchive
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, LedgerInfo{}, fmt.Errorf("decode archive: %w", err)
+	}
+	info := LedgerInfo{SchemaVersion: a.SchemaVersion, ExportedAt: a.ExportedAt, Checksum: a.Checksum}
+	switch {
+	case a.Format != ledgerFormat:
+		return nil, info, fmt.Errorf("not a sidecar ledger archive (format %q)", a.Format)
+	case a.Version < 1 || a.Version > LedgerFormatVersion:
+		return nil, info, fmt.Errorf("unsupported archive format version %d (this binary reads up to %d)", a.Version, LedgerFormatVersion)
+	case a.SchemaVersion > SchemaVersion:
+		return nil, info, fmt.Errorf("archive was exported from store schema %d, newer than this binary's %d", a.SchemaVersion, SchemaVersion)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, a.Ledger); err != nil {
+		return nil, info, fmt.Errorf("archive ledger: %w", err)
+	}
+	sum := sha256.Sum256(compact.Bytes())
+	if got := "sha256:" + hex.EncodeToString(sum[:]); got != a.Checksum {
+		return nil, info, fmt.Errorf("archive checksum mismatch: contents hash to %s, archive records %s", got, a.Checksum)
+	}
+
+	// Decoding the compacted form keeps re-indentation out of the raw
+	// task result payloads.
+	var l Ledger
+	if err := json.Unmarshal(compact.Bytes(), &l); err != nil {
+		return nil, info, fmt.Errorf("decode ledger: %w", err)
+	}
+	for _, r := range l.TaskResults {
+		if r.ID == "" || r.Type == "" || r.Status == "" {
+			return nil, info, fmt.Errorf("archive holds a task result without an id, type or status")
+		}
+	}
+	for _, m := range l.TxMarkers {
+		if m.TaskID == "" {
+			return nil, info, fmt.Errorf("archive holds a tx marker without a task id")
+		}
+	}
+	return &l, info, nil
+}
+
+// SchemaVersion returns the database's migration version. It exceeds the
+// package SchemaVersion when a newer binary last migrated the file.
+func (s *SQLiteStore) SchemaVersion() (int, error) {
+	var v int
+	err := s.db.QueryRow("PRAGMA user_version").Scan(&v)
+	return v, err
+}
+
+// ExportLedger reads every task result and tx marker in one read
+// transaction, so a ledger exported from a running sidecar is consistent.
+func (s *SQLiteStore) ExportLedger() (*Ledger, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(selectColumns + ` ORDER BY submitted_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	results, err := scanResults(rows)
+	if err != nil {
+		return nil, fmt.Errorf("read task results: %w", err)
+	}
+
+	markerRows, err := tx.Query(`
+		SELECT task_id, tx_hash, tx_bytes, account_number, sequence, chain_id
+		FROM tx_markers ORDER BY task_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer markerRows.Close()
+	var markers []TxMarker
+	for markerRows.Next() {
+		var m TxMarker
+		if err := markerRows.Scan(&m.TaskID, &m.TxHash, &m.TxBytes, &m.AccountNumber, &m.Sequence, &m.ChainID); err != nil {
+			return nil, fmt.Errorf("read tx markers: %w", err)
+		}
+		markers = append(markers, m)
+	}
+	if err := markerRows.Err(); err != nil {
+		return nil, fmt.Errorf("read tx markers: %w", err)
+	}
+	return &Ledger{TaskResults: results, TxMarkers: markers}, nil
+}
+
+// ExportLedgerFile exports the ledger of the SQLite database at dbPath
+// without writing to it, returning it with the file's own schema version.
+// It is safe against a sidecar serving the file, even one older than this
+// binary: the file is opened read-only and never migrated. A consistent
+// snapshot is copied to a temporary database, which alone is migrated to
+// this binary's schema and read. A file at a newer schema than this binary
+// knows is refused.
+func ExportLedgerFile(dbPath string) (*Ledger, int, error) {
+	src, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, 0, fmt.Errorf("open sqlite: %w", err)
+	}
+	defer src.Close()
+	src.SetMaxOpenConns(1)
+	if _, err := src.Exec("PRAGMA busy_timeout=5000"); err != nil {
+		return nil, 0, err
+	}
+	var schema int
+	if err := src.QueryRow("PRAGMA user_version").Scan(&schema); err != nil {
+		return nil, 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if schema > SchemaVersion {
+		return nil, schema, fmt.Errorf("%w: store is at schema %d, this binary's is %d", ErrSchemaTooNew, schema, SchemaVersion)
+	}
+
+	dir, err := os.MkdirTemp("", "sidecar-ledger-export-")
+	if err != nil {
+		return nil, schema, err
+	}
+	defer os.RemoveAll(dir)
+	snapshot := filepath.Join(dir, "snapshot.db")
+	if _, err := src.Exec("VACUUM INTO ?", snapshot); err != nil {
+		return nil, schema, fmt.Errorf("snapshot %s: %w", dbPath, err)
+	}
+
+	store, err := NewSQLiteStore(snapshot)
+	if err != nil {
+		return nil, schema, fmt.Errorf("open snapshot: %w", err)
+	}
+	defer store.Close()
+	l, err := store.ExportLedger()
+	return l, schema, err
+}
+
+// PendingImport returns the checksum of a ledger import that started but
+// did not finish, or "" when there is none. A store with a pending import
+// holds only part of its ledger and must not be served.
+func (s *SQLiteStore) PendingImport() (string, error) {
+	var checksum string
+	err := s.db.QueryRow(`SELECT checksum FROM ledger_import WHERE id = 1`).Scan(&checksum)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return checksum, nil
+}
+
+// ImportLedger loads l, read from the archive with the given checksum, into
+// the store. The store must hold no task results or tx markers, unless it
+// has a pending import, whose partial rows are discarded first. Rows are
+// written in batches; the store reports the import pending until the last
+// batch is durable.
+func (s *SQLiteStore) ImportLedger(l *Ledger, checksum string) error {
+	pending, err := s.PendingImport()
+	if err != nil {
+		return err
+	}
+	if pending == "" {
+		var populated bool
+		if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM task_results) OR EXISTS (SELECT 1 FROM tx_markers)`).Scan(&populated); err != nil {
+			return err
+		}
+		if populated {
+			return ErrStoreNotEmpty
+		}
+	}
+
+	if err := s.inTx(func(tx *sql.Tx) error {
+		for _, stmt := range []string{`DELETE FROM task_results`, `DELETE FROM tx_markers`} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(`INSERT OR REPLACE INTO ledger_import (id, checksum, started_at) VALUES (1, ?, ?)`,
+			checksum, formatTime(time.Now()))
+		return err
+	}); err != nil {
+		return fmt.Errorf("begin import: %w", err)
+	}
+
+	for start := 0; start < len(l.TaskResults); start += importBatchSize {
+		batch := l.TaskResults[start:min(start+importBatchSize, len(l.TaskResults))]
+		if err := s.inTx(func(tx *sql.Tx) error {
+			for i := range batch {
+				if err := saveResult(tx, &batch[i]); err != nil {
+					return fmt.Errorf("task %s: %w", batch[i].ID, err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("import task results: %w", err)
+		}
+	}
+	for start := 0; start < len(l.TxMarkers); start += importBatchSize {
+		batch := l.TxMarkers[start:min(start+importBatchSize, len(l.TxMarkers))]
+		if err := s.inTx(func(tx *sql.Tx) error {
+			for i := range batch {
+				if err := saveTxMarker(tx, &batch[i]); err != nil {
+					return fmt.Errorf("tx marker %s: %w", batch[i].TaskID, err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("import tx markers: %w", err)
+		}
+	}
+
+	// The markers must be on disk before the import stops reading as
+	// pending; synchronous=NORMAL alone does not guarantee that.
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(FULL)"); err != nil {
+		return fmt.Errorf("checkpoint import: %w", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM ledger_import`); err != nil {
+		return fmt.Errorf("finish import: %w", err)
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, committing when it returns nil.
+func (s *SQLiteStore) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ExportLedger copies every task result and tx marker under the store
+// lock, in the order SQLiteStore exports them.
+func (s *JournalStore) ExportLedger() (*Ledger, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := s.selectResults(func(*TaskResult) bool { return true })
+	slices.Reverse(results)
+	l := &Ledger{}
+	var err error
+	if l.TaskResults, err = cloneAll(results, -1); err != nil {
+		return nil, fmt.Errorf("copy task results: %w", err)
+	}
+	if l.TxMarkers, err = cloneAll(sortedValues(s.markers), -1); err != nil {
+		return nil, fmt.Errorf("copy tx markers: %w", err)
+	}
+	return l, nil
+}
+
+// PendingImport always returns "": a JournalStore import is applied by one
+// compaction, so it is never left half done.
+func (s *JournalStore) PendingImport() (string, error) {
+	return "", nil
+}
+
+// ImportLedger loads l into the store, which must hold no task results or
+// tx markers. The whole ledger lands in one snapshot renamed over the
+// journal, so a crash leaves either the empty journal or the imported one.
+// checksum is unused, as no import is ever pending.
+func (s *JournalStore) ImportLedger(l *Ledger, checksum string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failed != nil {
+		return fmt.Errorf("journal %s failed: %w", s.path, s.failed)
+	}
+	if len(s.results) > 0 || len(s.markers) > 0 {
+		return ErrStoreNotEmpty
+	}
+
+	for i := range l.TaskResults {
+		r, err := clone(&l.TaskResults[i])
+		if err != nil {
+			return fmt.Errorf("task %s: %w", l.TaskResults[i].ID, err)
+		}
+		s.results[r.ID] = r
+	}
+	for i := range l.TxMarkers {
+		m, err := clone(&l.TxMarkers[i])
+		if err != nil {
+			return fmt.Errorf("tx marker %s: %w", l.TxMarkers[i].TaskID, err)
+		}
+		s.markers[m.TaskID] = m
+	}
+	if err := s.compact(); err != nil {
+		clear(s.results)
+		clear(s.markers)
+		return fmt.Errorf("write imported journal: %w", err)
+	}
+	return nil
+}
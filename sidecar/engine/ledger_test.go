@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func seedLedger(t *testing.T, s *SQLiteStore) {
+	t.Helper()
+	now := time.Now().UTC()
+	for _, r := range []*TaskResult{
+		{ID: "done", Type: "gov-vote", Status: TaskStatusCompleted, Params: map[string]any{"proposalId": float64(7)}, Result: json.RawMessage(`{"txHash":"ABC"}`), SubmittedAt: now, CompletedAt: &now},
+		{ID: "running", Type: "gov-vote", Status: TaskStatusRunning, SubmittedAt: now.Add(time.Second)},
+	} {
+		if err := s.Save(r); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	if err := s.SaveTxMarker(&TxMarker{TaskID: "running", TxHash: "DEF", TxBytes: []byte{0x00, 0xFF}, Sequence: 9, ChainID: "pacific-1"}); err != nil {
+		t.Fatalf("save marker: %v", err)
+	}
+}
+
+func exportArchive(t *testing.T, s *SQLiteStore) []byte {
+	t.Helper()
+	ledger, err := s.ExportLedger()
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteLedgerArchive(&buf, ledger, SchemaVersion); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestLedgerExportImportRoundTrip(t *testing.T) {
+	src := newTestStore(t)
+	seedLedger(t, src)
+	archive := exportArchive(t, src)
+
+	// Re-indenting the archive leaves its checksum valid.
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, archive, "", "  "); err != nil {
+		t.Fatal(err)
+	}
+	ledger, info, err := ReadLedgerArchive(&indented)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	if info.SchemaVersion != SchemaVersion || !strings.HasPrefix(info.Checksum, "sha256:") {
+		t.Errorf("info = %+v", info)
+	}
+
+	dst := newTestStore(t)
+	if err := dst.ImportLedger(ledger, info.Checksum); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if pending, _ := dst.PendingImport(); pending != "" {
+		t.Errorf("pending import %q after a completed import", pending)
+	}
+	got, err := dst.Get("done")
+	if err != nil || got == nil {
+		t.Fatalf("get = %v, %v", got, err)
+	}
+	if got.Params["proposalId"] != json.Number("7") || string(got.Result) != `{"txHash":"ABC"}` || got.CompletedAt == nil {
+		t.Errorf("imported result = %+v", got)
+	}
+	m, err := dst.GetTxMarker("running")
+	if err != nil || m == nil || !bytes.Equal(m.TxBytes, []byte{0x00, 0xFF}) || m.Sequence != 9 {
+		t.Errorf("imported marker = %+v, %v", m, err)
+	}
+
+	if err := dst.ImportLedger(ledger, info.Checksum); !errors.Is(err, ErrStoreNotEmpty) {
+		t.Errorf("second import: err = %v, want ErrStoreNotEmpty", err)
+	}
+}
+
+func TestReadLedgerArchiveRejects(t *testing.T) {
+	s := newTestStore(t)
+	seedLedger(t, s)
+	archive := string(exportArchive(t, s))
+
+	for name, mutate := range map[string]func(string) string{
+		"tampered":     func(a string) string { return strings.Replace(a, `"DEF"`, `"XYZ"`, 1) },
+		"other format": func(a string) string { return strings.Replace(a, ledgerFormat, "something-else", 1) },
+		"newer format": func(a string) string { return strings.Replace(a, `"version":1`, `"version":2`, 1) },
+		"newer schema": func(a string) string {
+			return strings.Replace(a, fmt.Sprintf(`"schemaVersion":%d`, SchemaVersion), `"schemaVersion":99`, 1)
+		},
+		"not an archive": func(string) string { return "[]" },
+	} {
+		if _, _, err := ReadLedgerArchive(strings.NewReader(mutate(archive))); err == nil {
+			t.Errorf("%s: read without error", name)
+		}
+	}
+}
+
+// An interrupted import is reported pending, and rerunning it discards the
+// partial rows and finishes.
+func TestImportLedgerResumesPendingImport(t *testing.T) {
+	src := newTestStore(t)
+	seedLedger(t, src)
+	ledger, info, err := ReadLedgerArchive(bytes.NewReader(exportArchive(t, src)))
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+
+	dst := newTestStore(t)
+	if _, err := dst.db.Exec(`INSERT INTO ledger_import (id, checksum, started_at) VALUES (1, ?, ?)`, info.Checksum, formatTime(time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Save(&TaskResult{ID: "partial", Type: "gov-vote", Status: TaskStatusCompleted, SubmittedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := dst.PendingImport(); err != nil || pending != info.Checksum {
+		t.Fatalf("PendingImport = %q, %v; want %q", pending, err, info.Checksum)
+	}
+
+	if err := dst.ImportLedger(ledger, info.Checksum); err != nil {
+		t.Fatalf("rerun import: %v", err)
+	}
+	if pending, _ := dst.PendingImport(); pending != "" {
+		t.Errorf("still pending after rerun")
+	}
+	if all, _ := dst.List(10); len(all) != 2 {
+		t.Errorf("results = %d, want the archive's 2 and not the partial row", len(all))
+	}
+}
+
+func TestMigrateReachesSchemaVersion(t *testing.T) {
+	s := newTestStore(t)
+	if v, err := s.SchemaVersion(); err != nil || v != SchemaVersion {
+		t.Errorf("schema version = %d, %v; want %d", v, err, SchemaVersion)
+	}
+}
+
+// Exporting a file reads a snapshot without migrating or otherwise writing
+// the file, and reports the file's own schema.
+func TestExportLedgerFileLeavesStoreUntouched(t *testing.T) {
+	dir := t.TempDir()
+	live := filepath.Join(dir, "sidecar.db")
+	s, err := NewSQLiteStore(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedLedger(t, s)
+	ledger, schema, err := ExportLedgerFile(live)
+	if err != nil || schema != SchemaVersion || len(ledger.TaskResults) != 2 || len(ledger.TxMarkers) != 1 {
+		t.Fatalf("export of a served store = %+v, %d, %v", ledger, schema, err)
+	}
+	s.Close()
+
+	// A store older than this binary stays at its schema.
+	old := filepath.Join(dir, "old.db")
+	db, err := sql.Open("sqlite", old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("PRAGMA user_version = 0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE placeholder (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	ledger, schema, err = ExportLedgerFile(old)
+	if err != nil || schema != 0 || len(ledger.TaskResults) != 0 {
+		t.Fatalf("export of an old store = %+v, %d, %v", ledger, schema, err)
+	}
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil || version != 0 {
+		t.Errorf("old store at schema %d after export (%v), want it unmigrated", version, err)
+	}
+
+	if _, err := db.Exec("PRAGMA user_version = 99"); err != nil {
+		t.Fatal(err)
+	}
+	if _, schema, err := ExportLedgerFile(old); !errors.Is(err, ErrSchemaTooNew) || schema != 99 {
+		t.Errorf("export of a newer store: schema %d, err = %v; want ErrSchemaTooNew", schema, err)
+	}
+}
+
+// A ledger moves between backends: exported from SQLite, imported into a
+// journal that keeps it across a reopen, and exported back unchanged.
+func TestJournalLedgerImportExport(t *testing.T) {
+	src := newTestStore(t)
+	seedLedger(t, src)
+	ledger, info, err := ReadLedgerArchive(bytes.NewReader(exportArchive(t, src)))
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "sidecar.journal")
+	j, err := NewJournalStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.ImportLedger(ledger, info.Checksum); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if err := j.ImportLedger(ledger, info.Checksum); !errors.Is(err, ErrStoreNotEmpty) {
+		t.Errorf("second import: err = %v, want ErrStoreNotEmpty", err)
+	}
+	j.Close()
+
+	j, err = NewJournalStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if pending, err := j.PendingImport(); err != nil || pending != "" {
+		t.Errorf("PendingImport = %q, %v; want none", pending, err)
+	}
+	if m, err := j.GetTxMarker("running"); err != nil || m == nil || !bytes.Equal(m.TxBytes, []byte{0x00, 0xFF}) {
+		t.Errorf("imported marker = %+v, %v", m, err)
+	}
+	back, err := j.ExportLedger()
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	want, _ := json.Marshal(ledger)
+	got, _ := json.Marshal(back)
+	if !bytes.Equal(got, want) {
+		t.Errorf("journal export = %s\nwant %s", got, want)
+	}
+}
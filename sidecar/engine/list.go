@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Page sizes for ListResults.
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// ErrInvalidCursor is returned by ListResults for a cursor it did not issue.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ResultQuery selects a page of task results for ListResults. Zero fields
+// do not filter.
+type ResultQuery struct {
+	Type   string
+	Status TaskStatus
+
+	// SubmittedAfter keeps results submitted at or after it;
+	// SubmittedBefore keeps results submitted strictly before it.
+	SubmittedAfter  time.Time
+	SubmittedBefore time.Time
+
+	// Cursor continues a listing from the page that returned it. The other
+	// fields must match the ones that produced it.
+	Cursor string
+
+	// Limit caps the page size; zero means DefaultListLimit. It must not
+	// exceed MaxListLimit.
+	Limit int
+}
+
+// ResultFilter is a ResultQuery resolved for the store. Results are
+// ordered newest first by submission time, ties broken by descending ID;
+// After, when set, resumes strictly past that position.
+type ResultFilter struct {
+	Type            string
+	Status          TaskStatus
+	SubmittedAfter  time.Time
+	SubmittedBefore time.Time
+	After           *ResultKey
+	Limit           int
+}
+
+// ResultKey is a result's position in the ResultFilter order.
+type ResultKey struct {
+	SubmittedAt time.Time `json:"t"`
+	ID          string    `json:"id"`
+}
+
+// ListResults returns one page of task results matching q, newest first,
+// and the cursor for the next page, or "" on the last page.
+func (e *Engine) ListResults(q ResultQuery) ([]TaskResult, string, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+	if limit < 0 || limit > MaxListLimit {
+		return nil, "", fmt.Errorf("limit must be between 1 and %d", MaxListLimit)
+	}
+	f := ResultFilter{
+		Type:            q.Type,
+		Status:          q.Status,
+		SubmittedAfter:  q.SubmittedAfter,
+		SubmittedBefore: q.SubmittedBefore,
+		Limit:           limit + 1,
+	}
+	if q.Cursor != "" {
+		key, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		f.After = key
+	}
+
+	results, err := e.store.QueryResults(f)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, encodeCursor(ResultKey{SubmittedAt: last.SubmittedAt, ID: last.ID}), nil
+}
+
+func encodeCursor(k ResultKey) string {
+	b, _ := json.Marshal(k)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*ResultKey, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var k ResultKey
+	if err := json.Unmarshal(b, &k); err != nil || k.ID == "" || k.SubmittedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &k, nil
+}
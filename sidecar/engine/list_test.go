@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func seedResults(t *testing.T, eng *Engine, base time.Time) {
+	t.Helper()
+	for i := range 7 {
+		r := &TaskResult{
+			ID:          fmt.Sprintf("res-%d", i),
+			Type:        string(TaskConfigPatch),
+			Status:      TaskStatusCompleted,
+			SubmittedAt: base.Add(time.Duration(i) * 100 * time.Millisecond),
+		}
+		if i%2 == 1 {
+			r.Type, r.Status = string(TaskGovVote), TaskStatusFailed
+		}
+		if err := eng.store.Save(r); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+}
+
+func TestListResultsPaginates(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	seedResults(t, eng, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var got []string
+	cursor := ""
+	pages := 0
+	for {
+		page, next, err := eng.ListResults(ResultQuery{Limit: 3, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, r := range page {
+			got = append(got, r.ID)
+		}
+		pages++
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	want := "[res-6 res-5 res-4 res-3 res-2 res-1 res-0]"
+	if fmt.Sprint(got) != want || pages != 3 {
+		t.Errorf("got %v over %d pages, want %s over 3", got, pages, want)
+	}
+}
+
+func TestListResultsFilters(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	seedResults(t, eng, base)
+
+	tests := []struct {
+		name string
+		q    ResultQuery
+		want string
+	}{
+		{"type", ResultQuery{Type: string(TaskGovVote)}, "[res-5 res-3 res-1]"},
+		{"status", ResultQuery{Status: TaskStatusCompleted}, "[res-6 res-4 res-2 res-0]"},
+		// 300ms vs 1s: the window must compare instants, not trimmed strings.
+		{"window", ResultQuery{SubmittedAfter: base.Add(300 * time.Millisecond), SubmittedBefore: base.Add(600 * time.Millisecond)}, "[res-5 res-4 res-3]"},
+		{"type and window", ResultQuery{Type: string(TaskConfigPatch), SubmittedBefore: base.Add(time.Second)}, "[res-6 res-4 res-2 res-0]"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			page, next, err := eng.ListResults(tc.q)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			var got []string
+			for _, r := range page {
+				got = append(got, r.ID)
+			}
+			if fmt.Sprint(got) != tc.want || next != "" {
+				t.Errorf("got %v (next %q), want %s", got, next, tc.want)
+			}
+		})
+	}
+}
+
+func TestListResultsRejectsBadInput(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	if _, _, err := eng.ListResults(ResultQuery{Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("bad cursor: err = %v, want ErrInvalidCursor", err)
+	}
+	if _, _, err := eng.ListResults(ResultQuery{Limit: MaxListLimit + 1}); err == nil {
+		t.Error("limit above MaxListLimit: expected error")
+	}
+}
+
+// Rows written before timestamps were fixed-width are rewritten by the
+// migration so they sort in time order.
+func TestNormalizeResultTimes(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.db.Exec(`INSERT INTO task_results (id, type, status, params, submitted_at, completed_at) VALUES
+		('a', 'config-patch', 'completed', 'null', '2026-01-01T00:00:01Z', '2026-01-01T00:00:02.5Z'),
+		('b', 'config-patch', 'completed', 'null', '2026-01-01T00:00:01.5Z', NULL)`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := normalizeResultTimes(tx); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var submitted, completed string
+	if err := s.db.QueryRow(`SELECT submitted_at, completed_at FROM task_results WHERE id = 'a'`).Scan(&submitted, &completed); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if submitted != "2026-01-01T00:00:01.000000000Z" || completed != "2026-01-01T00:00:02.500000000Z" {
+		t.Errorf("stored = %q, %q", submitted, completed)
+	}
+	got, err := s.QueryResults(ResultFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "b" {
+		t.Errorf("order = %v, want b (1.5s) before a (1s)", got)
+	}
+}
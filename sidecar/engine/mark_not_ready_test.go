@@ -16,7 +16,7 @@ import (
 // flips the readiness flag for mark-ready / mark-not-ready.
 func noopHandler(context.Context, map[string]any) (json.RawMessage, error) { return nil, nil }
 
-func engineOver(t *testing.T, store ResultStore, handlers map[TaskType]TaskHandler) *Engine {
+func engineOver[H Handler](t *testing.T, store ResultStore, handlers map[TaskType]H) *Engine {
 	t.Helper()
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -44,6 +44,80 @@ var (
 		},
 		[]string{"type"},
 	)
+
+	// taskRetries counts automatic re-executions scheduled under a
+	// RetryPolicy. Each increment corresponds to one advance of a task's run.
+	taskRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "seictl_task_retries_total",
+			Help: "Total number of automatic task retries scheduled by the engine.",
+		},
+		[]string{"type"},
+	)
+
+	// taskCancellations counts tasks stopped through CancelTask.
+	taskCancellations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "seictl_task_cancellations_total",
+			Help: "Total number of tasks cancelled through the cancel endpoint.",
+		},
+		[]string{"type"},
+	)
+
+	// taskProgressGauge is the latest progress a running handler of each
+	// task type reported, one series per counter (bytes_done, bytes_total,
+	// heights_done, heights_total). Totals read zero when unknown. Series
+	// are per type, not per task: with two tasks of a type running, they
+	// show whichever reported last. A run's series are removed when it
+	// settles, so a type with nothing running reports none.
+	taskProgressGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "seictl_task_progress",
+			Help: "Latest progress reported by a running task handler, by counter.",
+		},
+		[]string{"type", "counter"},
+	)
+
+	// taskQueueDepth is the number of Pending tasks waiting for a worker or
+	// an exclusion group.
+	taskQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "seictl_task_queue_depth",
+			Help: "Number of pending tasks queued for a worker or exclusion group.",
+		},
+		[]string{"type"},
+	)
+
+	// taskQueueWait records how long each queued task spent Pending before
+	// it started. Buckets run from a second to several hours, since a task
+	// can wait out a long-running one in its exclusion group.
+	taskQueueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "seictl_task_queue_wait_seconds",
+			Help:    "Time in seconds queued tasks spent pending before they started.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		},
+		[]string{"type"},
+	)
+
+	// taskResultsPruned counts settled task results removed by the retention
+	// compactor, by the bound that removed them (age or count).
+	taskResultsPruned = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "seictl_task_results_pruned_total",
+			Help: "Total number of settled task results removed by retention, by reason (age or count).",
+		},
+		[]string{"type", "reason"},
+	)
+
+	// storeCompactDuration is how long the most recent store compaction
+	// (SQLite VACUUM) took.
+	storeCompactDuration = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "seictl_store_compact_duration_seconds",
+			Help: "Time in seconds the most recent result store compaction took.",
+		},
+	)
 )
 
 func init() {
@@ -51,4 +125,11 @@ func init() {
 	prometheus.MustRegister(taskSubmissions)
 	prometheus.MustRegister(taskFailures)
 	prometheus.MustRegister(taskPanics)
+	prometheus.MustRegister(taskRetries)
+	prometheus.MustRegister(taskCancellations)
+	prometheus.MustRegister(taskProgressGauge)
+	prometheus.MustRegister(taskQueueDepth)
+	prometheus.MustRegister(taskQueueWait)
+	prometheus.MustRegister(taskResultsPruned)
+	prometheus.MustRegister(storeCompactDuration)
 }
@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// progressSaveInterval bounds how often a task's progress is written to the
+// store. Reports between writes only refresh the gauges; the latest one is
+// carried onto the row when the run settles.
+const progressSaveInterval = time.Second
+
+// Progress is a handler's self-reported position within a long-running task.
+// Totals are zero when unknown. UpdatedAt is stamped by the engine.
+type Progress struct {
+	Phase        string    `json:"phase,omitempty"`
+	BytesDone    int64     `json:"bytesDone,omitempty"`
+	BytesTotal   int64     `json:"bytesTotal,omitempty"`
+	HeightsDone  int64     `json:"heightsDone,omitempty"`
+	HeightsTotal int64     `json:"heightsTotal,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// ProgressReporter publishes a running task's progress. Each Report replaces
+// the previous one. Report is cheap enough to call per chunk or per height
+// and safe for concurrent use.
+type ProgressReporter interface {
+	Report(p Progress)
+}
+
+type progressKey struct{}
+
+type noopProgress struct{}
+
+func (noopProgress) Report(Progress) {}
+
+// ProgressFromContext returns the reporter for the current handler, or a
+// no-op reporter when ctx is not engine-produced, so handlers can report
+// unconditionally.
+func ProgressFromContext(ctx context.Context) ProgressReporter {
+	if r, ok := ctx.Value(progressKey{}).(ProgressReporter); ok {
+		return r
+	}
+	return noopProgress{}
+}
+
+// WithProgressReporter attaches r to ctx for handler consumption. The engine
+// calls this in runTaskSync; tests use it to observe a handler's reports.
+func WithProgressReporter(ctx context.Context, r ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressKey{}, r)
+}
+
+// progressCounters are the counter label values of taskProgressGauge.
+var progressCounters = [...]string{"bytes_done", "bytes_total", "heights_done", "heights_total"}
+
+// taskProgress is the engine's ProgressReporter for one run. Every report
+// updates the progress gauges; at most one per interval is persisted and
+// published as an EventProgress, so a handler reporting per chunk does not
+// turn into a write per chunk.
+type taskProgress struct {
+	engine   *Engine
+	id       string
+	taskType string
+	interval time.Duration
+
+	mu     sync.Mutex
+	last   *Progress
+	saved  time.Time
+	closed bool
+}
+
+func newTaskProgress(e *Engine, tr *TaskResult) *taskProgress {
+	return &taskProgress{engine: e, id: tr.ID, taskType: tr.Type, interval: progressSaveInterval}
+}
+
+func (p *taskProgress) Report(rep Progress) {
+	now := time.Now().UTC()
+	rep.UpdatedAt = now
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.last = &rep
+	for i, v := range [...]int64{rep.BytesDone, rep.BytesTotal, rep.HeightsDone, rep.HeightsTotal} {
+		taskProgressGauge.WithLabelValues(p.taskType, progressCounters[i]).Set(float64(v))
+	}
+
+	if now.Sub(p.saved) < p.interval {
+		return
+	}
+	p.saved = now
+	store := p.engine.store
+	if err := store.SaveProgress(p.id, &rep); err != nil {
+		log.Warn("failed to persist task progress", "id", p.id, "err", err)
+		return
+	}
+	if tr, err := store.Get(p.id); err == nil && tr != nil && tr.Status == TaskStatusRunning {
+		p.engine.publish(EventProgress, tr)
+	}
+}
+
+// stamp carries the latest report onto tr ahead of a Save of the whole row,
+// so a write that lands between two persisted reports does not roll the
+// stored progress back. A run that never reported leaves tr's progress as
+// it was, keeping a rehydrated row's last persisted progress.
+func (p *taskProgress) stamp(tr *TaskResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.last != nil {
+		last := *p.last
+		tr.Progress = &last
+	}
+}
+
+// close stamps tr one last time and drops any later report, such as one
+// from a goroutine the handler left behind. A run that reported removes
+// its type's gauge series, so a settled task's progress does not linger;
+// another running task of the type brings them back on its next report.
+func (p *taskProgress) close(tr *TaskResult) {
+	p.stamp(tr)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	if p.last != nil {
+		for _, c := range progressCounters {
+			taskProgressGauge.DeleteLabelValues(p.taskType, c)
+		}
+	}
+}
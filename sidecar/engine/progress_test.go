@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestProgressFromContextWithoutEngine(t *testing.T) {
+	// Handlers report unconditionally; outside the engine that is a no-op.
+	ProgressFromContext(context.Background()).Report(Progress{Phase: "download"})
+}
+
+func TestProgressIsRateLimitedAndKeptOnTheRecord(t *testing.T) {
+	reported := make(chan struct{})
+	release := make(chan struct{})
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskSnapshotRestore: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			p := ProgressFromContext(ctx)
+			for i := int64(1); i <= 100; i++ {
+				p.Report(Progress{Phase: "download", BytesDone: i, BytesTotal: 100})
+			}
+			close(reported)
+			<-release
+			return nil, nil
+		},
+	})
+
+	id, err := eng.Submit(Task{Type: TaskSnapshotRestore})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-reported
+
+	// Only the first report of the burst reached the store.
+	r := eng.GetResult(id)
+	if r.Progress == nil || r.Progress.BytesDone != 1 || r.Progress.UpdatedAt.IsZero() {
+		t.Fatalf("persisted progress = %+v, want the first report", r.Progress)
+	}
+	if got := testutil.ToFloat64(taskProgressGauge.WithLabelValues(string(TaskSnapshotRestore), "bytes_done")); got != 100 {
+		t.Fatalf("bytes_done gauge = %v, want 100", got)
+	}
+
+	close(release)
+	r = waitForResult(t, eng, id)
+	if r.Status != TaskStatusCompleted || r.Progress == nil || r.Progress.BytesDone != 100 || r.Progress.Phase != "download" {
+		t.Fatalf("result = %+v, progress %+v; want completed with the last report", r, r.Progress)
+	}
+	// The settled run's gauge series are gone rather than left stale.
+	if taskProgressGauge.DeleteLabelValues(string(TaskSnapshotRestore), "bytes_done") {
+		t.Fatal("bytes_done gauge still set after the task settled")
+	}
+}
+
+func TestTaskProgressPersistsOncePerInterval(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	store := eng.store
+	tr := &TaskResult{ID: "prg-int0-0000-0000-0000-000000000000", Type: string(TaskResultExport), Status: TaskStatusRunning, SubmittedAt: time.Now()}
+	if err := store.Save(tr); err != nil {
+		t.Fatal(err)
+	}
+
+	p := newTaskProgress(eng, tr)
+	p.interval = 20 * time.Millisecond
+	p.Report(Progress{HeightsDone: 1})
+	p.Report(Progress{HeightsDone: 2})
+	time.Sleep(p.interval)
+	p.Report(Progress{HeightsDone: 3})
+
+	got, err := store.Get(tr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Progress == nil || got.Progress.HeightsDone != 3 {
+		t.Fatalf("progress = %+v, want the report after the interval", got.Progress)
+	}
+
+	p.close(got)
+	p.Report(Progress{HeightsDone: 4})
+	if got.Progress.HeightsDone != 3 {
+		t.Fatalf("stamped progress = %+v, want 3", got.Progress)
+	}
+	if after, _ := store.Get(tr.ID); after.Progress.HeightsDone != 3 {
+		t.Fatalf("report after close was persisted: %+v", after.Progress)
+	}
+}
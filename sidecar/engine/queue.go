@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"slices"
+	"time"
+)
+
+// ConcurrencyLimits bounds how many tasks execute at once. A submission that
+// would exceed a limit is persisted Pending and started as running tasks
+// finish, highest Priority first and in submission order within a priority.
+// A task keeps its worker through any retry backoff, since the retry runs on
+// the same goroutine.
+type ConcurrencyLimits struct {
+	// MaxWorkers caps running tasks across every type not listed in
+	// Unbounded. Zero means no global cap.
+	MaxWorkers int
+
+	// PerType caps running tasks of a single type, whether or not the type
+	// is Unbounded. Types absent from the map have no per-type cap.
+	PerType map[TaskType]int
+
+	// Unbounded types neither count toward nor wait on MaxWorkers: long-lived
+	// watchers that would pin a worker for hours, and readiness gates that
+	// must never queue behind bulk work.
+	Unbounded []TaskType
+}
+
+// waiter is a Pending task queued for a worker, an exclusion group, or both.
+type waiter struct {
+	tr         TaskResult
+	handler    Handler
+	groups     []string
+	enqueuedAt time.Time
+}
+
+// workerFree reports whether a task of taskType may start without exceeding
+// the concurrency limits. Callers MUST hold e.mu.
+func (e *Engine) workerFree(taskType TaskType) bool {
+	unbounded := slices.Contains(e.Concurrency.Unbounded, taskType)
+	total, ofType := 0, 0
+	for _, t := range e.running {
+		if t == taskType {
+			ofType++
+		}
+		if !slices.Contains(e.Concurrency.Unbounded, t) {
+			total++
+		}
+	}
+	if limit, ok := e.Concurrency.PerType[taskType]; ok && ofType >= limit {
+		return false
+	}
+	return unbounded || e.Concurrency.MaxWorkers <= 0 || total < e.Concurrency.MaxWorkers
+}
+
+// acquire records id as running: it takes a worker and becomes the holder of
+// groups. Callers MUST hold e.mu and have checked both are available, except
+// on rehydration, where a task that was running before the restart reclaims
+// its worker unconditionally.
+func (e *Engine) acquire(id string, taskType TaskType, groups []string) {
+	e.running[id] = taskType
+	for _, g := range groups {
+		e.locks[g] = id
+	}
+}
+
+// release frees id's worker and every group it holds, then starts the queued
+// tasks that can now run. It is called as soon as a task's handler is done
+// for good — before its terminal row is persisted — so a client that observes
+// the terminal row and resubmits never finds its own finished run still
+// occupying a worker or group.
+func (e *Engine) release(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, released := e.running[id]
+	delete(e.running, id)
+	for g, holder := range e.locks {
+		if holder == id {
+			delete(e.locks, g)
+			released = true
+		}
+	}
+	if released {
+		e.dispatchWaiters()
+	}
+}
+
+// enqueue inserts w behind every waiter of equal or higher priority. Callers
+// MUST hold e.mu.
+func (e *Engine) enqueue(w waiter) {
+	i := len(e.waiters)
+	for i > 0 && e.waiters[i-1].tr.Priority < w.tr.Priority {
+		i--
+	}
+	e.waiters = slices.Insert(e.waiters, i, w)
+	e.observeQueue()
+}
+
+// dispatchWaiters starts queued tasks in queue order. A waiter starts when a
+// worker is free for its type and none of its groups is held or claimed by an
+// earlier waiter, so a group is granted strictly in queue order. Nothing is
+// started once the engine is shutting down: the rows stay Pending and are
+// re-queued on restart. While the engine drains only DrainExempt types
+// start. Callers MUST hold e.mu.
+func (e *Engine) dispatchWaiters() {
+	if e.ctx.Err() != nil {
+		return
+	}
+	claimed := make(map[string]bool)
+	remaining := e.waiters[:0]
+	for _, w := range e.waiters {
+		free := !e.drainHolds(TaskType(w.tr.Type)) && e.workerFree(TaskType(w.tr.Type))
+		for _, g := range w.groups {
+			if _, held := e.locks[g]; held || claimed[g] {
+				free = false
+				break
+			}
+		}
+		if !free || !e.startPending(w) {
+			for _, g := range w.groups {
+				claimed[g] = true
+			}
+			remaining = append(remaining, w)
+		}
+	}
+	clear(e.waiters[len(remaining):])
+	e.waiters = remaining
+	e.observeQueue()
+}
+
+// startPending moves a queued task to running and dispatches it, reporting
+// false if the transition could not be persisted and the task must stay
+// queued. Callers MUST hold e.mu.
+func (e *Engine) startPending(w waiter) bool {
+	tr := w.tr
+	tr.Status = TaskStatusRunning
+	tr.startDeadline(time.Now().UTC())
+	if err := e.store.Save(&tr); err != nil {
+		log.Error("failed to persist dequeued task; leaving it pending", "id", tr.ID, "err", err)
+		return false
+	}
+	wait := time.Since(w.enqueuedAt)
+	taskQueueWait.WithLabelValues(tr.Type).Observe(wait.Seconds())
+	log.Info("starting queued task", "type", tr.Type, "id", tr.ID, "groups", w.groups, "waited", wait)
+	e.acquire(tr.ID, TaskType(tr.Type), w.groups)
+	ctx, gen := e.newTaskContext(tr.ID, tr.Deadline)
+	e.runTask(ctx, tr, w.handler, gen)
+	return true
+}
+
+// dequeue drops a pending task from the wait queue, reporting whether it was
+// queued. Callers MUST hold e.mu.
+func (e *Engine) dequeue(id string) bool {
+	for i, w := range e.waiters {
+		if w.tr.ID == id {
+			e.waiters = slices.Delete(e.waiters, i, i+1)
+			e.observeQueue()
+			return true
+		}
+	}
+	return false
+}
+
+// observeQueue publishes the queue depth of every registered task type,
+// zero included, so an emptied queue reads 0 rather than going stale.
+// Callers MUST hold e.mu.
+func (e *Engine) observeQueue() {
+	depth := make(map[string]int)
+	for _, w := range e.waiters {
+		depth[w.tr.Type]++
+	}
+	for t := range e.handlers {
+		taskQueueDepth.WithLabelValues(string(t)).Set(float64(depth[string(t)]))
+	}
+}
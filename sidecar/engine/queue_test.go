@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func concurrencyTestEngine(t *testing.T, limits ConcurrencyLimits) (*Engine, *gatedRecorder) {
+	t.Helper()
+	rec := newGatedRecorder()
+	t.Cleanup(rec.open)
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskResultExport:     rec.handler,
+		TaskEvmLogicalDigest: rec.handler,
+		TaskAwaitCondition:   rec.handler,
+	})
+	eng.Concurrency = limits
+	return eng, rec
+}
+
+func TestMaxWorkersQueuesExcessTasks(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{MaxWorkers: 1})
+
+	first, err := eng.Submit(Task{Type: TaskResultExport, Params: step("first")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "first")
+	second, err := eng.Submit(Task{Type: TaskEvmLogicalDigest, Params: step("second")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := eng.GetResult(second); r == nil || r.Status != TaskStatusPending {
+		t.Fatalf("second task = %+v, want status pending", r)
+	}
+	if got := testutil.ToFloat64(taskQueueDepth.WithLabelValues(string(TaskEvmLogicalDigest))); got != 1 {
+		t.Fatalf("queue depth = %v, want 1", got)
+	}
+
+	rec.open()
+	for _, id := range []string{first, second} {
+		if r := waitForResult(t, eng, id); r.Status != TaskStatusCompleted {
+			t.Fatalf("task %s status = %q, want completed", id, r.Status)
+		}
+	}
+	if got := testutil.ToFloat64(taskQueueDepth.WithLabelValues(string(TaskEvmLogicalDigest))); got != 0 {
+		t.Fatalf("queue depth after drain = %v, want 0", got)
+	}
+}
+
+func TestPerTypeLimitLeavesOtherTypesRunning(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{PerType: map[TaskType]int{TaskResultExport: 1}})
+
+	if _, err := eng.Submit(Task{Type: TaskResultExport, Params: step("export-1")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "export-1")
+	queued, err := eng.Submit(Task{Type: TaskResultExport, Params: step("export-2")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eng.Submit(Task{Type: TaskEvmLogicalDigest, Params: step("digest")}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec.waitForStep(t, "digest")
+	if r := eng.GetResult(queued); r.Status != TaskStatusPending {
+		t.Fatalf("second export status = %q, want pending", r.Status)
+	}
+	rec.open()
+	waitForResult(t, eng, queued)
+}
+
+func TestUnboundedTypesBypassMaxWorkers(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{
+		MaxWorkers: 1,
+		Unbounded:  []TaskType{TaskAwaitCondition},
+	})
+
+	if _, err := eng.Submit(Task{Type: TaskResultExport, Params: step("export")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "export")
+	if _, err := eng.Submit(Task{Type: TaskAwaitCondition, Params: step("await")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "await")
+}
+
+func TestQueueStartsHigherPriorityFirst(t *testing.T) {
+	eng, rec := concurrencyTestEngine(t, ConcurrencyLimits{MaxWorkers: 1})
+
+	if _, err := eng.Submit(Task{Type: TaskResultExport, Params: step("holder")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "holder")
+	var ids []string
+	for _, tc := range []struct {
+		step     string
+		priority int
+	}{{"low", 0}, {"high", 10}, {"low-2", 0}, {"high-2", 10}} {
+		id, err := eng.Submit(Task{Type: TaskResultExport, Params: step(tc.step), Priority: tc.priority})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	rec.open()
+	for _, id := range ids {
+		waitForResult(t, eng, id)
+	}
+	want := []string{"holder", "high", "high-2", "low", "low-2"}
+	if ran := rec.ran(); !slices.Equal(ran, want) {
+		t.Fatalf("run order = %v, want %v", ran, want)
+	}
+}
+
+// Pending rows survive a restart and are re-queued in priority order; a stale
+// running task reclaims its worker first, so nothing queued starts until it
+// finishes.
+// A queued task's deadline counts from when it starts, so time spent
+// waiting for a worker never eats into its run.
+func TestQueuedTaskGetsItsFullTimeout(t *testing.T) {
+	rec := newGatedRecorder()
+	t.Cleanup(rec.open)
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskResultExport: rec.handler,
+		TaskEvmLogicalDigest: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			select {
+			case <-time.After(100 * time.Millisecond):
+				return nil, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	})
+	eng.Concurrency = ConcurrencyLimits{MaxWorkers: 1}
+
+	if _, err := eng.Submit(Task{Type: TaskResultExport, Params: step("hold")}); err != nil {
+		t.Fatal(err)
+	}
+	rec.waitForStep(t, "hold")
+	const timeout = 300 * time.Millisecond
+	id, err := eng.Submit(Task{Type: TaskEvmLogicalDigest, Timeout: timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := eng.GetResult(id); r == nil || r.Status != TaskStatusPending || r.Deadline != nil || time.Duration(r.Timeout) != timeout {
+		t.Fatalf("queued task = %+v, want pending with its timeout and no deadline yet", r)
+	}
+
+	time.Sleep(2 * timeout)
+	started := time.Now()
+	rec.open()
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusCompleted {
+		t.Fatalf("queued task = %+v, want completed within its timeout", r)
+	}
+	if r.Deadline == nil || r.Deadline.Before(started.Add(timeout)) {
+		t.Errorf("deadline = %v, want at least %v after the task started", r.Deadline, timeout)
+	}
+}
+
+func TestRehydrateRestoresQueueOrder(t *testing.T) {
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	base := time.Now().UTC()
+	seed := func(status TaskStatus, s string, priority int, at time.Time) string {
+		id := uuid.New().String()
+		if err := store.Save(&TaskResult{
+			ID: id, Type: string(TaskResultExport), Status: status, Run: 1, Attempt: 1,
+			Params: step(s), Priority: priority, SubmittedAt: at,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	ids := []string{
+		seed(TaskStatusRunning, "stale", 0, base),
+		seed(TaskStatusPending, "low", 0, base.Add(time.Millisecond)),
+		seed(TaskStatusPending, "high", 5, base.Add(2*time.Millisecond)),
+	}
+
+	rec := newGatedRecorder()
+	rec.open()
+	eng := engineOver(t, store, map[TaskType]TaskHandler{TaskResultExport: rec.handler})
+	eng.Concurrency = ConcurrencyLimits{MaxWorkers: 1}
+	eng.RehydrateStaleTasks()
+
+	for _, id := range ids {
+		waitForResult(t, eng, id)
+	}
+	if ran := rec.ran(); !slices.Equal(ran, []string{"stale", "high", "low"}) {
+		t.Fatalf("run order = %v, want [stale high low]", ran)
+	}
+}
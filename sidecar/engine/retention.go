@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"time"
+)
+
+// Compaction cadence when a RetentionPolicy leaves it unset.
+const (
+	defaultCompactInterval = time.Hour
+	defaultVacuumInterval  = 24 * time.Hour
+)
+
+// RetentionPolicy bounds how many settled task results the store keeps.
+// Pending and running results are never pruned, nor are the newest
+// mark-ready and mark-not-ready results, which rehydration reads to decide
+// whether a stranded mark-ready may still release a hold, nor the tasks of
+// a graph that is still running. The zero value keeps everything.
+type RetentionPolicy struct {
+	// MaxAge prunes results that settled longer ago than this. Zero keeps
+	// results regardless of age.
+	MaxAge time.Duration
+
+	// MaxPerType keeps only the newest MaxPerType settled results of each
+	// task type. Zero means no per-type cap.
+	MaxPerType int
+
+	// Interval is how often the compactor prunes. Defaults to an hour.
+	Interval time.Duration
+
+	// VacuumInterval is the minimum time between two compactions of the
+	// store file; one runs only after rows were pruned. Defaults to a day.
+	VacuumInterval time.Duration
+}
+
+func (p RetentionPolicy) enabled() bool {
+	return p.MaxAge > 0 || p.MaxPerType > 0
+}
+
+// PruneCounts is how many results a PruneResults call removed, by task type,
+// under each bound.
+type PruneCounts struct {
+	ByAge   map[string]int
+	ByCount map[string]int
+}
+
+// StartCompactor prunes settled results per e.Retention on a background
+// goroutine until the engine context ends, compacting the store at most
+// once per VacuumInterval after a prune removed rows. It does nothing when
+// the policy keeps everything. Call once, after RehydrateStaleTasks.
+func (e *Engine) StartCompactor() {
+	if !e.Retention.enabled() {
+		return
+	}
+	go e.runCompactor()
+}
+
+func (e *Engine) runCompactor() {
+	interval := e.Retention.Interval
+	if interval <= 0 {
+		interval = defaultCompactInterval
+	}
+	vacuumInterval := e.Retention.VacuumInterval
+	if vacuumInterval <= 0 {
+		vacuumInterval = defaultVacuumInterval
+	}
+
+	var lastVacuum time.Time
+	dirty := false
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if e.pruneResults(time.Now().UTC()) > 0 {
+			dirty = true
+		}
+		if dirty && time.Since(lastVacuum) >= vacuumInterval {
+			start := time.Now()
+			if err := e.store.Compact(); err != nil {
+				log.Warn("failed to compact result store", "err", err)
+			} else {
+				storeCompactDuration.Set(time.Since(start).Seconds())
+				lastVacuum, dirty = time.Now(), false
+			}
+		}
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneResults applies e.Retention once as of now and returns how many
+// results it removed.
+func (e *Engine) pruneResults(now time.Time) int {
+	// Holding graphMu keeps graphs from advancing between gathering the
+	// running graphs' tasks and the prune, so a node that settles in
+	// between cannot be pruned before its graph reads it.
+	e.graphMu.Lock()
+	defer e.graphMu.Unlock()
+
+	keep, err := e.retainedResults()
+	if err != nil {
+		log.Warn("skipping result pruning", "err", err)
+		return 0
+	}
+	var before time.Time
+	if e.Retention.MaxAge > 0 {
+		before = now.Add(-e.Retention.MaxAge)
+	}
+	counts, err := e.store.PruneResults(before, e.Retention.MaxPerType, keep)
+	if err != nil {
+		log.Warn("failed to prune task results", "err", err)
+		return 0
+	}
+
+	total := 0
+	for reason, byType := range map[string]map[string]int{"age": counts.ByAge, "count": counts.ByCount} {
+		for taskType, n := range byType {
+			taskResultsPruned.WithLabelValues(taskType, reason).Add(float64(n))
+			total += n
+		}
+	}
+	if total > 0 {
+		log.Info("pruned task results", "byAge", counts.ByAge, "byCount", counts.ByCount)
+	}
+	return total
+}
+
+// retainedResults lists the settled results retention must keep: the newest
+// mark-ready and mark-not-ready, and every task of a running graph. Called
+// with graphMu held.
+func (e *Engine) retainedResults() ([]string, error) {
+	var keep []string
+	for _, t := range []TaskType{TaskMarkReady, TaskMarkNotReady} {
+		latest, err := e.store.LatestByType(string(t))
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil {
+			keep = append(keep, latest.ID)
+		}
+	}
+	graphs, err := e.store.ListActiveGraphs()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range graphs {
+		for _, n := range g.Nodes {
+			keep = append(keep, n.ID)
+		}
+	}
+	return keep, nil
+}
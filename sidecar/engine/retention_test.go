@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+// Retention never prunes what rehydration or a running graph still reads:
+// the newest mark-ready and mark-not-ready, and a running graph's settled
+// nodes.
+func TestPruneResultsKeepsRehydrationAndGraphState(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	eng.Retention = RetentionPolicy{MaxAge: time.Hour}
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	for _, r := range []TaskResult{
+		{ID: "mr-old", Type: string(TaskMarkReady), Status: TaskStatusCompleted, SubmittedAt: old, CompletedAt: &old},
+		{ID: "mr-new", Type: string(TaskMarkReady), Status: TaskStatusCompleted, SubmittedAt: old.Add(time.Minute), CompletedAt: &old},
+		{ID: "mnr", Type: string(TaskMarkNotReady), Status: TaskStatusFailed, SubmittedAt: old, CompletedAt: &old},
+		{ID: "node-a", Type: string(TaskConfigPatch), Status: TaskStatusCompleted, SubmittedAt: old, CompletedAt: &old},
+		{ID: "loose", Type: string(TaskConfigPatch), Status: TaskStatusCompleted, SubmittedAt: old, CompletedAt: &old},
+	} {
+		if err := eng.store.Save(&r); err != nil {
+			t.Fatalf("save %s: %v", r.ID, err)
+		}
+	}
+	g := &TaskGraph{
+		ID:          "graph-1",
+		Phase:       GraphPhaseRunning,
+		SubmittedAt: old,
+		Nodes: []GraphNode{
+			{Name: "a", ID: "node-a", Type: TaskConfigPatch},
+			{Name: "b", ID: "node-b", Type: TaskConfigPatch, DependsOn: []string{"a"}},
+		},
+	}
+	if err := eng.store.SaveGraph(g); err != nil {
+		t.Fatalf("save graph: %v", err)
+	}
+
+	if n := eng.pruneResults(time.Now().UTC()); n != 2 {
+		t.Errorf("pruned %d, want 2 (mr-old and loose)", n)
+	}
+	for id, want := range map[string]bool{"mr-old": false, "mr-new": true, "mnr": true, "node-a": true, "loose": false} {
+		got, err := eng.store.Get(id)
+		if err != nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		if (got != nil) != want {
+			t.Errorf("%s present = %v, want %v", id, got != nil, want)
+		}
+	}
+}
+
+func TestStartCompactorDisabledByDefault(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	if eng.Retention.enabled() {
+		t.Fatal("zero RetentionPolicy should keep everything")
+	}
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	if err := eng.store.Save(&TaskResult{ID: "kept", Type: "config-patch", Status: TaskStatusCompleted, SubmittedAt: old, CompletedAt: &old}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	eng.StartCompactor()
+	time.Sleep(20 * time.Millisecond)
+	if got, _ := eng.store.Get("kept"); got == nil {
+		t.Fatal("disabled compactor pruned a result")
+	}
+}
+
+func TestCompactorPrunesOnStart(t *testing.T) {
+	eng := newTestEngine[TaskHandler](t, nil)
+	eng.Retention = RetentionPolicy{MaxPerType: 1, Interval: time.Hour}
+	old := time.Now().UTC().Add(-time.Hour)
+	for _, id := range []string{"first", "second"} {
+		old = old.Add(time.Minute)
+		if err := eng.store.Save(&TaskResult{ID: id, Type: "config-patch", Status: TaskStatusCompleted, SubmittedAt: old, CompletedAt: &old}); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	eng.StartCompactor()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if got, _ := eng.store.Get("first"); got == nil {
+			if kept, _ := eng.store.Get("second"); kept == nil {
+				t.Fatal("compactor pruned the newest result")
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("compactor did not prune on start")
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Retry policy defaults applied to zero-valued RetryPolicy fields.
+const (
+	defaultRetryInitialBackoff = 5 * time.Second
+	defaultRetryMaxBackoff     = 5 * time.Minute
+	defaultRetryMultiplier     = 2.0
+)
+
+// RetryPolicy governs automatic re-execution of a failed task type. A task
+// that fails with a retryable error (see Retryable) is re-run by the engine
+// after an exponential backoff, advancing TaskResult.Run on every attempt,
+// until it succeeds, MaxAttempts is reached, or MaxElapsed has passed since
+// the task was submitted. Task types without a policy are never retried
+// automatically; a controller resubmit of a Failed ID remains the only path.
+type RetryPolicy struct {
+	// MaxAttempts bounds the total number of executions, including the
+	// first. Values <= 1 disable automatic retry.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the second attempt. Defaults
+	// to 5s.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between any two attempts. Defaults to 5m.
+	MaxBackoff time.Duration
+
+	// Multiplier scales the delay after each attempt. Defaults to 2.
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to ±Jitter of its value (0.2
+	// spreads a 10s delay over [8s, 12s]). Zero disables jitter.
+	Jitter float64
+
+	// MaxElapsed bounds the wall-clock time from submission to the start
+	// of the last attempt. Zero means no bound beyond MaxAttempts.
+	MaxElapsed time.Duration
+}
+
+// backoff returns the un-jittered delay that precedes attempt+1, given that
+// attempt executions have already failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = defaultRetryInitialBackoff
+	}
+	ceiling := p.MaxBackoff
+	if ceiling <= 0 {
+		ceiling = defaultRetryMaxBackoff
+	}
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = defaultRetryMultiplier
+	}
+	d := float64(initial) * math.Pow(mult, float64(attempt-1))
+	if d > float64(ceiling) {
+		return ceiling
+	}
+	return time.Duration(d)
+}
+
+// nextAttempt reports when attempt+1 should start, given that attempt
+// executions of a task submitted at submittedAt have failed as of now. ok is
+// false when the policy is exhausted and the failure is final.
+func (p RetryPolicy) nextAttempt(attempt int, submittedAt, now time.Time) (time.Time, bool) {
+	if p.MaxAttempts <= 1 || attempt >= p.MaxAttempts {
+		return time.Time{}, false
+	}
+	d := p.backoff(attempt)
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	next := now.Add(d)
+	if p.MaxElapsed > 0 && next.Sub(submittedAt) > p.MaxElapsed {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// terminal is implemented by handler errors that must never be retried
+// (e.g. tasks.TerminalError). It keeps the engine free of a dependency on
+// the handler package while letting handlers opt out of retry.
+type terminal interface {
+	Terminal() bool
+}
+
+// Retryable reports whether a failed task's error permits automatic retry.
+// An error marked terminal anywhere in its chain is final, as is a *TaskError
+// with Retryable=false. Any other error is presumed transient.
+func Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var t terminal
+	if errors.As(err, &t) && t.Terminal() {
+		return false
+	}
+	var te *TaskError
+	if errors.As(err, &te) {
+		return te.Retryable
+	}
+	return true
+}
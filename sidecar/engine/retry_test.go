@@ -0,0 +1,262 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fastRetry retries quickly enough for tests while still exercising the
+// persisted-backoff path.
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+type terminalTestErr struct{ error }
+
+func (terminalTestErr) Terminal() bool { return true }
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), true},
+		{"retryable TaskError", &TaskError{Retryable: true}, true},
+		{"non-retryable TaskError", &TaskError{Retryable: false}, false},
+		{"wrapped non-retryable TaskError", fmt.Errorf("ctx: %w", &TaskError{}), false},
+		{"terminal marker", terminalTestErr{errors.New("bad input")}, false},
+		{"wrapped terminal marker", fmt.Errorf("ctx: %w", terminalTestErr{errors.New("x")}), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Retryable(tc.err); got != tc.want {
+				t.Fatalf("Retryable(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffGrowsAndCaps(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.backoff(i + 1); got != w {
+			t.Fatalf("backoff(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
+
+func TestRetryPolicyNextAttemptBounds(t *testing.T) {
+	now := time.Now()
+	p := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxElapsed: 90 * time.Second}
+
+	if _, ok := p.nextAttempt(1, now, now); !ok {
+		t.Fatal("first failure within bounds must schedule a retry")
+	}
+	if _, ok := p.nextAttempt(3, now, now); ok {
+		t.Fatal("MaxAttempts reached must not schedule a retry")
+	}
+	if _, ok := p.nextAttempt(2, now, now); ok {
+		t.Fatal("a 2m backoff past a 90s MaxElapsed must not schedule a retry")
+	}
+	if _, ok := (RetryPolicy{}).nextAttempt(1, now, now); ok {
+		t.Fatal("zero policy must never retry")
+	}
+}
+
+func TestRetryPolicyJitterStaysInBounds(t *testing.T) {
+	now := time.Now()
+	p := RetryPolicy{MaxAttempts: 2, InitialBackoff: 10 * time.Second, Jitter: 0.2}
+	for range 100 {
+		next, ok := p.nextAttempt(1, now, now)
+		if !ok {
+			t.Fatal("expected a retry")
+		}
+		if d := next.Sub(now); d < 8*time.Second || d > 12*time.Second {
+			t.Fatalf("jittered delay %s outside [8s, 12s]", d)
+		}
+	}
+}
+
+func TestRetryReExecutesUntilSuccess(t *testing.T) {
+	var calls atomic.Int32
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			if calls.Add(1) < 3 {
+				return nil, errors.New("transient")
+			}
+			return nil, nil
+		},
+	})
+	eng.RetryPolicies = map[TaskType]RetryPolicy{TaskConfigPatch: fastRetry}
+
+	id, err := eng.Submit(Task{Type: TaskConfigPatch})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusCompleted {
+		t.Fatalf("status = %q, want completed (error %q)", r.Status, r.Error)
+	}
+	if r.Run != 3 || r.Attempt != 3 {
+		t.Fatalf("run/attempt = %d/%d, want 3/3", r.Run, r.Attempt)
+	}
+	if r.Error != "" || r.NextAttemptAt != nil {
+		t.Fatalf("completed row must clear retry state, got error %q next %v", r.Error, r.NextAttemptAt)
+	}
+}
+
+func TestRetryExhaustedPersistsFailed(t *testing.T) {
+	var calls atomic.Int32
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			calls.Add(1)
+			return nil, errors.New("still broken")
+		},
+	})
+	eng.RetryPolicies = map[TaskType]RetryPolicy{TaskConfigPatch: fastRetry}
+
+	id, _ := eng.Submit(Task{Type: TaskConfigPatch})
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusFailed {
+		t.Fatalf("status = %q, want failed", r.Status)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("handler calls = %d, want MaxAttempts (3)", got)
+	}
+	if r.Run != 3 {
+		t.Fatalf("run = %d, want 3", r.Run)
+	}
+}
+
+func TestRetrySkipsNonRetryableErrors(t *testing.T) {
+	for name, herr := range map[string]error{
+		"non-retryable TaskError": &TaskError{Task: "config-patch", Operation: "verify", Message: "bad"},
+		"terminal":                terminalTestErr{errors.New("malformed")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			eng := newTestEngine(t, map[TaskType]TaskHandler{
+				TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+					calls.Add(1)
+					return nil, herr
+				},
+			})
+			eng.RetryPolicies = map[TaskType]RetryPolicy{TaskConfigPatch: fastRetry}
+
+			id, _ := eng.Submit(Task{Type: TaskConfigPatch})
+			r := waitForResult(t, eng, id)
+			if r.Status != TaskStatusFailed || r.Run != 1 {
+				t.Fatalf("status/run = %q/%d, want failed/1", r.Status, r.Run)
+			}
+			if got := calls.Load(); got != 1 {
+				t.Fatalf("handler calls = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestRetryNotAppliedWithoutPolicy(t *testing.T) {
+	var calls atomic.Int32
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			calls.Add(1)
+			return nil, errors.New("transient")
+		},
+	})
+
+	id, _ := eng.Submit(Task{Type: TaskConfigPatch})
+	if r := waitForResult(t, eng, id); r.Status != TaskStatusFailed {
+		t.Fatalf("status = %q, want failed", r.Status)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("handler calls = %d, want 1", got)
+	}
+}
+
+// A pending retry is persisted 'running' with its next start time; the row is
+// visible mid-backoff and a resubmit of the same ID is an idempotent no-op.
+func TestRetryPendingRowIsRunningWithNextAttempt(t *testing.T) {
+	var calls atomic.Int32
+	eng := newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			calls.Add(1)
+			return nil, errors.New("transient")
+		},
+	})
+	eng.RetryPolicies = map[TaskType]RetryPolicy{
+		TaskConfigPatch: {MaxAttempts: 2, InitialBackoff: time.Hour, MaxBackoff: time.Hour},
+	}
+
+	id, _ := eng.Submit(Task{Type: TaskConfigPatch})
+	deadline := time.Now().Add(2 * time.Second)
+	var r *TaskResult
+	for time.Now().Before(deadline) {
+		if r = eng.GetResult(id); r != nil && r.NextAttemptAt != nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if r == nil || r.NextAttemptAt == nil {
+		t.Fatal("timed out waiting for a pending retry")
+	}
+	if r.Status != TaskStatusRunning || r.Run != 2 || r.Error != "transient" {
+		t.Fatalf("pending row = %q run %d error %q, want running run 2 with last error", r.Status, r.Run, r.Error)
+	}
+
+	if _, err := eng.Submit(Task{ID: id, Type: TaskConfigPatch}); err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("resubmit during backoff re-executed the handler (%d calls)", got)
+	}
+}
+
+// Rehydration of a pending retry waits out the persisted backoff rather than
+// running immediately, and does not advance the run counter.
+func TestRehydrateResumesPendingRetryBackoff(t *testing.T) {
+	store, err := NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	id := uuid.New().String()
+	next := time.Now().Add(150 * time.Millisecond).UTC()
+	if err := store.Save(&TaskResult{
+		ID: id, Type: string(TaskConfigPatch), Status: TaskStatusRunning,
+		Run: 2, Attempt: 2, Error: "transient",
+		SubmittedAt: time.Now().UTC(), NextAttemptAt: &next,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var startedAt atomic.Int64
+	eng := engineOver(t, store, map[TaskType]TaskHandler{
+		TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			startedAt.Store(time.Now().UnixNano())
+			return nil, nil
+		},
+	})
+	eng.RetryPolicies = map[TaskType]RetryPolicy{TaskConfigPatch: fastRetry}
+	eng.RehydrateStaleTasks()
+
+	r := waitForResult(t, eng, id)
+	if r.Status != TaskStatusCompleted {
+		t.Fatalf("status = %q, want completed", r.Status)
+	}
+	if got := time.Unix(0, startedAt.Load()); got.Before(next) {
+		t.Fatalf("rehydrated retry started at %s, before its persisted next attempt %s", got, next)
+	}
+	if r.Run != 2 {
+		t.Fatalf("run = %d, want 2 (rehydration does not advance run)", r.Run)
+	}
+}
@@ -0,0 +1,319 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSchedule is returned by CreateSchedule when a schedule is
+// malformed: neither or both of Cron and Interval, an unparseable cron
+// expression, an unknown missed-run policy, or a task template Submit would
+// reject.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// MinScheduleInterval is the shortest Interval a schedule may fire at.
+const MinScheduleInterval = time.Second
+
+// maxCatchUpRuns caps how many missed firings a MissedRunCatchUp schedule
+// submits at once, so a long outage of a short-interval schedule does not
+// flood the queue. Older missed firings beyond the cap are dropped.
+const maxCatchUpRuns = 10
+
+// MissedRunPolicy decides what a schedule does with firings that came due
+// while the sidecar was down or the scheduler was behind.
+type MissedRunPolicy string
+
+const (
+	// MissedRunSkip drops every missed firing but the most recent, so an
+	// overdue schedule runs once and resumes its cadence.
+	MissedRunSkip MissedRunPolicy = "skip"
+	// MissedRunCatchUp submits every missed firing, oldest first, up to
+	// maxCatchUpRuns.
+	MissedRunCatchUp MissedRunPolicy = "catch-up"
+)
+
+// Schedule submits a task from Task as a template at every firing of its
+// cron expression or interval. Each firing is an ordinary one-shot task whose
+// ID is derived from the schedule ID and the firing time, so a firing
+// re-driven after a crash resolves to the same task and Submit's idempotency
+// keeps it from running twice.
+type Schedule struct {
+	ID string `json:"id"`
+	// Cron is a five-field cron expression evaluated in UTC. Exactly one
+	// of Cron and Interval is set.
+	Cron     string        `json:"cron,omitempty"`
+	Interval time.Duration `json:"interval,omitempty"`
+	// Task is the template for each firing. Its ID must be empty.
+	Task         Task            `json:"task"`
+	MissedPolicy MissedRunPolicy `json:"missedPolicy"`
+	NextRunAt    time.Time       `json:"nextRunAt"`
+	LastRunAt    *time.Time      `json:"lastRunAt,omitempty"`
+	LastTaskID   string          `json:"lastTaskId,omitempty"`
+	// LastError is why the most recent firing could not be submitted;
+	// empty once a firing succeeds.
+	LastError string    `json:"lastError,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// CreatedBy is who created the schedule. Each firing's task records it
+	// in SubmittedBy as "schedule:<id> by <user>".
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+// after returns the schedule's first firing strictly after t, or the zero
+// time when the cron expression never matches again.
+func (s *Schedule) after(t time.Time) time.Time {
+	if s.Interval > 0 {
+		return t.Add(s.Interval)
+	}
+	spec, err := parseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return spec.next(t)
+}
+
+// submitter is the SubmittedBy each firing's task carries: the schedule,
+// and the user who created it when known.
+func (s *Schedule) submitter() string {
+	if s.CreatedBy == "" {
+		return "schedule:" + s.ID
+	}
+	return "schedule:" + s.ID + " by " + s.CreatedBy
+}
+
+// scheduleTaskID is the deterministic ID of a schedule's firing at at.
+func scheduleTaskID(scheduleID string, at time.Time) string {
+	return uuid.NewSHA1(uuid.MustParse(scheduleID), []byte(at.UTC().Format(time.RFC3339Nano))).String()
+}
+
+// CreateSchedule validates and persists a schedule and returns its ID. The
+// first firing is the first cron match, or one Interval, after now. The call
+// is idempotent on the schedule ID: recreating an existing schedule returns
+// its ID unchanged.
+func (e *Engine) CreateSchedule(s Schedule) (string, error) {
+	if err := validateTaskID(s.ID); err != nil {
+		return "", err
+	}
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	if err := e.validateSchedule(&s); err != nil {
+		return "", err
+	}
+
+	e.scheduleMu.Lock()
+	defer e.scheduleMu.Unlock()
+
+	existing, err := e.store.GetSchedule(s.ID)
+	if err != nil {
+		return "", fmt.Errorf("read schedule: %w", err)
+	}
+	if existing != nil {
+		return s.ID, nil
+	}
+
+	now := time.Now().UTC()
+	s.CreatedAt = now
+	s.NextRunAt = s.after(now)
+	s.LastRunAt, s.LastTaskID, s.LastError = nil, "", ""
+	if s.NextRunAt.IsZero() {
+		return "", fmt.Errorf("%w: cron expression %q never fires", ErrInvalidSchedule, s.Cron)
+	}
+	if err := e.store.SaveSchedule(&s); err != nil {
+		return "", fmt.Errorf("persist schedule: %w", err)
+	}
+	log.Info("schedule created", "id", s.ID, "type", s.Task.Type, "nextRunAt", s.NextRunAt)
+	e.wakeScheduler()
+	return s.ID, nil
+}
+
+// validateSchedule checks a schedule and defaults its MissedPolicy. Every
+// failure wraps ErrInvalidSchedule.
+func (e *Engine) validateSchedule(s *Schedule) error {
+	switch {
+	case s.Cron == "" && s.Interval == 0:
+		return fmt.Errorf("%w: one of cron or interval is required", ErrInvalidSchedule)
+	case s.Cron != "" && s.Interval != 0:
+		return fmt.Errorf("%w: cron and interval are mutually exclusive", ErrInvalidSchedule)
+	case s.Interval != 0 && s.Interval < MinScheduleInterval:
+		return fmt.Errorf("%w: interval %s is below the %s minimum", ErrInvalidSchedule, s.Interval, MinScheduleInterval)
+	}
+	if s.Cron != "" {
+		if _, err := parseCron(s.Cron); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+		}
+	}
+
+	switch s.MissedPolicy {
+	case "":
+		s.MissedPolicy = MissedRunSkip
+	case MissedRunSkip, MissedRunCatchUp:
+	default:
+		return fmt.Errorf("%w: unknown missed-run policy %q", ErrInvalidSchedule, s.MissedPolicy)
+	}
+
+	switch {
+	case s.Task.ID != "":
+		return fmt.Errorf("%w: task template must not carry an ID", ErrInvalidSchedule)
+	case s.Task.Timeout < 0:
+		return fmt.Errorf("%w: %w", ErrInvalidSchedule, ErrInvalidTimeout)
+	}
+	handler, ok := e.handlers[s.Task.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown task type: %s", ErrInvalidSchedule, s.Task.Type)
+	}
+	if err := checkParams(handler, s.Task.Params); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+	return nil
+}
+
+// GetSchedule returns a schedule, or nil when it does not exist or cannot be
+// read.
+func (e *Engine) GetSchedule(id string) *Schedule {
+	s, err := e.store.GetSchedule(id)
+	if err != nil {
+		log.Error("failed to get schedule", "id", id, "err", err)
+		return nil
+	}
+	return s
+}
+
+// ListSchedules returns every schedule, soonest firing first.
+func (e *Engine) ListSchedules() []Schedule {
+	schedules, err := e.store.ListSchedules()
+	if err != nil {
+		log.Error("failed to list schedules", "err", err)
+		return nil
+	}
+	return schedules
+}
+
+// DeleteSchedule removes a schedule so it never fires again. Tasks it has
+// already submitted are unaffected. Returns true if it existed.
+func (e *Engine) DeleteSchedule(id string) (bool, error) {
+	e.scheduleMu.Lock()
+	defer e.scheduleMu.Unlock()
+	deleted, err := e.store.DeleteSchedule(id)
+	if err != nil {
+		return false, err
+	}
+	if deleted {
+		log.Info("schedule deleted", "id", id)
+		e.wakeScheduler()
+	}
+	return deleted, nil
+}
+
+// StartScheduler fires due schedules on a background goroutine until the
+// engine context ends. Call once, after RehydrateStaleTasks, so firings
+// missed while the process was down are handled per each schedule's policy
+// against a fully rehydrated engine.
+func (e *Engine) StartScheduler() {
+	go e.runScheduler()
+}
+
+func (e *Engine) runScheduler() {
+	for {
+		wait := time.Hour
+		if next := e.fireDueSchedules(time.Now().UTC()); !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-e.ctx.Done():
+			timer.Stop()
+			return
+		case <-e.scheduleWake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// wakeScheduler makes the scheduler re-read its schedules, e.g. after one is
+// created with an earlier next firing than the scheduler is sleeping until.
+func (e *Engine) wakeScheduler() {
+	select {
+	case e.scheduleWake <- struct{}{}:
+	default:
+	}
+}
+
+// fireDueSchedules submits every firing due at now and advances each fired
+// schedule past now. It returns the earliest upcoming firing across all
+// schedules, or the zero time when there is none.
+func (e *Engine) fireDueSchedules(now time.Time) time.Time {
+	e.scheduleMu.Lock()
+	defer e.scheduleMu.Unlock()
+
+	schedules, err := e.store.ListSchedules()
+	if err != nil {
+		log.Error("failed to list schedules", "err", err)
+		return now.Add(time.Minute)
+	}
+
+	var earliest time.Time
+	for i := range schedules {
+		s := &schedules[i]
+		if !s.NextRunAt.After(now) {
+			e.fireSchedule(s, now)
+		}
+		if !s.NextRunAt.IsZero() && (earliest.IsZero() || s.NextRunAt.Before(earliest)) {
+			earliest = s.NextRunAt
+		}
+	}
+	return earliest
+}
+
+// fireSchedule submits a due schedule's firings per its MissedPolicy, then
+// persists its next firing. Callers MUST hold e.scheduleMu.
+func (e *Engine) fireSchedule(s *Schedule, now time.Time) {
+	// An interval schedule fast-forwards over firings the cap would drop
+	// anyway rather than stepping through a long outage one at a time.
+	if s.Interval > 0 {
+		if missed := int64(now.Sub(s.NextRunAt) / s.Interval); missed > maxCatchUpRuns {
+			s.NextRunAt = s.NextRunAt.Add(time.Duration(missed-maxCatchUpRuns) * s.Interval)
+		}
+	}
+
+	var due []time.Time
+	for at := s.NextRunAt; !at.IsZero() && !at.After(now); at = s.after(at) {
+		due = append(due, at)
+		if len(due) > maxCatchUpRuns {
+			due = due[1:]
+		}
+		s.NextRunAt = s.after(at)
+	}
+	if len(due) == 0 {
+		return
+	}
+	if s.MissedPolicy != MissedRunCatchUp {
+		due = due[len(due)-1:]
+	}
+
+	for _, at := range due {
+		task := s.Task
+		task.ID = scheduleTaskID(s.ID, at)
+		task.SubmittedBy = s.submitter()
+		id, err := e.Submit(task)
+		t := at
+		s.LastRunAt = &t
+		if err != nil {
+			log.Error("scheduled task not submitted", "schedule", s.ID, "firing", at, "err", err)
+			s.LastError = err.Error()
+			continue
+		}
+		log.Info("scheduled task submitted", "schedule", s.ID, "id", id, "firing", at)
+		s.LastTaskID, s.LastError = id, ""
+	}
+
+	if s.NextRunAt.IsZero() {
+		log.Warn("schedule has no further firings", "id", s.ID, "cron", s.Cron)
+	}
+	if err := e.store.SaveSchedule(s); err != nil {
+		log.Error("failed to persist schedule; firing will be re-driven", "id", s.ID, "err", err)
+	}
+}
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func scheduleTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	return newTestEngine(t, map[TaskType]TaskHandler{
+		TaskConfigValidate: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+}
+
+func TestCreateScheduleRejectsInvalid(t *testing.T) {
+	eng := scheduleTestEngine(t)
+	task := Task{Type: TaskConfigValidate}
+
+	for name, s := range map[string]Schedule{
+		"no cadence":      {Task: task},
+		"cron+interval":   {Cron: "@daily", Interval: time.Hour, Task: task},
+		"short interval":  {Interval: time.Millisecond, Task: task},
+		"bad cron":        {Cron: "61 * * * *", Task: task},
+		"never fires":     {Cron: "0 0 31 2 *", Task: task},
+		"bad policy":      {Interval: time.Hour, MissedPolicy: "sometimes", Task: task},
+		"templated ID":    {Interval: time.Hour, Task: Task{ID: "00000000-0000-0000-0000-000000000001", Type: TaskConfigValidate}},
+		"unknown type":    {Interval: time.Hour, Task: Task{Type: TaskSnapshotUpload}},
+		"negative budget": {Interval: time.Hour, Task: Task{Type: TaskConfigValidate, Timeout: -time.Second}},
+	} {
+		if _, err := eng.CreateSchedule(s); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("%s: err = %v, want ErrInvalidSchedule", name, err)
+		}
+	}
+	if got := eng.ListSchedules(); len(got) != 0 {
+		t.Fatalf("invalid schedules persisted: %+v", got)
+	}
+}
+
+func TestCreateScheduleIsIdempotent(t *testing.T) {
+	eng := scheduleTestEngine(t)
+	id := "5f1c7c2e-2b55-4d8a-9c4f-6a5b3f0e2d11"
+
+	for range 2 {
+		got, err := eng.CreateSchedule(Schedule{ID: id, Cron: "0 3 * * *", Task: Task{Type: TaskConfigValidate}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Fatalf("id = %s, want %s", got, id)
+		}
+	}
+	s := eng.GetSchedule(id)
+	if s == nil || s.MissedPolicy != MissedRunSkip || s.NextRunAt.Hour() != 3 {
+		t.Fatalf("schedule = %+v, want skip policy and a 03:00 next firing", s)
+	}
+	if got := eng.ListSchedules(); len(got) != 1 {
+		t.Fatalf("got %d schedules, want 1", len(got))
+	}
+}
+
+// fireOverdue fires a schedule three and a half intervals overdue and returns
+// the engine, the fired schedule, and its four due firing times, oldest first.
+func fireOverdue(t *testing.T, policy MissedRunPolicy) (*Engine, *Schedule, []time.Time) {
+	t.Helper()
+	eng := scheduleTestEngine(t)
+	id, err := eng.CreateSchedule(Schedule{Interval: time.Minute, MissedPolicy: policy, Task: Task{Type: TaskConfigValidate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := eng.GetSchedule(id)
+	first := s.NextRunAt
+	due := []time.Time{first, first.Add(time.Minute), first.Add(2 * time.Minute), first.Add(3 * time.Minute)}
+
+	next := eng.fireDueSchedules(first.Add(3*time.Minute + 30*time.Second))
+	if want := first.Add(4 * time.Minute); !next.Equal(want) {
+		t.Fatalf("earliest next firing = %s, want %s", next, want)
+	}
+	return eng, eng.GetSchedule(id), due
+}
+
+func TestFireSchedulesSkipRunsOnlyLatest(t *testing.T) {
+	eng, s, due := fireOverdue(t, MissedRunSkip)
+
+	latest := scheduleTaskID(s.ID, due[3])
+	if r := waitForResult(t, eng, latest); r.Status != TaskStatusCompleted {
+		t.Fatalf("latest firing status = %q, want completed", r.Status)
+	}
+	for _, at := range due[:3] {
+		if r := eng.GetResult(scheduleTaskID(s.ID, at)); r != nil {
+			t.Fatalf("skipped firing at %s ran as %s", at, r.ID)
+		}
+	}
+	if s.LastTaskID != latest || !s.LastRunAt.Equal(due[3]) || !s.NextRunAt.Equal(due[3].Add(time.Minute)) {
+		t.Fatalf("schedule = %+v, want last %s at %s", s, latest, due[3])
+	}
+}
+
+func TestFireSchedulesCatchUpRunsEachMissed(t *testing.T) {
+	eng, s, due := fireOverdue(t, MissedRunCatchUp)
+
+	for _, at := range due {
+		waitForResult(t, eng, scheduleTaskID(s.ID, at))
+	}
+	if got := len(eng.RecentResults()); got != len(due) {
+		t.Fatalf("got %d tasks, want %d", got, len(due))
+	}
+	if s.LastTaskID != scheduleTaskID(s.ID, due[3]) {
+		t.Fatalf("lastTaskId = %s, want the newest firing", s.LastTaskID)
+	}
+}
+
+// A firing re-driven after a crash lost the schedule's advanced next_run_at
+// resolves to the same task IDs, so nothing runs twice.
+func TestRefiringScheduleIsIdempotent(t *testing.T) {
+	eng, s, due := fireOverdue(t, MissedRunCatchUp)
+	for _, at := range due {
+		waitForResult(t, eng, scheduleTaskID(s.ID, at))
+	}
+
+	s.NextRunAt = due[0]
+	if err := eng.store.SaveSchedule(s); err != nil {
+		t.Fatal(err)
+	}
+	eng.fireDueSchedules(due[3].Add(30 * time.Second))
+	if got := len(eng.RecentResults()); got != len(due) {
+		t.Fatalf("got %d tasks after refiring, want %d", got, len(due))
+	}
+}
+
+func TestCatchUpIsCapped(t *testing.T) {
+	eng := scheduleTestEngine(t)
+	id, err := eng.CreateSchedule(Schedule{Interval: time.Minute, MissedPolicy: MissedRunCatchUp, Task: Task{Type: TaskConfigValidate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := eng.GetSchedule(id).NextRunAt
+
+	eng.fireDueSchedules(first.Add(24 * time.Hour))
+	s := eng.GetSchedule(id)
+	if !s.LastRunAt.Equal(first.Add(24 * time.Hour)) {
+		t.Fatalf("lastRunAt = %s, want the newest firing", s.LastRunAt)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(eng.RecentResults()) < maxCatchUpRuns && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(eng.RecentResults()); got != maxCatchUpRuns {
+		t.Fatalf("got %d tasks, want %d", got, maxCatchUpRuns)
+	}
+}
+
+func TestSchedulerFiresDueSchedule(t *testing.T) {
+	eng := scheduleTestEngine(t)
+	eng.StartScheduler()
+	id, err := eng.CreateSchedule(Schedule{Interval: time.Hour, Task: Task{Type: TaskConfigValidate}, CreatedBy: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pull the first firing into the past, as after a restart.
+	s := eng.GetSchedule(id)
+	due := time.Now().UTC().Add(-time.Minute)
+	s.NextRunAt = due
+	if err := eng.store.SaveSchedule(s); err != nil {
+		t.Fatal(err)
+	}
+	eng.wakeScheduler()
+
+	if r := waitForResult(t, eng, scheduleTaskID(id, due)); r.SubmittedBy != "schedule:"+id+" by alice" {
+		t.Fatalf("submittedBy = %q, want schedule:%s by alice", r.SubmittedBy, id)
+	}
+	if s := eng.GetSchedule(id); !s.NextRunAt.Equal(due.Add(time.Hour)) {
+		t.Fatalf("nextRunAt = %s, want %s", s.NextRunAt, due.Add(time.Hour))
+	}
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	eng := scheduleTestEngine(t)
+	id, err := eng.CreateSchedule(Schedule{Cron: "@daily", Task: Task{Type: TaskConfigValidate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted, err := eng.DeleteSchedule(id); err != nil || !deleted {
+		t.Fatalf("DeleteSchedule = %v, %v; want true, nil", deleted, err)
+	}
+	if eng.GetSchedule(id) != nil {
+		t.Fatal("schedule still present after delete")
+	}
+	if deleted, err := eng.DeleteSchedule(id); err != nil || deleted {
+		t.Fatalf("second DeleteSchedule = %v, %v; want false, nil", deleted, err)
+	}
+}
@@ -1,6 +1,13 @@
 package engine
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion is the user_version migrate brings a database to.
+const SchemaVersion = 20
 
 // migrate runs pending schema migrations. Each version is wrapped in an
 // explicit transaction so that DDL and the user_version bump are atomic.
@@ -147,5 +154,486 @@ func migrate(db *sql.DB) error {
 		}
 	}
 
+	if version < 6 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// attempt / next_attempt_at: automatic-retry bookkeeping. A pending
+		// retry keeps its row 'running' with next_attempt_at set so
+		// rehydration resumes the backoff instead of restarting it.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_results ADD COLUMN attempt INTEGER NOT NULL DEFAULT 1;
+			ALTER TABLE task_results ADD COLUMN next_attempt_at TEXT;
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 6"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 7 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// deadline: absolute execution deadline from the submission's
+		// timeout, kept so rehydration does not grant a fresh budget.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_results ADD COLUMN deadline TEXT;
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 7"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 8 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// task_graphs / task_graph_nodes: DAG submissions. Node state is
+		// not stored here — it is read from the node's task_results row
+		// (absent until the node starts).
+		if _, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS task_graphs (
+				id           TEXT PRIMARY KEY,
+				phase        TEXT NOT NULL,
+				submitted_at TEXT NOT NULL,
+				completed_at TEXT
+			);
+			CREATE INDEX IF NOT EXISTS idx_task_graphs_phase
+				ON task_graphs (phase);
+			CREATE TABLE IF NOT EXISTS task_graph_nodes (
+				graph_id   TEXT    NOT NULL,
+				position   INTEGER NOT NULL,
+				name       TEXT    NOT NULL,
+				task_id    TEXT    NOT NULL,
+				type       TEXT    NOT NULL,
+				params     TEXT,
+				timeout_ns INTEGER NOT NULL DEFAULT 0,
+				depends_on TEXT    NOT NULL DEFAULT '[]',
+				PRIMARY KEY (graph_id, name)
+			);
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 8"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 9 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// priority: queue order of pending tasks, higher first. The index
+		// serves the rehydration scan that rebuilds the queue.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_results ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+			CREATE INDEX IF NOT EXISTS idx_task_results_status_priority
+				ON task_results (status, priority DESC, submitted_at);
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 9"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 10 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// task_schedules: recurring task templates. Each firing is an
+		// ordinary task_results row; the schedule row only tracks when the
+		// next one is due.
+		if _, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS task_schedules (
+				id            TEXT    PRIMARY KEY,
+				cron          TEXT    NOT NULL DEFAULT '',
+				interval_ns   INTEGER NOT NULL DEFAULT 0,
+				task_type     TEXT    NOT NULL,
+				params        TEXT,
+				timeout_ns    INTEGER NOT NULL DEFAULT 0,
+				priority      INTEGER NOT NULL DEFAULT 0,
+				missed_policy TEXT    NOT NULL,
+				next_run_at   TEXT    NOT NULL,
+				last_run_at   TEXT,
+				last_task_id  TEXT    NOT NULL DEFAULT '',
+				last_error    TEXT    NOT NULL DEFAULT '',
+				created_at    TEXT    NOT NULL
+			);
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 10"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 11 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// cancelled_by / cancel_reason: who cancelled a task through
+		// POST /v0/tasks/{id}:cancel, and why.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_results ADD COLUMN cancelled_by  TEXT NOT NULL DEFAULT '';
+			ALTER TABLE task_results ADD COLUMN cancel_reason TEXT NOT NULL DEFAULT '';
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 11"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 12 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// progress: the handler's latest reported progress as JSON, NULL
+		// when it never reported.
+		if _, err := tx.Exec(`ALTER TABLE task_results ADD COLUMN progress TEXT`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 12"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 13 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// task_events: the lifecycle event log behind GET /v0/events.
+		// AUTOINCREMENT keeps seq strictly increasing across pruning, so a
+		// client's Last-Event-ID never names a reused sequence.
+		if _, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS task_events (
+				seq     INTEGER PRIMARY KEY AUTOINCREMENT,
+				type    TEXT NOT NULL,
+				task_id TEXT NOT NULL,
+				task    TEXT NOT NULL,
+				at      TEXT NOT NULL
+			);
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 13"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 14 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Serves retention's per-type ranking and LatestByType.
+		if _, err := tx.Exec(`
+			CREATE INDEX IF NOT EXISTS idx_task_results_type_submitted_at
+				ON task_results (type, submitted_at DESC);
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 14"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 15 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Rewrite task_results timestamps from RFC3339Nano, whose trimmed
+		// fractions do not sort in time order, to the fixed-width
+		// storedTimeFormat that range filters and cursors compare on.
+		if err := normalizeResultTimes(tx); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 15"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 16 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// ledger_import: the single row marks a ledger import that has
+		// started but not finished, so serve refuses the partial store.
+		if _, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ledger_import (
+				id         INTEGER PRIMARY KEY CHECK (id = 1),
+				checksum   TEXT NOT NULL,
+				started_at TEXT NOT NULL
+			);
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 16"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 17 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// submitted_by: the identity that submitted a task or graph — an
+		// API caller, or "schedule:<id>" for a scheduled firing.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_results ADD COLUMN submitted_by TEXT NOT NULL DEFAULT '';
+			ALTER TABLE task_graphs  ADD COLUMN submitted_by TEXT NOT NULL DEFAULT '';
+		`); err != nil {
+			return err
+		}
+
+		// audit_log: the append-only record behind GET /v0/audit. The
+		// triggers refuse updates and deletes so nothing short of editing
+		// the file rewrites history.
+		if _, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS audit_log (
+				seq           INTEGER PRIMARY KEY AUTOINCREMENT,
+				at            TEXT NOT NULL,
+				request_id    TEXT NOT NULL DEFAULT '',
+				remote_addr   TEXT NOT NULL DEFAULT '',
+				user          TEXT NOT NULL DEFAULT '',
+				groups        TEXT,
+				extra         TEXT,
+				action        TEXT NOT NULL,
+				task_id       TEXT NOT NULL DEFAULT '',
+				task_type     TEXT NOT NULL DEFAULT '',
+				params_digest TEXT NOT NULL DEFAULT '',
+				status        INTEGER NOT NULL
+			);
+			CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+			CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 17"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 18 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// timeout: the task's execution budget in nanoseconds, kept so a
+		// task queued Pending starts its deadline clock when it starts
+		// running rather than at submission.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_results ADD COLUMN timeout INTEGER NOT NULL DEFAULT 0;
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 18"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 19 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// started: set before a node's task is submitted, so a node whose
+		// task row is later deleted or pruned is never submitted again.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_graph_nodes ADD COLUMN started INTEGER NOT NULL DEFAULT 0;
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 19"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if version < 20 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// created_by: the caller who created the schedule, carried onto
+		// each firing's submitted_by.
+		if _, err := tx.Exec(`
+			ALTER TABLE task_schedules ADD COLUMN created_by TEXT NOT NULL DEFAULT '';
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("PRAGMA user_version = 20"); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeResultTimes rewrites every task_results submitted_at and
+// completed_at in storedTimeFormat.
+func normalizeResultTimes(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, submitted_at, completed_at FROM task_results`)
+	if err != nil {
+		return err
+	}
+	type stamp struct {
+		id, submitted string
+		completed     sql.NullString
+	}
+	var stamps []stamp
+	for rows.Next() {
+		var st stamp
+		if err := rows.Scan(&st.id, &st.submitted, &st.completed); err != nil {
+			rows.Close()
+			return err
+		}
+		stamps = append(stamps, st)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, st := range stamps {
+		submitted, err := time.Parse(time.RFC3339Nano, st.submitted)
+		if err != nil {
+			return fmt.Errorf("task %s submitted_at: %w", st.id, err)
+		}
+		var completed any
+		if st.completed.Valid {
+			t, err := time.Parse(time.RFC3339Nano, st.completed.String)
+			if err != nil {
+				return fmt.Errorf("task %s completed_at: %w", st.id, err)
+			}
+			completed = formatTime(t)
+		}
+		if _, err := tx.Exec(`UPDATE task_results SET submitted_at = ?, completed_at = ? WHERE id = ?`,
+			formatTime(submitted), completed, st.id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -23,7 +24,7 @@ type SQLiteStore struct {
 // filesystem (e.g. EBS, GCE PD, local SSD). WAL mode is unsafe on
 // NFS-backed volumes (EFS, Azure Files, CephFS over NFS) because they
 // do not support the POSIX byte-range locks that SQLite requires for
-// the shared-memory (-shm) file.
+// the shared-memory (-shm) file. Use a JournalStore on such volumes.
 func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 	return openStore(dbPath)
 }
@@ -63,28 +64,61 @@ func openStore(dsn string) (*SQLiteStore, error) {
 }
 
 func (s *SQLiteStore) Save(r *TaskResult) error {
+	return saveResult(s.db, r)
+}
+
+// execer is the Exec method *sql.DB and *sql.Tx share.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func saveResult(db execer, r *TaskResult) error {
 	params, err := json.Marshal(r.Params)
 	if err != nil {
 		return fmt.Errorf("marshal params: %w", err)
 	}
+	progress, err := marshalProgress(r.Progress)
+	if err != nil {
+		return err
+	}
 
-	_, err = s.db.Exec(`
+	_, err = db.Exec(`
 		INSERT OR REPLACE INTO task_results
-			(id, type, status, run, params, result, error, submitted_at, completed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			(id, type, status, run, attempt, params, result, error, submitted_at, completed_at, next_attempt_at, deadline, timeout,
+			 priority, submitted_by, cancelled_by, cancel_reason, progress)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		r.ID,
 		r.Type,
 		string(r.Status),
 		r.Run,
+		r.Attempt,
 		string(params),
 		nullableRawJSON(r.Result),
 		r.Error,
-		r.SubmittedAt.UTC().Format(time.RFC3339Nano),
+		formatTime(r.SubmittedAt),
 		formatNullableTime(r.CompletedAt),
+		formatNullableTime(r.NextAttemptAt),
+		formatNullableTime(r.Deadline),
+		int64(r.Timeout),
+		r.Priority,
+		r.SubmittedBy,
+		r.CancelledBy,
+		r.CancelReason,
+		progress,
 	)
 	return err
 }
 
+func (s *SQLiteStore) SaveProgress(id string, p *Progress) error {
+	progress, err := marshalProgress(p)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE task_results SET progress = ? WHERE id = ? AND status = ?`,
+		progress, id, string(TaskStatusRunning))
+	return err
+}
+
 func (s *SQLiteStore) Get(id string) (*TaskResult, error) {
 	row := s.db.QueryRow(selectColumns+` WHERE id = ?`, id)
 	r, err := scanTaskResult(row)
@@ -101,10 +135,46 @@ func (s *SQLiteStore) List(limit int) ([]TaskResult, error) {
 	return s.queryMany(selectColumns+` ORDER BY submitted_at DESC LIMIT ?`, limit)
 }
 
+func (s *SQLiteStore) QueryResults(f ResultFilter) ([]TaskResult, error) {
+	var where []string
+	var args []any
+	if f.Type != "" {
+		where = append(where, "type = ?")
+		args = append(args, f.Type)
+	}
+	if f.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, string(f.Status))
+	}
+	if !f.SubmittedAfter.IsZero() {
+		where = append(where, "submitted_at >= ?")
+		args = append(args, formatTime(f.SubmittedAfter))
+	}
+	if !f.SubmittedBefore.IsZero() {
+		where = append(where, "submitted_at < ?")
+		args = append(args, formatTime(f.SubmittedBefore))
+	}
+	if f.After != nil {
+		at := formatTime(f.After.SubmittedAt)
+		where = append(where, "(submitted_at < ? OR (submitted_at = ? AND id < ?))")
+		args = append(args, at, at, f.After.ID)
+	}
+	query := selectColumns
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY submitted_at DESC, id DESC LIMIT ?"
+	return s.queryMany(query, append(args, f.Limit)...)
+}
+
 func (s *SQLiteStore) ListStaleTasks() ([]TaskResult, error) {
 	return s.queryMany(selectColumns+` WHERE status = ?`, string(TaskStatusRunning))
 }
 
+func (s *SQLiteStore) ListPendingTasks() ([]TaskResult, error) {
+	return s.queryMany(selectColumns+` WHERE status = ? ORDER BY priority DESC, submitted_at`, string(TaskStatusPending))
+}
+
 func (s *SQLiteStore) Delete(id string) (bool, error) {
 	res, err := s.db.Exec("DELETE FROM task_results WHERE id = ?", id)
 	if err != nil {
@@ -135,17 +205,322 @@ func (s *SQLiteStore) LatestByType(taskType string) (*TaskResult, error) {
 	return r, nil
 }
 
+func (s *SQLiteStore) PruneResults(before time.Time, maxPerType int, keep []string) (PruneCounts, error) {
+	counts := PruneCounts{ByAge: map[string]int{}, ByCount: map[string]int{}}
+	settled := `status NOT IN (?, ?)`
+	args := []any{string(TaskStatusPending), string(TaskStatusRunning)}
+	if len(keep) > 0 {
+		settled += ` AND id NOT IN (?` + strings.Repeat(`, ?`, len(keep)-1) + `)`
+		for _, id := range keep {
+			args = append(args, id)
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return counts, err
+	}
+	defer tx.Rollback()
+
+	if !before.IsZero() {
+		query := `DELETE FROM task_results WHERE ` + settled +
+			` AND COALESCE(completed_at, submitted_at) < ? RETURNING type`
+		if err := collectPruned(tx, counts.ByAge, query, append(args, formatTime(before))...); err != nil {
+			return counts, fmt.Errorf("prune by age: %w", err)
+		}
+	}
+	if maxPerType > 0 {
+		query := `DELETE FROM task_results WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY type ORDER BY submitted_at DESC) AS rank
+				FROM task_results WHERE ` + settled + `
+			) WHERE rank > ?
+		) RETURNING type`
+		if err := collectPruned(tx, counts.ByCount, query, append(args, maxPerType)...); err != nil {
+			return counts, fmt.Errorf("prune by count: %w", err)
+		}
+	}
+	return counts, tx.Commit()
+}
+
+// collectPruned runs a DELETE ... RETURNING type and tallies the deleted
+// rows by type into into.
+func collectPruned(tx *sql.Tx, into map[string]int, query string, args ...any) error {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var typ string
+		if err := rows.Scan(&typ); err != nil {
+			return err
+		}
+		into[typ]++
+	}
+	return rows.Err()
+}
+
+func (s *SQLiteStore) Compact() error {
+	_, err := s.db.Exec("VACUUM")
+	return err
+}
+
+func (s *SQLiteStore) SaveGraph(g *TaskGraph) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO task_graphs (id, phase, submitted_at, completed_at, submitted_by)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET phase = excluded.phase, completed_at = excluded.completed_at`,
+		g.ID,
+		string(g.Phase),
+		formatTime(g.SubmittedAt),
+		formatNullableTime(g.CompletedAt),
+		g.SubmittedBy,
+	); err != nil {
+		return err
+	}
+
+	for i, n := range g.Nodes {
+		params, err := json.Marshal(n.Params)
+		if err != nil {
+			return fmt.Errorf("marshal params for node %q: %w", n.Name, err)
+		}
+		deps, err := json.Marshal(n.DependsOn)
+		if err != nil {
+			return fmt.Errorf("marshal dependsOn for node %q: %w", n.Name, err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO task_graph_nodes
+				(graph_id, position, name, task_id, type, params, timeout_ns, depends_on, started)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (graph_id, name) DO UPDATE SET started = started OR excluded.started`,
+			g.ID, i, n.Name, n.ID, string(n.Type), string(params), int64(n.Timeout), string(deps), n.Started,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) GetGraph(id string) (*TaskGraph, error) {
+	graphs, err := s.queryGraphs(`SELECT id, phase, submitted_at, completed_at, submitted_by FROM task_graphs WHERE id = ?`, id)
+	if err != nil || len(graphs) == 0 {
+		return nil, err
+	}
+	return &graphs[0], nil
+}
+
+func (s *SQLiteStore) ListActiveGraphs() ([]TaskGraph, error) {
+	return s.queryGraphs(`SELECT id, phase, submitted_at, completed_at, submitted_by FROM task_graphs WHERE phase = ? ORDER BY submitted_at`,
+		string(GraphPhaseRunning))
+}
+
+// queryGraphs loads the task_graphs rows a query selects, then each graph's
+// nodes in submission order.
+func (s *SQLiteStore) queryGraphs(query string, args ...any) ([]TaskGraph, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var graphs []TaskGraph
+	for rows.Next() {
+		var (
+			g           TaskGraph
+			phase       string
+			submittedAt string
+			completedAt sql.NullString
+		)
+		if err := rows.Scan(&g.ID, &phase, &submittedAt, &completedAt, &g.SubmittedBy); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		g.Phase = GraphPhase(phase)
+		if g.SubmittedAt, err = time.Parse(time.RFC3339Nano, submittedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("parse submitted_at: %w", err)
+		}
+		if completedAt.Valid {
+			t, err := time.Parse(time.RFC3339Nano, completedAt.String)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("parse completed_at: %w", err)
+			}
+			g.CompletedAt = &t
+		}
+		graphs = append(graphs, g)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Nodes are read after the graph cursor is closed: the store runs a
+	// single connection, so a nested query would deadlock.
+	for i := range graphs {
+		if graphs[i].Nodes, err = s.graphNodes(graphs[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return graphs, nil
+}
+
+func (s *SQLiteStore) graphNodes(graphID string) ([]GraphNode, error) {
+	rows, err := s.db.Query(`
+		SELECT name, task_id, type, params, timeout_ns, depends_on, started
+		FROM task_graph_nodes WHERE graph_id = ? ORDER BY position`, graphID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []GraphNode
+	for rows.Next() {
+		var (
+			n          GraphNode
+			taskType   string
+			paramsJSON sql.NullString
+			timeoutNs  int64
+			depsJSON   string
+		)
+		if err := rows.Scan(&n.Name, &n.ID, &taskType, &paramsJSON, &timeoutNs, &depsJSON, &n.Started); err != nil {
+			return nil, err
+		}
+		n.Type = TaskType(taskType)
+		n.Timeout = time.Duration(timeoutNs)
+		if paramsJSON.Valid && paramsJSON.String != "" {
+			if err := unmarshalStored([]byte(paramsJSON.String), &n.Params); err != nil {
+				return nil, fmt.Errorf("unmarshal params for node %q: %w", n.Name, err)
+			}
+		}
+		if err := json.Unmarshal([]byte(depsJSON), &n.DependsOn); err != nil {
+			return nil, fmt.Errorf("unmarshal dependsOn for node %q: %w", n.Name, err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+func (s *SQLiteStore) SaveSchedule(sc *Schedule) error {
+	params, err := json.Marshal(sc.Task.Params)
+	if err != nil {
+		return fmt.Errorf("marshal params: %w", err)
+	}
+	_, err = s.db.Exec(`
+		INSERT OR REPLACE INTO task_schedules
+			(id, cron, interval_ns, task_type, params, timeout_ns, priority, missed_policy,
+			 next_run_at, last_run_at, last_task_id, last_error, created_at, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sc.ID,
+		sc.Cron,
+		int64(sc.Interval),
+		string(sc.Task.Type),
+		string(params),
+		int64(sc.Task.Timeout),
+		sc.Task.Priority,
+		string(sc.MissedPolicy),
+		formatTime(sc.NextRunAt),
+		formatNullableTime(sc.LastRunAt),
+		sc.LastTaskID,
+		sc.LastError,
+		formatTime(sc.CreatedAt),
+		sc.CreatedBy,
+	)
+	return err
+}
+
+func (s *SQLiteStore) GetSchedule(id string) (*Schedule, error) {
+	schedules, err := s.querySchedules(scheduleColumns+` WHERE id = ?`, id)
+	if err != nil || len(schedules) == 0 {
+		return nil, err
+	}
+	return &schedules[0], nil
+}
+
+func (s *SQLiteStore) ListSchedules() ([]Schedule, error) {
+	return s.querySchedules(scheduleColumns + ` ORDER BY next_run_at, id`)
+}
+
+func (s *SQLiteStore) DeleteSchedule(id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM task_schedules WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const scheduleColumns = `
+	SELECT id, cron, interval_ns, task_type, params, timeout_ns, priority, missed_policy,
+	       next_run_at, last_run_at, last_task_id, last_error, created_at, created_by
+	FROM task_schedules`
+
+func (s *SQLiteStore) querySchedules(query string, args ...any) ([]Schedule, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []Schedule
+	for rows.Next() {
+		var (
+			sc         Schedule
+			intervalNs int64
+			taskType   string
+			paramsJSON sql.NullString
+			timeoutNs  int64
+			policy     string
+			nextRunAt  string
+			lastRunAt  sql.NullString
+			createdAt  string
+		)
+		if err := rows.Scan(&sc.ID, &sc.Cron, &intervalNs, &taskType, &paramsJSON, &timeoutNs,
+			&sc.Task.Priority, &policy, &nextRunAt, &lastRunAt, &sc.LastTaskID, &sc.LastError, &createdAt, &sc.CreatedBy); err != nil {
+			return nil, err
+		}
+		sc.Interval = time.Duration(intervalNs)
+		sc.Task.Type = TaskType(taskType)
+		sc.Task.Timeout = time.Duration(timeoutNs)
+		sc.MissedPolicy = MissedRunPolicy(policy)
+		if paramsJSON.Valid && paramsJSON.String != "" {
+			if err := unmarshalStored([]byte(paramsJSON.String), &sc.Task.Params); err != nil {
+				return nil, fmt.Errorf("unmarshal params for schedule %s: %w", sc.ID, err)
+			}
+		}
+		if sc.NextRunAt, err = time.Parse(time.RFC3339Nano, nextRunAt); err != nil {
+			return nil, fmt.Errorf("parse next_run_at: %w", err)
+		}
+		if sc.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("parse created_at: %w", err)
+		}
+		if lastRunAt.Valid {
+			t, err := time.Parse(time.RFC3339Nano, lastRunAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("parse last_run_at: %w", err)
+			}
+			sc.LastRunAt = &t
+		}
+		schedules = append(schedules, sc)
+	}
+	return schedules, rows.Err()
+}
+
 // SaveTxMarker persists a pre-broadcast marker and fsyncs it (via checkpoint,
 // since the store runs synchronous=NORMAL) before returning, so it survives a
 // crash. Callers MUST let it return before broadcasting.
 func (s *SQLiteStore) SaveTxMarker(m *TxMarker) error {
-	if _, err := s.db.Exec(`
-		INSERT OR REPLACE INTO tx_markers
-			(task_id, tx_hash, tx_bytes, account_number, sequence, chain_id, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		m.TaskID, m.TxHash, m.TxBytes, m.AccountNumber, m.Sequence, m.ChainID,
-		time.Now().UTC().Format(time.RFC3339Nano),
-	); err != nil {
+	if err := saveTxMarker(s.db, m); err != nil {
 		return err
 	}
 	// Ignoring FULL's busy row is safe only under SetMaxOpenConns(1) (no
@@ -156,6 +531,17 @@ func (s *SQLiteStore) SaveTxMarker(m *TxMarker) error {
 	return nil
 }
 
+func saveTxMarker(db execer, m *TxMarker) error {
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO tx_markers
+			(task_id, tx_hash, tx_bytes, account_number, sequence, chain_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		m.TaskID, m.TxHash, m.TxBytes, m.AccountNumber, m.Sequence, m.ChainID,
+		formatTime(time.Now()),
+	)
+	return err
+}
+
 func (s *SQLiteStore) GetTxMarker(taskID string) (*TxMarker, error) {
 	row := s.db.QueryRow(
 		`SELECT task_id, tx_hash, tx_bytes, account_number, sequence, chain_id
@@ -171,6 +557,124 @@ func (s *SQLiteStore) GetTxMarker(taskID string) (*TxMarker, error) {
 	return &m, nil
 }
 
+func (s *SQLiteStore) AppendEvent(ev *TaskEvent, keep int) error {
+	task, err := json.Marshal(ev.Task)
+	if err != nil {
+		return fmt.Errorf("marshal event task: %w", err)
+	}
+	res, err := s.db.Exec(`INSERT INTO task_events (type, task_id, task, at) VALUES (?, ?, ?, ?)`,
+		string(ev.Type), ev.Task.ID, string(task), formatTime(ev.At))
+	if err != nil {
+		return err
+	}
+	seq, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	ev.Seq = seq
+	if seq > int64(keep) {
+		if _, err := s.db.Exec(`DELETE FROM task_events WHERE seq <= ?`, seq-int64(keep)); err != nil {
+			return fmt.Errorf("prune events: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListEvents(after int64, limit int) ([]TaskEvent, error) {
+	rows, err := s.db.Query(`SELECT seq, type, task, at FROM task_events WHERE seq > ? ORDER BY seq LIMIT ?`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []TaskEvent
+	for rows.Next() {
+		var (
+			ev       TaskEvent
+			typ      string
+			taskJSON string
+			at       string
+		)
+		if err := rows.Scan(&ev.Seq, &typ, &taskJSON, &at); err != nil {
+			return nil, err
+		}
+		ev.Type = EventType(typ)
+		if err := json.Unmarshal([]byte(taskJSON), &ev.Task); err != nil {
+			return nil, fmt.Errorf("unmarshal event task: %w", err)
+		}
+		t, err := time.Parse(time.RFC3339Nano, at)
+		if err != nil {
+			return nil, fmt.Errorf("parse event at: %w", err)
+		}
+		ev.At = t
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+func (s *SQLiteStore) AppendAudit(a *AuditEntry) error {
+	groups, err := json.Marshal(a.Caller.Groups)
+	if err != nil {
+		return fmt.Errorf("marshal audit groups: %w", err)
+	}
+	extra, err := json.Marshal(a.Caller.Extra)
+	if err != nil {
+		return fmt.Errorf("marshal audit extra: %w", err)
+	}
+	res, err := s.db.Exec(`
+		INSERT INTO audit_log
+			(at, request_id, remote_addr, user, groups, extra, action, task_id, task_type, params_digest, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		formatTime(a.At), a.RequestID, a.RemoteAddr, a.Caller.User, string(groups), string(extra),
+		a.Action, a.TaskID, a.TaskType, a.ParamsDigest, a.Status)
+	if err != nil {
+		return err
+	}
+	a.Seq, err = res.LastInsertId()
+	return err
+}
+
+func (s *SQLiteStore) ListAudit(after int64, limit int) ([]AuditEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT seq, at, request_id, remote_addr, user, groups, extra, action, task_id, task_type, params_digest, status
+		FROM audit_log WHERE seq > ? ORDER BY seq LIMIT ?`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var (
+			a      AuditEntry
+			at     string
+			groups sql.NullString
+			extra  sql.NullString
+		)
+		if err := rows.Scan(&a.Seq, &at, &a.RequestID, &a.RemoteAddr, &a.Caller.User, &groups, &extra,
+			&a.Action, &a.TaskID, &a.TaskType, &a.ParamsDigest, &a.Status); err != nil {
+			return nil, err
+		}
+		if groups.Valid {
+			if err := json.Unmarshal([]byte(groups.String), &a.Caller.Groups); err != nil {
+				return nil, fmt.Errorf("unmarshal audit groups: %w", err)
+			}
+		}
+		if extra.Valid {
+			if err := json.Unmarshal([]byte(extra.String), &a.Caller.Extra); err != nil {
+				return nil, fmt.Errorf("unmarshal audit extra: %w", err)
+			}
+		}
+		t, err := time.Parse(time.RFC3339Nano, at)
+		if err != nil {
+			return nil, fmt.Errorf("parse audit at: %w", err)
+		}
+		a.At = t
+		entries = append(entries, a)
+	}
+	return entries, rows.Err()
+}
+
 func (s *SQLiteStore) Ping() error {
 	var n int
 	return s.db.QueryRow("SELECT 1").Scan(&n)
@@ -183,7 +687,8 @@ func (s *SQLiteStore) Close() error {
 // --- query helpers ---
 
 const selectColumns = `
-	SELECT id, type, status, run, params, result, error, submitted_at, completed_at
+	SELECT id, type, status, run, attempt, params, result, error, submitted_at, completed_at, next_attempt_at, deadline, timeout,
+	       priority, submitted_by, cancelled_by, cancel_reason, progress
 	FROM task_results`
 
 // queryMany executes a query and scans all rows into TaskResults.
@@ -192,6 +697,11 @@ func (s *SQLiteStore) queryMany(query string, args ...any) ([]TaskResult, error)
 	if err != nil {
 		return nil, err
 	}
+	return scanResults(rows)
+}
+
+// scanResults scans and closes rows of selectColumns.
+func scanResults(rows *sql.Rows) ([]TaskResult, error) {
 	defer rows.Close()
 
 	var results []TaskResult
@@ -218,11 +728,15 @@ func scanTaskResult(s rowScanner) (*TaskResult, error) {
 		resultJSON  sql.NullString
 		submittedAt string
 		completedAt sql.NullString
+		nextAttempt sql.NullString
+		deadline    sql.NullString
+		progress    sql.NullString
 	)
 
 	if err := s.Scan(
-		&r.ID, &r.Type, &status, &r.Run, &paramsJSON, &resultJSON,
-		&r.Error, &submittedAt, &completedAt,
+		&r.ID, &r.Type, &status, &r.Run, &r.Attempt, &paramsJSON, &resultJSON,
+		&r.Error, &submittedAt, &completedAt, &nextAttempt, &deadline, &r.Timeout, &r.Priority,
+		&r.SubmittedBy, &r.CancelledBy, &r.CancelReason, &progress,
 	); err != nil {
 		return nil, err
 	}
@@ -230,7 +744,7 @@ func scanTaskResult(s rowScanner) (*TaskResult, error) {
 	r.Status = TaskStatus(status)
 
 	if paramsJSON != "" {
-		if err := json.Unmarshal([]byte(paramsJSON), &r.Params); err != nil {
+		if err := unmarshalStored([]byte(paramsJSON), &r.Params); err != nil {
 			return nil, fmt.Errorf("unmarshal params: %w", err)
 		}
 	}
@@ -253,14 +767,59 @@ func scanTaskResult(s rowScanner) (*TaskResult, error) {
 		r.CompletedAt = &t
 	}
 
+	if nextAttempt.Valid {
+		t, err := time.Parse(time.RFC3339Nano, nextAttempt.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse next_attempt_at: %w", err)
+		}
+		r.NextAttemptAt = &t
+	}
+
+	if deadline.Valid {
+		t, err := time.Parse(time.RFC3339Nano, deadline.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse deadline: %w", err)
+		}
+		r.Deadline = &t
+	}
+
+	if progress.Valid {
+		r.Progress = new(Progress)
+		if err := json.Unmarshal([]byte(progress.String), r.Progress); err != nil {
+			return nil, fmt.Errorf("unmarshal progress: %w", err)
+		}
+	}
+
 	return &r, nil
 }
 
+// storedTimeFormat is RFC 3339 with a fixed nine-digit fraction. Unlike
+// time.RFC3339Nano, which trims trailing zeros, its strings sort in time
+// order, so range filters and ORDER BY on stored timestamps are exact.
+// Stored values still parse with time.RFC3339Nano.
+const storedTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(storedTimeFormat)
+}
+
 func formatNullableTime(t *time.Time) any {
 	if t == nil {
 		return nil
 	}
-	return t.UTC().Format(time.RFC3339Nano)
+	return formatTime(*t)
+}
+
+// marshalProgress binds progress as JSON, or SQL NULL when there is none.
+func marshalProgress(p *Progress) (any, error) {
+	if p == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal progress: %w", err)
+	}
+	return string(b), nil
 }
 
 // nullableRawJSON binds a result payload as SQL NULL when empty so the
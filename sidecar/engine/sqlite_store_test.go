@@ -253,6 +253,36 @@ func TestStoreRunFieldRoundTrip(t *testing.T) {
 	}
 }
 
+func TestStoreRetryFieldsRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	next := time.Now().Add(time.Minute).UTC().Truncate(time.Nanosecond)
+
+	r := &TaskResult{
+		ID:            "rty-rt00-0000-0000-0000-000000000000",
+		Type:          "snapshot-restore",
+		Status:        TaskStatusRunning,
+		Run:           2,
+		Attempt:       2,
+		Error:         "transient",
+		SubmittedAt:   time.Now(),
+		NextAttemptAt: &next,
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Attempt != 2 {
+		t.Fatalf("Attempt = %d, want 2", got.Attempt)
+	}
+	if got.NextAttemptAt == nil || !got.NextAttemptAt.Equal(next) {
+		t.Fatalf("NextAttemptAt = %v, want %v", got.NextAttemptAt, next)
+	}
+}
+
 func TestStoreResultFieldRoundTrip(t *testing.T) {
 	s := newTestStore(t)
 	now := time.Now().Truncate(time.Nanosecond)
@@ -387,3 +417,344 @@ func TestTxMarkerRoundTrip(t *testing.T) {
 		t.Fatalf("replace did not overwrite cleanly: got %+v want %+v", got2, m2)
 	}
 }
+
+func TestStoreDeadlineRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	deadline := time.Now().Add(time.Hour).UTC().Truncate(time.Nanosecond)
+
+	bounded := &TaskResult{
+		ID:          "ddl-rt00-0000-0000-0000-000000000000",
+		Type:        "config-patch",
+		Status:      TaskStatusRunning,
+		Run:         1,
+		Attempt:     1,
+		SubmittedAt: time.Now(),
+		Deadline:    &deadline,
+	}
+	unbounded := &TaskResult{
+		ID:          "ddl-rt01-0000-0000-0000-000000000000",
+		Type:        "await-condition",
+		Status:      TaskStatusRunning,
+		Run:         1,
+		Attempt:     1,
+		SubmittedAt: time.Now(),
+	}
+	for _, r := range []*TaskResult{bounded, unbounded} {
+		if err := s.Save(r); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	got, err := s.Get(bounded.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Deadline == nil || !got.Deadline.Equal(deadline) {
+		t.Fatalf("Deadline = %v, want %v", got.Deadline, deadline)
+	}
+
+	got, err = s.Get(unbounded.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Deadline != nil {
+		t.Fatalf("Deadline = %v, want nil for an unbounded task", got.Deadline)
+	}
+}
+
+func TestStoreCancellationRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	done := time.Now().UTC()
+	r := &TaskResult{
+		ID:           "cnl-rt00-0000-0000-0000-000000000000",
+		Type:         "result-export",
+		Status:       TaskStatusCancelled,
+		Run:          1,
+		Attempt:      1,
+		SubmittedAt:  time.Now(),
+		CompletedAt:  &done,
+		CancelledBy:  "system:serviceaccount:sei:operator",
+		CancelReason: "superseded",
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != TaskStatusCancelled || got.CancelledBy != r.CancelledBy || got.CancelReason != "superseded" {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestStoreSaveProgressOnlyWhileRunning(t *testing.T) {
+	s := newTestStore(t)
+	r := &TaskResult{
+		ID:          "prg-rt00-0000-0000-0000-000000000000",
+		Type:        "snapshot-restore",
+		Status:      TaskStatusRunning,
+		Run:         1,
+		Error:       "last attempt failed",
+		SubmittedAt: time.Now(),
+	}
+	if err := s.Save(r); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	p := &Progress{Phase: "download", BytesDone: 10, BytesTotal: 40, UpdatedAt: time.Now().UTC()}
+	if err := s.SaveProgress(r.ID, p); err != nil {
+		t.Fatalf("save progress: %v", err)
+	}
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Progress == nil || got.Progress.Phase != "download" || got.Progress.BytesDone != 10 || !got.Progress.UpdatedAt.Equal(p.UpdatedAt) {
+		t.Fatalf("progress = %+v, want %+v", got.Progress, p)
+	}
+	if got.Error != r.Error || got.Status != TaskStatusRunning {
+		t.Fatalf("SaveProgress touched other columns: %+v", got)
+	}
+
+	got.Status = TaskStatusCompleted
+	if err := s.Save(got); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := s.SaveProgress(r.ID, &Progress{Phase: "late"}); err != nil {
+		t.Fatalf("save progress: %v", err)
+	}
+	if got, _ := s.Get(r.ID); got.Progress.Phase != "download" {
+		t.Fatalf("progress on a finished row = %+v, want it unchanged", got.Progress)
+	}
+}
+
+func TestStoreListPendingTasksInQueueOrder(t *testing.T) {
+	s := newTestStore(t)
+	base := time.Now().UTC()
+	rows := []*TaskResult{
+		{ID: "pend-b000-0000-0000-0000-000000000000", Status: TaskStatusPending, SubmittedAt: base.Add(time.Second)},
+		{ID: "pend-a000-0000-0000-0000-000000000000", Status: TaskStatusPending, SubmittedAt: base},
+		{ID: "pend-r000-0000-0000-0000-000000000000", Status: TaskStatusRunning, SubmittedAt: base},
+		{ID: "pend-p000-0000-0000-0000-000000000000", Status: TaskStatusPending, SubmittedAt: base.Add(2 * time.Second), Priority: 3},
+	}
+	for _, r := range rows {
+		r.Type, r.Run, r.Attempt = "config-patch", 1, 1
+		if err := s.Save(r); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	got, err := s.ListPendingTasks()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 3 || got[0].ID != rows[3].ID || got[1].ID != rows[1].ID || got[2].ID != rows[0].ID {
+		t.Fatalf("pending = %+v, want the pending rows by priority, then oldest first", got)
+	}
+	if got[0].Priority != 3 {
+		t.Fatalf("Priority = %d, want 3", got[0].Priority)
+	}
+}
+
+func TestStoreGraphRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	g := &TaskGraph{
+		ID:          "grf-rt00-0000-0000-0000-000000000000",
+		Phase:       GraphPhaseRunning,
+		SubmittedAt: time.Now().UTC(),
+		Nodes: []GraphNode{
+			{Name: "restore", ID: "grf-n000-0000-0000-0000-000000000000", Type: "snapshot-restore", Timeout: time.Hour},
+			{Name: "apply", ID: "grf-n001-0000-0000-0000-000000000000", Type: "config-apply",
+				Params: map[string]any{"mode": "full"}, DependsOn: []string{"restore"}},
+		},
+	}
+	if err := s.SaveGraph(g); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	active, err := s.ListActiveGraphs()
+	if err != nil {
+		t.Fatalf("list active: %v", err)
+	}
+	if len(active) != 1 || active[0].ID != g.ID {
+		t.Fatalf("active graphs = %+v, want just %s", active, g.ID)
+	}
+
+	got, err := s.GetGraph(g.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[0].Name != "restore" || got.Nodes[1].Name != "apply" {
+		t.Fatalf("nodes = %+v, want restore then apply", got.Nodes)
+	}
+	if got.Nodes[0].Timeout != time.Hour {
+		t.Fatalf("timeout = %s, want 1h", got.Nodes[0].Timeout)
+	}
+	if deps := got.Nodes[1].DependsOn; len(deps) != 1 || deps[0] != "restore" {
+		t.Fatalf("dependsOn = %v, want [restore]", deps)
+	}
+	if got.Nodes[1].Params["mode"] != "full" {
+		t.Fatalf("params = %v", got.Nodes[1].Params)
+	}
+
+	done := time.Now().UTC()
+	g.Phase = GraphPhaseCompleted
+	g.CompletedAt = &done
+	if err := s.SaveGraph(g); err != nil {
+		t.Fatalf("save phase: %v", err)
+	}
+	got, err = s.GetGraph(g.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Phase != GraphPhaseCompleted || got.CompletedAt == nil || len(got.Nodes) != 2 {
+		t.Fatalf("after phase update: %+v", got)
+	}
+	if active, _ := s.ListActiveGraphs(); len(active) != 0 {
+		t.Fatalf("completed graph still active: %+v", active)
+	}
+
+	if missing, err := s.GetGraph("nope"); err != nil || missing != nil {
+		t.Fatalf("GetGraph(missing) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestStoreScheduleRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UTC()
+	last := now.Add(-time.Hour)
+	daily := &Schedule{
+		ID:           "sch-d000-0000-0000-0000-000000000000",
+		Cron:         "0 3 * * *",
+		MissedPolicy: MissedRunCatchUp,
+		Task: Task{
+			Type:     TaskEvmLogicalDigest,
+			Params:   map[string]any{"height": "latest"},
+			Timeout:  30 * time.Minute,
+			Priority: 2,
+		},
+		NextRunAt:  now.Add(2 * time.Hour),
+		LastRunAt:  &last,
+		LastTaskID: "tsk-0000-0000-0000-0000-000000000000",
+		LastError:  "conflict",
+		CreatedAt:  now,
+	}
+	hourly := &Schedule{
+		ID:           "sch-h000-0000-0000-0000-000000000000",
+		Interval:     time.Hour,
+		MissedPolicy: MissedRunSkip,
+		Task:         Task{Type: TaskConfigValidate},
+		NextRunAt:    now.Add(time.Hour),
+		CreatedAt:    now,
+	}
+	for _, sc := range []*Schedule{daily, hourly} {
+		if err := s.SaveSchedule(sc); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	got, err := s.GetSchedule(daily.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Cron != daily.Cron || got.MissedPolicy != MissedRunCatchUp || got.Task.Type != TaskEvmLogicalDigest ||
+		got.Task.Timeout != 30*time.Minute || got.Task.Priority != 2 || got.Task.Params["height"] != "latest" {
+		t.Fatalf("schedule = %+v", got)
+	}
+	if !got.NextRunAt.Equal(daily.NextRunAt) || got.LastRunAt == nil || !got.LastRunAt.Equal(last) ||
+		got.LastTaskID != daily.LastTaskID || got.LastError != "conflict" {
+		t.Fatalf("firing state = %+v", got)
+	}
+
+	list, err := s.ListSchedules()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != hourly.ID || list[0].Interval != time.Hour || list[0].LastRunAt != nil {
+		t.Fatalf("schedules = %+v, want hourly first", list)
+	}
+
+	if deleted, err := s.DeleteSchedule(hourly.ID); err != nil || !deleted {
+		t.Fatalf("delete = %v, %v", deleted, err)
+	}
+	if missing, err := s.GetSchedule(hourly.ID); err != nil || missing != nil {
+		t.Fatalf("GetSchedule(deleted) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestStoreAppendAndListEvents(t *testing.T) {
+	s := newTestStore(t)
+	for i := range 5 {
+		ev := &TaskEvent{
+			Type: EventStarted,
+			Task: TaskResult{ID: "evt-0000", Type: "config-patch", Status: TaskStatusRunning, Run: i},
+			At:   time.Now().UTC(),
+		}
+		if err := s.AppendEvent(ev, 3); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		if ev.Seq != int64(i+1) {
+			t.Fatalf("seq = %d, want %d", ev.Seq, i+1)
+		}
+	}
+
+	got, err := s.ListEvents(0, 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 3 || got[0].Seq != 3 || got[2].Seq != 5 {
+		t.Fatalf("events = %+v, want seqs 3..5 after pruning to 3", got)
+	}
+	if got[2].Type != EventStarted || got[2].Task.Run != 4 || got[2].Task.ID != "evt-0000" {
+		t.Errorf("last event = %+v, want the fifth append", got[2])
+	}
+
+	got, err = s.ListEvents(4, 10)
+	if err != nil {
+		t.Fatalf("list after 4: %v", err)
+	}
+	if len(got) != 1 || got[0].Seq != 5 {
+		t.Errorf("events after 4 = %+v, want only seq 5", got)
+	}
+}
+
+func TestStorePruneResults(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UTC()
+	ago := func(h int) *time.Time { t := now.Add(-time.Duration(h) * time.Hour); return &t }
+	for _, r := range []TaskResult{
+		{ID: "old-done", Type: "config-patch", Status: TaskStatusCompleted, SubmittedAt: *ago(50), CompletedAt: ago(49)},
+		{ID: "old-kept", Type: "config-patch", Status: TaskStatusFailed, SubmittedAt: *ago(48), CompletedAt: ago(47)},
+		{ID: "old-running", Type: "config-patch", Status: TaskStatusRunning, SubmittedAt: *ago(46)},
+		{ID: "old-pending", Type: "config-patch", Status: TaskStatusPending, SubmittedAt: *ago(45)},
+		{ID: "vote-1", Type: "gov-vote", Status: TaskStatusCompleted, SubmittedAt: *ago(3), CompletedAt: ago(3)},
+		{ID: "vote-2", Type: "gov-vote", Status: TaskStatusCompleted, SubmittedAt: *ago(2), CompletedAt: ago(2)},
+		{ID: "vote-3", Type: "gov-vote", Status: TaskStatusCompleted, SubmittedAt: *ago(1), CompletedAt: ago(1)},
+	} {
+		if err := s.Save(&r); err != nil {
+			t.Fatalf("save %s: %v", r.ID, err)
+		}
+	}
+
+	counts, err := s.PruneResults(now.Add(-24*time.Hour), 2, []string{"old-kept"})
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if counts.ByAge["config-patch"] != 1 || counts.ByCount["gov-vote"] != 1 {
+		t.Errorf("counts = %+v, want 1 config-patch by age and 1 gov-vote by count", counts)
+	}
+	for id, want := range map[string]bool{
+		"old-done": false, "old-kept": true, "old-running": true, "old-pending": true,
+		"vote-1": false, "vote-2": true, "vote-3": true,
+	} {
+		got, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		if (got != nil) != want {
+			t.Errorf("%s present = %v, want %v", id, got != nil, want)
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package engine
 
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
 // ResultStore persists task results across all lifecycle states.
 // Implementations must be safe for concurrent use.
 type ResultStore interface {
@@ -7,16 +13,30 @@ type ResultStore interface {
 	// exists, it is overwritten (upsert).
 	Save(r *TaskResult) error
 
+	// SaveProgress updates only the progress of a running result, leaving
+	// every other column untouched. It is a no-op when the result is gone
+	// or no longer running.
+	SaveProgress(id string, p *Progress) error
+
 	// Get returns a result by ID, or (nil, nil) when not found.
 	Get(id string) (*TaskResult, error)
 
 	// List returns the most recent results, newest first, up to limit.
 	List(limit int) ([]TaskResult, error)
 
+	// QueryResults returns the results matching f in the order ResultFilter
+	// defines, up to f.Limit.
+	QueryResults(f ResultFilter) ([]TaskResult, error)
+
 	// ListStaleTasks returns tasks left in "running" state from a
 	// previous process that exited without completing them.
 	ListStaleTasks() ([]TaskResult, error)
 
+	// ListPendingTasks returns tasks left in "pending" state in queue
+	// order — highest priority first, oldest first within a priority — so
+	// rehydration restores the wait queue as it was.
+	ListPendingTasks() ([]TaskResult, error)
+
 	// Delete removes a result by ID. Returns true if it existed.
 	Delete(id string) (bool, error)
 
@@ -33,9 +53,71 @@ type ResultStore interface {
 	// persisted it Failed), which a stale-only scan would miss.
 	LatestByType(taskType string) (*TaskResult, error)
 
+	// PruneResults deletes settled results (neither pending nor running)
+	// that settled before before, when it is non-zero, and then, per task
+	// type, all but the newest maxPerType settled results, when it is
+	// positive. Results whose IDs are in keep are never deleted. It returns
+	// how many results each bound removed, by task type.
+	PruneResults(before time.Time, maxPerType int, keep []string) (PruneCounts, error)
+
+	// Compact returns space freed by deletions to the filesystem.
+	Compact() error
+
+	// SaveGraph persists a task graph. Nodes are written on the first save
+	// and never change, except that a later save may set a node's Started
+	// flag; later saves otherwise update only the phase and completion
+	// time.
+	SaveGraph(g *TaskGraph) error
+
+	// GetGraph returns a task graph by ID, or (nil, nil) when not found.
+	GetGraph(id string) (*TaskGraph, error)
+
+	// ListActiveGraphs returns every graph still in GraphPhaseRunning.
+	// Rehydration uses it to resume graphs a previous process left
+	// half-finished.
+	ListActiveGraphs() ([]TaskGraph, error)
+
+	// SaveSchedule persists a schedule (upsert).
+	SaveSchedule(s *Schedule) error
+
+	// GetSchedule returns a schedule by ID, or (nil, nil) when not found.
+	GetSchedule(id string) (*Schedule, error)
+
+	// ListSchedules returns every schedule, soonest next firing first.
+	ListSchedules() ([]Schedule, error)
+
+	// DeleteSchedule removes a schedule by ID. Returns true if it existed.
+	DeleteSchedule(id string) (bool, error)
+
+	// AppendEvent appends ev to the event log, assigning ev.Seq, and prunes
+	// the log to its newest keep events.
+	AppendEvent(ev *TaskEvent, keep int) error
+
+	// ListEvents returns logged events with Seq greater than after, oldest
+	// first, up to limit.
+	ListEvents(after int64, limit int) ([]TaskEvent, error)
+
+	// AppendAudit appends a to the audit log, assigning a.Seq. Audit
+	// entries are never updated. SQLiteStore never prunes them; JournalStore
+	// keeps only the newest, without ever reusing a Seq.
+	AppendAudit(a *AuditEntry) error
+
+	// ListAudit returns audit entries with Seq greater than after, oldest
+	// first, up to limit.
+	ListAudit(after int64, limit int) ([]AuditEntry, error)
+
 	// Ping verifies the store is responsive. Used by liveness checks.
 	Ping() error
 
 	// Close releases underlying resources.
 	Close() error
 }
+
+// unmarshalStored decodes a stored JSON value with UseNumber, so numbers in
+// task params come back as the json.Number they were submitted as rather
+// than float64, and large integers survive the round trip.
+func unmarshalStored(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,35 @@
+package engine_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+	"github.com/sei-protocol/seictl/sidecar/engine/storetest"
+)
+
+func TestStoreConformance(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		open func(dir string) (storetest.Store, error)
+	}{
+		{"memory", func(string) (storetest.Store, error) { return engine.NewMemoryStore() }},
+		{"sqlite", func(dir string) (storetest.Store, error) {
+			return engine.NewSQLiteStore(filepath.Join(dir, "sidecar.db"))
+		}},
+		{"journal", func(dir string) (storetest.Store, error) {
+			return engine.NewJournalStore(filepath.Join(dir, "sidecar.journal"))
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			storetest.Run(t, func(t *testing.T) storetest.Store {
+				s, err := tc.open(t.TempDir())
+				if err != nil {
+					t.Fatalf("open: %v", err)
+				}
+				t.Cleanup(func() { s.Close() })
+				return s
+			})
+		})
+	}
+}
@@ -0,0 +1,605 @@
+// Package storetest is a conformance suite for engine.ResultStore backends.
+// A backend's tests call Run with a constructor for empty stores, so every
+// backend is held to the same contract: the one the engine's scheduling,
+// rehydration, retention and sign-tx recovery rely on.
+package storetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// Store is what the suite exercises: a result store that also backs the
+// sign-tx idempotency checkpoint, as every durable backend does.
+type Store interface {
+	engine.ResultStore
+	engine.Checkpointer
+}
+
+// Run runs the conformance suite as subtests of t. open must return a new,
+// empty store for each call and arrange for it to be closed.
+func Run(t *testing.T, open func(t *testing.T) Store) {
+	for _, tc := range []struct {
+		name string
+		fn   func(t *testing.T, s Store)
+	}{
+		{"SaveAndGet", testSaveAndGet},
+		{"SaveUpserts", testSaveUpserts},
+		{"ReturnsCopies", testReturnsCopies},
+		{"SaveProgressOnlyWhileRunning", testSaveProgressOnlyWhileRunning},
+		{"List", testList},
+		{"QueryResults", testQueryResults},
+		{"ListStaleTasks", testListStaleTasks},
+		{"ListPendingTasks", testListPendingTasks},
+		{"Delete", testDelete},
+		{"DeleteByType", testDeleteByType},
+		{"LatestByType", testLatestByType},
+		{"PruneResults", testPruneResults},
+		{"Graphs", testGraphs},
+		{"Schedules", testSchedules},
+		{"Events", testEvents},
+		{"Audit", testAudit},
+		{"Checkpointer", testCheckpointer},
+		{"Ping", testPing},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, open(t))
+		})
+	}
+}
+
+var base = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(d time.Duration) time.Time { return base.Add(d) }
+
+func ptr[T any](v T) *T { return &v }
+
+func save(t *testing.T, s Store, results ...*engine.TaskResult) {
+	t.Helper()
+	for _, r := range results {
+		if r.Type == "" {
+			r.Type = string(engine.TaskConfigPatch)
+		}
+		if err := s.Save(r); err != nil {
+			t.Fatalf("save %s: %v", r.ID, err)
+		}
+	}
+}
+
+func ids(results []engine.TaskResult) string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.ID
+	}
+	return fmt.Sprint(out)
+}
+
+func testSaveAndGet(t *testing.T, s Store) {
+	want := &engine.TaskResult{
+		ID:            "full",
+		Type:          string(engine.TaskSnapshotRestore),
+		Status:        engine.TaskStatusFailed,
+		Run:           2,
+		Attempt:       3,
+		Params:        map[string]any{"file": "config.toml", "nested": map[string]any{"key": "val"}, "height": json.Number("9007199254740993")},
+		Result:        []byte(`{"txHash":"ABC"}`),
+		Error:         "boom",
+		SubmittedAt:   at(1500 * time.Millisecond),
+		CompletedAt:   ptr(at(time.Minute)),
+		NextAttemptAt: ptr(at(2 * time.Minute)),
+		Deadline:      ptr(at(time.Hour)),
+		Timeout:       engine.Duration(90 * time.Minute),
+		Priority:      5,
+		SubmittedBy:   "alice",
+		CancelledBy:   "operator",
+		CancelReason:  "maintenance",
+		Progress:      &engine.Progress{Phase: "download", BytesDone: 10, BytesTotal: 40, UpdatedAt: at(time.Second)},
+	}
+	save(t, s, want)
+
+	got, err := s.Get(want.ID)
+	if err != nil || got == nil {
+		t.Fatalf("get = %v, %v", got, err)
+	}
+	if got.Type != want.Type || got.Status != want.Status || got.Run != 2 || got.Attempt != 3 ||
+		got.Error != "boom" || got.Timeout != want.Timeout || got.Priority != 5 || got.SubmittedBy != "alice" || got.CancelledBy != "operator" || got.CancelReason != "maintenance" {
+		t.Errorf("scalar fields = %+v", got)
+	}
+	if nested, _ := got.Params["nested"].(map[string]any); got.Params["file"] != "config.toml" || nested["key"] != "val" {
+		t.Errorf("params = %v", got.Params)
+	}
+	// Above 2^53, so a float64 round trip would come back off by one.
+	if got.Params["height"] != json.Number("9007199254740993") {
+		t.Errorf("params.height = %#v, want the exact json.Number", got.Params["height"])
+	}
+	if string(got.Result) != `{"txHash":"ABC"}` {
+		t.Errorf("result = %s", got.Result)
+	}
+	if !got.SubmittedAt.Equal(want.SubmittedAt) || got.CompletedAt == nil || !got.CompletedAt.Equal(*want.CompletedAt) ||
+		got.NextAttemptAt == nil || !got.NextAttemptAt.Equal(*want.NextAttemptAt) ||
+		got.Deadline == nil || !got.Deadline.Equal(*want.Deadline) {
+		t.Errorf("times = %v %v %v %v", got.SubmittedAt, got.CompletedAt, got.NextAttemptAt, got.Deadline)
+	}
+	if got.Progress == nil || got.Progress.Phase != "download" || got.Progress.BytesDone != 10 || !got.Progress.UpdatedAt.Equal(at(time.Second)) {
+		t.Errorf("progress = %+v", got.Progress)
+	}
+
+	save(t, s, &engine.TaskResult{ID: "bare", Status: engine.TaskStatusPending, SubmittedAt: base})
+	bare, err := s.Get("bare")
+	if err != nil {
+		t.Fatalf("get bare: %v", err)
+	}
+	if bare.Result != nil || bare.CompletedAt != nil || bare.NextAttemptAt != nil || bare.Deadline != nil || bare.Timeout != 0 || bare.Progress != nil {
+		t.Errorf("unset fields read back set: %+v", bare)
+	}
+
+	if missing, err := s.Get("missing"); err != nil || missing != nil {
+		t.Errorf("Get(missing) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func testSaveUpserts(t *testing.T, s Store) {
+	r := &engine.TaskResult{ID: "upsert", Status: engine.TaskStatusRunning, SubmittedAt: base}
+	save(t, s, r)
+	r.Status, r.CompletedAt = engine.TaskStatusCompleted, ptr(at(time.Second))
+	save(t, s, r)
+
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != engine.TaskStatusCompleted || got.CompletedAt == nil {
+		t.Errorf("after upsert = %+v", got)
+	}
+	if all, _ := s.List(10); len(all) != 1 {
+		t.Errorf("upsert left %d rows, want 1", len(all))
+	}
+}
+
+// Results handed out must not share state with the store: the engine
+// mutates them before saving them back.
+func testReturnsCopies(t *testing.T, s Store) {
+	save(t, s, &engine.TaskResult{ID: "copy", Status: engine.TaskStatusRunning, Params: map[string]any{"k": "v"}, SubmittedAt: base})
+	got, err := s.Get("copy")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got.Params["k"] = "changed"
+	got.Status = engine.TaskStatusFailed
+
+	again, err := s.Get("copy")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if again.Params["k"] != "v" || again.Status != engine.TaskStatusRunning {
+		t.Errorf("stored result changed through a returned copy: %+v", again)
+	}
+}
+
+func testSaveProgressOnlyWhileRunning(t *testing.T, s Store) {
+	r := &engine.TaskResult{ID: "progress", Status: engine.TaskStatusRunning, Error: "last attempt failed", SubmittedAt: base}
+	save(t, s, r)
+
+	if err := s.SaveProgress(r.ID, &engine.Progress{Phase: "download", BytesDone: 10}); err != nil {
+		t.Fatalf("save progress: %v", err)
+	}
+	got, err := s.Get(r.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Progress == nil || got.Progress.Phase != "download" || got.Error != r.Error || got.Status != engine.TaskStatusRunning {
+		t.Fatalf("after SaveProgress = %+v", got)
+	}
+
+	got.Status = engine.TaskStatusCompleted
+	save(t, s, got)
+	if err := s.SaveProgress(r.ID, &engine.Progress{Phase: "late"}); err != nil {
+		t.Fatalf("save progress: %v", err)
+	}
+	if got, _ := s.Get(r.ID); got.Progress == nil || got.Progress.Phase != "download" {
+		t.Errorf("progress on a settled result = %+v, want it unchanged", got.Progress)
+	}
+	if err := s.SaveProgress("missing", &engine.Progress{Phase: "x"}); err != nil {
+		t.Errorf("SaveProgress(missing) = %v, want a no-op", err)
+	}
+	if got, _ := s.Get("missing"); got != nil {
+		t.Errorf("SaveProgress created a result: %+v", got)
+	}
+}
+
+func testList(t *testing.T, s Store) {
+	for i := range 5 {
+		save(t, s, &engine.TaskResult{ID: fmt.Sprintf("list-%d", i), Status: engine.TaskStatusCompleted, SubmittedAt: at(time.Duration(i) * time.Minute)})
+	}
+	got, err := s.List(3)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if ids(got) != "[list-4 list-3 list-2]" {
+		t.Errorf("List(3) = %s, want the newest three, newest first", ids(got))
+	}
+}
+
+func testQueryResults(t *testing.T, s Store) {
+	for i := range 6 {
+		r := &engine.TaskResult{ID: fmt.Sprintf("q-%d", i), Status: engine.TaskStatusCompleted, SubmittedAt: at(time.Duration(i) * 100 * time.Millisecond)}
+		if i%2 == 1 {
+			r.Type, r.Status = string(engine.TaskGovVote), engine.TaskStatusFailed
+		}
+		save(t, s, r)
+	}
+	// Two results submitted at the same instant order by descending ID.
+	save(t, s, &engine.TaskResult{ID: "q-5b", Status: engine.TaskStatusCompleted, SubmittedAt: at(500 * time.Millisecond)})
+
+	for _, tc := range []struct {
+		name string
+		f    engine.ResultFilter
+		want string
+	}{
+		{"all", engine.ResultFilter{Limit: 10}, "[q-5b q-5 q-4 q-3 q-2 q-1 q-0]"},
+		{"limit", engine.ResultFilter{Limit: 2}, "[q-5b q-5]"},
+		{"type", engine.ResultFilter{Type: string(engine.TaskGovVote), Limit: 10}, "[q-5 q-3 q-1]"},
+		{"status", engine.ResultFilter{Status: engine.TaskStatusCompleted, Limit: 10}, "[q-5b q-4 q-2 q-0]"},
+		{"window", engine.ResultFilter{SubmittedAfter: at(200 * time.Millisecond), SubmittedBefore: at(400 * time.Millisecond), Limit: 10}, "[q-3 q-2]"},
+		{"after tie", engine.ResultFilter{After: &engine.ResultKey{SubmittedAt: at(500 * time.Millisecond), ID: "q-5b"}, Limit: 2}, "[q-5 q-4]"},
+		{"after", engine.ResultFilter{Type: string(engine.TaskGovVote), After: &engine.ResultKey{SubmittedAt: at(300 * time.Millisecond), ID: "q-3"}, Limit: 10}, "[q-1]"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.QueryResults(tc.f)
+			if err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			if ids(got) != tc.want {
+				t.Errorf("got %s, want %s", ids(got), tc.want)
+			}
+		})
+	}
+}
+
+func testListStaleTasks(t *testing.T, s Store) {
+	save(t, s,
+		&engine.TaskResult{ID: "stale", Status: engine.TaskStatusRunning, SubmittedAt: base},
+		&engine.TaskResult{ID: "queued", Status: engine.TaskStatusPending, SubmittedAt: base},
+		&engine.TaskResult{ID: "done", Status: engine.TaskStatusCompleted, SubmittedAt: base},
+	)
+	got, err := s.ListStaleTasks()
+	if err != nil {
+		t.Fatalf("list stale: %v", err)
+	}
+	if ids(got) != "[stale]" {
+		t.Errorf("stale = %s, want only the running result", ids(got))
+	}
+}
+
+func testListPendingTasks(t *testing.T, s Store) {
+	save(t, s,
+		&engine.TaskResult{ID: "pend-b", Status: engine.TaskStatusPending, SubmittedAt: at(time.Second)},
+		&engine.TaskResult{ID: "pend-a", Status: engine.TaskStatusPending, SubmittedAt: base},
+		&engine.TaskResult{ID: "run", Status: engine.TaskStatusRunning, SubmittedAt: base},
+		&engine.TaskResult{ID: "pend-p", Status: engine.TaskStatusPending, SubmittedAt: at(2 * time.Second), Priority: 3},
+	)
+	got, err := s.ListPendingTasks()
+	if err != nil {
+		t.Fatalf("list pending: %v", err)
+	}
+	if ids(got) != "[pend-p pend-a pend-b]" {
+		t.Errorf("pending = %s, want highest priority first, then oldest first", ids(got))
+	}
+}
+
+func testDelete(t *testing.T, s Store) {
+	save(t, s, &engine.TaskResult{ID: "gone", Status: engine.TaskStatusCompleted, SubmittedAt: base})
+	if deleted, err := s.Delete("gone"); err != nil || !deleted {
+		t.Fatalf("delete = %v, %v; want true", deleted, err)
+	}
+	if got, _ := s.Get("gone"); got != nil {
+		t.Errorf("result still present after delete")
+	}
+	if deleted, err := s.Delete("gone"); err != nil || deleted {
+		t.Errorf("second delete = %v, %v; want false", deleted, err)
+	}
+}
+
+func testDeleteByType(t *testing.T, s Store) {
+	save(t, s,
+		&engine.TaskResult{ID: "ready-1", Type: string(engine.TaskMarkReady), Status: engine.TaskStatusCompleted, SubmittedAt: base},
+		&engine.TaskResult{ID: "ready-2", Type: string(engine.TaskMarkReady), Status: engine.TaskStatusRunning, SubmittedAt: base},
+		&engine.TaskResult{ID: "patch", Status: engine.TaskStatusRunning, SubmittedAt: base},
+	)
+	n, err := s.DeleteByType(string(engine.TaskMarkReady))
+	if err != nil || n != 2 {
+		t.Fatalf("DeleteByType = %d, %v; want 2", n, err)
+	}
+	if all, _ := s.List(10); ids(all) != "[patch]" {
+		t.Errorf("remaining = %s, want [patch]", ids(all))
+	}
+	if n, err := s.DeleteByType(string(engine.TaskMarkReady)); err != nil || n != 0 {
+		t.Errorf("second DeleteByType = %d, %v; want 0", n, err)
+	}
+}
+
+func testLatestByType(t *testing.T, s Store) {
+	if got, err := s.LatestByType(string(engine.TaskMarkNotReady)); err != nil || got != nil {
+		t.Fatalf("LatestByType(none) = %v, %v; want nil, nil", got, err)
+	}
+	save(t, s,
+		&engine.TaskResult{ID: "hold-old", Type: string(engine.TaskMarkNotReady), Status: engine.TaskStatusCompleted, SubmittedAt: base},
+		&engine.TaskResult{ID: "hold-new", Type: string(engine.TaskMarkNotReady), Status: engine.TaskStatusFailed, SubmittedAt: at(time.Minute)},
+		&engine.TaskResult{ID: "newer-other", Status: engine.TaskStatusCompleted, SubmittedAt: at(time.Hour)},
+	)
+	got, err := s.LatestByType(string(engine.TaskMarkNotReady))
+	if err != nil || got == nil || got.ID != "hold-new" {
+		t.Errorf("LatestByType = %v, %v; want hold-new regardless of status", got, err)
+	}
+}
+
+func testPruneResults(t *testing.T, s Store) {
+	now := at(100 * time.Hour)
+	ago := func(h int) *time.Time { return ptr(now.Add(-time.Duration(h) * time.Hour)) }
+	save(t, s,
+		&engine.TaskResult{ID: "old-done", Status: engine.TaskStatusCompleted, SubmittedAt: *ago(50), CompletedAt: ago(49)},
+		&engine.TaskResult{ID: "old-kept", Status: engine.TaskStatusFailed, SubmittedAt: *ago(48), CompletedAt: ago(47)},
+		&engine.TaskResult{ID: "old-running", Status: engine.TaskStatusRunning, SubmittedAt: *ago(46)},
+		&engine.TaskResult{ID: "old-pending", Status: engine.TaskStatusPending, SubmittedAt: *ago(45)},
+		// Submitted long ago but settled recently: age counts from settling.
+		&engine.TaskResult{ID: "late-done", Status: engine.TaskStatusCompleted, SubmittedAt: *ago(44), CompletedAt: ago(1)},
+		&engine.TaskResult{ID: "vote-1", Type: string(engine.TaskGovVote), Status: engine.TaskStatusCompleted, SubmittedAt: *ago(3), CompletedAt: ago(3)},
+		&engine.TaskResult{ID: "vote-2", Type: string(engine.TaskGovVote), Status: engine.TaskStatusCompleted, SubmittedAt: *ago(2), CompletedAt: ago(2)},
+		&engine.TaskResult{ID: "vote-3", Type: string(engine.TaskGovVote), Status: engine.TaskStatusCompleted, SubmittedAt: *ago(1), CompletedAt: ago(1)},
+	)
+
+	counts, err := s.PruneResults(now.Add(-24*time.Hour), 2, []string{"old-kept"})
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	patch, vote := string(engine.TaskConfigPatch), string(engine.TaskGovVote)
+	if len(counts.ByAge) != 1 || counts.ByAge[patch] != 1 || len(counts.ByCount) != 1 || counts.ByCount[vote] != 1 {
+		t.Errorf("counts = %+v, want 1 %s by age and 1 %s by count", counts, patch, vote)
+	}
+	if all, _ := s.List(10); ids(all) != "[vote-3 vote-2 late-done old-pending old-running old-kept]" {
+		t.Errorf("remaining = %s", ids(all))
+	}
+
+	counts, err = s.PruneResults(time.Time{}, 0, nil)
+	if err != nil || len(counts.ByAge)+len(counts.ByCount) != 0 {
+		t.Errorf("unbounded prune = %+v, %v; want nothing removed", counts, err)
+	}
+	if err := s.Compact(); err != nil {
+		t.Errorf("compact: %v", err)
+	}
+}
+
+func testGraphs(t *testing.T, s Store) {
+	g := &engine.TaskGraph{
+		ID:          "graph",
+		Phase:       engine.GraphPhaseRunning,
+		SubmittedAt: base,
+		SubmittedBy: "alice",
+		Nodes: []engine.GraphNode{
+			{Name: "restore", ID: "graph-restore", Type: engine.TaskSnapshotRestore, Timeout: time.Hour},
+			{Name: "apply", ID: "graph-apply", Type: engine.TaskConfigApply, Params: map[string]any{"mode": "full"}, DependsOn: []string{"restore"}},
+		},
+	}
+	if err := s.SaveGraph(g); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	earlier := &engine.TaskGraph{ID: "earlier", Phase: engine.GraphPhaseRunning, SubmittedAt: at(-time.Minute), Nodes: []engine.GraphNode{{Name: "n", ID: "earlier-n", Type: engine.TaskConfigPatch}}}
+	if err := s.SaveGraph(earlier); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	active, err := s.ListActiveGraphs()
+	if err != nil {
+		t.Fatalf("list active: %v", err)
+	}
+	if len(active) != 2 || active[0].ID != "earlier" || active[1].ID != "graph" {
+		t.Fatalf("active = %+v, want earlier then graph", active)
+	}
+
+	// A later save may mark a node started; nothing un-marks it.
+	g.Nodes[0].Started = true
+	if err := s.SaveGraph(g); err != nil {
+		t.Fatalf("save started: %v", err)
+	}
+
+	// Later saves otherwise update only the phase and completion time.
+	done := at(time.Hour)
+	update := &engine.TaskGraph{ID: g.ID, Phase: engine.GraphPhaseCompleted, SubmittedAt: at(time.Hour), CompletedAt: &done}
+	if err := s.SaveGraph(update); err != nil {
+		t.Fatalf("save phase: %v", err)
+	}
+	got, err := s.GetGraph(g.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Phase != engine.GraphPhaseCompleted || got.CompletedAt == nil || !got.CompletedAt.Equal(done) || !got.SubmittedAt.Equal(base) || got.SubmittedBy != "alice" {
+		t.Errorf("after phase update = %+v", got)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[0].Timeout != time.Hour || got.Nodes[1].Params["mode"] != "full" ||
+		len(got.Nodes[1].DependsOn) != 1 || got.Nodes[1].DependsOn[0] != "restore" ||
+		!got.Nodes[0].Started || got.Nodes[1].Started {
+		t.Errorf("nodes = %+v", got.Nodes)
+	}
+	if active, _ := s.ListActiveGraphs(); len(active) != 1 || active[0].ID != "earlier" {
+		t.Errorf("active after completion = %+v", active)
+	}
+	if missing, err := s.GetGraph("missing"); err != nil || missing != nil {
+		t.Errorf("GetGraph(missing) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func testSchedules(t *testing.T, s Store) {
+	daily := &engine.Schedule{
+		ID:           "daily",
+		Cron:         "0 3 * * *",
+		MissedPolicy: engine.MissedRunCatchUp,
+		Task:         engine.Task{Type: engine.TaskEvmLogicalDigest, Params: map[string]any{"height": "latest"}, Timeout: 30 * time.Minute, Priority: 2},
+		NextRunAt:    at(2 * time.Hour),
+		LastRunAt:    ptr(at(-time.Hour)),
+		LastTaskID:   "last",
+		LastError:    "conflict",
+		CreatedAt:    base,
+		CreatedBy:    "alice",
+	}
+	hourly := &engine.Schedule{ID: "hourly", Interval: time.Hour, MissedPolicy: engine.MissedRunSkip, Task: engine.Task{Type: engine.TaskConfigValidate}, NextRunAt: at(time.Hour), CreatedAt: base}
+	for _, sc := range []*engine.Schedule{daily, hourly} {
+		if err := s.SaveSchedule(sc); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	got, err := s.GetSchedule("daily")
+	if err != nil || got == nil {
+		t.Fatalf("get = %v, %v", got, err)
+	}
+	if got.Cron != daily.Cron || got.MissedPolicy != engine.MissedRunCatchUp || got.Task.Timeout != 30*time.Minute ||
+		got.Task.Priority != 2 || got.Task.Params["height"] != "latest" || !got.NextRunAt.Equal(daily.NextRunAt) ||
+		got.LastRunAt == nil || got.LastTaskID != "last" || got.LastError != "conflict" ||
+		got.CreatedBy != "alice" {
+		t.Errorf("schedule = %+v", got)
+	}
+
+	list, err := s.ListSchedules()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "hourly" || list[0].Interval != time.Hour || list[0].LastRunAt != nil {
+		t.Errorf("schedules = %+v, want soonest firing first", list)
+	}
+
+	if deleted, err := s.DeleteSchedule("hourly"); err != nil || !deleted {
+		t.Fatalf("delete = %v, %v", deleted, err)
+	}
+	if deleted, err := s.DeleteSchedule("hourly"); err != nil || deleted {
+		t.Errorf("second delete = %v, %v; want false", deleted, err)
+	}
+	if missing, err := s.GetSchedule("hourly"); err != nil || missing != nil {
+		t.Errorf("GetSchedule(deleted) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func testEvents(t *testing.T, s Store) {
+	for i := range 5 {
+		ev := &engine.TaskEvent{
+			Type: engine.EventStarted,
+			Task: engine.TaskResult{ID: "evt", Type: string(engine.TaskConfigPatch), Status: engine.TaskStatusRunning, Run: i},
+			At:   at(time.Duration(i) * time.Second),
+		}
+		if err := s.AppendEvent(ev, 3); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		if ev.Seq != int64(i+1) {
+			t.Fatalf("seq = %d, want %d", ev.Seq, i+1)
+		}
+	}
+
+	got, err := s.ListEvents(0, 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 3 || got[0].Seq != 3 || got[2].Seq != 5 {
+		t.Fatalf("events = %+v, want seqs 3..5 after pruning to 3", got)
+	}
+	if last := got[2]; last.Type != engine.EventStarted || last.Task.Run != 4 || !last.At.Equal(at(4*time.Second)) {
+		t.Errorf("last event = %+v, want the fifth append", last)
+	}
+	if got, _ := s.ListEvents(3, 1); len(got) != 1 || got[0].Seq != 4 {
+		t.Errorf("ListEvents(3, 1) = %+v, want only seq 4", got)
+	}
+
+	// Sequence numbers are never reused, even once every earlier event is
+	// pruned.
+	ev := &engine.TaskEvent{Type: engine.EventCompleted, Task: engine.TaskResult{ID: "evt"}, At: base}
+	if err := s.AppendEvent(ev, 1); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if got, _ := s.ListEvents(0, 10); ev.Seq != 6 || len(got) != 1 || got[0].Seq != 6 {
+		t.Errorf("after pruning to 1: seq = %d, events = %+v", ev.Seq, got)
+	}
+}
+
+func testAudit(t *testing.T, s Store) {
+	if got, err := s.ListAudit(0, 10); err != nil || len(got) != 0 {
+		t.Fatalf("empty ListAudit = %+v, %v", got, err)
+	}
+	for i := range 3 {
+		a := &engine.AuditEntry{
+			At:           at(time.Duration(i) * time.Second),
+			RequestID:    fmt.Sprintf("req-%d", i),
+			RemoteAddr:   "10.0.0.1:5000",
+			Caller:       engine.Caller{User: "alice", Groups: []string{"ops", "sre"}, Extra: map[string][]string{"scope": {"node"}}},
+			Action:       "submit",
+			TaskID:       fmt.Sprintf("task-%d", i),
+			TaskType:     string(engine.TaskConfigPatch),
+			ParamsDigest: "sha256:abc",
+			Status:       202,
+		}
+		if err := s.AppendAudit(a); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		if a.Seq != int64(i+1) {
+			t.Fatalf("seq = %d, want %d", a.Seq, i+1)
+		}
+	}
+
+	got, err := s.ListAudit(0, 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 3 || got[0].Seq != 1 || got[2].Seq != 3 {
+		t.Fatalf("audit = %+v, want seqs 1..3", got)
+	}
+	last := got[2]
+	if last.RequestID != "req-2" || last.RemoteAddr != "10.0.0.1:5000" || last.Action != "submit" || last.TaskID != "task-2" ||
+		last.TaskType != string(engine.TaskConfigPatch) || last.ParamsDigest != "sha256:abc" || last.Status != 202 ||
+		!last.At.Equal(at(2*time.Second)) {
+		t.Errorf("last entry = %+v", last)
+	}
+	if c := last.Caller; c.User != "alice" || fmt.Sprint(c.Groups) != "[ops sre]" || fmt.Sprint(c.Extra) != "map[scope:[node]]" {
+		t.Errorf("caller = %+v", c)
+	}
+	if got, _ := s.ListAudit(1, 1); len(got) != 1 || got[0].Seq != 2 {
+		t.Errorf("ListAudit(1, 1) = %+v, want only seq 2", got)
+	}
+}
+
+func testCheckpointer(t *testing.T, s Store) {
+	if got, err := s.GetTxMarker("missing"); err != nil || got != nil {
+		t.Fatalf("GetTxMarker(missing) = %v, %v; want nil, nil", got, err)
+	}
+	m := &engine.TxMarker{TaskID: "sign", TxHash: "ABCDEF", TxBytes: []byte{0x00, 0xDE, 0xAD, 0xFF}, AccountNumber: 17, Sequence: 42, ChainID: "pacific-1"}
+	if err := s.SaveTxMarker(m); err != nil {
+		t.Fatalf("save marker: %v", err)
+	}
+	got, err := s.GetTxMarker("sign")
+	if err != nil || got == nil {
+		t.Fatalf("get marker = %v, %v", got, err)
+	}
+	if string(got.TxBytes) != string(m.TxBytes) || got.TxHash != m.TxHash || got.AccountNumber != 17 || got.Sequence != 42 || got.ChainID != "pacific-1" {
+		t.Errorf("marker = %+v, want %+v", got, m)
+	}
+
+	replaced := &engine.TxMarker{TaskID: "sign", TxHash: "111111", TxBytes: []byte{0x11}, Sequence: 43, ChainID: "atlantic-2"}
+	if err := s.SaveTxMarker(replaced); err != nil {
+		t.Fatalf("replace marker: %v", err)
+	}
+	if got, _ := s.GetTxMarker("sign"); got == nil || got.TxHash != "111111" || got.Sequence != 43 || got.AccountNumber != 0 {
+		t.Errorf("after replace = %+v, want %+v", got, replaced)
+	}
+}
+
+func testPing(t *testing.T, s Store) {
+	if err := s.Ping(); err != nil {
+		t.Fatalf("ping open store: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := s.Ping(); err == nil {
+		t.Error("ping on a closed store succeeded")
+	}
+}
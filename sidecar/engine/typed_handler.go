@@ -1,36 +1,80 @@
 package engine
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sei-protocol/seictl/sidecar/wire"
 )
 
-// TypedHandler wraps a result-less typed handler into a TaskHandler. The
-// map[string]any params are marshaled to JSON and unmarshaled into the typed
-// struct T, giving handlers compile-time type safety without changing the
-// engine's dispatch mechanism. Handlers that produce a structured result use
-// TypedHandlerWithResult instead.
-func TypedHandler[T any](fn func(ctx context.Context, params T) error) TaskHandler {
-	return TypedHandlerWithResult(func(ctx context.Context, params T) (json.RawMessage, error) {
+// TypedTask is a Handler built by TypedHandler or TypedHandlerWithResult. It
+// carries the schema of its params and result, and the params check the
+// engine runs at submit time, alongside the handler itself.
+type TypedTask struct {
+	run    TaskHandler
+	schema TaskSchema
+	check  func(params map[string]any) error
+}
+
+// Handle decodes params and runs the typed handler.
+func (t *TypedTask) Handle(ctx context.Context, params map[string]any) (json.RawMessage, error) {
+	return t.run(ctx, params)
+}
+
+// Schema returns the schema of t's params and result.
+func (t *TypedTask) Schema() TaskSchema { return t.schema }
+
+// Wrap returns a TypedTask that runs wrap(t's handler) under t's schema and
+// params check. Middleware around a typed handler goes through Wrap so the
+// engine keeps validating its params; wrapping the bare TaskHandler instead
+// yields a handler with opaque params.
+func (t *TypedTask) Wrap(wrap func(TaskHandler) TaskHandler) *TypedTask {
+	return &TypedTask{run: wrap(t.run), schema: t.schema, check: t.check}
+}
+
+// TypedHandler wraps a result-less typed handler into a TypedTask. The
+// map[string]any params are marshaled to JSON and decoded strictly into the
+// typed struct T, giving handlers compile-time type safety without changing
+// the engine's dispatch mechanism. Handlers that produce a structured result
+// use TypedHandlerWithResult instead.
+//
+// Decoding rejects fields T does not declare and keeps numbers exact
+// (json.Number for any-typed values) rather than routing them through
+// float64. When T (or *T) has a Validate() error method it runs after
+// decoding. The engine applies the same checks at submit time, so bad
+// params are refused up front as a *ParamsError rather than failing the
+// task later.
+func TypedHandler[T any](fn func(ctx context.Context, params T) error) *TypedTask {
+	return typedHandler(func(ctx context.Context, params T) (json.RawMessage, error) {
 		return nil, fn(ctx, params)
-	})
+	}, false)
 }
 
 // TypedHandlerWithResult wraps a typed handler that returns a structured
-// result into a TaskHandler. R is marshaled to json.RawMessage and returned
-// alongside the error, so the engine persists it on both the success and
-// error paths (an error return may still carry a meaningful R). A nil/zero R
-// that marshals to "null" is treated as no result.
-func TypedHandlerWithResult[T, R any](fn func(ctx context.Context, params T) (R, error)) TaskHandler {
-	return func(ctx context.Context, params map[string]any) (json.RawMessage, error) {
-		data, err := json.Marshal(params)
+// result into a TypedTask. Params are decoded as for TypedHandler. R is
+// marshaled to json.RawMessage and returned alongside the error, so the
+// engine persists it on both the success and error paths (an error return
+// may still carry a meaningful R). A nil/zero R that marshals to "null" is
+// treated as no result.
+func TypedHandlerWithResult[T, R any](fn func(ctx context.Context, params T) (R, error)) *TypedTask {
+	return typedHandler(fn, true)
+}
+
+func typedHandler[T, R any](fn func(ctx context.Context, params T) (R, error), withResult bool) *TypedTask {
+	schema := TaskSchema{Params: wire.SchemaOf(reflect.TypeFor[T]())}
+	if withResult {
+		schema.Result = wire.SchemaOf(reflect.TypeFor[R]())
+	}
+	h := func(ctx context.Context, params map[string]any) (json.RawMessage, error) {
+		typed, err := decodeParams[T](schema.Params, params)
 		if err != nil {
-			return nil, fmt.Errorf("marshaling params: %w", err)
-		}
-		var typed T
-		if err := json.Unmarshal(data, &typed); err != nil {
-			return nil, fmt.Errorf("parsing params: %w", err)
+			return nil, err
 		}
 		result, ferr := fn(ctx, typed)
 		raw, merr := json.Marshal(result)
@@ -47,4 +91,187 @@ func TypedHandlerWithResult[T, R any](fn func(ctx context.Context, params T) (R,
 		}
 		return raw, ferr
 	}
+	return &TypedTask{
+		run:    h,
+		schema: schema,
+		check: func(params map[string]any) error {
+			_, err := decodeParams[T](schema.Params, params)
+			return err
+		},
+	}
+}
+
+// ErrInvalidParams is matched by every *ParamsError.
+var ErrInvalidParams = errors.New("invalid task params")
+
+// FieldError is a problem with one params field. Field is the path to it
+// within params, e.g. "targetHeight" or "changes[0].key". A Validate hook
+// may return FieldErrors, several joined with errors.Join, so a rejected
+// submission names the fields at fault.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ParamsError reports params a typed handler cannot accept: fields its
+// request type does not declare, values of the wrong JSON type, or a
+// failed Validate hook. Errs holds one entry per problem, a *FieldError
+// wherever the problem is with a single field. Retrying cannot fix bad
+// params, so a ParamsError is terminal.
+type ParamsError struct {
+	Errs []error
+}
+
+func (e *ParamsError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return ErrInvalidParams.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (e *ParamsError) Unwrap() []error { return e.Errs }
+
+func (e *ParamsError) Is(target error) bool { return target == ErrInvalidParams }
+
+// Terminal marks the error final for Retryable.
+func (e *ParamsError) Terminal() bool { return true }
+
+// Fields lists the paths of the fields named by e's FieldErrors, in order.
+func (e *ParamsError) Fields() []string {
+	var out []string
+	for _, err := range e.Errs {
+		var fe *FieldError
+		if errors.As(err, &fe) {
+			out = append(out, fe.Field)
+		}
+	}
+	return out
+}
+
+// decodeParams decodes params into T the way a typed handler receives
+// them, failing with a *ParamsError when they do not fit.
+func decodeParams[T any](schema *wire.Schema, params map[string]any) (T, error) {
+	var typed T
+	var errs []error
+	unknownFields(schema, "", params, &errs)
+	if len(errs) > 0 {
+		return typed, &ParamsError{Errs: errs}
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return typed, fmt.Errorf("marshaling params: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	dec.UseNumber()
+	if err := dec.Decode(&typed); err != nil {
+		var te *json.UnmarshalTypeError
+		if errors.As(err, &te) && te.Field != "" {
+			err = &FieldError{Field: te.Field, Reason: fmt.Sprintf("cannot use %s as %s", te.Value, te.Type)}
+		}
+		return typed, &ParamsError{Errs: []error{err}}
+	}
+
+	if v, ok := any(&typed).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				return typed, &ParamsError{Errs: joined.Unwrap()}
+			}
+			return typed, &ParamsError{Errs: []error{err}}
+		}
+	}
+	return typed, nil
+}
+
+// unknownFields appends a FieldError for every key in v that the struct
+// schema at its position does not declare. Keys match field names
+// case-insensitively, as encoding/json does. Maps, and values typed any,
+// accept every key.
+func unknownFields(s *wire.Schema, path string, v any, errs *[]error) {
+	if s == nil {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		if s.Type != "object" {
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+			fs := s.AdditionalProperties
+			if fs == nil && s.Properties != nil {
+				if fs = s.Property(k); fs == nil {
+					*errs = append(*errs, &FieldError{Field: fieldPath, Reason: "unknown field"})
+					continue
+				}
+			}
+			unknownFields(fs, fieldPath, v[k], errs)
+		}
+	case []any:
+		if s.Type != "array" {
+			return
+		}
+		for i, item := range v {
+			unknownFields(s.Items, fmt.Sprintf("%s[%d]", path, i), item, errs)
+		}
+	}
+}
+
+// checkParams runs handler's params decoding and Validate hook without
+// running the handler. Handlers other than a TypedTask accept any params.
+func checkParams(h Handler, params map[string]any) error {
+	t, ok := h.(*TypedTask)
+	if !ok {
+		return nil
+	}
+	return t.check(params)
+}
+
+// TaskSchema describes the params a typed handler accepts and the result
+// it produces. Result is nil for a handler built with TypedHandler.
+type TaskSchema struct {
+	Params *wire.Schema `json:"params"`
+	Result *wire.Schema `json:"result,omitempty"`
+}
+
+// HandlerSchema returns the schema of a TypedTask. ok is false for any
+// other handler, whose params are opaque.
+func HandlerSchema(h Handler) (s TaskSchema, ok bool) {
+	t, ok := h.(*TypedTask)
+	if !ok {
+		return TaskSchema{}, false
+	}
+	return t.schema, true
+}
+
+// TaskCapability is a task type the engine has a handler for and, when
+// the handler is typed, the schema of its params and result.
+type TaskCapability struct {
+	Type TaskType `json:"type"`
+	TaskSchema
+}
+
+// Capabilities lists every registered task type, sorted by type.
+func (e *Engine) Capabilities() []TaskCapability {
+	out := make([]TaskCapability, 0, len(e.handlers))
+	for t, h := range e.handlers {
+		s, _ := HandlerSchema(h)
+		out = append(out, TaskCapability{Type: t, TaskSchema: s})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
 }
@@ -2,7 +2,11 @@ package engine
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestTypedHandler_HappyPath(t *testing.T) {
@@ -17,7 +21,7 @@ func TestTypedHandler_HappyPath(t *testing.T) {
 		return nil
 	})
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"name": "alice",
 		"age":  float64(30),
 	})
@@ -42,7 +46,7 @@ func TestTypedHandler_MalformedJSON(t *testing.T) {
 	})
 
 	// A channel cannot be marshaled to JSON, causing a marshal error.
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"value": make(chan int),
 	})
 	if err == nil {
@@ -62,7 +66,7 @@ func TestTypedHandler_NilParams(t *testing.T) {
 		return nil
 	})
 
-	_, err := handler(context.Background(), nil)
+	_, err := handler.Handle(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("unexpected error for nil params: %v", err)
 	}
@@ -86,7 +90,7 @@ func TestTypedHandler_EmptyParams(t *testing.T) {
 		return nil
 	})
 
-	_, err := handler(context.Background(), map[string]any{})
+	_, err := handler.Handle(context.Background(), map[string]any{})
 	if err != nil {
 		t.Fatalf("unexpected error for empty params: %v", err)
 	}
@@ -113,7 +117,7 @@ func TestTypedHandler_NestedStruct(t *testing.T) {
 		return nil
 	})
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"nested": map[string]any{
 			"key":   "foo",
 			"value": "bar",
@@ -142,7 +146,7 @@ func TestTypedHandler_Float64ToInt64Coercion(t *testing.T) {
 	})
 
 	// JSON numbers arrive as float64 when unmarshaled into map[string]any.
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"height": float64(198030000),
 	})
 	if err != nil {
@@ -152,3 +156,222 @@ func TestTypedHandler_Float64ToInt64Coercion(t *testing.T) {
 		t.Errorf("Height = %d, want 198030000", captured.Height)
 	}
 }
+
+func TestTypedHandler_ExactNumbers(t *testing.T) {
+	type req struct {
+		Height int64 `json:"height"`
+		Value  any   `json:"value"`
+	}
+
+	var captured req
+	handler := TypedHandler(func(_ context.Context, r req) error {
+		captured = r
+		return nil
+	})
+
+	// Above 2^53: exact only if decoding never goes through float64.
+	_, err := handler.Handle(context.Background(), map[string]any{
+		"height": json.Number("9007199254740993"),
+		"value":  json.Number("9007199254740993"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.Height != 9007199254740993 {
+		t.Errorf("Height = %d, want 9007199254740993", captured.Height)
+	}
+	if captured.Value != json.Number("9007199254740993") {
+		t.Errorf("Value = %#v, want json.Number", captured.Value)
+	}
+}
+
+type validatedReq struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (r validatedReq) Validate() error {
+	var errs []error
+	if r.Name == "" {
+		errs = append(errs, &FieldError{Field: "name", Reason: "is required"})
+	}
+	if r.Count < 0 {
+		errs = append(errs, &FieldError{Field: "count", Reason: "must not be negative"})
+	}
+	return errors.Join(errs...)
+}
+
+func TestTypedHandler_RejectsInvalidParams(t *testing.T) {
+	type inner struct {
+		Key string `json:"key"`
+	}
+	type req struct {
+		TargetHeight int64            `json:"targetHeight"`
+		Items        []inner          `json:"items"`
+		Labels       map[string]inner `json:"labels"`
+		Extra        any              `json:"extra"`
+	}
+
+	tests := []struct {
+		name    string
+		handler Handler
+		params  map[string]any
+		fields  []string
+	}{
+		{
+			name:    "unknown fields at every depth",
+			handler: TypedHandler(func(context.Context, req) error { return nil }),
+			params: map[string]any{
+				"target_height": 5,
+				"items":         []any{map[string]any{"key": "a"}, map[string]any{"kye": "b"}},
+				"labels":        map[string]any{"x": map[string]any{"value": "c"}},
+			},
+			fields: []string{"items[1].kye", "labels.x.value", "target_height"},
+		},
+		{
+			name:    "wrong type",
+			handler: TypedHandler(func(context.Context, req) error { return nil }),
+			params:  map[string]any{"targetHeight": "tall"},
+			fields:  []string{"targetHeight"},
+		},
+		{
+			name:    "fraction into integer",
+			handler: TypedHandler(func(context.Context, req) error { return nil }),
+			params:  map[string]any{"targetHeight": json.Number("1.5")},
+			fields:  []string{"targetHeight"},
+		},
+		{
+			name:    "validate hook",
+			handler: TypedHandlerWithResult(func(context.Context, validatedReq) (any, error) { return nil, nil }),
+			params:  map[string]any{"count": -1},
+			fields:  []string{"name", "count"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.handler.Handle(context.Background(), tt.params)
+			var pe *ParamsError
+			if !errors.As(err, &pe) || !errors.Is(err, ErrInvalidParams) {
+				t.Fatalf("err = %v, want a *ParamsError", err)
+			}
+			if got := pe.Fields(); !slices.Equal(got, tt.fields) {
+				t.Errorf("fields = %v, want %v", got, tt.fields)
+			}
+			if Retryable(err) {
+				t.Error("invalid params reported retryable")
+			}
+		})
+	}
+}
+
+func TestTypedHandler_AcceptsFieldNamesAnyCase(t *testing.T) {
+	type req struct {
+		TargetHeight int64 `json:"targetHeight"`
+		Extra        any   `json:"extra"`
+	}
+
+	var captured req
+	handler := TypedHandler(func(_ context.Context, r req) error {
+		captured = r
+		return nil
+	})
+
+	// encoding/json matches names case-insensitively; so does the check.
+	_, err := handler.Handle(context.Background(), map[string]any{
+		"targetheight": 7,
+		"extra":        map[string]any{"anything": "goes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.TargetHeight != 7 {
+		t.Errorf("TargetHeight = %d, want 7", captured.TargetHeight)
+	}
+}
+
+func TestSubmitRejectsInvalidParams(t *testing.T) {
+	var ran bool
+	handler := TypedHandler(func(context.Context, validatedReq) error {
+		ran = true
+		return nil
+	})
+	eng := newTestEngine(t, map[TaskType]Handler{TaskConfigPatch: handler})
+
+	_, err := eng.Submit(Task{ID: "bad", Type: TaskConfigPatch, Params: map[string]any{"nmae": "x"}})
+	var pe *ParamsError
+	if !errors.As(err, &pe) || !slices.Equal(pe.Fields(), []string{"nmae"}) {
+		t.Fatalf("err = %v, want a *ParamsError naming nmae", err)
+	}
+	if got := eng.GetResult("bad"); got != nil || ran {
+		t.Errorf("rejected submission was persisted (%+v) or ran (%v)", got, ran)
+	}
+
+	_, err = eng.SubmitGraph(TaskGraph{Nodes: []GraphNode{{Name: "a", Type: TaskConfigPatch}}})
+	if !errors.Is(err, ErrInvalidGraph) || !errors.As(err, &pe) || !slices.Equal(pe.Fields(), []string{"name"}) {
+		t.Errorf("graph err = %v, want ErrInvalidGraph wrapping a *ParamsError naming name", err)
+	}
+
+	_, err = eng.CreateSchedule(Schedule{Interval: time.Hour, Task: Task{Type: TaskConfigPatch, Params: map[string]any{"count": "many"}}})
+	if !errors.Is(err, ErrInvalidSchedule) || !errors.As(err, &pe) || !slices.Equal(pe.Fields(), []string{"count"}) {
+		t.Errorf("schedule err = %v, want ErrInvalidSchedule wrapping a *ParamsError naming count", err)
+	}
+}
+
+func TestHandlerSchema(t *testing.T) {
+	type req struct {
+		Height int64 `json:"height"`
+	}
+	type res struct {
+		Hash string `json:"hash"`
+	}
+	plain := TypedHandler(func(_ context.Context, _ req) error { return nil })
+	withResult := TypedHandlerWithResult(func(_ context.Context, _ req) (res, error) { return res{}, nil })
+	raw := TaskHandler(func(context.Context, map[string]any) (json.RawMessage, error) { return nil, nil })
+
+	s, ok := HandlerSchema(plain)
+	if !ok || s.Params.Properties["height"].Type != "integer" || s.Result != nil {
+		t.Errorf("TypedHandler schema = %+v, %v", s, ok)
+	}
+	s, ok = HandlerSchema(withResult)
+	if !ok || s.Params.Properties["height"] == nil || s.Result.Properties["hash"].Type != "string" {
+		t.Errorf("TypedHandlerWithResult schema = %+v, %v", s, ok)
+	}
+	if _, ok := HandlerSchema(raw); ok {
+		t.Error("untyped handler reported a schema")
+	}
+
+	e := &Engine{handlers: map[TaskType]Handler{TaskMarkReady: raw, TaskConfigPatch: plain, TaskGovVote: withResult}}
+	caps := e.Capabilities()
+	if len(caps) != 3 || caps[0].Type != TaskConfigPatch || caps[1].Type != TaskGovVote || caps[2].Type != TaskMarkReady {
+		t.Fatalf("capabilities = %+v, want all three sorted by type", caps)
+	}
+	if caps[2].Params != nil {
+		t.Errorf("untyped handler capability has params %+v", caps[2].Params)
+	}
+}
+
+func TestTypedTaskWrapKeepsParamsCheck(t *testing.T) {
+	var ran, wrapped bool
+	handler := TypedHandler(func(context.Context, validatedReq) error {
+		ran = true
+		return nil
+	}).Wrap(func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, params map[string]any) (json.RawMessage, error) {
+			wrapped = true
+			return next(ctx, params)
+		}
+	})
+	if s, ok := HandlerSchema(handler); !ok || s.Params.Properties["name"] == nil {
+		t.Errorf("wrapped handler schema = %+v, %v", s, ok)
+	}
+
+	eng := newTestEngine(t, map[TaskType]Handler{TaskConfigPatch: handler})
+	_, err := eng.Submit(Task{ID: "bad", Type: TaskConfigPatch, Params: map[string]any{"nmae": "x"}})
+	var pe *ParamsError
+	if !errors.As(err, &pe) || !slices.Equal(pe.Fields(), []string{"nmae"}) {
+		t.Fatalf("err = %v, want a *ParamsError naming nmae", err)
+	}
+	if ran || wrapped {
+		t.Errorf("rejected submission ran (handler %v, wrapper %v)", ran, wrapped)
+	}
+}
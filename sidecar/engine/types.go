@@ -43,15 +43,30 @@ const (
 	TaskMarkNotReady             = wire.TaskMarkNotReady
 	TaskStopSeid                 = wire.TaskStopSeid
 	TaskResetData                = wire.TaskResetData
+	TaskPrepareUpgrade           = wire.TaskPrepareUpgrade
+	TaskApplyUpgrade             = wire.TaskApplyUpgrade
 )
 
 // Task is a unit of work submitted by the controller. When ID is set, the
 // engine uses it as the canonical task identifier (enabling deterministic
 // IDs from the controller). When empty, the engine generates a random UUID.
+//
+// Timeout bounds the task's execution from when it starts running; time
+// spent queued Pending does not count. Zero falls back to
+// the engine's per-type default (Engine.Timeouts), and no default means no
+// deadline.
 type Task struct {
-	ID     string         `json:"id,omitempty"`
-	Type   TaskType       `json:"type"`
-	Params map[string]any `json:"params,omitempty"`
+	ID      string         `json:"id,omitempty"`
+	Type    TaskType       `json:"type"`
+	Params  map[string]any `json:"params,omitempty"`
+	Timeout time.Duration  `json:"timeout,omitempty"`
+	// Priority orders the task among queued tasks: higher starts first,
+	// ties start in submission order. It has no effect on a task that
+	// starts immediately.
+	Priority int `json:"priority,omitempty"`
+	// SubmittedBy is who submitted the task, recorded on its TaskResult.
+	// It is set by the engine's callers, never decoded from a request.
+	SubmittedBy string `json:"-"`
 }
 
 // TaskHandler executes a specific task type. Handlers MUST be idempotent:
@@ -63,6 +78,40 @@ type Task struct {
 // tx hash for an inclusion-undetermined gov submit).
 type TaskHandler func(ctx context.Context, params map[string]any) (json.RawMessage, error)
 
+// Handle runs h. A bare TaskHandler's params are opaque to the engine; build
+// handlers with TypedHandler or TypedHandlerWithResult to have them checked
+// at submit time and described by Capabilities.
+func (h TaskHandler) Handle(ctx context.Context, params map[string]any) (json.RawMessage, error) {
+	return h(ctx, params)
+}
+
+// Handler is what the engine registers for a task type: a TaskHandler, or a
+// *TypedTask carrying its params schema and check.
+type Handler interface {
+	Handle(ctx context.Context, params map[string]any) (json.RawMessage, error)
+}
+
+// Duration is a time.Duration that encodes in JSON as a Go duration
+// string, e.g. "30m", the form task submissions give timeouts in.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
 type taskIDKey struct{}
 
 // TaskIDFromContext returns the engine-assigned task ID for the current
@@ -88,6 +137,20 @@ const (
 	TaskStatusRunning   TaskStatus = "running"
 	TaskStatusCompleted TaskStatus = "completed"
 	TaskStatusFailed    TaskStatus = "failed"
+
+	// TaskStatusSkipped is terminal: a task-graph node that never ran
+	// because a dependency failed or was itself skipped.
+	TaskStatusSkipped TaskStatus = "skipped"
+
+	// TaskStatusPending marks a task queued for a worker or an exclusion
+	// group; it moves to running when started. Graph status also reports a
+	// node whose dependencies have not all completed as pending, though no
+	// task row exists for it until the node is submitted.
+	TaskStatusPending TaskStatus = "pending"
+
+	// TaskStatusCancelled is terminal: the task was stopped through
+	// CancelTask. The row keeps who cancelled it and why.
+	TaskStatusCancelled TaskStatus = "cancelled"
 )
 
 // TaskError is a structured error that includes operator-actionable context.
@@ -118,21 +181,60 @@ func (e *TaskError) Error() string {
 // by the controller over the trusted GET /v0/tasks/{id} path — is the
 // authenticated alternative to publishing results through attacker-writable
 // shared storage.
+//
+// Attempt and NextAttemptAt track automatic retry under a RetryPolicy. While
+// a retry is pending the row stays "running" with Error holding the last
+// failure and NextAttemptAt the persisted start of the next attempt, so a
+// restarted sidecar resumes the backoff schedule rather than resetting it.
+//
+// Timeout is the task's execution budget, from the submission or the task
+// type's default; zero when it runs unbounded. Deadline is the absolute
+// deadline the budget gives: it is set when the task starts running, so time
+// spent queued Pending never counts against it, and is persisted so a
+// rehydrated task keeps its original deadline. A task that overruns it fails
+// with a TaskError whose Operation is "deadline".
+//
+// SubmittedBy is the caller identity that submitted the task's latest run:
+// the authenticated user for an API submission (empty when the request
+// carried none), "schedule:<id> by <user>" for a scheduled firing (see
+// Schedule.CreatedBy), or the graph's submitter for a graph node.
+//
+// CancelledBy and CancelReason are set on a TaskStatusCancelled row:
+// the caller identity that cancelled the task (empty when the request
+// carried none) and the reason it gave.
+//
+// Progress is the latest position the handler published through its
+// ProgressReporter; nil when it never reported.
 type TaskResult struct {
-	ID          string          `json:"id"`
-	Type        string          `json:"type"`
-	Status      TaskStatus      `json:"status"`
-	Run         int             `json:"run"`
-	Params      map[string]any  `json:"params,omitempty"`
-	Result      json.RawMessage `json:"result,omitempty"`
-	Error       string          `json:"error,omitempty"`
-	SubmittedAt time.Time       `json:"submittedAt"`
-	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Status        TaskStatus      `json:"status"`
+	Run           int             `json:"run"`
+	Attempt       int             `json:"attempt,omitempty"`
+	Params        map[string]any  `json:"params,omitempty"`
+	Result        json.RawMessage `json:"result,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	SubmittedAt   time.Time       `json:"submittedAt"`
+	CompletedAt   *time.Time      `json:"completedAt,omitempty"`
+	NextAttemptAt *time.Time      `json:"nextAttemptAt,omitempty"`
+	Deadline      *time.Time      `json:"deadline,omitempty"`
+	Timeout       Duration        `json:"timeout,omitempty"`
+	Priority      int             `json:"priority,omitempty"`
+	SubmittedBy   string          `json:"submittedBy,omitempty"`
+	CancelledBy   string          `json:"cancelledBy,omitempty"`
+	CancelReason  string          `json:"cancelReason,omitempty"`
+	Progress      *Progress       `json:"progress,omitempty"`
 }
 
 // StatusResponse is the shape returned by the status endpoint.
+//
+// Locks lists every configured exclusion group with its current holder and
+// queued waiters; it is omitted when no groups are configured. Drain is set
+// while the engine drains.
 type StatusResponse struct {
-	Status string `json:"status"`
+	Status string                `json:"status"`
+	Locks  []ExclusionLockStatus `json:"locks,omitempty"`
+	Drain  *DrainStatus          `json:"drain,omitempty"`
 }
 
 // ExecutionConfig carries process-wide deps the engine exposes to handlers.
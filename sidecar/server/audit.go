@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// requestIDHeader carries a request's ID: the caller's, when it sends
+// one, or a generated UUID. The sidecar echoes it on the response and
+// records it on the audit log, so a client-side trace can be matched to
+// its audit entry.
+const requestIDHeader = "X-Request-Id"
+
+// Audit log actions, one per mutating route.
+const (
+	auditSubmit         = "submit"
+	auditCancel         = "cancel"
+	auditDelete         = "delete"
+	auditSubmitGraph    = "submit-graph"
+	auditCreateSchedule = "create-schedule"
+	auditDeleteSchedule = "delete-schedule"
+	auditDrain          = "drain"
+	auditUndrain        = "undrain"
+)
+
+type auditKey struct{}
+
+// caller returns the authenticated identity behind r: the peer's
+// credentials for a request over the Unix socket; otherwise the proxy's
+// user, group and extra headers in trusted-header mode, the client
+// certificate's subject in mtls mode, and the zero Caller in the other
+// modes — in unauthenticated mode any client could forge the headers,
+// and a bearer token names no one.
+func (s *Server) caller(r *http.Request) engine.Caller {
+	if cred, ok := peerCredOf(r); ok {
+		return cred.caller()
+	}
+	switch s.authnMode {
+	case AuthnModeTrustedHeader:
+	case AuthnModeMTLS:
+		user, groups := certCaller(r)
+		return engine.Caller{User: user, Groups: groups}
+	default:
+		return engine.Caller{}
+	}
+	c := engine.Caller{User: r.Header.Get(remoteUserHeader), Groups: r.Header.Values(remoteGroupHeader)}
+	for name, vals := range r.Header {
+		key, ok := strings.CutPrefix(name, remoteExtraHeaderPrefix)
+		if !ok || key == "" {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(key); err == nil {
+			key = unescaped
+		}
+		if c.Extra == nil {
+			c.Extra = map[string][]string{}
+		}
+		key = strings.ToLower(key)
+		c.Extra[key] = append(c.Extra[key], vals...)
+	}
+	return c
+}
+
+// statusRecorder captures the status code a handler answers with.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusRecorder) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// audited wraps a mutating handler so every request it serves, accepted
+// or refused, is appended to the audit log once answered. The handler
+// fills in the task it acted on through auditOf. A failure to record is
+// logged; the response has already been sent.
+func (s *Server) audited(action string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := r.Header.Get(requestIDHeader)
+		if reqID == "" {
+			reqID = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, reqID)
+
+		entry := &engine.AuditEntry{
+			RequestID:  reqID,
+			RemoteAddr: r.RemoteAddr,
+			Caller:     s.caller(r),
+			Action:     action,
+		}
+		rec := &statusRecorder{ResponseWriter: w}
+		h(rec, r.WithContext(context.WithValue(r.Context(), auditKey{}, entry)))
+
+		entry.Status = rec.status
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+		if err := s.engine.RecordAudit(entry); err != nil {
+			serverLog.Error("recording audit entry", "action", action, "requestId", reqID, "err", err)
+		}
+	}
+}
+
+// auditOf returns the audit entry for r. Outside audited it returns a
+// scratch entry, so handlers can fill it in unconditionally.
+func auditOf(r *http.Request) *engine.AuditEntry {
+	if a, ok := r.Context().Value(auditKey{}).(*engine.AuditEntry); ok {
+		return a
+	}
+	return &engine.AuditEntry{}
+}
+
+// paramsDigest is the "sha256:<hex>" digest of params' JSON encoding, or
+// "" when there are none. Map keys encode sorted, so equal params digest
+// equally.
+func paramsDigest(params map[string]any) string {
+	if len(params) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// handleListAudit serves GET /v0/audit: audit entries after the "after"
+// sequence, oldest first.
+func (s *Server) handleListAudit(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	var after int64
+	if raw := v.Get("after"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "after must be a non-negative integer")
+			return
+		}
+		after = n
+	}
+	var limit int
+	if raw := v.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > engine.MaxListLimit {
+			writeError(w, http.StatusBadRequest, "limit must be an integer between 1 and "+strconv.Itoa(engine.MaxListLimit))
+			return
+		}
+		limit = n
+	}
+	entries, err := s.engine.ListAudit(after, limit)
+	if err != nil {
+		serverLog.Error("listing audit log", "err", err)
+		writeError(w, http.StatusInternalServerError, "failed to list audit log")
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
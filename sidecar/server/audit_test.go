@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func noopHandler(context.Context, map[string]any) (json.RawMessage, error) { return nil, nil }
+
+func listAudit(t *testing.T, srv *Server, query string) []engine.AuditEntry {
+	t.Helper()
+	rec := serveHTTP(srv, http.MethodGet, "/v0/audit"+query, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /v0/audit%s = %d: %s", query, rec.Code, rec.Body.String())
+	}
+	var entries []engine.AuditEntry
+	if err := json.NewDecoder(rec.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode audit: %v", err)
+	}
+	return entries
+}
+
+func TestAuditRecordsCallerAndOutcome(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{engine.TaskConfigPatch: noopHandler})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeTrustedHeader)
+
+	req := httptest.NewRequest(http.MethodPost, "/v0/tasks", strings.NewReader(`{"type":"config-patch","params":{"k":"v"}}`))
+	req.Header.Set(remoteUserHeader, "alice")
+	req.Header.Add(remoteGroupHeader, "ops")
+	req.Header.Add(remoteGroupHeader, "sre")
+	req.Header.Set("X-Remote-Extra-Acme.io%2fTeam", "blue")
+	req.Header.Set(requestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("submit = %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get(requestIDHeader); got != "req-1" {
+		t.Errorf("%s = %q, want the caller's", requestIDHeader, got)
+	}
+	var resp map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if r := eng.GetResult(resp["id"]); r == nil || r.SubmittedBy != "alice" {
+		t.Fatalf("result = %+v, want submittedBy alice", r)
+	}
+
+	// A refused request is audited too, under a generated request ID.
+	req = httptest.NewRequest(http.MethodPost, "/v0/tasks", strings.NewReader(`{}`))
+	req.Header.Set(remoteUserHeader, "bob")
+	rec = httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("bad submit = %d", rec.Code)
+	}
+	generated := rec.Header().Get(requestIDHeader)
+	if generated == "" {
+		t.Errorf("no %s on response", requestIDHeader)
+	}
+
+	entries := listAudit(t, srv, "")
+	if len(entries) != 2 {
+		t.Fatalf("audit = %+v, want 2 entries", entries)
+	}
+	sum := sha256.Sum256([]byte(`{"k":"v"}`))
+	first := entries[0]
+	if first.Seq != 1 || first.Action != auditSubmit || first.Status != http.StatusCreated || first.RequestID != "req-1" ||
+		first.TaskID != resp["id"] || first.TaskType != "config-patch" || first.ParamsDigest != "sha256:"+hex.EncodeToString(sum[:]) ||
+		first.RemoteAddr == "" {
+		t.Errorf("first entry = %+v", first)
+	}
+	if c := first.Caller; c.User != "alice" || strings.Join(c.Groups, ",") != "ops,sre" || len(c.Extra["acme.io/team"]) != 1 {
+		t.Errorf("caller = %+v", c)
+	}
+	if second := entries[1]; second.Caller.User != "bob" || second.Status != http.StatusBadRequest || second.RequestID != generated {
+		t.Errorf("second entry = %+v", second)
+	}
+	if page := listAudit(t, srv, "?after=1&limit=1"); len(page) != 1 || page[0].Seq != 2 {
+		t.Errorf("page after 1 = %+v", page)
+	}
+}
+
+func TestAuditIgnoresIdentityHeadersWhenUnauthenticated(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{engine.TaskConfigPatch: noopHandler})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	req := httptest.NewRequest(http.MethodPost, "/v0/tasks", strings.NewReader(`{"type":"config-patch"}`))
+	req.Header.Set(remoteUserHeader, "mallory")
+	req.Header.Set(remoteGroupHeader, "admins")
+	rec := httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("submit = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	entries := listAudit(t, srv, "")
+	if len(entries) != 1 || entries[0].Caller.User != "" || entries[0].Caller.Groups != nil {
+		t.Fatalf("audit = %+v, want one anonymous entry", entries)
+	}
+	if r := eng.GetResult(entries[0].TaskID); r == nil || r.SubmittedBy != "" {
+		t.Fatalf("result = %+v, want no submittedBy", r)
+	}
+}
+
+func TestListAuditRejectsBadQuery(t *testing.T) {
+	srv := NewServer(":0", newTestEngine[engine.TaskHandler](t, nil), t.TempDir(), AuthnModeUnauthenticated)
+	for _, q := range []string{"?after=-1", "?after=x", "?limit=0", "?limit=1001"} {
+		if rec := serveHTTP(srv, http.MethodGet, "/v0/audit"+q, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET /v0/audit%s = %d, want 400", q, rec.Code)
+		}
+	}
+}
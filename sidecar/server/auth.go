@@ -25,6 +25,10 @@ import (
 // PR): hostNetwork, hostPID, and hostIPC MUST all be false.
 // hostNetwork would expose 127.0.0.1 to every other hostNetwork pod
 // on the node. hostPID/hostIPC would let off-pod processes attach.
+//
+// A Unix socket (ListenUnix) narrows the boundary from the network
+// namespace to the filesystem: only processes that can open the socket
+// file reach it, and each is identified by its SO_PEERCRED uid.
 
 const (
 	// AuthnModeUnauthenticated: sidecar binds all interfaces; every
@@ -39,13 +43,32 @@ const (
 	// identity.
 	AuthnModeTrustedHeader = "trusted-header"
 
+	// AuthnModeToken requires Authorization: Bearer <token> on every
+	// request outside bypassPaths, for hosts with no proxy in front of
+	// the sidecar (bare metal, docker-compose). The token names no
+	// caller: requests carry no identity.
+	AuthnModeToken = "token"
+
+	// AuthnModeMTLS terminates TLS in the sidecar and requires a client
+	// certificate signed by the configured CA on every request outside
+	// bypassPaths. The certificate's subject names the caller.
+	AuthnModeMTLS = "mtls"
+
 	remoteUserHeader = "X-Remote-User"
+
+	// The proxy's optional group and extra-attribute headers, recorded
+	// with the user on the audit log. kube-rbac-proxy sets one
+	// X-Remote-Group per group and X-Remote-Extra-<key> per extra value,
+	// with <key> percent-encoded.
+	remoteGroupHeader       = "X-Remote-Group"
+	remoteExtraHeaderPrefix = "X-Remote-Extra-"
 )
 
-// bypassPaths skip the X-Remote-User check in trusted-header mode.
-// Each path's caller does not carry K8s auth headers, so requiring
-// X-Remote-User would break the corresponding probe / scrape.
-// kube-rbac-proxy must include every path here in its --allow-paths.
+// bypassPaths skip the authentication check in every mode. Each path's
+// caller does not carry K8s auth headers, a bearer token or a client
+// certificate, so requiring one would break the corresponding probe /
+// scrape. kube-rbac-proxy must include every path here in its
+// --allow-paths.
 var bypassPaths = map[string]struct{}{
 	"/v0/healthz":  {}, // kubelet readiness probe
 	"/v0/startupz": {}, // kubelet startup probe
@@ -53,7 +76,7 @@ var bypassPaths = map[string]struct{}{
 	"/v0/metrics":  {}, // Prometheus scrape
 }
 
-// BypassPaths returns the set of paths exempt from the X-Remote-User
+// BypassPaths returns the set of paths exempt from the authentication
 // check, sorted, so serve.go can log them at startup and the
 // controller-side PR can keep --allow-paths in sync.
 func BypassPaths() []string {
@@ -65,19 +88,29 @@ func BypassPaths() []string {
 	return out
 }
 
-// authnRejections counts 401s from the trust-header check. Tagged by
-// reason so a misconfigured proxy (duplicate-header) is grep-able
-// apart from genuine missing-header attempts.
+// authnRejections counts 401s from the authentication middleware. Tagged
+// by reason so a misconfigured proxy (duplicate-header) is grep-able
+// apart from genuine missing-header, missing-token or missing-cert
+// attempts.
 var authnRejections = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Name: "seictl_sidecar_authn_rejections_total",
-		Help: "Count of 401 responses from the trusted-header middleware, by reason.",
+		Help: "Count of 401 responses from the authentication middleware, by reason.",
 	},
 	[]string{"reason"},
 )
 
+// authzDenials counts 403s from the authorization policy, by verb.
+var authzDenials = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "seictl_sidecar_authz_denials_total",
+		Help: "Count of 403 responses from the authorization policy, by verb.",
+	},
+	[]string{"verb"},
+)
+
 func init() {
-	prometheus.MustRegister(authnRejections)
+	prometheus.MustRegister(authnRejections, authzDenials)
 }
 
 // AuthnMode reads SEI_SIDECAR_AUTHN_MODE and returns the canonical
@@ -89,17 +122,19 @@ func AuthnMode() (string, error) {
 	switch raw {
 	case "", "unauthenticated":
 		return AuthnModeUnauthenticated, nil
-	case AuthnModeTrustedHeader:
-		return AuthnModeTrustedHeader, nil
+	case AuthnModeTrustedHeader, AuthnModeToken, AuthnModeMTLS:
+		return raw, nil
 	default:
-		return "", fmt.Errorf("SEI_SIDECAR_AUTHN_MODE=%q is not recognized (allowed: \"\", \"unauthenticated\", %q)", raw, AuthnModeTrustedHeader)
+		return "", fmt.Errorf("SEI_SIDECAR_AUTHN_MODE=%q is not recognized (allowed: \"\", \"unauthenticated\", %q, %q, %q)",
+			raw, AuthnModeTrustedHeader, AuthnModeToken, AuthnModeMTLS)
 	}
 }
 
 // BindAddress returns the listen address for the given mode. The
 // loopback bind in trusted-header mode is load-bearing — it confines
 // the listen socket to the pod's network namespace so the only path
-// to :7777 is through the in-pod proxy.
+// to :7777 is through the in-pod proxy. Token and mtls modes
+// authenticate every request themselves and bind all interfaces.
 func BindAddress(port, mode string) string {
 	if mode == AuthnModeTrustedHeader {
 		return "127.0.0.1:" + port
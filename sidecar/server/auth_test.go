@@ -22,7 +22,9 @@ func TestAuthnMode(t *testing.T) {
 		{"whitespace tolerated", "  trusted-header  ", AuthnModeTrustedHeader, ""},
 		{"typo with underscore", "trusted_header", "", "not recognized"},
 		{"missing hyphen", "trustedheader", "", "not recognized"},
-		{"future mode not yet supported", "mtls", "", "not recognized"},
+		{"token", "token", AuthnModeToken, ""},
+		{"mtls", "MTLS", AuthnModeMTLS, ""},
+		{"future mode not yet supported", "oidc", "", "not recognized"},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -54,6 +56,11 @@ func TestBindAddress(t *testing.T) {
 	if got := BindAddress("7777", AuthnModeTrustedHeader); got != "127.0.0.1:7777" {
 		t.Errorf("trusted-header: %q, want 127.0.0.1:7777", got)
 	}
+	for _, mode := range []string{AuthnModeToken, AuthnModeMTLS} {
+		if got := BindAddress("7777", mode); got != ":7777" {
+			t.Errorf("%s: %q, want :7777", mode, got)
+		}
+	}
 }
 
 func TestTrustedHeaderMiddleware(t *testing.T) {
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// CapabilitiesResponse is the body of GET /v0/capabilities: what this
+// sidecar build is and which task types it runs, so clients can check a
+// submission before sending it rather than learn from a 400.
+type CapabilitiesResponse struct {
+	Version            string                  `json:"version"`
+	StoreSchemaVersion int                     `json:"storeSchemaVersion"`
+	AuthnMode          string                  `json:"authnMode"`
+	TaskTypes          []engine.TaskCapability `json:"taskTypes"`
+}
+
+func (s *Server) handleCapabilities(w http.ResponseWriter, _ *http.Request) {
+	mode := s.authnMode
+	if mode == AuthnModeUnauthenticated {
+		mode = "unauthenticated"
+	}
+	writeJSON(w, http.StatusOK, CapabilitiesResponse{
+		Version:            buildVersion(),
+		StoreSchemaVersion: engine.SchemaVersion,
+		AuthnMode:          mode,
+		TaskTypes:          s.engine.Capabilities(),
+	})
+}
+
+// buildVersion names the running binary: its module version, and the VCS
+// revision it was built from when the build recorded one.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	version := info.Main.Version
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			version += "+" + s.Value
+		}
+	}
+	return version
+}
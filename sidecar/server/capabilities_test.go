@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func TestCapabilities(t *testing.T) {
+	type patchRequest struct {
+		Files map[string]map[string]any `json:"files"`
+	}
+	eng := newTestEngine(t, map[engine.TaskType]engine.Handler{
+		engine.TaskConfigPatch: engine.TypedHandler(func(context.Context, patchRequest) error { return nil }),
+		engine.TaskMarkReady:   engine.TaskHandler(noopHandler),
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	rec := httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/capabilities", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var got CapabilitiesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version == "" || got.StoreSchemaVersion != engine.SchemaVersion || got.AuthnMode != "unauthenticated" {
+		t.Errorf("build info = %q, %d, %q", got.Version, got.StoreSchemaVersion, got.AuthnMode)
+	}
+	if len(got.TaskTypes) != 2 || got.TaskTypes[0].Type != engine.TaskConfigPatch || got.TaskTypes[1].Type != engine.TaskMarkReady {
+		t.Fatalf("task types = %+v", got.TaskTypes)
+	}
+	if files := got.TaskTypes[0].Params.Properties["files"]; files == nil || files.Type != "object" {
+		t.Errorf("config-patch params schema = %+v", got.TaskTypes[0].Params)
+	}
+	if got.TaskTypes[1].Params != nil {
+		t.Errorf("untyped mark-ready published params %+v", got.TaskTypes[1].Params)
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// drainRetryAfter is the Retry-After sent with a 503 for a submission
+// refused by a drain that has no deadline.
+const drainRetryAfter = 30 * time.Second
+
+// DrainRequest is the optional JSON body for POST /v0/admin/drain.
+// Timeout is a Go duration bounding how long the drain waits on running
+// tasks; empty leaves it open-ended.
+type DrainRequest struct {
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// handleDrain puts the engine into drain mode and returns its status.
+// Draining an engine already draining replaces the deadline.
+func (s *Server) handleDrain(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, VerbWrite) {
+		return
+	}
+	var req DrainRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	timeout, err := parseTimeout(req.Timeout)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.engine.Drain(timeout))
+}
+
+// handleUndrain ends a drain. It succeeds whether or not the engine was
+// draining.
+func (s *Server) handleUndrain(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r, VerbWrite) {
+		return
+	}
+	s.engine.Undrain()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeDraining writes the 503 for a submission refused by a drain. Its
+// Retry-After is the time left until the drain's deadline, when it has
+// one.
+func (s *Server) writeDraining(w http.ResponseWriter, err error) {
+	retry := drainRetryAfter
+	if st := s.engine.Draining(); st != nil && st.Deadline != nil {
+		retry = max(time.Until(*st.Deadline), time.Second)
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+	writeError(w, http.StatusServiceUnavailable, err.Error())
+}
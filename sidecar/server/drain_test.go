@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func TestDrainRefusesSubmissionsWithRetryAfter(t *testing.T) {
+	noop := func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil }
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch:  noop,
+		engine.TaskMarkNotReady: noop,
+	})
+	eng.DrainExempt = []engine.TaskType{engine.TaskMarkNotReady}
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	rec := serveHTTP(srv, http.MethodPost, "/v0/admin/drain", `{"timeout":"2m"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("drain: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var st engine.DrainStatus
+	if err := json.NewDecoder(rec.Body).Decode(&st); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Drained || st.Deadline == nil {
+		t.Fatalf("drain status = %+v, want drained with a deadline", st)
+	}
+
+	rec = serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"config-patch"}`)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("submit while draining: expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if secs, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || secs < 1 || secs > 120 {
+		t.Fatalf("Retry-After = %q, want the seconds left until the deadline", rec.Header().Get("Retry-After"))
+	}
+	rec = serveHTTP(srv, http.MethodPost, "/v0/task-graphs", `{"nodes":[{"name":"a","type":"config-patch"}]}`)
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("graph submit while draining: expected 503 with Retry-After, got %d", rec.Code)
+	}
+	if rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"mark-not-ready"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("exempt submit while draining: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveHTTP(srv, http.MethodGet, "/v0/status", "")
+	var status engine.StatusResponse
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Drain == nil {
+		t.Fatal("status does not report the drain")
+	}
+
+	if rec := serveHTTP(srv, http.MethodPost, "/v0/admin/undrain", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("undrain: expected 204, got %d", rec.Code)
+	}
+	if rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"config-patch"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("submit after undrain: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDrainWithoutDeadlineRetriesAfterDefault(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	if rec := serveHTTP(srv, http.MethodPost, "/v0/admin/drain", `{"timeout":"soon"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("drain with a bad timeout: expected 400, got %d", rec.Code)
+	}
+	if eng.Draining() != nil {
+		t.Fatal("a rejected drain request started a drain")
+	}
+	if rec := serveHTTP(srv, http.MethodPost, "/v0/admin/drain", ""); rec.Code != http.StatusOK {
+		t.Fatalf("drain: expected 200, got %d", rec.Code)
+	}
+	rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"config-patch"}`)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	if got, want := rec.Header().Get("Retry-After"), strconv.Itoa(int(drainRetryAfter.Seconds())); got != want {
+		t.Fatalf("Retry-After = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// eventKeepalive is how often an idle event stream sends an SSE comment, so
+// clients and proxies can tell a quiet stream from a dead connection.
+const eventKeepalive = 15 * time.Second
+
+// handleEvents serves GET /v0/events: a text/event-stream of task lifecycle
+// events, each carrying the task's TaskResult as its data and its log
+// sequence as its id. ?task= limits the stream to one task. A client that
+// reconnects with Last-Event-ID first receives the logged events it missed.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	taskID := r.URL.Query().Get("task")
+	var after int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid Last-Event-ID %q", v))
+			return
+		}
+		after = n
+	}
+
+	sub, err := s.engine.SubscribeEvents(taskID, after)
+	if err != nil {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusServiceUnavailable, "failed to read event log; retry")
+		return
+	}
+	defer func() { sub.Close() }()
+
+	// The stream outlives the server's WriteTimeout, which is sized for
+	// ordinary responses.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		serverLog.Warn("clearing event stream write deadline", "err", err)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	last := after
+	for _, ev := range sub.Backlog {
+		if err := writeEvent(w, ev); err != nil {
+			return
+		}
+		last = ev.Seq
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(eventKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.streamsDone:
+			return
+		case ev, ok := <-sub.Events:
+			if !ok {
+				// Fell behind the live feed: pick up where the log says this
+				// client left off. A closed feed has drained its buffer first,
+				// so last is the newest event written.
+				sub, err = s.engine.SubscribeEvents(taskID, last)
+				if err != nil {
+					return
+				}
+				for _, ev := range sub.Backlog {
+					if err := writeEvent(w, ev); err != nil {
+						return
+					}
+					last = ev.Seq
+				}
+				continue
+			}
+			if err := writeEvent(w, ev); err != nil {
+				return
+			}
+			last = ev.Seq
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes ev as one SSE event.
+func writeEvent(w http.ResponseWriter, ev engine.TaskEvent) error {
+	data, err := json.Marshal(ev.Task)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data)
+	return err
+}
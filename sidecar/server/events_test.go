@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// readSSE reads n events from an event stream as "id event status" strings.
+func readSSE(t *testing.T, r *bufio.Reader, n int) []string {
+	t.Helper()
+	var out []string
+	var id, typ string
+	for len(out) < n {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream after %v: %v", out, err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			typ = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var tr engine.TaskResult
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &tr); err != nil {
+				t.Fatalf("data is not a TaskResult: %v", err)
+			}
+			out = append(out, id+" "+typ+" "+string(tr.Status))
+		}
+	}
+	return out
+}
+
+func TestEventsStreamResumesFromLastEventID(t *testing.T) {
+	release := make(chan struct{})
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) {
+			<-release
+			return nil, nil
+		},
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+	ts := httptest.NewServer(srv.mux)
+	t.Cleanup(ts.Close)
+
+	id, err := eng.Submit(engine.Task{Type: engine.TaskConfigPatch})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v0/events?task="+id, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /v0/events: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	// Event 1 (submitted) was acknowledged; started comes from the log, then
+	// completed live once the handler returns.
+	r := bufio.NewReader(resp.Body)
+	if got := readSSE(t, r, 1); got[0] != "2 started running" {
+		t.Fatalf("backlog = %v, want [2 started running]", got)
+	}
+	close(release)
+	if got := readSSE(t, r, 1); got[0] != "3 completed completed" {
+		t.Fatalf("live = %v, want [3 completed completed]", got)
+	}
+}
+
+func TestEventsInvalidLastEventIDReturns400(t *testing.T) {
+	eng := newTestEngine[engine.TaskHandler](t, nil)
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+	req := httptest.NewRequest(http.MethodGet, "/v0/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rec := httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
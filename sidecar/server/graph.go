@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// TaskGraphRequest is the JSON body for POST /v0/task-graphs. When ID is
+// provided it is the graph's canonical identifier and resubmission is
+// idempotent; otherwise a random UUID is generated.
+type TaskGraphRequest struct {
+	ID    string                 `json:"id,omitempty"`
+	Nodes []TaskGraphNodeRequest `json:"nodes"`
+}
+
+// TaskGraphNodeRequest is one node of a TaskGraphRequest. Name keys the node
+// within the graph and is what DependsOn refers to; ID, Type, Params and
+// Timeout have the same meaning as on TaskRequest.
+type TaskGraphNodeRequest struct {
+	Name      string         `json:"name"`
+	ID        string         `json:"id,omitempty"`
+	Type      string         `json:"type"`
+	Params    map[string]any `json:"params,omitempty"`
+	Timeout   string         `json:"timeout,omitempty"`
+	DependsOn []string       `json:"dependsOn,omitempty"`
+}
+
+func (s *Server) handlePostTaskGraph(w http.ResponseWriter, r *http.Request) {
+	var req TaskGraphRequest
+	if err := decodeSubmission(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	auditOf(r).TaskID = req.ID
+	if !s.authorize(w, r, VerbWrite, policyTaskTypes(req.Nodes)...) {
+		return
+	}
+	graph := engine.TaskGraph{ID: req.ID, SubmittedBy: s.remoteUser(r), Nodes: make([]engine.GraphNode, 0, len(req.Nodes))}
+	for _, n := range req.Nodes {
+		if n.Type == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("node %q: type is required", n.Name))
+			return
+		}
+		timeout, err := parseTimeout(n.Timeout)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("node %q: %v", n.Name, err))
+			return
+		}
+		graph.Nodes = append(graph.Nodes, engine.GraphNode{
+			Name:      n.Name,
+			ID:        n.ID,
+			Type:      engine.TaskType(n.Type),
+			Params:    n.Params,
+			Timeout:   timeout,
+			DependsOn: n.DependsOn,
+		})
+	}
+
+	id, err := s.engine.SubmitGraph(graph)
+	switch {
+	case errors.Is(err, engine.ErrInvalidGraph), errors.Is(err, engine.ErrInvalidTaskID):
+		writeBadRequest(w, err)
+		return
+	case errors.Is(err, engine.ErrDraining):
+		s.writeDraining(w, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	auditOf(r).TaskID = id
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+func (s *Server) handleGetTaskGraph(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing task graph ID")
+		return
+	}
+	status := s.engine.GetGraph(id)
+	if status == nil {
+		writeError(w, http.StatusNotFound, "task graph not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
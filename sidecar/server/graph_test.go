@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func TestPostTaskGraphAndGetStatus(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	body := `{"nodes":[
+		{"name":"first","type":"config-patch"},
+		{"name":"second","type":"config-patch","timeout":"1m","dependsOn":["first"]}
+	]}`
+	rec := serveHTTP(srv, http.MethodPost, "/v0/task-graphs", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	var gs engine.GraphStatus
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		rec = serveHTTP(srv, http.MethodGet, "/v0/task-graphs/"+resp["id"], "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&gs); err != nil {
+			t.Fatalf("failed to decode: %v", err)
+		}
+		if gs.Phase != engine.GraphPhaseRunning {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if gs.Phase != engine.GraphPhaseCompleted {
+		t.Fatalf("phase = %q, want completed", gs.Phase)
+	}
+	if len(gs.Nodes) != 2 || gs.Nodes[1].Status != engine.TaskStatusCompleted {
+		t.Fatalf("nodes = %+v", gs.Nodes)
+	}
+}
+
+func TestPostTaskGraphInvalidReturns400(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	for name, body := range map[string]string{
+		"malformed":    `{nodes}`,
+		"no nodes":     `{"nodes":[]}`,
+		"cycle":        `{"nodes":[{"name":"a","type":"config-patch","dependsOn":["b"]},{"name":"b","type":"config-patch","dependsOn":["a"]}]}`,
+		"missing type": `{"nodes":[{"name":"a"}]}`,
+		"bad timeout":  `{"nodes":[{"name":"a","type":"config-patch","timeout":"later"}]}`,
+	} {
+		rec := serveHTTP(srv, http.MethodPost, "/v0/task-graphs", body)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestGetTaskGraphNotFound(t *testing.T) {
+	eng := newTestEngine[engine.TaskHandler](t, nil)
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+	rec := serveHTTP(srv, http.MethodGet, "/v0/task-graphs/00000000-0000-0000-0000-000000000000", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertReloader serves the sidecar's TLS certificate and the CA bundle
+// client certificates are verified against in mtls mode. It re-reads the
+// three files whenever one's modification time changes, so rotated
+// certificates (cert-manager, a mounted Secret) take effect on the next
+// handshake without a restart. A reload that fails keeps the previous
+// certificates in force.
+type CertReloader struct {
+	certFile, keyFile, clientCAFile string
+
+	mu       sync.Mutex
+	modTimes [3]time.Time
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	lastErr  string // last reload failure, logged once
+}
+
+// NewCertReloader loads the server key pair and client CA bundle, failing
+// when any of them is missing or invalid.
+func NewCertReloader(certFile, keyFile, clientCAFile string) (*CertReloader, error) {
+	if certFile == "" || keyFile == "" || clientCAFile == "" {
+		return nil, errors.New("mtls: a certificate, key and client CA file are all required")
+	}
+	c := &CertReloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// reload re-reads the files if any changed since the last load. The
+// caller holds c.mu, or has not yet shared c.
+func (c *CertReloader) reload() error {
+	var modTimes [3]time.Time
+	for i, f := range []string{c.certFile, c.keyFile, c.clientCAFile} {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return fmt.Errorf("mtls: %w", err)
+		}
+		modTimes[i] = fi.ModTime()
+	}
+	if c.cert != nil && modTimes == c.modTimes {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("mtls: load key pair: %w", err)
+	}
+	pem, err := os.ReadFile(c.clientCAFile)
+	if err != nil {
+		return fmt.Errorf("mtls: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("mtls: %s holds no PEM certificates", c.clientCAFile)
+	}
+	c.cert, c.clientCA, c.modTimes = &cert, pool, modTimes
+	return nil
+}
+
+// current returns the certificates to handshake with, reloading first.
+func (c *CertReloader) current() (*tls.Certificate, *x509.CertPool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastErr = warnReload(c.reload(), c.lastErr, "keeping previous TLS certificates")
+	return c.cert, c.clientCA
+}
+
+// warnReload logs a credential reload failure unless it repeats the last
+// one, so a broken file is reported once rather than on every request.
+// It returns the failure to remember, "" once a reload succeeds.
+func warnReload(err error, last, msg string) string {
+	if err == nil {
+		return ""
+	}
+	if err.Error() != last {
+		serverLog.Warn(msg, "err", err)
+	}
+	return err.Error()
+}
+
+// TLSConfig returns a server config that takes its certificate and client
+// CAs from c on every handshake. Client certificates are verified when
+// presented but not demanded at the handshake, so kubelet probes and
+// Prometheus can still reach bypassPaths; mtlsMiddleware requires one
+// everywhere else.
+func (c *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := c.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.VerifyClientCertIfGiven,
+				ClientCAs:    pool,
+			}, nil
+		},
+	}
+}
+
+// mtlsMiddleware requires a verified client certificate on every path
+// outside bypassPaths. The handshake has already checked the chain
+// against the client CA bundle; this only refuses requests that
+// presented none.
+func mtlsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := bypassPaths[r.URL.Path]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			authnRejections.WithLabelValues("missing_client_cert").Inc()
+			writeError(w, http.StatusUnauthorized, "a client certificate signed by the configured CA is required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// certCaller names the caller behind a verified client certificate the
+// way Kubernetes does: the subject's common name is the user and its
+// organizations are the groups.
+func certCaller(r *http.Request) (user string, groups []string) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return "", nil
+	}
+	leaf := r.TLS.VerifiedChains[0][0]
+	return leaf.Subject.CommonName, leaf.Subject.Organization
+}
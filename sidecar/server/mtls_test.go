@@ -0,0 +1,216 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// testCA is a throwaway certificate authority for mtls tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue signs a leaf certificate and returns its PEM certificate and key.
+func (ca *testCA) issue(t *testing.T, serial int64, subject pkix.Name, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFileAt(t *testing.T, path string, b []byte, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewCertReloaderRejectsBadConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "ca")
+	cert, key := ca.issue(t, 2, pkix.Name{CommonName: "sidecar"}, x509.ExtKeyUsageServerAuth)
+	now := time.Now()
+	writeFileAt(t, filepath.Join(dir, "tls.crt"), cert, now)
+	writeFileAt(t, filepath.Join(dir, "tls.key"), key, now)
+	writeFileAt(t, filepath.Join(dir, "ca.crt"), ca.pem, now)
+	writeFileAt(t, filepath.Join(dir, "junk"), []byte("not pem"), now)
+	f := func(name string) string { return filepath.Join(dir, name) }
+
+	if _, err := NewCertReloader(f("tls.crt"), f("tls.key"), f("ca.crt")); err != nil {
+		t.Fatalf("valid files: %v", err)
+	}
+	for name, files := range map[string][3]string{
+		"missing client CA": {f("tls.crt"), f("tls.key"), ""},
+		"absent key":        {f("tls.crt"), f("absent"), f("ca.crt")},
+		"mismatched pair":   {f("tls.crt"), f("ca.crt"), f("ca.crt")},
+		"CA bundle not PEM": {f("tls.crt"), f("tls.key"), f("junk")},
+	} {
+		if _, err := NewCertReloader(files[0], files[1], files[2]); err == nil {
+			t.Errorf("%s: NewCertReloader succeeded, want an error", name)
+		}
+	}
+}
+
+func TestMTLSServer(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	ca := newTestCA(t, "sidecar-ca")
+	start := time.Now()
+	cert, key := ca.issue(t, 10, pkix.Name{CommonName: "sidecar"}, x509.ExtKeyUsageServerAuth)
+	writeFileAt(t, certFile, cert, start)
+	writeFileAt(t, keyFile, key, start)
+	writeFileAt(t, caFile, ca.pem, start)
+
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{engine.TaskConfigPatch: noopHandler})
+	srv := NewServer(":0", eng, dir, AuthnModeMTLS)
+	reloader, err := NewCertReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.TLS = reloader
+	ts := httptest.NewUnstartedServer(srv.handler)
+	ts.TLS = reloader.TLSConfig()
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := func(issuer *testCA, subject pkix.Name) *http.Client {
+		cfg := &tls.Config{RootCAs: roots}
+		if issuer != nil {
+			certPEM, keyPEM := issuer.issue(t, 20, subject, x509.ExtKeyUsageClientAuth)
+			pair, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Certificates = []tls.Certificate{pair}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+	}
+	anonymous := client(nil, pkix.Name{})
+	alice := client(ca, pkix.Name{CommonName: "alice", Organization: []string{"ops"}})
+
+	get := func(c *http.Client, path string) (*http.Response, error) {
+		resp, err := c.Get(ts.URL + path)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+	if resp, err := get(anonymous, "/v0/livez"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("livez without a client cert = %v, %v; want 200", resp, err)
+	}
+	if resp, err := get(anonymous, "/v0/tasks"); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("tasks without a client cert = %v, %v; want 401", resp, err)
+	}
+	if resp, err := get(alice, "/v0/tasks"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("tasks with a client cert = %v, %v; want 200", resp, err)
+	}
+	// Go's client withholds a certificate none of the server's acceptable
+	// CAs issued; present one regardless to reach the server's check.
+	rogue := client(newTestCA(t, "rogue-ca"), pkix.Name{CommonName: "mallory"})
+	rogueCfg := rogue.Transport.(*http.Transport).TLSClientConfig
+	pair := rogueCfg.Certificates[0]
+	rogueCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return &pair, nil }
+	if _, err := get(rogue, "/v0/tasks"); err == nil {
+		t.Fatal("a foreign client cert presented regardless passed verification")
+	}
+
+	// The certificate's subject is the caller.
+	resp, err := alice.Post(ts.URL+"/v0/tasks", "application/json", strings.NewReader(`{"type":"config-patch"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("submit = %d, want 201", resp.StatusCode)
+	}
+	entries, err := eng.ListAudit(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Caller.User != "alice" || strings.Join(entries[0].Caller.Groups, ",") != "ops" {
+		t.Fatalf("audit = %+v, want caller alice in ops", entries)
+	}
+
+	// A rotated server certificate is served on the next handshake.
+	cert, key = ca.issue(t, 11, pkix.Name{CommonName: "sidecar"}, x509.ExtKeyUsageServerAuth)
+	writeFileAt(t, certFile, cert, start.Add(time.Minute))
+	writeFileAt(t, keyFile, key, start.Add(time.Minute))
+	fresh := client(ca, pkix.Name{CommonName: "alice"})
+	resp, err = fresh.Get(ts.URL + "/v0/livez")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.TLS.PeerCertificates[0].SerialNumber.Int64(); got != 11 {
+		t.Fatalf("served certificate serial = %d, want the rotated 11", got)
+	}
+}
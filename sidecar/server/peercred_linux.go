@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net"
+	"syscall"
+)
+
+// peerCredentials reads SO_PEERCRED from the connection's socket.
+func peerCredentials(c *net.UnixConn) (peerCred, error) {
+	raw, err := c.SyscallConn()
+	if err != nil {
+		return peerCred{}, err
+	}
+	var (
+		ucred   *syscall.Ucred
+		credErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		ucred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return peerCred{}, err
+	}
+	if credErr != nil {
+		return peerCred{}, credErr
+	}
+	return peerCred{UID: ucred.Uid, GID: ucred.Gid, PID: ucred.Pid}, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package server
+
+import (
+	"errors"
+	"net"
+)
+
+// peerCredentials is Linux-only; elsewhere socket peers get no identity.
+func peerCredentials(*net.UnixConn) (peerCred, error) {
+	return peerCred{}, errors.New("SO_PEERCRED is only supported on linux")
+}
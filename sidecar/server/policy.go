@@ -0,0 +1,224 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// Policy verbs. Reads are the GET endpoints; writes are every POST and
+// DELETE, checked against the task types they act on.
+const (
+	VerbRead  = "read"
+	VerbWrite = "write"
+)
+
+// SignTxTypes are the task types that sign and broadcast a transaction
+// with the validator's operator key. A policy denies writes of them
+// unless a rule allows them explicitly.
+var SignTxTypes = []engine.TaskType{
+	engine.TaskGovVote,
+	engine.TaskGovSoftwareUpgrade,
+	engine.TaskGovParamChange,
+}
+
+// Policy narrows what an identified caller may do. Each rule grants
+// its users and groups some verbs on some task types. A request that one
+// or more rules cover is allowed only when the caller matches one of
+// them; a request no rule covers is allowed, except a write of a
+// SignTxTypes task, which is denied.
+//
+// A rule with no task types covers every request of its verbs, typed or
+// not. Reads carry no task type, so only such rules cover them.
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule is one grant of a Policy. Users and groups are matched
+// against X-Remote-User and X-Remote-Group, or in mtls mode the client
+// certificate's common name and organizations; "*" in Users matches any
+// authenticated user.
+type PolicyRule struct {
+	Verbs     []string `json:"verbs"`
+	TaskTypes []string `json:"taskTypes,omitempty"`
+	Users     []string `json:"users,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+}
+
+// PolicyRequest is what a Policy decides on: who is asking, to do what,
+// to which task type. TaskType is empty for reads and for writes to a
+// task the sidecar does not know.
+type PolicyRequest struct {
+	Caller   engine.Caller
+	Verb     string
+	TaskType string
+}
+
+// PolicyDecision is a Policy's answer. Rule is the index of the rule
+// that allowed the request, or -1.
+type PolicyDecision struct {
+	Allowed bool
+	Rule    int
+	Reason  string
+}
+
+// LoadPolicy reads and validates a YAML (or JSON) policy file.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := yaml.UnmarshalStrict(b, &p); err != nil {
+		return nil, fmt.Errorf("parse policy %s: %w", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) validate() error {
+	for i, r := range p.Rules {
+		if len(r.Verbs) == 0 {
+			return fmt.Errorf("rule %d: verbs is required", i)
+		}
+		for _, v := range r.Verbs {
+			if v != VerbRead && v != VerbWrite {
+				return fmt.Errorf("rule %d: unknown verb %q (allowed: %s, %s)", i, v, VerbRead, VerbWrite)
+			}
+		}
+		if len(r.Users) == 0 && len(r.Groups) == 0 {
+			return fmt.Errorf("rule %d: at least one user or group is required", i)
+		}
+		if len(r.TaskTypes) > 0 && !slices.Contains(r.Verbs, VerbWrite) {
+			return fmt.Errorf("rule %d: taskTypes only apply to the %s verb; reads carry no task type", i, VerbWrite)
+		}
+	}
+	return nil
+}
+
+// TaskTypes returns every task type the policy names, so serve can
+// reject a policy that misspells one.
+func (p *Policy) TaskTypes() []string {
+	var out []string
+	for _, r := range p.Rules {
+		for _, t := range r.TaskTypes {
+			if !slices.Contains(out, t) {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// Evaluate decides req.
+func (p *Policy) Evaluate(req PolicyRequest) PolicyDecision {
+	covered := false
+	for i, r := range p.Rules {
+		if !r.covers(req) {
+			continue
+		}
+		covered = true
+		if r.matches(req.Caller) {
+			return PolicyDecision{Allowed: true, Rule: i, Reason: fmt.Sprintf("allowed by rule %d", i)}
+		}
+	}
+	switch {
+	case covered:
+		return PolicyDecision{Rule: -1, Reason: "no rule covering this request matches the caller"}
+	case req.Verb == VerbWrite && slices.Contains(SignTxTypes, engine.TaskType(req.TaskType)):
+		return PolicyDecision{Rule: -1, Reason: fmt.Sprintf("%s signs transactions and no rule allows it", req.TaskType)}
+	default:
+		return PolicyDecision{Allowed: true, Rule: -1, Reason: "no rule covers this request"}
+	}
+}
+
+func (r PolicyRule) covers(req PolicyRequest) bool {
+	if !slices.Contains(r.Verbs, req.Verb) {
+		return false
+	}
+	return len(r.TaskTypes) == 0 || (req.TaskType != "" && slices.Contains(r.TaskTypes, req.TaskType))
+}
+
+func (r PolicyRule) matches(c engine.Caller) bool {
+	if c.User != "" && (slices.Contains(r.Users, "*") || slices.Contains(r.Users, c.User)) {
+		return true
+	}
+	for _, g := range c.Groups {
+		if slices.Contains(r.Groups, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks r against the server's policy for verb on each of
+// taskTypes (or on no task type when none are given), answering 403 and
+// returning false on the first denial. Without a policy every request is
+// allowed.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, verb string, taskTypes ...string) bool {
+	if s.Policy == nil {
+		return true
+	}
+	if len(taskTypes) == 0 {
+		taskTypes = []string{""}
+	}
+	caller := s.caller(r)
+	for _, t := range taskTypes {
+		d := s.Policy.Evaluate(PolicyRequest{Caller: caller, Verb: verb, TaskType: t})
+		if d.Allowed {
+			continue
+		}
+		authzDenials.WithLabelValues(verb).Inc()
+		writeForbidden(w, caller.User, verb, t, d.Reason)
+		return false
+	}
+	return true
+}
+
+// authorizedRead wraps a read handler with the policy's read check.
+func (s *Server) authorizedRead(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.authorize(w, r, VerbRead) {
+			h(w, r)
+		}
+	}
+}
+
+// writeForbidden answers 403 with a Kubernetes Status body, the shape
+// kube-rbac-proxy's own denials take, so clients handle both alike.
+func writeForbidden(w http.ResponseWriter, user, verb, taskType, reason string) {
+	subject := "this endpoint"
+	if taskType != "" {
+		subject = fmt.Sprintf("task type %q", taskType)
+	}
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  fmt.Sprintf("user %q cannot %s %s: %s", user, verb, subject, reason),
+		Reason:   metav1.StatusReasonForbidden,
+		Code:     http.StatusForbidden,
+	}
+	if taskType != "" {
+		status.Details = &metav1.StatusDetails{Kind: "task", Name: taskType}
+	}
+	writeJSON(w, http.StatusForbidden, status)
+}
+
+// policyTaskTypes returns the distinct task types of a graph's nodes.
+func policyTaskTypes(nodes []TaskGraphNodeRequest) []string {
+	var out []string
+	for _, n := range nodes {
+		if !slices.Contains(out, n.Type) {
+			out = append(out, n.Type)
+		}
+	}
+	return out
+}
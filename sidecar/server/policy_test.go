@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func writePolicy(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testPolicy = `
+rules:
+  - verbs: [write]
+    taskTypes: [gov-vote]
+    groups: [governance]
+  - verbs: [write]
+    taskTypes: [reset-data]
+    users: [oncall]
+  - verbs: [read]
+    users: ["*"]
+`
+
+func TestPolicyEvaluate(t *testing.T) {
+	p, err := LoadPolicy(writePolicy(t, testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := engine.Caller{User: "alice"}
+	gov := engine.Caller{User: "bob", Groups: []string{"governance"}}
+	for _, tc := range []struct {
+		name    string
+		req     PolicyRequest
+		allowed bool
+		rule    int
+	}{
+		{"sign-tx granted by group", PolicyRequest{Caller: gov, Verb: VerbWrite, TaskType: "gov-vote"}, true, 0},
+		{"sign-tx outside the group", PolicyRequest{Caller: alice, Verb: VerbWrite, TaskType: "gov-vote"}, false, -1},
+		{"sign-tx no rule names", PolicyRequest{Caller: gov, Verb: VerbWrite, TaskType: "gov-param-change"}, false, -1},
+		{"covered type, other user", PolicyRequest{Caller: alice, Verb: VerbWrite, TaskType: "reset-data"}, false, -1},
+		{"covered type, granted user", PolicyRequest{Caller: engine.Caller{User: "oncall"}, Verb: VerbWrite, TaskType: "reset-data"}, true, 1},
+		{"uncovered type", PolicyRequest{Caller: alice, Verb: VerbWrite, TaskType: "config-patch"}, true, -1},
+		{"read by any user", PolicyRequest{Caller: alice, Verb: VerbRead}, true, 2},
+		{"read without identity", PolicyRequest{Verb: VerbRead}, false, -1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := p.Evaluate(tc.req)
+			if d.Allowed != tc.allowed || d.Rule != tc.rule || d.Reason == "" {
+				t.Fatalf("decision = %+v, want allowed=%v rule=%d", d, tc.allowed, tc.rule)
+			}
+		})
+	}
+
+	if got := strings.Join(p.TaskTypes(), ","); got != "gov-vote,reset-data" {
+		t.Errorf("TaskTypes() = %s", got)
+	}
+}
+
+func TestLoadPolicyRejectsInvalid(t *testing.T) {
+	for name, body := range map[string]string{
+		"unknown field":     "rules:\n  - verbs: [write]\n    user: [alice]\n",
+		"no verbs":          "rules:\n  - users: [alice]\n",
+		"unknown verb":      "rules:\n  - verbs: [delete]\n    users: [alice]\n",
+		"no subjects":       "rules:\n  - verbs: [write]\n",
+		"typed read":        "rules:\n  - verbs: [read]\n    taskTypes: [gov-vote]\n    users: [alice]\n",
+		"not a policy file": "- just\n- a list\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadPolicy(writePolicy(t, body)); err == nil {
+				t.Fatal("LoadPolicy succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestPolicyForbidsOverHTTP(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: noopHandler,
+		engine.TaskGovVote:     noopHandler,
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeTrustedHeader)
+	p, err := LoadPolicy(writePolicy(t, testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Policy = p
+
+	do := func(method, path, body, user string, groups ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(remoteUserHeader, user)
+		for _, g := range groups {
+			req.Header.Add(remoteGroupHeader, g)
+		}
+		rec := httptest.NewRecorder()
+		srv.handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/v0/tasks", `{"type":"gov-vote"}`, "alice")
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("alice gov-vote = %d, want 403: %s", rec.Code, rec.Body.String())
+	}
+	var status metav1.Status
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Kind != "Status" || status.Reason != metav1.StatusReasonForbidden || status.Code != http.StatusForbidden ||
+		status.Details == nil || status.Details.Name != "gov-vote" || !strings.Contains(status.Message, `"alice"`) {
+		t.Errorf("status = %+v", status)
+	}
+
+	if rec := do(http.MethodPost, "/v0/tasks", `{"type":"gov-vote"}`, "bob", "governance"); rec.Code != http.StatusCreated {
+		t.Errorf("governance gov-vote = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/v0/task-graphs", `{"nodes":[{"name":"a","type":"config-patch"},{"name":"b","type":"gov-vote"}]}`, "alice"); rec.Code != http.StatusForbidden {
+		t.Errorf("graph with a gov-vote node = %d, want 403", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/v0/schedules", `{"interval":"1h","task":{"type":"gov-vote"}}`, "alice"); rec.Code != http.StatusForbidden {
+		t.Errorf("gov-vote schedule = %d, want 403", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/v0/tasks", "", "alice"); rec.Code != http.StatusOK {
+		t.Errorf("read = %d, want 200", rec.Code)
+	}
+
+	// Denials are audited like any other answer.
+	entries, err := eng.ListAudit(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || entries[0].Status != http.StatusForbidden || entries[0].TaskType != "gov-vote" {
+		t.Errorf("audit = %+v, want the denial first", entries)
+	}
+}
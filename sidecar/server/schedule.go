@@ -0,0 +1,182 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// ScheduleRequest is the JSON body for POST /v0/schedules. Exactly one of
+// Cron (five-field, UTC) and Interval (a Go duration string) is required.
+// MissedPolicy is "skip" (default) or "catch-up". When ID is provided it is
+// the schedule's canonical identifier and resubmission is idempotent;
+// otherwise a random UUID is generated.
+type ScheduleRequest struct {
+	ID           string              `json:"id,omitempty"`
+	Cron         string              `json:"cron,omitempty"`
+	Interval     string              `json:"interval,omitempty"`
+	MissedPolicy string              `json:"missedPolicy,omitempty"`
+	Task         ScheduleTaskRequest `json:"task"`
+}
+
+// ScheduleTaskRequest is the task template a schedule submits at each
+// firing. Fields mean the same as on TaskRequest; the ID is derived per
+// firing and cannot be set.
+type ScheduleTaskRequest struct {
+	Type     string         `json:"type"`
+	Params   map[string]any `json:"params,omitempty"`
+	Timeout  string         `json:"timeout,omitempty"`
+	Priority int            `json:"priority,omitempty"`
+}
+
+// ScheduleResponse is the wire view of an engine.Schedule, with durations
+// rendered as Go duration strings.
+type ScheduleResponse struct {
+	ID           string              `json:"id"`
+	Cron         string              `json:"cron,omitempty"`
+	Interval     string              `json:"interval,omitempty"`
+	MissedPolicy string              `json:"missedPolicy"`
+	Task         ScheduleTaskRequest `json:"task"`
+	NextRunAt    *time.Time          `json:"nextRunAt,omitempty"`
+	LastRunAt    *time.Time          `json:"lastRunAt,omitempty"`
+	LastTaskID   string              `json:"lastTaskId,omitempty"`
+	LastError    string              `json:"lastError,omitempty"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	CreatedBy    string              `json:"createdBy,omitempty"`
+}
+
+func scheduleResponse(s engine.Schedule) ScheduleResponse {
+	resp := ScheduleResponse{
+		ID:           s.ID,
+		Cron:         s.Cron,
+		MissedPolicy: string(s.MissedPolicy),
+		Task: ScheduleTaskRequest{
+			Type:     string(s.Task.Type),
+			Params:   s.Task.Params,
+			Priority: s.Task.Priority,
+		},
+		LastRunAt:  s.LastRunAt,
+		LastTaskID: s.LastTaskID,
+		LastError:  s.LastError,
+		CreatedAt:  s.CreatedAt,
+		CreatedBy:  s.CreatedBy,
+	}
+	if s.Interval > 0 {
+		resp.Interval = s.Interval.String()
+	}
+	if s.Task.Timeout > 0 {
+		resp.Task.Timeout = s.Task.Timeout.String()
+	}
+	// A cron schedule with no further matches has no next firing.
+	if !s.NextRunAt.IsZero() {
+		next := s.NextRunAt
+		resp.NextRunAt = &next
+	}
+	return resp
+}
+
+func (s *Server) handlePostSchedule(w http.ResponseWriter, r *http.Request) {
+	var req ScheduleRequest
+	if err := decodeSubmission(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	audit := auditOf(r)
+	audit.TaskType, audit.ParamsDigest = req.Task.Type, paramsDigest(req.Task.Params)
+	if req.Task.Type == "" {
+		writeError(w, http.StatusBadRequest, "task.type is required")
+		return
+	}
+	if !s.authorize(w, r, VerbWrite, req.Task.Type) {
+		return
+	}
+	timeout, err := parseTimeout(req.Task.Timeout)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var interval time.Duration
+	if req.Interval != "" {
+		if interval, err = time.ParseDuration(req.Interval); err != nil || interval <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid interval %q: must be a positive duration", req.Interval))
+			return
+		}
+	}
+
+	id, err := s.engine.CreateSchedule(engine.Schedule{
+		ID:           req.ID,
+		Cron:         req.Cron,
+		Interval:     interval,
+		MissedPolicy: engine.MissedRunPolicy(req.MissedPolicy),
+		Task: engine.Task{
+			Type:     engine.TaskType(req.Task.Type),
+			Params:   req.Task.Params,
+			Timeout:  timeout,
+			Priority: req.Task.Priority,
+		},
+		CreatedBy: s.remoteUser(r),
+	})
+	switch {
+	case errors.Is(err, engine.ErrInvalidSchedule), errors.Is(err, engine.ErrInvalidTaskID):
+		writeBadRequest(w, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	audit.TaskID = id
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+func (s *Server) handleListSchedules(w http.ResponseWriter, _ *http.Request) {
+	schedules := s.engine.ListSchedules()
+	resp := make([]ScheduleResponse, 0, len(schedules))
+	for _, sc := range schedules {
+		resp = append(resp, scheduleResponse(sc))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing schedule ID")
+		return
+	}
+	sc := s.engine.GetSchedule(id)
+	if sc == nil {
+		writeError(w, http.StatusNotFound, "schedule not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, scheduleResponse(*sc))
+}
+
+func (s *Server) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing schedule ID")
+		return
+	}
+	auditOf(r).TaskID = id
+	taskType := ""
+	if sc := s.engine.GetSchedule(id); sc != nil {
+		taskType = string(sc.Task.Type)
+	}
+	if !s.authorize(w, r, VerbWrite, taskType) {
+		return
+	}
+	deleted, err := s.engine.DeleteSchedule(id)
+	if err != nil {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusServiceUnavailable, "failed to delete schedule; retry")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "schedule not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func scheduleTestServer(t *testing.T) *Server {
+	t.Helper()
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigValidate: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	return NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+}
+
+func TestScheduleLifecycle(t *testing.T) {
+	srv := scheduleTestServer(t)
+
+	body := `{"interval":"1h","missedPolicy":"catch-up","task":{"type":"config-validate","timeout":"5m","priority":3}}`
+	rec := serveHTTP(srv, http.MethodPost, "/v0/schedules", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	id := created["id"]
+
+	rec = serveHTTP(srv, http.MethodGet, "/v0/schedules/"+id, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got ScheduleResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if got.Interval != "1h0m0s" || got.MissedPolicy != "catch-up" || got.NextRunAt == nil ||
+		got.Task.Type != "config-validate" || got.Task.Timeout != "5m0s" || got.Task.Priority != 3 {
+		t.Fatalf("schedule = %+v", got)
+	}
+
+	rec = serveHTTP(srv, http.MethodGet, "/v0/schedules", "")
+	var list []ScheduleResponse
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != id {
+		t.Fatalf("schedules = %+v, want just %s", list, id)
+	}
+
+	if rec = serveHTTP(srv, http.MethodDelete, "/v0/schedules/"+id, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec = serveHTTP(srv, http.MethodDelete, "/v0/schedules/"+id, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 on second delete, got %d", rec.Code)
+	}
+	if rec = serveHTTP(srv, http.MethodGet, "/v0/schedules/"+id, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestPostScheduleInvalidReturns400(t *testing.T) {
+	srv := scheduleTestServer(t)
+
+	for name, body := range map[string]string{
+		"malformed":     `{task}`,
+		"missing type":  `{"interval":"1h","task":{}}`,
+		"no cadence":    `{"task":{"type":"config-validate"}}`,
+		"both cadences": `{"cron":"@daily","interval":"1h","task":{"type":"config-validate"}}`,
+		"bad cron":      `{"cron":"every tuesday","task":{"type":"config-validate"}}`,
+		"bad interval":  `{"interval":"often","task":{"type":"config-validate"}}`,
+		"bad timeout":   `{"interval":"1h","task":{"type":"config-validate","timeout":"later"}}`,
+		"bad policy":    `{"interval":"1h","missedPolicy":"never","task":{"type":"config-validate"}}`,
+		"bad id":        `{"id":"not-a-uuid","interval":"1h","task":{"type":"config-validate"}}`,
+		"unknown type":  `{"interval":"1h","task":{"type":"snapshot-upload"}}`,
+	} {
+		rec := serveHTTP(srv, http.MethodPost, "/v0/schedules", body)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestPostScheduleRecordsCreator(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{engine.TaskConfigValidate: noopHandler})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeTrustedHeader)
+
+	req := httptest.NewRequest(http.MethodPost, "/v0/schedules", strings.NewReader(`{"interval":"1h","task":{"type":"config-validate"}}`))
+	req.Header.Set(remoteUserHeader, "alice")
+	rec := httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	id := created["id"]
+
+	if sc := eng.GetSchedule(id); sc == nil || sc.CreatedBy != "alice" {
+		t.Fatalf("schedule = %+v, want createdBy alice", sc)
+	}
+	entries := listAudit(t, srv, "")
+	if len(entries) != 1 {
+		t.Fatalf("audit = %+v, want 1 entry", entries)
+	}
+	if e := entries[0]; e.Action != auditCreateSchedule || e.Caller.User != "alice" || e.TaskID != id ||
+		e.TaskType != "config-validate" || e.Status != http.StatusCreated {
+		t.Errorf("audit entry = %+v", e)
+	}
+}
@@ -7,9 +7,14 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -28,50 +33,105 @@ const (
 
 // Server is the HTTP API for the sidecar.
 type Server struct {
-	addr    string
-	homeDir string
-	engine  *engine.Engine
-	mux     *http.ServeMux
-	handler http.Handler // mux, possibly wrapped by trustedHeaderMiddleware
+	addr      string
+	homeDir   string
+	authnMode string
+	engine    *engine.Engine
+	mux       *http.ServeMux
+	handler   http.Handler // mux, possibly wrapped by the authn mode's middleware
+
+	// Policy, when set, authorizes each request by the caller's
+	// identity. It must be set before serving, and only in trusted-header
+	// or mtls mode, where callers have an identity.
+	Policy *Policy
+
+	// Token verifies bearer tokens in token mode; TLS serves the
+	// certificates in mtls mode. The mode's field must be set before
+	// serving: without it token mode rejects every request and mtls mode
+	// fails to start.
+	Token *BearerToken
+	TLS   *CertReloader
+
+	// Listener, when set before serving, is served on in place of
+	// listening on the server's address, e.g. one on an ephemeral port.
+	Listener net.Listener
+
+	// UnixListener, when set before serving, is a Unix socket (see
+	// ListenUnix) the API is also served on. Requests through it bypass
+	// the authn mode; their caller is the peer process's uid.
+	UnixListener net.Listener
+
+	// streamsDone is closed when graceful shutdown begins, ending every
+	// open event stream; Shutdown would otherwise wait on them.
+	streamsDone chan struct{}
 }
 
 // TaskRequest is the JSON body for POST /v0/tasks. When ID is provided,
 // the engine uses it as the task's canonical identifier; otherwise a
-// random UUID is generated.
+// random UUID is generated. Timeout is a Go duration string ("90s",
+// "2h") bounding execution; empty falls back to the per-type default.
+// Priority orders the task if it has to queue; higher starts first.
 type TaskRequest struct {
-	ID     string         `json:"id,omitempty"`
-	Type   string         `json:"type"`
-	Params map[string]any `json:"params,omitempty"`
+	ID       string         `json:"id,omitempty"`
+	Type     string         `json:"type"`
+	Params   map[string]any `json:"params,omitempty"`
+	Timeout  string         `json:"timeout,omitempty"`
+	Priority int            `json:"priority,omitempty"`
 }
 
-// ErrorResponse is a standard JSON error envelope.
+// ErrorResponse is a standard JSON error envelope. Fields names the task
+// params at fault when a submission is rejected for its params.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error  string   `json:"error"`
+	Fields []string `json:"fields,omitempty"`
 }
 
 // NewServer wires a Server to the engine. authnMode must come from
 // AuthnMode() so the env read and validation happen once at startup.
 func NewServer(addr string, eng *engine.Engine, homeDir, authnMode string) *Server {
 	s := &Server{
-		addr:    addr,
-		homeDir: homeDir,
-		engine:  eng,
-		mux:     http.NewServeMux(),
+		addr:      addr,
+		homeDir:   homeDir,
+		authnMode: authnMode,
+		engine:    eng,
+		mux:       http.NewServeMux(),
+
+		streamsDone: make(chan struct{}),
 	}
 	s.mux.HandleFunc("GET /v0/healthz", s.handleHealthz)
 	s.mux.HandleFunc("GET /v0/startupz", s.handleHealthz)
 	s.mux.HandleFunc("GET /v0/livez", s.handleLivez)
-	s.mux.HandleFunc("GET /v0/status", s.handleStatus)
+	s.mux.HandleFunc("GET /v0/status", s.authorizedRead(s.handleStatus))
 	s.mux.Handle("GET /v0/metrics", promhttp.Handler())
-	s.mux.HandleFunc("GET /v0/node-id", s.handleNodeID)
-	s.mux.HandleFunc("POST /v0/tasks", s.handlePostTask)
-	s.mux.HandleFunc("GET /v0/tasks", s.handleListTasks)
-	s.mux.HandleFunc("GET /v0/tasks/{id}", s.handleGetTask)
-	s.mux.HandleFunc("DELETE /v0/tasks/{id}", s.handleDeleteTask)
-
-	s.handler = s.mux
-	if authnMode == AuthnModeTrustedHeader {
+	s.mux.HandleFunc("GET /v0/node-id", s.authorizedRead(s.handleNodeID))
+	s.mux.HandleFunc("GET /v0/capabilities", s.authorizedRead(s.handleCapabilities))
+	s.mux.HandleFunc("GET /v0/events", s.authorizedRead(s.handleEvents))
+	s.mux.HandleFunc("POST /v0/admin/drain", s.audited(auditDrain, s.handleDrain))
+	s.mux.HandleFunc("POST /v0/admin/undrain", s.audited(auditUndrain, s.handleUndrain))
+	s.mux.HandleFunc("POST /v0/tasks", s.audited(auditSubmit, s.handlePostTask))
+	s.mux.HandleFunc("GET /v0/tasks", s.authorizedRead(s.handleListTasks))
+	s.mux.HandleFunc("GET /v0/tasks/{id}", s.authorizedRead(s.handleGetTask))
+	s.mux.HandleFunc("DELETE /v0/tasks/{id}", s.audited(auditDelete, s.handleDeleteTask))
+	// ServeMux wildcards span whole segments, so "{id}:cancel" is routed
+	// here and split by the handler.
+	s.mux.HandleFunc("POST /v0/tasks/{action}", s.audited(auditCancel, s.handleTaskAction))
+	s.mux.HandleFunc("POST /v0/task-graphs", s.audited(auditSubmitGraph, s.handlePostTaskGraph))
+	s.mux.HandleFunc("GET /v0/task-graphs/{id}", s.authorizedRead(s.handleGetTaskGraph))
+	s.mux.HandleFunc("POST /v0/schedules", s.audited(auditCreateSchedule, s.handlePostSchedule))
+	s.mux.HandleFunc("GET /v0/schedules", s.authorizedRead(s.handleListSchedules))
+	s.mux.HandleFunc("GET /v0/schedules/{id}", s.authorizedRead(s.handleGetSchedule))
+	s.mux.HandleFunc("DELETE /v0/schedules/{id}", s.audited(auditDeleteSchedule, s.handleDeleteSchedule))
+	s.mux.HandleFunc("GET /v0/audit", s.authorizedRead(s.handleListAudit))
+
+	switch authnMode {
+	case AuthnModeTrustedHeader:
 		s.handler = trustedHeaderMiddleware(s.mux)
+	case AuthnModeToken:
+		s.handler = s.tokenMiddleware(s.mux)
+	case AuthnModeMTLS:
+		s.handler = mtlsMiddleware(s.mux)
+	default:
+		s.handler = s.mux
 	}
 	return s
 }
@@ -86,6 +146,25 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 	writeJSON(w, status, ErrorResponse{Error: msg})
 }
 
+// writeBadRequest writes a 400 for a submission the engine rejected,
+// listing the offending params fields when err carries them.
+func writeBadRequest(w http.ResponseWriter, err error) {
+	resp := ErrorResponse{Error: err.Error()}
+	var pe *engine.ParamsError
+	if errors.As(err, &pe) {
+		resp.Fields = pe.Fields()
+	}
+	writeJSON(w, http.StatusBadRequest, resp)
+}
+
+// decodeSubmission decodes a submission body. Numbers in params stay
+// json.Number so large integers reach the handler exactly.
+func decodeSubmission(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
 func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
 	if !s.engine.Healthz() {
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -116,33 +195,132 @@ func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
 
 func (s *Server) handlePostTask(w http.ResponseWriter, r *http.Request) {
 	var req TaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeSubmission(r, &req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	audit := auditOf(r)
+	audit.TaskID, audit.TaskType, audit.ParamsDigest = req.ID, req.Type, paramsDigest(req.Params)
 	if req.Type == "" {
 		writeError(w, http.StatusBadRequest, "type is required")
 		return
 	}
 
-	task := engine.Task{ID: req.ID, Type: engine.TaskType(req.Type), Params: req.Params}
+	if !s.authorize(w, r, VerbWrite, req.Type) {
+		return
+	}
 
-	id, err := s.engine.Submit(task)
+	timeout, err := parseTimeout(req.Timeout)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+
+	task := engine.Task{
+		ID:          req.ID,
+		Type:        engine.TaskType(req.Type),
+		Params:      req.Params,
+		Timeout:     timeout,
+		Priority:    req.Priority,
+		SubmittedBy: s.remoteUser(r),
+	}
+
+	id, err := s.engine.Submit(task)
+	switch {
+	case errors.Is(err, engine.ErrTaskConflict):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, engine.ErrDraining):
+		s.writeDraining(w, err)
+		return
+	case err != nil:
+		writeBadRequest(w, err)
+		return
+	}
+	audit.TaskID = id
 	writeJSON(w, http.StatusCreated, map[string]string{"id": id})
 }
 
-func (s *Server) handleListTasks(w http.ResponseWriter, _ *http.Request) {
-	results := s.engine.RecentResults()
+// parseTimeout parses a request's optional Go-duration timeout. Empty yields
+// zero, which defers to the engine's per-type default.
+func parseTimeout(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be a positive duration", v)
+	}
+	return d, nil
+}
+
+// nextCursorHeader carries the cursor for the next page of GET /v0/tasks,
+// keeping the response body a plain array of results.
+const nextCursorHeader = "X-Next-Cursor"
+
+func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	q, err := parseResultQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	results, next, err := s.engine.ListResults(q)
+	if errors.Is(err, engine.ErrInvalidCursor) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		serverLog.Error("listing task results", "err", err)
+		writeError(w, http.StatusInternalServerError, "failed to list task results")
+		return
+	}
 	if results == nil {
 		results = []engine.TaskResult{}
 	}
+	if next != "" {
+		w.Header().Set(nextCursorHeader, next)
+	}
 	writeJSON(w, http.StatusOK, results)
 }
 
+// parseResultQuery reads GET /v0/tasks's filter and paging parameters.
+func parseResultQuery(r *http.Request) (engine.ResultQuery, error) {
+	v := r.URL.Query()
+	q := engine.ResultQuery{Type: v.Get("type"), Cursor: v.Get("cursor")}
+	if raw := v.Get("status"); raw != "" {
+		switch st := engine.TaskStatus(raw); st {
+		case engine.TaskStatusPending, engine.TaskStatusRunning, engine.TaskStatusCompleted,
+			engine.TaskStatusFailed, engine.TaskStatusCancelled, engine.TaskStatusSkipped:
+			q.Status = st
+		default:
+			return q, fmt.Errorf("unknown status %q", raw)
+		}
+	}
+	for _, p := range []struct {
+		name string
+		into *time.Time
+	}{
+		{"submittedAfter", &q.SubmittedAfter},
+		{"submittedBefore", &q.SubmittedBefore},
+	} {
+		if raw := v.Get(p.name); raw != "" {
+			t, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return q, fmt.Errorf("%s must be an RFC 3339 timestamp: %q", p.name, raw)
+			}
+			*p.into = t
+		}
+	}
+	if raw := v.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > engine.MaxListLimit {
+			return q, fmt.Errorf("limit must be an integer between 1 and %d", engine.MaxListLimit)
+		}
+		q.Limit = n
+	}
+	return q, nil
+}
+
 func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
@@ -163,6 +341,10 @@ func (s *Server) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "missing task ID")
 		return
 	}
+	auditOf(r).TaskID = id
+	if !s.authorize(w, r, VerbWrite, s.taskType(id)) {
+		return
+	}
 	deleted, err := s.engine.RemoveResult(id)
 	if err != nil {
 		// A failed delete leaves the row recoverable (RemoveResult cancels the
@@ -180,6 +362,70 @@ func (s *Server) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// cancelWait bounds how long POST /v0/tasks/{id}:cancel waits for a running
+// handler to stop before answering 202. It stays well under the typed
+// client's per-request timeout so the 202 arrives before the caller gives up.
+const cancelWait = 5 * time.Second
+
+// CancelRequest is the optional JSON body for POST /v0/tasks/{id}:cancel.
+type CancelRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// handleTaskAction dispatches POST /v0/tasks/{id}:<verb>. cancel is the only
+// verb.
+func (s *Server) handleTaskAction(w http.ResponseWriter, r *http.Request) {
+	id, ok := strings.CutSuffix(r.PathValue("action"), ":cancel")
+	if !ok || id == "" {
+		writeError(w, http.StatusNotFound, "unknown task action")
+		return
+	}
+	auditOf(r).TaskID = id
+	if !s.authorize(w, r, VerbWrite, s.taskType(id)) {
+		return
+	}
+
+	var req CancelRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), cancelWait)
+	defer cancel()
+	result, err := s.engine.CancelTask(ctx, id, s.remoteUser(r), req.Reason)
+	switch {
+	case errors.Is(err, engine.ErrTaskFinished):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusServiceUnavailable, "failed to cancel task; retry")
+	case result == nil:
+		writeError(w, http.StatusNotFound, "task not found")
+	case result.Status != engine.TaskStatusCancelled:
+		writeJSON(w, http.StatusAccepted, result)
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// taskType returns the type of the task with the given ID, or "" when
+// the sidecar has no record of it.
+func (s *Server) taskType(id string) string {
+	if r := s.engine.GetResult(id); r != nil {
+		return r.Type
+	}
+	return ""
+}
+
+// remoteUser returns the authenticated caller's user name, or "" when
+// the request carries no identity.
+func (s *Server) remoteUser(r *http.Request) string {
+	return s.caller(r).User
+}
+
 // handleNodeID reads node_key.json from the home directory and returns the
 // Tendermint node ID. The node ID is hex(SHA256(ed25519_pubkey)[:20]), matching
 // CometBFT's p2p.PubKeyToID derivation.
@@ -215,19 +461,42 @@ func (s *Server) handleNodeID(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"nodeId": nodeID})
 }
 
-// ListenAndServe starts the HTTP server and blocks until ctx is cancelled.
+// ListenAndServe starts the HTTP server, on s.Listener when set, and blocks
+// until ctx is cancelled. In mtls mode it serves HTTPS with the
+// certificates s.TLS provides. When
+// s.UnixListener is set the API is served on it too, and a failure of
+// either listener stops both.
 func (s *Server) ListenAndServe(ctx context.Context) error {
-	srv := &http.Server{
-		Addr:              s.addr,
-		Handler:           s.handler,
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       10 * time.Second,
-		WriteTimeout:      30 * time.Second,
-		IdleTimeout:       60 * time.Second,
-		// Cap impersonation-header amplification in trusted-header
-		// mode (proxy injects X-Remote-User / Group / Extra-*).
-		// Go's default is 1MB.
-		MaxHeaderBytes: 32 * 1024,
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	srv := newHTTPServer(s.addr, s.handler)
+	if s.authnMode == AuthnModeMTLS {
+		if s.TLS == nil {
+			return errors.New("mtls mode requires TLS certificates")
+		}
+		srv.TLSConfig = s.TLS.TLSConfig()
+	}
+	srv.RegisterOnShutdown(func() { close(s.streamsDone) })
+	servers := []*http.Server{srv}
+	serve := []func() error{func() error {
+		switch {
+		case s.Listener != nil && srv.TLSConfig != nil:
+			return srv.ServeTLS(s.Listener, "", "")
+		case s.Listener != nil:
+			return srv.Serve(s.Listener)
+		case srv.TLSConfig != nil:
+			return srv.ListenAndServeTLS("", "")
+		}
+		return srv.ListenAndServe()
+	}}
+	if s.UnixListener != nil {
+		// The socket skips the authn mode's middleware: its file
+		// permissions gate access and peer credentials name the caller.
+		usrv := newHTTPServer("", s.mux)
+		usrv.ConnContext = unixConnContext
+		servers = append(servers, usrv)
+		serve = append(serve, func() error { return usrv.Serve(s.UnixListener) })
 	}
 
 	go func() {
@@ -236,13 +505,40 @@ func (s *Server) ListenAndServe(ctx context.Context) error {
 		// terminationGracePeriodSeconds.
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
 		defer cancel()
-		if err := srv.Shutdown(shutdownCtx); err != nil {
-			serverLog.Warn("graceful shutdown failed", "err", err)
+		for _, srv := range servers {
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				serverLog.Warn("graceful shutdown failed", "err", err)
+			}
 		}
 	}()
 
-	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-		return err
+	errs := make(chan error, len(serve))
+	for _, fn := range serve {
+		go func() { errs <- fn() }()
+	}
+	var first error
+	for range serve {
+		if err := <-errs; !errors.Is(err, http.ErrServerClosed) && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	return first
+}
+
+// newHTTPServer returns an http.Server for handler with the sidecar's
+// timeouts and header cap.
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		// Cap impersonation-header amplification in trusted-header
+		// mode (proxy injects X-Remote-User / Group / Extra-*).
+		// Go's default is 1MB.
+		MaxHeaderBytes: 32 * 1024,
 	}
-	return nil
 }
@@ -18,7 +18,7 @@ import (
 	"github.com/sei-protocol/seictl/sidecar/engine"
 )
 
-func newTestEngine(t *testing.T, handlers map[engine.TaskType]engine.TaskHandler) *engine.Engine {
+func newTestEngine[H engine.Handler](t *testing.T, handlers map[engine.TaskType]H) *engine.Engine {
 	t.Helper()
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -64,7 +64,7 @@ func waitForTaskResult(eng *engine.Engine, id string) *engine.TaskResult {
 }
 
 func TestLivezReturns200WhenStoreHealthy(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 	rec := serveHTTP(srv, http.MethodGet, "/v0/livez", "")
 
@@ -75,7 +75,7 @@ func TestLivezReturns200WhenStoreHealthy(t *testing.T) {
 
 func TestLivezReturns200BeforeReady(t *testing.T) {
 	// Livez should pass even before mark-ready (healthz would return 503).
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 
 	if eng.Healthz() {
@@ -94,7 +94,7 @@ func TestLivezReturns503WhenStoreDown(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	eng := engine.NewEngine(ctx, nil, store)
+	eng := engine.NewEngine[engine.TaskHandler](ctx, nil, store)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 
 	// Close the backing store to simulate SQLite failure.
@@ -107,7 +107,7 @@ func TestLivezReturns503WhenStoreDown(t *testing.T) {
 }
 
 func TestHealthzReturns503BeforeReady(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 	rec := serveHTTP(srv, http.MethodGet, "/v0/healthz", "")
 
@@ -269,7 +269,7 @@ func TestPostTaskInvalidIDReturns400(t *testing.T) {
 }
 
 func TestPostTaskInvalidJSON(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 	rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{not json}`)
 	if rec.Code != http.StatusBadRequest {
@@ -278,7 +278,7 @@ func TestPostTaskInvalidJSON(t *testing.T) {
 }
 
 func TestPostTaskMissingType(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 	rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"params":{}}`)
 	if rec.Code != http.StatusBadRequest {
@@ -287,7 +287,7 @@ func TestPostTaskMissingType(t *testing.T) {
 }
 
 func TestPostTaskUnknownType(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 	rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"nonexistent"}`)
 	if rec.Code != http.StatusBadRequest {
@@ -295,8 +295,117 @@ func TestPostTaskUnknownType(t *testing.T) {
 	}
 }
 
+func TestPostTaskInvalidParamsReturns400WithFields(t *testing.T) {
+	type req struct {
+		TargetHeight int64 `json:"targetHeight"`
+	}
+	var got int64
+	eng := newTestEngine(t, map[engine.TaskType]engine.Handler{
+		engine.TaskSnapshotRestore: engine.TypedHandler(func(_ context.Context, r req) error {
+			got = r.TargetHeight
+			return nil
+		}),
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+	const id = "7f9c2d4e-1a3b-4c5d-8e6f-0a1b2c3d4e5f"
+
+	rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"id":"`+id+`","type":"snapshot-restore","params":{"target_height":5,"bucket":"b"}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(resp.Fields, ",") != "bucket,target_height" || !strings.Contains(resp.Error, "target_height: unknown field") {
+		t.Errorf("response = %+v, want both unknown fields listed", resp)
+	}
+	if eng.GetResult(id) != nil {
+		t.Error("rejected task was persisted")
+	}
+
+	// Large integers reach the handler exactly.
+	rec = serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"id":"`+id+`","type":"snapshot-restore","params":{"targetHeight":9007199254740993}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if r := waitForTaskResult(eng, id); r == nil || r.Status != engine.TaskStatusCompleted || got != 9007199254740993 {
+		t.Errorf("result = %+v, targetHeight = %d", r, got)
+	}
+}
+
+func TestPostTaskInvalidTimeoutReturns400(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	for _, timeout := range []string{"soon", "-5m", "0s"} {
+		body := `{"type":"config-patch","timeout":"` + timeout + `"}`
+		rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", body)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("timeout %q: expected 400, got %d: %s", timeout, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestPostTaskTimeoutSetsDeadline(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	before := time.Now()
+	rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"config-patch","timeout":"90s"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	r := waitForTaskResult(eng, resp["id"])
+	if r == nil {
+		t.Fatal("task did not complete")
+	}
+	if r.Deadline == nil || r.Deadline.Before(before.Add(90*time.Second)) || r.Deadline.After(time.Now().Add(90*time.Second)) {
+		t.Fatalf("Deadline = %v, want submission + 90s", r.Deadline)
+	}
+}
+
+func TestPostTaskExclusionConflictReturns409(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskResetData: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return nil, nil
+		},
+		engine.TaskSnapshotRestore: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	eng.Exclusions = map[string]engine.ExclusionGroup{
+		"data-dir": {Types: []engine.TaskType{engine.TaskResetData, engine.TaskSnapshotRestore}, Policy: engine.ExclusionReject},
+	}
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	if rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"reset-data"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"snapshot-restore"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "data-dir") {
+		t.Fatalf("body = %s, want the conflicting group named", rec.Body.String())
+	}
+}
+
 func TestListTasksEmpty(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 	rec := serveHTTP(srv, http.MethodGet, "/v0/tasks", "")
 
@@ -330,6 +439,61 @@ func TestListTasksAfterSubmit(t *testing.T) {
 	}
 }
 
+func TestListTasksFiltersAndPages(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+		engine.TaskConfigApply: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+	for _, typ := range []string{"config-patch", "config-apply", "config-patch", "config-patch"} {
+		rec := serveHTTP(srv, http.MethodPost, "/v0/tasks", `{"type":"`+typ+`"}`)
+		var resp map[string]string
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		waitForTaskResult(eng, resp["id"])
+	}
+
+	var seen []string
+	path := "/v0/tasks?type=config-patch&status=completed&limit=2"
+	for pages := 0; ; pages++ {
+		if pages > 2 {
+			t.Fatal("cursor never ran out")
+		}
+		rec := serveHTTP(srv, http.MethodGet, path, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", path, rec.Code, rec.Body)
+		}
+		var results []engine.TaskResult
+		if err := json.NewDecoder(rec.Body).Decode(&results); err != nil {
+			t.Fatalf("failed to decode: %v", err)
+		}
+		for _, r := range results {
+			if r.Type != "config-patch" {
+				t.Errorf("type filter let through %s", r.Type)
+			}
+			seen = append(seen, r.ID)
+		}
+		next := rec.Header().Get("X-Next-Cursor")
+		if next == "" {
+			break
+		}
+		path = "/v0/tasks?type=config-patch&status=completed&limit=2&cursor=" + next
+	}
+	if len(seen) != 3 {
+		t.Fatalf("listed %d config-patch results across pages, want 3", len(seen))
+	}
+}
+
+func TestListTasksRejectsBadQuery(t *testing.T) {
+	eng := newTestEngine[engine.TaskHandler](t, nil)
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+	for _, q := range []string{"status=done", "limit=0", "limit=5000", "submittedAfter=yesterday", "cursor=%21%21"} {
+		rec := serveHTTP(srv, http.MethodGet, "/v0/tasks?"+q, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", q, rec.Code)
+		}
+	}
+}
+
 func TestGetTask(t *testing.T) {
 	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
 		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
@@ -412,7 +576,7 @@ func TestGetTaskInProgress(t *testing.T) {
 }
 
 func TestGetTaskNotFound(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 	rec := serveHTTP(srv, http.MethodGet, "/v0/tasks/nonexistent", "")
 	if rec.Code != http.StatusNotFound {
@@ -489,7 +653,7 @@ func TestNodeID_ReturnsCorrectID(t *testing.T) {
 	homeDir := t.TempDir()
 	want := writeTestNodeKey(t, homeDir)
 
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, homeDir, AuthnModeUnauthenticated)
 
 	rec := serveHTTP(srv, http.MethodGet, "/v0/node-id", "")
@@ -509,7 +673,7 @@ func TestNodeID_ReturnsCorrectID(t *testing.T) {
 }
 
 func TestNodeID_MissingKeyFile(t *testing.T) {
-	eng := newTestEngine(t, nil)
+	eng := newTestEngine[engine.TaskHandler](t, nil)
 	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
 
 	rec := serveHTTP(srv, http.MethodGet, "/v0/node-id", "")
@@ -517,3 +681,104 @@ func TestNodeID_MissingKeyFile(t *testing.T) {
 		t.Fatalf("expected 500, got %d", rec.Code)
 	}
 }
+
+func TestCancelTaskKeepsRecord(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			select {
+			case <-release:
+				return nil, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeTrustedHeader)
+
+	id, err := eng.Submit(engine.Task{Type: engine.TaskConfigPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v0/tasks/"+id+":cancel", strings.NewReader(`{"reason":"wrong config"}`))
+	req.Header.Set(remoteUserHeader, "alice")
+	rec := httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result engine.TaskResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if result.Status != engine.TaskStatusCancelled || result.CancelledBy != "alice" || result.CancelReason != "wrong config" {
+		t.Fatalf("result = %+v, want cancelled by alice", result)
+	}
+
+	// The record survives, and cancelling again is an idempotent 200.
+	if rec := serveHTTP(srv, http.MethodGet, "/v0/tasks/"+id, ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on get, got %d", rec.Code)
+	}
+	if rec := serveHTTP(srv, http.MethodPost, "/v0/tasks/"+id+":cancel", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on repeat cancel, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCancelTaskIgnoresUntrustedRemoteUser(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(ctx context.Context, _ map[string]any) (json.RawMessage, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	id, err := eng.Submit(engine.Task{Type: engine.TaskConfigPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v0/tasks/"+id+":cancel", nil)
+	req.Header.Set(remoteUserHeader, "mallory")
+	rec := httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if r := eng.GetResult(id); r.CancelledBy != "" {
+		t.Fatalf("cancelledBy = %q, want empty outside trusted-header mode", r.CancelledBy)
+	}
+}
+
+func TestCancelTaskErrors(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{
+		engine.TaskConfigPatch: func(_ context.Context, _ map[string]any) (json.RawMessage, error) { return nil, nil },
+	})
+	srv := NewServer(":0", eng, t.TempDir(), AuthnModeUnauthenticated)
+
+	id, err := eng.Submit(engine.Task{Type: engine.TaskConfigPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if r := eng.GetResult(id); r != nil && r.CompletedAt != nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	for _, tc := range []struct {
+		path, body string
+		want       int
+	}{
+		{"/v0/tasks/" + id + ":cancel", "", http.StatusConflict},
+		{"/v0/tasks/00000000-0000-0000-0000-000000000000:cancel", "", http.StatusNotFound},
+		{"/v0/tasks/" + id + ":pause", "", http.StatusNotFound},
+		{"/v0/tasks/" + id + ":cancel", `{reason}`, http.StatusBadRequest},
+	} {
+		if rec := serveHTTP(srv, http.MethodPost, tc.path, tc.body); rec.Code != tc.want {
+			t.Errorf("POST %s: expected %d, got %d: %s", tc.path, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BearerToken verifies the Authorization: Bearer credential in token
+// mode. The token is either fixed at startup or read from a file that is
+// re-read whenever its modification time or size changes, so an operator
+// (or a mounted Kubernetes Secret) can rotate it without a restart.
+type BearerToken struct {
+	path string
+
+	mu      sync.Mutex
+	digest  [sha256.Size]byte
+	modTime time.Time
+	size    int64
+	lastErr string // last reload failure, logged once
+}
+
+// NewBearerToken returns a verifier for token, or, when path is set, for
+// the token the file at path holds. Exactly one of token and path must be
+// given, and the token must not be empty.
+func NewBearerToken(token, path string) (*BearerToken, error) {
+	switch {
+	case token != "" && path != "":
+		return nil, errors.New("bearer token: set a token or a token file, not both")
+	case path != "":
+		t := &BearerToken{path: path}
+		if err := t.reload(); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case strings.TrimSpace(token) == "":
+		return nil, errors.New("bearer token: no token configured")
+	default:
+		return &BearerToken{digest: sha256.Sum256([]byte(strings.TrimSpace(token)))}, nil
+	}
+}
+
+// reload reads the token file if it changed since the last read. On
+// error the previous token stays in force. The caller holds t.mu, or
+// has not yet shared t.
+func (t *BearerToken) reload() error {
+	fi, err := os.Stat(t.path)
+	if err != nil {
+		return fmt.Errorf("bearer token: %w", err)
+	}
+	if fi.ModTime().Equal(t.modTime) && fi.Size() == t.size {
+		return nil
+	}
+	b, err := os.ReadFile(t.path)
+	if err != nil {
+		return fmt.Errorf("bearer token: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return fmt.Errorf("bearer token: %s is empty", t.path)
+	}
+	t.digest = sha256.Sum256([]byte(token))
+	t.modTime, t.size = fi.ModTime(), fi.Size()
+	return nil
+}
+
+// Verify reports whether presented is the current token. It compares
+// SHA-256 digests in constant time, so neither the token's content nor
+// its length leaks through timing.
+func (t *BearerToken) Verify(presented string) bool {
+	got := sha256.Sum256([]byte(presented))
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.path != "" {
+		t.lastErr = warnReload(t.reload(), t.lastErr, "keeping previous bearer token")
+	}
+	return subtle.ConstantTimeCompare(got[:], t.digest[:]) == 1
+}
+
+// tokenMiddleware requires a bearer token matching s.Token on every path
+// outside bypassPaths. Without a Token every request is rejected, failing
+// closed.
+func (s *Server) tokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := bypassPaths[r.URL.Path]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		switch {
+		case !ok || presented == "":
+			authnRejections.WithLabelValues("missing_token").Inc()
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+		case s.Token == nil || !s.Token.Verify(presented):
+			authnRejections.WithLabelValues("invalid_token").Inc()
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, http.StatusUnauthorized, "invalid bearer token")
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func TestNewBearerTokenRejectsBadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte(" \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, args := range map[string][2]string{
+		"neither":      {"", ""},
+		"both":         {"s3cret", path},
+		"blank token":  {"   ", ""},
+		"empty file":   {"", path},
+		"missing file": {"", filepath.Join(t.TempDir(), "absent")},
+	} {
+		if _, err := NewBearerToken(args[0], args[1]); err == nil {
+			t.Errorf("%s: NewBearerToken succeeded, want an error", name)
+		}
+	}
+}
+
+func TestTokenMiddleware(t *testing.T) {
+	srv := NewServer(":0", newTestEngine[engine.TaskHandler](t, nil), t.TempDir(), AuthnModeToken)
+	do := func(path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		srv.handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Without a Token the server fails closed.
+	if rec := do("/v0/tasks", "Bearer s3cret"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no token configured = %d, want 401", rec.Code)
+	}
+
+	token, err := NewBearerToken("s3cret\n", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Token = token
+	for _, tc := range []struct {
+		name, path, auth string
+		want             int
+	}{
+		{"livez bypasses", "/v0/livez", "", http.StatusOK},
+		{"metrics bypasses", "/v0/metrics", "", http.StatusOK},
+		{"missing", "/v0/tasks", "", http.StatusUnauthorized},
+		{"wrong scheme", "/v0/tasks", "Basic s3cret", http.StatusUnauthorized},
+		{"wrong token", "/v0/tasks", "Bearer s3cre", http.StatusUnauthorized},
+		{"valid", "/v0/tasks", "Bearer s3cret", http.StatusOK},
+	} {
+		rec := do(tc.path, tc.auth)
+		if rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, rec.Code, tc.want)
+		}
+		if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: 401 without WWW-Authenticate", tc.name)
+		}
+	}
+}
+
+func TestBearerTokenFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now()
+	write("first\n", start)
+	token, err := NewBearerToken("", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !token.Verify("first") {
+		t.Fatal("initial token rejected")
+	}
+
+	write("second\n", start.Add(time.Minute))
+	if token.Verify("first") || !token.Verify("second") {
+		t.Fatal("rotated token not picked up")
+	}
+
+	// A botched rotation keeps the previous token rather than locking
+	// everyone out or letting everyone in.
+	write("", start.Add(2*time.Minute))
+	if !token.Verify("second") || token.Verify("") {
+		t.Fatal("empty token file replaced the previous token")
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if !token.Verify("second") {
+		t.Fatal("removed token file replaced the previous token")
+	}
+}
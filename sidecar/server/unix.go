@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// peerCred is the identity the kernel reports for the process on the
+// other end of a Unix socket connection.
+type peerCred struct {
+	UID, GID uint32
+	PID      int32
+}
+
+type peerCredKey struct{}
+
+// caller names a Unix socket peer by its uid, with its gid as the one
+// group and its pid as an extra attribute, so policy rules and the audit
+// log can tell local callers apart.
+func (p peerCred) caller() engine.Caller {
+	return engine.Caller{
+		User:   "uid:" + strconv.FormatUint(uint64(p.UID), 10),
+		Groups: []string{"gid:" + strconv.FormatUint(uint64(p.GID), 10)},
+		Extra:  map[string][]string{"pid": {strconv.FormatInt(int64(p.PID), 10)}},
+	}
+}
+
+// ListenUnix listens on a Unix socket at path with the given file mode,
+// owned by uid and gid (-1 leaves either unchanged). Filesystem
+// permissions on the socket are the access boundary for connections
+// through it. A stale socket left by an earlier run is replaced; any
+// other file at path is an error.
+func ListenUnix(path string, mode os.FileMode, uid, gid int) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("unix socket %s: path exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("unix socket %s: remove stale socket: %w", path, err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("unix socket %s: %w", path, err)
+	}
+	if uid != -1 || gid != -1 {
+		if err := os.Chown(path, uid, gid); err != nil {
+			ln.Close()
+			return nil, fmt.Errorf("unix socket %s: %w", path, err)
+		}
+	}
+	return ln, nil
+}
+
+// unixConnContext records the peer credentials of a Unix socket
+// connection on its requests' context. A peer the kernel cannot
+// identify gets no identity.
+func unixConnContext(ctx context.Context, c net.Conn) context.Context {
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+	cred, err := peerCredentials(uc)
+	if err != nil {
+		serverLog.Warn("unix socket peer credentials unavailable", "err", err)
+		return ctx
+	}
+	return context.WithValue(ctx, peerCredKey{}, cred)
+}
+
+// peerCredOf returns the Unix socket peer behind r, if r arrived over
+// the socket listener.
+func peerCredOf(r *http.Request) (peerCred, bool) {
+	cred, ok := r.Context().Value(peerCredKey{}).(peerCred)
+	return cred, ok
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidecar.sock")
+	ln, err := ListenUnix(path, 0o600, -1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("socket mode = %v, want a 0600 socket", fi.Mode())
+	}
+
+	// A socket left behind by a crashed run is replaced.
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	ln, err = ListenUnix(path, 0o660, -1, -1)
+	if err != nil {
+		t.Fatalf("stale socket: %v", err)
+	}
+	ln.Close()
+
+	regular := filepath.Join(t.TempDir(), "not-a-socket")
+	if err := os.WriteFile(regular, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ListenUnix(regular, 0o660, -1, -1); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Fatalf("regular file: err = %v, want refusal to replace it", err)
+	}
+}
+
+func TestUnixSocketPeerIdentity(t *testing.T) {
+	eng := newTestEngine(t, map[engine.TaskType]engine.TaskHandler{engine.TaskConfigPatch: noopHandler})
+	// Token mode with no token configured refuses every TCP request; the
+	// socket is gated by its file mode instead.
+	srv := NewServer("127.0.0.1:0", eng, t.TempDir(), AuthnModeToken)
+	path := filepath.Join(t.TempDir(), "sidecar.sock")
+	ln, err := ListenUnix(path, 0o600, -1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.UnixListener = ln
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("ListenAndServe: %v", err)
+		}
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Post("http://sidecar/v0/tasks", "application/json", strings.NewReader(`{"type":"config-patch"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("submit over the socket = %d, want 201", resp.StatusCode)
+	}
+
+	entries, err := eng.ListAudit(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("audit = %+v, want one entry", entries)
+	}
+	want := "uid:" + strconv.Itoa(os.Getuid())
+	c := entries[0].Caller
+	if c.User != want || len(c.Groups) != 1 || c.Extra["pid"][0] != strconv.Itoa(os.Getpid()) {
+		t.Fatalf("caller = %+v, want %s with this process's pid", c, want)
+	}
+	if r := eng.GetResult(entries[0].TaskID); r == nil || r.SubmittedBy != want {
+		t.Fatalf("task = %+v, want SubmittedBy %s", r, want)
+	}
+}
@@ -0,0 +1,278 @@
+// Package sidecartest runs a real sidecar, the server and engine over an
+// in-memory store, with scriptable fake task handlers, for testing code that
+// drives the sidecar API through sidecar/client.
+//
+// Every task type has a handler that, until scripted otherwise, succeeds
+// with no result. Params are checked against the real handler's request
+// type, Validate hook included, so a submission the real sidecar would
+// refuse is refused here too:
+//
+//	sc := sidecartest.New(t)
+//	sc.FailTimes(engine.TaskSnapshotUploadOnce, 2, errors.New("s3 down"))
+//	release := sc.Hang(engine.TaskAwaitCondition)
+//	defer release()
+//	// ... drive sc.Client() ...
+//	if got := sc.Submitted(); len(got) != 1 { ... }
+package sidecartest
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	seiconfig "github.com/sei-protocol/sei-config"
+
+	"github.com/sei-protocol/seictl/sidecar/client"
+	"github.com/sei-protocol/seictl/sidecar/engine"
+	"github.com/sei-protocol/seictl/sidecar/server"
+	"github.com/sei-protocol/seictl/sidecar/tasks"
+	"github.com/sei-protocol/seictl/sidecar/wire"
+)
+
+// shapes gives each task type a Sidecar serves the request and result
+// types of its real handler. The fakes run under these shapes, so a
+// submission is checked, and capabilities are published, as by a real
+// sidecar; only the scripted behavior is fake.
+var shapes = map[engine.TaskType]*engine.TypedTask{
+	engine.TaskSnapshotRestore:          shape[tasks.SnapshotRestoreRequest](),
+	engine.TaskConfigPatch:              shape[tasks.ConfigPatchRequest](),
+	engine.TaskConfigApply:              shape[seiconfig.ConfigIntent](),
+	engine.TaskConfigValidate:           shape[struct{}](),
+	engine.TaskConfigReload:             shapeWithResult[tasks.ConfigReloadRequest, *tasks.ConfigReloadResult](),
+	engine.TaskMarkReady:                shape[struct{}](),
+	engine.TaskRestartSeid:              shape[struct{}](),
+	engine.TaskConfigureGenesis:         shape[tasks.ConfigureGenesisRequest](),
+	engine.TaskConfigureStateSync:       shape[tasks.StateSyncRequest](),
+	engine.TaskSnapshotUpload:           shape[tasks.SnapshotUploadRequest](),
+	engine.TaskSnapshotUploadOnce:       shapeWithResult[tasks.SnapshotUploadRequest, tasks.SnapshotUploadResult](),
+	engine.TaskResultExport:             shape[tasks.ResultExportRequest](),
+	engine.TaskAwaitCondition:           shape[tasks.AwaitConditionRequest](),
+	engine.TaskGenerateIdentity:         shape[tasks.GenerateIdentityRequest](),
+	engine.TaskGenerateGentx:            shape[tasks.GenerateGentxRequest](),
+	engine.TaskUploadGenesisArtifacts:   shape[tasks.UploadArtifactsRequest](),
+	engine.TaskAssembleAndUploadGenesis: shapeWithResult[tasks.AssembleGenesisRequest, *tasks.AssembleGenesisResult](),
+	engine.TaskSetGenesisPeers:          shape[tasks.SetGenesisPeersRequest](),
+	engine.TaskGovVote:                  shapeWithResult[tasks.GovVoteRequest, *wire.GovTxResult](),
+	engine.TaskGovSoftwareUpgrade:       shapeWithResult[tasks.GovSoftwareUpgradeRequest, *wire.GovTxResult](),
+	engine.TaskGovParamChange:           shapeWithResult[tasks.GovParamChangeRequest, *wire.GovTxResult](),
+	engine.TaskEvmLogicalDigest:         shape[tasks.EvmLogicalDigestRequest](),
+	engine.TaskMarkNotReady:             shape[struct{}](),
+	engine.TaskStopSeid:                 shape[struct{}](),
+	engine.TaskResetData:                shapeWithResult[struct{}, tasks.ResetDataResult](),
+	engine.TaskPrepareUpgrade:           shapeWithResult[tasks.PrepareUpgradeRequest, *tasks.PrepareUpgradeResult](),
+	engine.TaskApplyUpgrade:             shapeWithResult[tasks.ApplyUpgradeRequest, *tasks.ApplyUpgradeResult](),
+}
+
+func shape[T any]() *engine.TypedTask {
+	return engine.TypedHandler(func(context.Context, T) error { return nil })
+}
+
+func shapeWithResult[T, R any]() *engine.TypedTask {
+	return engine.TypedHandlerWithResult(func(context.Context, T) (R, error) {
+		var zero R
+		return zero, nil
+	})
+}
+
+// Sidecar is a running sidecar API backed by fake task handlers. It is shut
+// down when the test that created it ends.
+type Sidecar struct {
+	// URL is the base URL the API is served on.
+	URL string
+
+	// Engine is the engine behind the API, for state the API does not
+	// expose. Its configuration fields may be set before the first task is
+	// submitted.
+	Engine *engine.Engine
+
+	t testing.TB
+
+	mu      sync.Mutex
+	scripts map[engine.TaskType]*script
+	runs    map[engine.TaskType][]map[string]any
+}
+
+// script is how a task type's fake handler behaves. Runs first fail while
+// fails is positive, then block on hang when it is set, then defer to
+// handle when it is set, else return result.
+type script struct {
+	fails   int
+	failErr error
+	hang    chan struct{}
+	handle  engine.TaskHandler
+	result  json.RawMessage
+}
+
+// New starts a Sidecar serving unauthenticated on a loopback port.
+func New(t testing.TB) *Sidecar {
+	t.Helper()
+	s := &Sidecar{
+		t:       t,
+		scripts: make(map[engine.TaskType]*script),
+		runs:    make(map[engine.TaskType][]map[string]any),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store, err := engine.NewMemoryStore()
+	if err != nil {
+		cancel()
+		t.Fatalf("sidecartest: opening store: %v", err)
+	}
+	handlers := make(map[engine.TaskType]engine.Handler, len(shapes))
+	for typ, shape := range shapes {
+		handlers[typ] = shape.Wrap(func(engine.TaskHandler) engine.TaskHandler { return s.handler(typ) })
+	}
+	s.Engine = engine.NewEngine(ctx, handlers, store)
+	s.Engine.StartScheduler()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		cancel()
+		_ = store.Close()
+		t.Fatalf("sidecartest: listening: %v", err)
+	}
+	srv := server.NewServer(ln.Addr().String(), s.Engine, t.TempDir(), server.AuthnModeUnauthenticated)
+	srv.Listener = ln
+	s.URL = "http://" + ln.Addr().String()
+
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("sidecartest: serving: %v", err)
+		}
+		_ = store.Close()
+	})
+	return s
+}
+
+// Client returns a client for the sidecar's API.
+func (s *Sidecar) Client(opts ...client.Option) *client.SidecarClient {
+	s.t.Helper()
+	c, err := client.NewSidecarClient(s.URL, opts...)
+	if err != nil {
+		s.t.Fatalf("sidecartest: creating client: %v", err)
+	}
+	return c
+}
+
+// Succeed makes runs of typ succeed with no result, undoing any earlier
+// script for it.
+func (s *Sidecar) Succeed(typ engine.TaskType) {
+	s.setScript(typ, &script{})
+}
+
+// Return makes runs of typ succeed with result, marshaled to JSON.
+func (s *Sidecar) Return(typ engine.TaskType, result any) {
+	s.t.Helper()
+	raw, err := json.Marshal(result)
+	if err != nil {
+		s.t.Fatalf("sidecartest: marshaling %s result: %v", typ, err)
+	}
+	s.update(typ, func(sc *script) { sc.result, sc.handle = raw, nil })
+}
+
+// FailTimes makes the next n runs of typ fail with err. Later runs behave
+// as scripted otherwise.
+func (s *Sidecar) FailTimes(typ engine.TaskType, n int, err error) {
+	s.update(typ, func(sc *script) { sc.fails, sc.failErr = n, err })
+}
+
+// Hang makes runs of typ block until their context ends, as when the task
+// is cancelled or times out, or until release is called. Runs after
+// release proceed as scripted otherwise.
+func (s *Sidecar) Hang(typ engine.TaskType) (release func()) {
+	hang := make(chan struct{})
+	s.update(typ, func(sc *script) { sc.hang = hang })
+	var once sync.Once
+	return func() { once.Do(func() { close(hang) }) }
+}
+
+// Handle makes runs of typ call h, for behavior the other scripts do not
+// cover.
+func (s *Sidecar) Handle(typ engine.TaskType, h engine.TaskHandler) {
+	s.update(typ, func(sc *script) { sc.handle = h })
+}
+
+// Runs returns the params of each run of typ's handler, oldest first. A
+// task retried or resubmitted after failing appears once per run.
+func (s *Sidecar) Runs(typ engine.TaskType) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.runs[typ])
+}
+
+// Submitted returns every task the engine holds a result for, oldest
+// first, including tasks still pending or never run.
+func (s *Sidecar) Submitted() []engine.TaskResult {
+	s.t.Helper()
+	var all []engine.TaskResult
+	q := engine.ResultQuery{Limit: engine.MaxListLimit}
+	for {
+		page, next, err := s.Engine.ListResults(q)
+		if err != nil {
+			s.t.Fatalf("sidecartest: listing tasks: %v", err)
+		}
+		all = append(all, page...)
+		if next == "" {
+			break
+		}
+		q.Cursor = next
+	}
+	slices.Reverse(all)
+	return all
+}
+
+func (s *Sidecar) setScript(typ engine.TaskType, sc *script) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[typ] = sc
+}
+
+func (s *Sidecar) update(typ engine.TaskType, fn func(*script)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc := s.scripts[typ]
+	if sc == nil {
+		sc = &script{}
+		s.scripts[typ] = sc
+	}
+	fn(sc)
+}
+
+// handler returns typ's fake handler, which records each run and follows
+// the type's script as it stands when the run starts.
+func (s *Sidecar) handler(typ engine.TaskType) engine.TaskHandler {
+	return func(ctx context.Context, params map[string]any) (json.RawMessage, error) {
+		s.mu.Lock()
+		s.runs[typ] = append(s.runs[typ], maps.Clone(params))
+		var sc script
+		if cur := s.scripts[typ]; cur != nil {
+			sc = *cur
+			if cur.fails > 0 {
+				cur.fails--
+			}
+		}
+		s.mu.Unlock()
+
+		if sc.fails > 0 {
+			return nil, sc.failErr
+		}
+		if sc.hang != nil {
+			select {
+			case <-sc.hang:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if sc.handle != nil {
+			return sc.handle(ctx, params)
+		}
+		return sc.result, nil
+	}
+}
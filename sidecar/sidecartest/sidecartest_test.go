@@ -0,0 +1,139 @@
+package sidecartest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sei-protocol/seictl/sidecar/client"
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+var fastWait = client.WaitOptions{PollInterval: 5 * time.Millisecond, Timeout: 5 * time.Second}
+
+func TestReturnAndSubmitted(t *testing.T) {
+	sc := New(t)
+	sc.Return(engine.TaskSnapshotUploadOnce, map[string]any{"height": 42})
+	c := sc.Client()
+
+	got, _, err := client.SubmitAndWait[struct {
+		Height int64 `json:"height"`
+	}](context.Background(), c, client.TaskRequest{Type: client.TaskTypeSnapshotUploadOnce}, fastWait)
+	if err != nil {
+		t.Fatalf("SubmitAndWait() error = %v", err)
+	}
+	if got.Height != 42 {
+		t.Errorf("result height = %d, want 42", got.Height)
+	}
+	params := map[string]interface{}{"files": map[string]any{"app.toml": map[string]any{"pruning": "nothing"}}}
+	if _, _, err := client.SubmitAndWait[any](context.Background(), c, client.TaskRequest{Type: client.TaskTypeConfigPatch, Params: &params}, fastWait); err != nil {
+		t.Fatalf("unscripted task: %v", err)
+	}
+
+	subs := sc.Submitted()
+	if len(subs) != 2 || subs[0].Type != string(engine.TaskSnapshotUploadOnce) || subs[1].Type != string(engine.TaskConfigPatch) {
+		t.Fatalf("submitted = %+v, want the upload then the patch", subs)
+	}
+	if runs := sc.Runs(engine.TaskConfigPatch); len(runs) != 1 || runs[0]["files"] == nil {
+		t.Errorf("runs = %v, want one with the submitted params", runs)
+	}
+}
+
+func TestParamsCheckedAsByRealHandler(t *testing.T) {
+	sc := New(t)
+	c := sc.Client()
+	ctx := context.Background()
+
+	for _, params := range []map[string]interface{}{
+		{"flies": map[string]any{"app.toml": map[string]any{}}},
+		{},
+	} {
+		_, err := c.SubmitTask(ctx, client.TaskRequest{Type: client.TaskTypeConfigPatch, Params: &params})
+		if err == nil || !strings.Contains(err.Error(), "invalid task params") {
+			t.Errorf("params %v: err = %v, want them rejected", params, err)
+		}
+	}
+	if runs := sc.Runs(engine.TaskConfigPatch); len(runs) != 0 {
+		t.Errorf("rejected submissions ran: %v", runs)
+	}
+
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range caps.TaskTypes {
+		if tc.Type == client.TaskTypeGovVote && (tc.Params == nil || tc.Result == nil) {
+			t.Errorf("gov-vote capability = %+v, want its params and result schemas", tc)
+		}
+	}
+}
+
+func TestFailTimesThenSucceed(t *testing.T) {
+	sc := New(t)
+	sc.FailTimes(engine.TaskConfigApply, 2, errors.New("disk full"))
+	c := sc.Client()
+	id := uuid.New()
+	task := client.TaskRequest{Id: &id, Type: client.TaskTypeConfigApply}
+
+	for run := 1; run <= 2; run++ {
+		_, tr, err := client.SubmitAndWait[any](context.Background(), c, task, fastWait)
+		var failed *client.TaskFailedError
+		if !errors.As(err, &failed) || tr.Error == nil || *tr.Error != "disk full" {
+			t.Fatalf("run %d: err = %v, want the scripted failure", run, err)
+		}
+	}
+	if _, _, err := client.SubmitAndWait[any](context.Background(), c, task, fastWait); err != nil {
+		t.Fatalf("third run: %v", err)
+	}
+	if subs := sc.Submitted(); len(subs) != 1 || subs[0].Run != 3 {
+		t.Errorf("submitted = %+v, want one task on its third run", subs)
+	}
+	if got := len(sc.Runs(engine.TaskConfigApply)); got != 3 {
+		t.Errorf("handler ran %d times, want 3", got)
+	}
+}
+
+func TestHangUntilCancelledOrReleased(t *testing.T) {
+	sc := New(t)
+	release := sc.Hang(engine.TaskAwaitCondition)
+	c := sc.Client()
+	ctx := context.Background()
+
+	params := map[string]interface{}{"condition": "catchingUp"}
+	id, err := c.SubmitTask(ctx, client.TaskRequest{Type: client.TaskTypeAwaitCondition, Params: &params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForRuns(t, sc, engine.TaskAwaitCondition, 1)
+	if _, err := c.CancelTask(ctx, id, "test"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	if _, tr, err := client.WaitForTask[any](ctx, c, id, fastWait); err == nil || tr.Status != client.Cancelled {
+		t.Fatalf("hung task settled as %+v, %v; want cancelled", tr, err)
+	}
+
+	id, err = c.SubmitTask(ctx, client.TaskRequest{Type: client.TaskTypeAwaitCondition, Params: &params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForRuns(t, sc, engine.TaskAwaitCondition, 2)
+	release()
+	if _, _, err := client.WaitForTask[any](ctx, c, id, fastWait); err != nil {
+		t.Fatalf("released task: %v", err)
+	}
+}
+
+func waitForRuns(t *testing.T, sc *Sidecar, typ engine.TaskType, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sc.Runs(typ)) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s ran %d times, want %d", typ, len(sc.Runs(typ)), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,223 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+	"github.com/sei-protocol/seictl/sidecar/rpc"
+	"github.com/sei-protocol/seilog"
+)
+
+var applyUpgradeLog = seilog.NewLogger("seictl", "task", "apply-upgrade")
+
+// errUpgradeNotPrepared is returned by apply-upgrade when the upgrade's
+// binary is not in place.
+var errUpgradeNotPrepared = errors.New("upgrade binary is not prepared; run prepare-upgrade first")
+
+// ApplyUpgradeRequest holds the typed parameters for the apply-upgrade task.
+// UpgradeName and UpgradeHeight are the software-upgrade plan's.
+type ApplyUpgradeRequest struct {
+	UpgradeName   string `json:"upgradeName"`
+	UpgradeHeight int64  `json:"upgradeHeight"`
+}
+
+// Validate checks the name is usable as a directory and the height leaves
+// a block for the old binary to halt after.
+func (r ApplyUpgradeRequest) Validate() error {
+	if err := validateUpgradeName(r.UpgradeName); err != nil {
+		return err
+	}
+	if r.UpgradeHeight <= 1 {
+		return &engine.FieldError{Field: "upgradeHeight", Reason: fmt.Sprintf("must be > 1, got %d", r.UpgradeHeight)}
+	}
+	return nil
+}
+
+// ApplyUpgradeResult is the apply-upgrade task's structured result. Previous
+// is the current link's target before the switch, what a rollback restores;
+// it is empty when there was no link, or when a re-run found it already
+// switched.
+type ApplyUpgradeResult struct {
+	Previous   string `json:"previous,omitempty"`
+	Current    string `json:"current"`
+	HaltHeight int64  `json:"haltHeight"`
+}
+
+// upgradeInfoFile is where seid records the plan it halted for, as
+// cosmovisor watches for, relative to the home directory.
+const upgradeInfoFile = "data/upgrade-info.json"
+
+// haltPollInterval is how often apply-upgrade re-checks for the halt while
+// it waits.
+const haltPollInterval = time.Second
+
+// UpgradeApplier cuts seid over to a prepared upgrade binary once the old
+// binary has halted for the plan. It re-verifies the binary against the
+// digest prepare-upgrade recorded, switches the current link to the
+// upgrade's directory, then restarts seid in place as restart-seid does,
+// so the kubelet brings the container back on the new binary.
+//
+// Before switching it waits, bounded by the task's timeout, until seid
+// reaches the halt height (the last block before the upgrade height, since
+// the old binary refuses the upgrade block) or records the halt in
+// upgrade-info.json. A task holding an exclusion group holds it for that
+// whole wait, so where apply-upgrade is in one callers should chain it
+// behind an await-condition on the halt height, as
+// client.ApplyUpgradeTask.Graph does; the wait then finds seid already
+// halted.
+//
+// A re-run after a crash that finds the link already switched skips the
+// height check and only restarts, which is harmless for a seid already on
+// the new binary.
+type UpgradeApplier struct {
+	homeDir      string
+	latestHeight func(ctx context.Context) (int64, error)
+	restarter    *RestartSeider
+	haltPoll     time.Duration
+}
+
+// NewUpgradeApplier creates an applier rooted at homeDir with the real
+// local-RPC height check and restart-seid machinery.
+func NewUpgradeApplier(homeDir string) *UpgradeApplier {
+	return &UpgradeApplier{
+		homeDir:      homeDir,
+		latestHeight: rpc.NewStatusClient("", nil).LatestHeight,
+		restarter:    NewRestartSeider(),
+		haltPoll:     haltPollInterval,
+	}
+}
+
+// Handler returns an engine.Handler for the apply-upgrade task type.
+func (a *UpgradeApplier) Handler() engine.Handler {
+	return engine.TypedHandlerWithResult(func(ctx context.Context, params ApplyUpgradeRequest) (*ApplyUpgradeResult, error) {
+		return a.apply(ctx, params)
+	})
+}
+
+func (a *UpgradeApplier) apply(ctx context.Context, params ApplyUpgradeRequest) (*ApplyUpgradeResult, error) {
+	if err := verifyPreparedBinary(upgradeBinaryPath(a.homeDir, params.UpgradeName)); err != nil {
+		return nil, err
+	}
+
+	link := filepath.Join(a.homeDir, upgradeRootDir, upgradeCurrentLink)
+	target := upgradeDir(params.UpgradeName)
+	prev, err := os.Readlink(link)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("apply-upgrade: reading %s: %w", link, err)
+	}
+	res := &ApplyUpgradeResult{Previous: prev, Current: target, HaltHeight: params.UpgradeHeight - 1}
+
+	if prev == target {
+		applyUpgradeLog.Info("current link already switched; restarting seid", "upgrade", params.UpgradeName)
+		res.Previous = ""
+	} else {
+		if err := a.awaitHalt(ctx, params, res.HaltHeight); err != nil {
+			return nil, err
+		}
+		if err := switchLink(link, target); err != nil {
+			return nil, fmt.Errorf("apply-upgrade: switching %s to %s: %w", link, target, err)
+		}
+		applyUpgradeLog.Info("switched current binary", "upgrade", params.UpgradeName, "previous", prev)
+	}
+
+	if err := a.restarter.stopSeid(ctx); err != nil {
+		return res, fmt.Errorf("apply-upgrade: %w", err)
+	}
+	if err := a.restarter.waitForUp(ctx); err != nil {
+		return res, fmt.Errorf("apply-upgrade: %w", err)
+	}
+	return res, nil
+}
+
+// verifyPreparedBinary checks the binary at bin is executable and still
+// hashes to the digest prepare-upgrade recorded beside it.
+func verifyPreparedBinary(bin string) error {
+	if info, err := os.Stat(bin); err != nil || info.Mode()&0o111 == 0 {
+		return fmt.Errorf("apply-upgrade: %s: %w", bin, errUpgradeNotPrepared)
+	}
+	raw, err := os.ReadFile(bin + upgradeDigestExt)
+	if err != nil {
+		return fmt.Errorf("apply-upgrade: %s has no recorded digest: %w", bin, errUpgradeNotPrepared)
+	}
+	want := strings.TrimSpace(string(raw))
+	got, err := fileSHA256(bin)
+	if err != nil {
+		return fmt.Errorf("apply-upgrade: hashing %s: %w", bin, err)
+	}
+	if got != want {
+		return &engine.TaskError{
+			Task:      "apply-upgrade",
+			Operation: "verify-checksum",
+			Message:   fmt.Sprintf("%s has SHA-256 %s, prepare-upgrade recorded %s", bin, got, want),
+			Hint:      "the prepared binary changed on disk; re-run prepare-upgrade before applying",
+			Retryable: false,
+		}
+	}
+	return nil
+}
+
+// awaitHalt waits until the old binary is done with the chain: seid's RPC
+// reports the halt height, or seid recorded this plan in upgrade-info.json
+// on halting for it (its RPC may be down by then). It returns ctx's error
+// when the task is cancelled or times out first.
+func (a *UpgradeApplier) awaitHalt(ctx context.Context, params ApplyUpgradeRequest, haltHeight int64) error {
+	ticker := time.NewTicker(a.haltPoll)
+	defer ticker.Stop()
+
+	logged := false
+	for {
+		if a.halted(ctx, params, haltHeight) {
+			return nil
+		}
+		if !logged {
+			applyUpgradeLog.Info("waiting for the upgrade halt", "upgrade", params.UpgradeName, "haltHeight", haltHeight)
+			logged = true
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("apply-upgrade: waiting for halt height %d: %w", haltHeight, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// halted reports whether seid has reached haltHeight or recorded the halt
+// for params' plan.
+func (a *UpgradeApplier) halted(ctx context.Context, params ApplyUpgradeRequest, haltHeight int64) bool {
+	var info struct {
+		Name   string `json:"name"`
+		Height int64  `json:"height"`
+	}
+	if raw, err := os.ReadFile(filepath.Join(a.homeDir, upgradeInfoFile)); err == nil &&
+		json.Unmarshal(raw, &info) == nil && info.Name == params.UpgradeName && info.Height == params.UpgradeHeight {
+		applyUpgradeLog.Info("seid recorded the upgrade halt", "upgrade", params.UpgradeName)
+		return true
+	}
+	if height, err := a.latestHeight(ctx); err == nil && height >= haltHeight {
+		applyUpgradeLog.Info("halt height reached", "upgrade", params.UpgradeName, "height", height)
+		return true
+	}
+	return false
+}
+
+// switchLink atomically points the symlink at link to target, creating it
+// when absent.
+func switchLink(link, target string) error {
+	tmp := link + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return syncDir(filepath.Dir(link))
+}
@@ -0,0 +1,174 @@
+package tasks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+func testUpgradeApplier(home string, sig *fakeSignaler, height int64) *UpgradeApplier {
+	return &UpgradeApplier{
+		homeDir:      home,
+		latestHeight: func(context.Context) (int64, error) { return height, nil },
+		restarter: &RestartSeider{
+			signaler:    sig,
+			probeUp:     upAfter(0),
+			gracePeriod: time.Second,
+			upTimeout:   time.Second,
+			upInterval:  time.Millisecond,
+		},
+		haltPoll: time.Millisecond,
+	}
+}
+
+// placeUpgradeBinary places name's binary and records its digest, as
+// prepare-upgrade does.
+func placeUpgradeBinary(t *testing.T, home, name string) {
+	t.Helper()
+	bin := upgradeBinaryPath(home, name)
+	if err := os.MkdirAll(filepath.Dir(bin), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("#!/bin/sh\n")
+	if err := os.WriteFile(bin, data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	if err := os.WriteFile(bin+".sha256", []byte(hex.EncodeToString(sum[:])+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func applyParams(name string, height int64) map[string]any {
+	return map[string]any{"upgradeName": name, "upgradeHeight": height}
+}
+
+func TestUpgradeApplier_SwitchesAndRestarts(t *testing.T) {
+	home := t.TempDir()
+	placeUpgradeBinary(t, home, "v5.0.0")
+	placeUpgradeBinary(t, home, "v6.0.0")
+	link := filepath.Join(home, "cosmovisor", "current")
+	if err := os.Symlink(filepath.Join("upgrades", "v5.0.0"), link); err != nil {
+		t.Fatal(err)
+	}
+	sig := &fakeSignaler{findPID: 42}
+	a := testUpgradeApplier(home, sig, 999)
+
+	raw, err := a.Handler().Handle(context.Background(), applyParams("v6.0.0", 1000))
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	var res ApplyUpgradeResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Previous != filepath.Join("upgrades", "v5.0.0") || res.HaltHeight != 999 {
+		t.Errorf("result = %+v", res)
+	}
+	if got, _ := os.Readlink(link); got != filepath.Join("upgrades", "v6.0.0") {
+		t.Errorf("current -> %q, want the upgrade", got)
+	}
+	if len(sig.signals) != 1 || sig.signals[0] != syscall.SIGTERM {
+		t.Errorf("signals = %v, want seid restarted", sig.signals)
+	}
+
+	// A re-run, as after a crash, only restarts, whatever the height now.
+	a.latestHeight = func(context.Context) (int64, error) { return 0, errors.New("connection refused") }
+	if _, err := a.Handler().Handle(context.Background(), applyParams("v6.0.0", 1000)); err != nil {
+		t.Fatalf("re-run: %v", err)
+	}
+	if len(sig.signals) != 2 {
+		t.Errorf("re-run sent %v, want a second restart", sig.signals)
+	}
+}
+
+func TestUpgradeApplier_WaitsForHaltHeight(t *testing.T) {
+	home := t.TempDir()
+	placeUpgradeBinary(t, home, "v6.0.0")
+	link := filepath.Join(home, "cosmovisor", "current")
+	sig := &fakeSignaler{findPID: 42}
+
+	// Below the halt height, a cancelled wait neither switches nor signals.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := testUpgradeApplier(home, sig, 998).Handler().Handle(ctx, applyParams("v6.0.0", 1000))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the wait to end with the task's deadline", err)
+	}
+	if _, err := os.Lstat(link); !os.IsNotExist(err) || len(sig.signals) != 0 {
+		t.Fatalf("an early apply switched the link (%v) or signaled %v", err, sig.signals)
+	}
+
+	// The switch happens once seid reaches the halt height.
+	a := testUpgradeApplier(home, sig, 0)
+	var polls int64
+	a.latestHeight = func(context.Context) (int64, error) {
+		polls++
+		return 996 + polls, nil
+	}
+	if _, err := a.Handler().Handle(context.Background(), applyParams("v6.0.0", 1000)); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("polled %d times, want 3 (until height 999)", polls)
+	}
+	if got, _ := os.Readlink(link); got != filepath.Join("upgrades", "v6.0.0") {
+		t.Errorf("current -> %q, want the upgrade", got)
+	}
+}
+
+func TestUpgradeApplier_HaltRecordedInUpgradeInfo(t *testing.T) {
+	home := t.TempDir()
+	placeUpgradeBinary(t, home, "v6.0.0")
+	sig := &fakeSignaler{findPID: 42}
+
+	// With its RPC down, seid's record of halting for this plan suffices.
+	info := filepath.Join(home, "data", "upgrade-info.json")
+	if err := os.MkdirAll(filepath.Dir(info), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(info, []byte(`{"name":"v6.0.0","height":1000}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a := testUpgradeApplier(home, sig, 0)
+	a.latestHeight = func(context.Context) (int64, error) { return 0, errors.New("connection refused") }
+	if _, err := a.Handler().Handle(context.Background(), applyParams("v6.0.0", 1000)); err != nil {
+		t.Fatalf("apply after a recorded halt: %v", err)
+	}
+}
+
+func TestUpgradeApplier_RequiresVerifiedBinary(t *testing.T) {
+	home := t.TempDir()
+	sig := &fakeSignaler{findPID: 42}
+	_, err := testUpgradeApplier(home, sig, 999).Handler().Handle(context.Background(), applyParams("v6.0.0", 1000))
+	if !errors.Is(err, errUpgradeNotPrepared) {
+		t.Fatalf("err = %v, want errUpgradeNotPrepared", err)
+	}
+
+	// A binary that no longer matches its recorded digest, e.g. truncated
+	// by a power loss, is never swapped in.
+	placeUpgradeBinary(t, home, "v6.0.0")
+	if err := os.WriteFile(upgradeBinaryPath(home, "v6.0.0"), []byte("#!/bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err = testUpgradeApplier(home, sig, 999).Handler().Handle(context.Background(), applyParams("v6.0.0", 1000))
+	var te *engine.TaskError
+	if !errors.As(err, &te) || te.Operation != "verify-checksum" {
+		t.Fatalf("err = %v, want a checksum error", err)
+	}
+	if len(sig.signals) != 0 {
+		t.Error("an unverified upgrade signaled seid")
+	}
+	if _, err := os.Lstat(filepath.Join(home, "cosmovisor", "current")); !os.IsNotExist(err) {
+		t.Errorf("current link exists after a refused apply: %v", err)
+	}
+}
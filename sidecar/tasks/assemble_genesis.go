@@ -134,9 +134,9 @@ func NewGenesisAssembler(homeDir, bucket, region, chainID string, s3Factory S3Cl
 	}
 }
 
-// Handler returns an engine.TaskHandler for the assemble-and-upload-genesis task type.
+// Handler returns an engine.Handler for the assemble-and-upload-genesis task type.
 // S3 coordinates are derived from the sidecar's environment.
-func (a *GenesisAssembler) Handler() engine.TaskHandler {
+func (a *GenesisAssembler) Handler() engine.Handler {
 	return engine.TypedHandlerWithResult(func(ctx context.Context, cfg AssembleGenesisRequest) (*AssembleGenesisResult, error) {
 		if markerExists(a.homeDir, assembleMarkerFile) {
 			assembleLog.Debug("already completed, skipping")
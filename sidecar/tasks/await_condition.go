@@ -28,6 +28,22 @@ type AwaitConditionRequest struct {
 	TargetHeight int64  `json:"targetHeight"`
 }
 
+// Validate checks the condition is known and carries what it needs.
+func (r AwaitConditionRequest) Validate() error {
+	switch r.Condition {
+	case "":
+		return &engine.FieldError{Field: "condition", Reason: "is required"}
+	case conditionHeight:
+		if r.TargetHeight <= 0 {
+			return &engine.FieldError{Field: "targetHeight", Reason: fmt.Sprintf("must be > 0, got %d", r.TargetHeight)}
+		}
+	case conditionCatchingUp:
+	default:
+		return &engine.FieldError{Field: "condition", Reason: fmt.Sprintf("unknown condition %q", r.Condition)}
+	}
+	return nil
+}
+
 // ConditionWaiter polls a local node until a condition is met, then
 // optionally executes a post-condition action.
 type ConditionWaiter struct {
@@ -42,18 +58,11 @@ func NewConditionWaiter(rpcClient *rpc.StatusClient) *ConditionWaiter {
 	return &ConditionWaiter{rpc: rpcClient}
 }
 
-// Handler returns an engine.TaskHandler for the await-condition task type.
-func (w *ConditionWaiter) Handler() engine.TaskHandler {
+// Handler returns an engine.Handler for the await-condition task type.
+func (w *ConditionWaiter) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, params AwaitConditionRequest) error {
-		if params.Condition == "" {
-			return fmt.Errorf("condition is required")
-		}
-
 		switch params.Condition {
 		case conditionHeight:
-			if params.TargetHeight <= 0 {
-				return fmt.Errorf("targetHeight must be > 0, got %d", params.TargetHeight)
-			}
 			if err := w.awaitHeight(ctx, params.TargetHeight); err != nil {
 				return err
 			}
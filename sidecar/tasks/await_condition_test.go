@@ -45,7 +45,7 @@ func TestAwaitHeight_ReachesTarget(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, params); err != nil {
+	if _, err := handler.Handle(ctx, params); err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
 }
@@ -62,7 +62,7 @@ func TestAwaitHeight_AlreadyPastTarget(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, params); err != nil {
+	if _, err := handler.Handle(ctx, params); err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
 }
@@ -73,7 +73,7 @@ func TestAwaitHeight_MissingTargetHeight(t *testing.T) {
 
 	handler := NewConditionWaiter(rpcClient(srv.URL)).Handler()
 	params := map[string]any{"condition": "height"}
-	if _, err := handler(context.Background(), params); err == nil {
+	if _, err := handler.Handle(context.Background(), params); err == nil {
 		t.Fatal("expected error for missing targetHeight")
 	}
 }
@@ -87,7 +87,7 @@ func TestAwaitHeight_ZeroTargetHeight(t *testing.T) {
 		"condition":    "height",
 		"targetHeight": float64(0),
 	}
-	if _, err := handler(context.Background(), params); err == nil {
+	if _, err := handler.Handle(context.Background(), params); err == nil {
 		t.Fatal("expected error for zero targetHeight")
 	}
 }
@@ -101,7 +101,7 @@ func TestAwaitHeight_NegativeTargetHeight(t *testing.T) {
 		"condition":    "height",
 		"targetHeight": float64(-5),
 	}
-	if _, err := handler(context.Background(), params); err == nil {
+	if _, err := handler.Handle(context.Background(), params); err == nil {
 		t.Fatal("expected error for negative targetHeight")
 	}
 }
@@ -156,7 +156,7 @@ func TestAwaitHeight_TransientRPCErrors(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, params); err != nil {
+	if _, err := handler.Handle(ctx, params); err != nil {
 		t.Fatalf("expected success after transient errors, got %v", err)
 	}
 }
@@ -173,7 +173,7 @@ func TestAwaitHeight_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
 
-	_, err := handler(ctx, params)
+	_, err := handler.Handle(ctx, params)
 	if err == nil {
 		t.Fatal("expected context error")
 	}
@@ -185,7 +185,7 @@ func TestAwaitHeight_ContextCancellation(t *testing.T) {
 func TestAwaitHeight_MissingCondition(t *testing.T) {
 	handler := NewConditionWaiter(rpcClient("http://unused")).Handler()
 	params := map[string]any{}
-	if _, err := handler(context.Background(), params); err == nil {
+	if _, err := handler.Handle(context.Background(), params); err == nil {
 		t.Fatal("expected error for missing condition")
 	}
 }
@@ -193,7 +193,7 @@ func TestAwaitHeight_MissingCondition(t *testing.T) {
 func TestAwaitHeight_UnknownCondition(t *testing.T) {
 	handler := NewConditionWaiter(rpcClient("http://unused")).Handler()
 	params := map[string]any{"condition": "unknown"}
-	if _, err := handler(context.Background(), params); err == nil {
+	if _, err := handler.Handle(context.Background(), params); err == nil {
 		t.Fatal("expected error for unknown condition")
 	}
 }
@@ -234,7 +234,7 @@ func TestAwaitCatchingUp_ReachesCaughtUp(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, map[string]any{"condition": "catchingUp"}); err != nil {
+	if _, err := handler.Handle(ctx, map[string]any{"condition": "catchingUp"}); err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
 }
@@ -254,7 +254,7 @@ func TestAwaitCatchingUp_IgnoresNotCaughtUpAtGenesisHeight(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, map[string]any{"condition": "catchingUp"}); err != nil {
+	if _, err := handler.Handle(ctx, map[string]any{"condition": "catchingUp"}); err != nil {
 		t.Fatalf("expected success once height passes 1, got %v", err)
 	}
 }
@@ -267,7 +267,7 @@ func TestAwaitCatchingUp_BlocksWhileCatchingUp(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
-	_, err := handler(ctx, map[string]any{"condition": "catchingUp"})
+	_, err := handler.Handle(ctx, map[string]any{"condition": "catchingUp"})
 	if err != context.DeadlineExceeded {
 		t.Fatalf("expected DeadlineExceeded while catching up, got %v", err)
 	}
@@ -286,7 +286,7 @@ func TestAwaitHeight_UnknownAction(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, params); err == nil {
+	if _, err := handler.Handle(ctx, params); err == nil {
 		t.Fatal("expected error for unknown action")
 	}
 }
@@ -303,7 +303,7 @@ func TestAwaitHeight_Int64TargetHeight(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, params); err != nil {
+	if _, err := handler.Handle(ctx, params); err != nil {
 		t.Fatalf("expected success with int64 targetHeight, got %v", err)
 	}
 }
@@ -320,7 +320,7 @@ func TestAwaitHeight_JSONNumberTargetHeight(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if _, err := handler(ctx, params); err != nil {
+	if _, err := handler.Handle(ctx, params); err != nil {
 		t.Fatalf("expected success with json.Number targetHeight, got %v", err)
 	}
 }
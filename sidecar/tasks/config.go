@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,14 @@ type ConfigPatchRequest struct {
 	Files map[string]map[string]any `json:"files"`
 }
 
+// Validate requires at least one file to patch.
+func (r ConfigPatchRequest) Validate() error {
+	if len(r.Files) == 0 {
+		return &engine.FieldError{Field: "files", Reason: "at least one file is required"}
+	}
+	return nil
+}
+
 // ConfigPatcher applies generic TOML merge-patches to seid configuration files.
 type ConfigPatcher struct {
 	homeDir string
@@ -31,7 +40,7 @@ func NewConfigPatcher(homeDir string) *ConfigPatcher {
 	return &ConfigPatcher{homeDir: homeDir}
 }
 
-// Handler returns an engine.TaskHandler that reads a "files" map from params
+// Handler returns an engine.Handler that reads a "files" map from params
 // and merge-patches each named file under homeDir/config/.
 //
 // Expected params format:
@@ -42,11 +51,8 @@ func NewConfigPatcher(homeDir string) *ConfigPatcher {
 //	    "app.toml":    {"pruning": "nothing"}
 //	  }
 //	}
-func (p *ConfigPatcher) Handler() engine.TaskHandler {
+func (p *ConfigPatcher) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, params ConfigPatchRequest) error {
-		if len(params.Files) == 0 {
-			return fmt.Errorf("config-patch: at least one file is required")
-		}
 		// Convert to map[string]any for PatchFiles (public API).
 		files := make(map[string]any, len(params.Files))
 		for k, v := range params.Files {
@@ -78,13 +84,41 @@ func mergeAndWrite(filePath string, patchMap map[string]any) error {
 	if err != nil {
 		return fmt.Errorf("reading %s: %w", filepath.Base(filePath), err)
 	}
-	merged, ok := patch.Merge(doc, patchMap).(map[string]any)
+	merged, ok := patch.Merge(doc, tomlValue(patchMap)).(map[string]any)
 	if !ok {
 		return fmt.Errorf("merge produced non-map result for %s", filepath.Base(filePath))
 	}
 	return patch.WriteTOML(filePath, merged)
 }
 
+// tomlValue converts the json.Number values task params decode to into
+// int64 or float64, which TOML encodes as numbers rather than strings.
+func tomlValue(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = tomlValue(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = tomlValue(e)
+		}
+		return out
+	}
+	return v
+}
+
 // EnsureDefaultConfig creates the seid home directory structure and writes a
 // minimal default config.toml if one does not already exist. The default is
 // embedded from defaults/config.toml.
@@ -25,8 +25,8 @@ func NewConfigApplier(homeDir string) *ConfigApplier {
 	return &ConfigApplier{homeDir: homeDir}
 }
 
-// Handler returns an engine.TaskHandler for the config-apply task type.
-func (a *ConfigApplier) Handler() engine.TaskHandler {
+// Handler returns an engine.Handler for the config-apply task type.
+func (a *ConfigApplier) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, intent seiconfig.ConfigIntent) error {
 		if intent.Incremental {
 			return a.applyIncremental(ctx, intent)
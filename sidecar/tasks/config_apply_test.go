@@ -23,7 +23,7 @@ func TestConfigApplier_FullGeneration(t *testing.T) {
 	applier := NewConfigApplier(homeDir)
 	handler := applier.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"mode":        "validator",
 		"incremental": false,
 	})
@@ -48,7 +48,7 @@ func TestConfigApplier_FullWithOverrides(t *testing.T) {
 	applier := NewConfigApplier(homeDir)
 	handler := applier.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"mode":        "full",
 		"incremental": false,
 		"overrides": map[string]any{
@@ -73,7 +73,7 @@ func TestConfigApplier_FullMissingMode(t *testing.T) {
 	applier := NewConfigApplier(homeDir)
 	handler := applier.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"incremental": false,
 	})
 	if err == nil {
@@ -89,7 +89,7 @@ func TestConfigApplier_FullInvalidMode(t *testing.T) {
 	applier := NewConfigApplier(homeDir)
 	handler := applier.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"mode":        "bogus",
 		"incremental": false,
 	})
@@ -108,7 +108,7 @@ func TestConfigApplier_Incremental(t *testing.T) {
 	applier := NewConfigApplier(homeDir)
 	handler := applier.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"incremental": true,
 		"overrides": map[string]any{
 			"evm.http_port": "9999",
@@ -140,7 +140,7 @@ func TestConfigApplier_IncrementalPreservesExisting(t *testing.T) {
 	applier := NewConfigApplier(homeDir)
 	handler := applier.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"incremental": true,
 		"overrides": map[string]any{
 			"evm.http_port": "7777",
@@ -161,7 +161,7 @@ func TestConfigApplier_WritesFiles(t *testing.T) {
 	applier := NewConfigApplier(homeDir)
 	handler := applier.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"mode":        "full",
 		"incremental": false,
 	})
@@ -190,7 +190,7 @@ func TestConfigApplier_AllModes(t *testing.T) {
 			applier := NewConfigApplier(homeDir)
 			handler := applier.Handler()
 
-			_, err := handler(context.Background(), map[string]any{
+			_, err := handler.Handle(context.Background(), map[string]any{
 				"mode":        mode,
 				"incremental": false,
 			})
@@ -217,7 +217,7 @@ func TestConfigApplier_SeedIsReachable(t *testing.T) {
 	homeDir := t.TempDir()
 	handler := NewConfigApplier(homeDir).Handler()
 
-	if _, err := handler(context.Background(), map[string]any{
+	if _, err := handler.Handle(context.Background(), map[string]any{
 		"mode":        string(seiconfig.ModeSeed),
 		"incremental": false,
 	}); err != nil {
@@ -243,7 +243,7 @@ func TestConfigApplier_SeedWithoutPexRejected(t *testing.T) {
 	homeDir := t.TempDir()
 	handler := NewConfigApplier(homeDir).Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"mode":        string(seiconfig.ModeSeed),
 		"incremental": false,
 		"overrides":   map[string]any{"network.p2p.pex": "false"},
@@ -1,40 +1,114 @@
 package tasks
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	seiconfig "github.com/sei-protocol/sei-config"
+	"github.com/sei-protocol/seictl/sidecar/actions"
 	"github.com/sei-protocol/seictl/sidecar/engine"
+	"github.com/sei-protocol/seictl/sidecar/rpc"
 	"github.com/sei-protocol/seilog"
 )
 
 var reloadLog = seilog.NewLogger("seictl", "task", "config-reload")
 
+const (
+	// configReloadTimeout bounds the wait, from the signal, for seid's live
+	// config to report every reloaded field's new value.
+	configReloadTimeout = 30 * time.Second
+
+	configReloadPollInterval = 200 * time.Millisecond
+)
+
 // ConfigReloadRequest holds the typed parameters for the config-reload task.
 type ConfigReloadRequest struct {
 	Fields map[string]string `json:"fields"`
 }
 
-// ConfigReloader patches hot-reloadable fields on disk and signals seid
-// to re-read its configuration. The signal mechanism is deferred to a future
-// release; for now only the on-disk write is performed.
-type ConfigReloader struct {
-	homeDir string
+// Validate requires at least one field to reload.
+func (r ConfigReloadRequest) Validate() error {
+	if len(r.Fields) == 0 {
+		return &engine.FieldError{Field: "fields", Reason: "at least one field is required"}
+	}
+	return nil
+}
+
+// ConfigReloadResult is the config-reload task's structured result: each
+// reloaded field's value before and after, and the seid process signaled.
+type ConfigReloadResult struct {
+	Fields map[string]ConfigFieldChange `json:"fields"`
+	// PID is the seid process sent SIGHUP. It is zero when seid was not
+	// signaled.
+	PID int `json:"pid,omitempty"`
+	// RestartRequired is set when seid is running but cannot be reloaded
+	// in place: no live-config endpoint is configured, or it does not
+	// answer. The fields are written and take effect when seid restarts.
+	RestartRequired bool `json:"restartRequired,omitempty"`
 }
 
-// NewConfigReloader creates a reloader targeting the given home directory.
-func NewConfigReloader(homeDir string) *ConfigReloader {
-	return &ConfigReloader{homeDir: homeDir}
+// ConfigFieldChange is one field's value before and after a reload. After
+// is the value seid's live config reports once reloaded; when seid was not
+// signaled it is the value as written to disk. A value the config has no
+// path for is empty before and the requested value after.
+type ConfigFieldChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
 }
 
-// Handler returns an engine.TaskHandler for the config-reload task type.
-func (r *ConfigReloader) Handler() engine.TaskHandler {
-	return engine.TypedHandler(func(_ context.Context, params ConfigReloadRequest) error {
-		if len(params.Fields) == 0 {
-			return fmt.Errorf("config-reload: at least one field is required")
+// ConfigReloader patches hot-reloadable fields on disk and, when seid is
+// known to reload in place, SIGHUPs the running `seid start` process and
+// verifies the new values against its live config.
+//
+// seid's default SIGHUP action is to exit, so the signal is opt-in: only a
+// seid serving its live config at configURL is taken to handle it, and only
+// after that endpoint answers. Without one the task writes the fields and
+// reports RestartRequired instead of signaling.
+//
+// The OS interactions are injectable for testing, as for RestartSeider.
+type ConfigReloader struct {
+	homeDir      string
+	signaler     actions.ProcessSignaler
+	probeUp      func(ctx context.Context) bool
+	liveConfig   func(ctx context.Context) (map[string]any, error)
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// NewConfigReloader creates a reloader targeting the given home directory,
+// with the real /proc + syscall + local-RPC implementations. configURL is
+// seid's live-config endpoint, serving the running config as JSON; pass ""
+// when seid has none, and config-reload then never signals it.
+func NewConfigReloader(homeDir, configURL string) *ConfigReloader {
+	statusClient := rpc.NewStatusClient("", nil)
+	r := &ConfigReloader{
+		homeDir:      homeDir,
+		signaler:     seidStartFinder{},
+		probeUp:      func(ctx context.Context) bool { return seidRPCUp(ctx, statusClient) },
+		timeout:      configReloadTimeout,
+		pollInterval: configReloadPollInterval,
+	}
+	if configURL != "" {
+		configClient := rpc.NewClient(configURL, nil)
+		r.liveConfig = func(ctx context.Context) (map[string]any, error) {
+			return fetchLiveConfig(ctx, configClient)
 		}
+	}
+	return r
+}
 
+// Handler returns an engine.Handler for the config-reload task type.
+// The result is recorded on failure too, once the fields are written.
+func (r *ConfigReloader) Handler() engine.Handler {
+	return engine.TypedHandlerWithResult(func(ctx context.Context, params ConfigReloadRequest) (*ConfigReloadResult, error) {
 		registry := seiconfig.BuildRegistry()
 		registry.EnrichAll(seiconfig.DefaultEnrichments())
 
@@ -42,40 +116,336 @@ func (r *ConfigReloader) Handler() engine.TaskHandler {
 		for key := range params.Fields {
 			f := registry.Field(key)
 			if f == nil {
-				return fmt.Errorf("config-reload: unknown field %q", key)
+				return nil, fmt.Errorf("config-reload: unknown field %q", key)
 			}
 			if !f.HotReload {
 				nonHotReload = append(nonHotReload, key)
 			}
 		}
 		if len(nonHotReload) > 0 {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"config-reload: fields %v are not hot-reloadable and require a restart",
 				nonHotReload)
 		}
 
 		cfg, err := seiconfig.ReadConfigFromDir(r.homeDir)
 		if err != nil {
-			return fmt.Errorf("config-reload: reading config: %w", err)
+			return nil, fmt.Errorf("config-reload: reading config: %w", err)
+		}
+		res := &ConfigReloadResult{Fields: make(map[string]ConfigFieldChange, len(params.Fields))}
+		for key := range params.Fields {
+			before, _ := configFieldValue(cfg, key)
+			res.Fields[key] = ConfigFieldChange{Before: before}
 		}
 
 		if err := seiconfig.ApplyOverrides(cfg, params.Fields); err != nil {
-			return fmt.Errorf("config-reload: applying fields: %w", err)
+			return nil, fmt.Errorf("config-reload: applying fields: %w", err)
 		}
 
 		vr := seiconfig.Validate(cfg)
 		if vr.HasErrors() {
-			return validationError(vr)
+			return nil, validationError(vr)
 		}
 
 		if err := seiconfig.WriteConfigToDir(cfg, r.homeDir); err != nil {
-			return fmt.Errorf("config-reload: writing config: %w", err)
+			return nil, fmt.Errorf("config-reload: writing config: %w", err)
 		}
 
-		// TODO: signal seid to re-read config (SIGHUP or API call)
-		reloadLog.Info("hot-reloadable fields written, seid signal pending implementation",
-			"fields", len(params.Fields))
+		// The values seid should report once reloaded: the config as
+		// written, not as requested.
+		written, err := seiconfig.ReadConfigFromDir(r.homeDir)
+		if err != nil {
+			return nil, fmt.Errorf("config-reload: re-reading config: %w", err)
+		}
+		want := make(map[string]any, len(params.Fields))
+		for key, requested := range params.Fields {
+			if v, ok := lookupConfigField(written, key); ok {
+				want[key] = v
+			} else {
+				want[key] = requested
+			}
+		}
 
-		return nil
+		err = r.reload(ctx, want, res)
+		return res, err
 	})
 }
+
+// reload brings the running seid onto the written values want, filling in
+// res. With no seid in /proc it disambiguates on the local RPC as
+// seidStopper does: serving means seid is running but invisible to us and
+// cannot be told to reload, an error; down means it is not running and
+// will read the new config when it starts, a success with PID zero.
+func (r *ConfigReloader) reload(ctx context.Context, want map[string]any, res *ConfigReloadResult) error {
+	setAfter := func(live map[string]any) {
+		for key, v := range want {
+			after := renderConfigValue(v)
+			if live != nil {
+				after, _ = configFieldValue(live, key)
+			}
+			res.Fields[key] = ConfigFieldChange{Before: res.Fields[key].Before, After: after}
+		}
+	}
+	setAfter(nil)
+
+	pid, err := r.signaler.FindPID(restartSeidProcess)
+	if err != nil {
+		if r.probeUp(ctx) {
+			return fmt.Errorf("config-reload: seid RPC is serving but its process was not found in /proc: %w — cannot signal it to reload", err)
+		}
+		reloadLog.Info("seid not running; fields take effect at its next start", "reason", err.Error())
+		return nil
+	}
+
+	// Only a seid serving its live config is known to handle SIGHUP; any
+	// other would take the default action and exit.
+	if r.liveConfig == nil {
+		reloadLog.Info("no live-config endpoint configured; fields take effect when seid restarts", "pid", pid)
+		res.RestartRequired = true
+		return nil
+	}
+	if _, err := r.liveConfig(ctx); err != nil {
+		reloadLog.Warn("seid live config unavailable; not signaling, fields take effect when seid restarts", "pid", pid, "err", err)
+		res.RestartRequired = true
+		return nil
+	}
+
+	reloadLog.Info("signaling seid to reload config", "pid", pid)
+	if err := r.signaler.Signal(pid, syscall.SIGHUP); err != nil {
+		return fmt.Errorf("config-reload: sending SIGHUP to seid pid %d: %w", pid, err)
+	}
+	res.PID = pid
+	live, err := r.verifyReload(ctx, pid, want)
+	if live != nil {
+		setAfter(live)
+	}
+	return err
+}
+
+// verifyReload polls seid's live config until every field in want reports
+// its new value, within the timeout from the signal, returning the last
+// live config read.
+func (r *ConfigReloader) verifyReload(ctx context.Context, pid int, want map[string]any) (map[string]any, error) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	deadline := time.After(r.timeout)
+
+	var live map[string]any
+	var stale []string
+	for {
+		if !r.signaler.Alive(pid) {
+			return live, fmt.Errorf("config-reload: seid pid %d exited on SIGHUP instead of reloading; the fields are written and take effect when it restarts", pid)
+		}
+		if cur, err := r.liveConfig(ctx); err == nil {
+			live, stale = cur, staleConfigFields(cur, want)
+			if len(stale) == 0 {
+				reloadLog.Info("seid reloaded config", "pid", pid)
+				return live, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return live, ctx.Err()
+		case <-deadline:
+			if live == nil {
+				return nil, fmt.Errorf("config-reload: seid pid %d did not serve its live config within %s of SIGHUP", pid, r.timeout)
+			}
+			return live, fmt.Errorf("config-reload: seid pid %d still reports old values for %v %s after SIGHUP", pid, stale, r.timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// staleConfigFields returns the sorted keys of want whose live value
+// differs.
+func staleConfigFields(live map[string]any, want map[string]any) []string {
+	var stale []string
+	for key, v := range want {
+		if got, ok := lookupConfigField(live, key); !ok || !sameConfigValue(v, got) {
+			stale = append(stale, key)
+		}
+	}
+	slices.Sort(stale)
+	return stale
+}
+
+// fetchLiveConfig reads seid's running config as a JSON object.
+func fetchLiveConfig(ctx context.Context, c *rpc.Client) (map[string]any, error) {
+	raw, err := c.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var live map[string]any
+	if err := dec.Decode(&live); err != nil {
+		return nil, fmt.Errorf("decoding live config: %w", err)
+	}
+	return live, nil
+}
+
+// configFieldValue renders the value at a dotted registry key in cfg, as
+// lookupConfigField finds it.
+func configFieldValue(cfg any, key string) (string, bool) {
+	v, ok := lookupConfigField(cfg, key)
+	return renderConfigValue(v), ok
+}
+
+func renderConfigValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// lookupConfigField returns the value at a dotted registry key, e.g.
+// "logging.level" or "evm.http_port", in cfg: a config struct or a decoded
+// JSON object. Each segment matches a field by name or toml/json tag, or a
+// map key, ignoring case, underscores and dashes. It reports false when
+// the path does not resolve, and a nil value for a nil pointer.
+func lookupConfigField(cfg any, key string) (any, bool) {
+	v := reflect.ValueOf(cfg)
+	for _, seg := range strings.Split(key, ".") {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, false
+			}
+			v = v.Elem()
+		}
+		var f reflect.Value
+		var ok bool
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok = structFieldByKey(v, seg)
+		case reflect.Map:
+			f, ok = mapValueByKey(v, seg)
+		}
+		if !ok {
+			return nil, false
+		}
+		v = f
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, true
+		}
+		v = v.Elem()
+	}
+	return v.Interface(), true
+}
+
+// sameConfigValue reports whether a value as written to the config and
+// one from seid's live config mean the same thing. Both go through a JSON
+// round trip first, so they are compared as seid's endpoint would encode
+// them. Scalars may still differ in JSON type between the two sides, so
+// they compare by meaning: a duration as nanoseconds or as a Go duration
+// string such as "10s", a number as 1, 1.0 or "1", and a bool as true or
+// "true".
+func sameConfigValue(written, live any) bool {
+	a, b := normalizeConfigValue(written), normalizeConfigValue(live)
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	if x, ok := configNumber(a); ok {
+		y, ok := configNumber(b)
+		return ok && x.Cmp(y) == 0
+	}
+	if x, ok := configBool(a); ok {
+		y, ok := configBool(b)
+		return ok && x == y
+	}
+	return false
+}
+
+// normalizeConfigValue returns v as decoding its JSON encoding yields it,
+// with numbers as json.Number. A value that does not encode is returned
+// unchanged.
+func normalizeConfigValue(v any) any {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return v
+	}
+	return out
+}
+
+// configNumber reads a normalized scalar as an exact number: a JSON
+// number, a numeric string, or a Go duration string in nanoseconds.
+func configNumber(v any) (*big.Rat, bool) {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return new(big.Rat).SetInt64(int64(d)), true
+		}
+		s = v
+	default:
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// configBool reads a normalized scalar as a bool: a JSON bool, or the
+// string "true" or "false" in any case.
+func configBool(v any) (bool, bool) {
+	switch v := v.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+func structFieldByKey(v reflect.Value, seg string) (reflect.Value, bool) {
+	want := normalizeConfigKey(seg)
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		names := []string{sf.Name}
+		for _, tag := range []string{"toml", "json"} {
+			if name, _, _ := strings.Cut(sf.Tag.Get(tag), ","); name != "" && name != "-" {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			if normalizeConfigKey(name) == want {
+				return v.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func mapValueByKey(v reflect.Value, seg string) (reflect.Value, bool) {
+	if v.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, false
+	}
+	want := normalizeConfigKey(seg)
+	iter := v.MapRange()
+	for iter.Next() {
+		if normalizeConfigKey(iter.Key().String()) == want {
+			return iter.Value(), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func normalizeConfigKey(s string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+}
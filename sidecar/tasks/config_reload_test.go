@@ -2,8 +2,14 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	seiconfig "github.com/sei-protocol/sei-config"
 )
@@ -12,10 +18,10 @@ func TestConfigReloader_HotReloadableField(t *testing.T) {
 	homeDir := t.TempDir()
 	writeDefaultConfig(t, homeDir, seiconfig.ModeFull)
 
-	reloader := NewConfigReloader(homeDir)
+	reloader := testReloader(homeDir, &fakeSignaler{findErr: errors.New("not found")}, neverUp, nil)
 	handler := reloader.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"fields": map[string]any{
 			"logging.level": "debug",
 		},
@@ -37,10 +43,10 @@ func TestConfigReloader_NonHotReloadableField(t *testing.T) {
 	homeDir := t.TempDir()
 	writeDefaultConfig(t, homeDir, seiconfig.ModeFull)
 
-	reloader := NewConfigReloader(homeDir)
+	reloader := testReloader(homeDir, &fakeSignaler{findErr: errors.New("not found")}, neverUp, nil)
 	handler := reloader.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"fields": map[string]any{
 			"storage.db_backend": "rocksdb",
 		},
@@ -57,10 +63,10 @@ func TestConfigReloader_UnknownField(t *testing.T) {
 	homeDir := t.TempDir()
 	writeDefaultConfig(t, homeDir, seiconfig.ModeFull)
 
-	reloader := NewConfigReloader(homeDir)
+	reloader := testReloader(homeDir, &fakeSignaler{findErr: errors.New("not found")}, neverUp, nil)
 	handler := reloader.Handler()
 
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"fields": map[string]any{
 			"nonexistent.field": "value",
 		},
@@ -75,10 +81,10 @@ func TestConfigReloader_UnknownField(t *testing.T) {
 
 func TestConfigReloader_EmptyFields(t *testing.T) {
 	homeDir := t.TempDir()
-	reloader := NewConfigReloader(homeDir)
+	reloader := testReloader(homeDir, &fakeSignaler{findErr: errors.New("not found")}, neverUp, nil)
 	handler := reloader.Handler()
 
-	_, err := handler(context.Background(), map[string]any{})
+	_, err := handler.Handle(context.Background(), map[string]any{})
 	if err == nil {
 		t.Fatal("expected error for empty fields")
 	}
@@ -86,3 +92,189 @@ func TestConfigReloader_EmptyFields(t *testing.T) {
 		t.Errorf("error should mention at least one field: %v", err)
 	}
 }
+
+// testReloader builds a ConfigReloader on fakes only, so no test can find
+// or signal a real seid on the host.
+func testReloader(homeDir string, sig *fakeSignaler, probeUp func(context.Context) bool, live func(context.Context) (map[string]any, error)) *ConfigReloader {
+	return &ConfigReloader{
+		homeDir:      homeDir,
+		signaler:     sig,
+		probeUp:      probeUp,
+		liveConfig:   live,
+		timeout:      time.Second,
+		pollInterval: time.Millisecond,
+	}
+}
+
+// fakeLiveConfig serves logging.level as "info" until seid is signaled and
+// then read reads more times, and as level after.
+func fakeLiveConfig(sig *fakeSignaler, level string, reads int) func(context.Context) (map[string]any, error) {
+	var n atomic.Int32
+	return func(context.Context) (map[string]any, error) {
+		cur := "info"
+		if len(sig.signals) > 0 && n.Add(1) > int32(reads) {
+			cur = level
+		}
+		return map[string]any{"logging": map[string]any{"level": cur}}, nil
+	}
+}
+
+func newReloadResult() *ConfigReloadResult {
+	return &ConfigReloadResult{Fields: map[string]ConfigFieldChange{"logging.level": {Before: "info"}}}
+}
+
+func TestConfigReloader_ReloadVerifiesLiveValues(t *testing.T) {
+	sig := &fakeSignaler{findPID: 42}
+	sig.alive.Store(true)
+	res := newReloadResult()
+	err := testReloader("", sig, upAfter(0), fakeLiveConfig(sig, "debug", 3)).
+		reload(context.Background(), map[string]any{"logging.level": "debug"}, res)
+	if err != nil || res.PID != 42 || res.RestartRequired {
+		t.Fatalf("reload() = %v, result %+v; want pid 42 reloaded", err, res)
+	}
+	if len(sig.signals) != 1 || sig.signals[0] != syscall.SIGHUP {
+		t.Errorf("signals = %v, want one SIGHUP", sig.signals)
+	}
+	if got := res.Fields["logging.level"]; got != (ConfigFieldChange{Before: "info", After: "debug"}) {
+		t.Errorf("logging.level = %+v, want info -> debug from the live config", got)
+	}
+}
+
+func TestConfigReloader_ReloadWithoutLiveConfigNeverSignals(t *testing.T) {
+	want := map[string]any{"logging.level": "debug"}
+	unavailable := func(context.Context) (map[string]any, error) { return nil, errors.New("404 Not Found") }
+	for name, live := range map[string]func(context.Context) (map[string]any, error){
+		"not configured": nil,
+		"not served":     unavailable,
+	} {
+		sig := &fakeSignaler{findPID: 42}
+		sig.alive.Store(true)
+		res := newReloadResult()
+		if err := testReloader("", sig, upAfter(0), live).reload(context.Background(), want, res); err != nil {
+			t.Fatalf("%s: reload() = %v", name, err)
+		}
+		if !res.RestartRequired || res.PID != 0 || len(sig.signals) != 0 {
+			t.Errorf("%s: result %+v, signals %v; want restart required and no signal", name, res, sig.signals)
+		}
+		if got := res.Fields["logging.level"].After; got != "debug" {
+			t.Errorf("%s: after = %q, want the written value", name, got)
+		}
+	}
+}
+
+func TestConfigReloader_ReloadFailures(t *testing.T) {
+	want := map[string]any{"logging.level": "debug"}
+
+	// seid exits on the signal.
+	sig := &fakeSignaler{findPID: 42}
+	sig.alive.Store(true)
+	live := fakeLiveConfig(sig, "debug", 1000)
+	sig.signalFn = func(int, syscall.Signal) error { sig.alive.Store(false); return nil }
+	if err := testReloader("", sig, upAfter(0), live).reload(context.Background(), want, newReloadResult()); err == nil || !strings.Contains(err.Error(), "exited on SIGHUP") {
+		t.Errorf("exiting seid: err = %v", err)
+	}
+
+	// Alive but still reporting the old value runs into the timeout.
+	sig = &fakeSignaler{findPID: 42}
+	sig.alive.Store(true)
+	r := testReloader("", sig, upAfter(0), fakeLiveConfig(sig, "info", 0))
+	r.timeout = 50 * time.Millisecond
+	res := newReloadResult()
+	if err := r.reload(context.Background(), want, res); err == nil || !strings.Contains(err.Error(), "old values for [logging.level]") {
+		t.Errorf("stale seid: err = %v", err)
+	}
+	if got := res.Fields["logging.level"].After; got != "info" {
+		t.Errorf("stale seid: after = %q, want the live value", got)
+	}
+
+	// Not in /proc: an error only while the RPC still serves.
+	sig = &fakeSignaler{findErr: errors.New("not found")}
+	if err := testReloader("", sig, upAfter(0), nil).reload(context.Background(), want, newReloadResult()); err == nil {
+		t.Error("invisible serving seid: expected an error")
+	}
+	res = newReloadResult()
+	if err := testReloader("", sig, neverUp, nil).reload(context.Background(), want, res); err != nil || res.PID != 0 || res.RestartRequired {
+		t.Errorf("stopped seid: reload() = %v, result %+v; want a no-op success", err, res)
+	}
+	if len(sig.signals) != 0 {
+		t.Errorf("signals = %v, want none without a process", sig.signals)
+	}
+}
+
+func TestConfigFieldValue(t *testing.T) {
+	type p2p struct {
+		ListenAddress string `toml:"laddr"`
+	}
+	cfg := &struct {
+		EVM struct{ HTTPPort int }
+		P2P *p2p
+	}{P2P: &p2p{ListenAddress: "tcp://0.0.0.0:26656"}}
+	cfg.EVM.HTTPPort = 8545
+
+	for key, want := range map[string]string{
+		"evm.http_port":      "8545",
+		"p2p.laddr":          "tcp://0.0.0.0:26656",
+		"p2p.listen-address": "tcp://0.0.0.0:26656",
+	} {
+		if got, ok := configFieldValue(cfg, key); !ok || got != want {
+			t.Errorf("configFieldValue(%q) = %q, %v; want %q", key, got, ok, want)
+		}
+	}
+	live := map[string]any{"EVM": map[string]any{"http-port": json.Number("8545")}}
+	if got, ok := configFieldValue(live, "evm.http_port"); !ok || got != "8545" {
+		t.Errorf("configFieldValue(live) = %q, %v; want 8545", got, ok)
+	}
+	if _, ok := configFieldValue(cfg, "evm.http_port.x"); ok {
+		t.Error("a path through a scalar resolved")
+	}
+}
+
+// Written values come from the config struct while live ones are seid's
+// JSON, so a duration or bool matches whichever encoding seid reports.
+func TestStaleConfigFieldsComparesByMeaning(t *testing.T) {
+	written := &struct {
+		Mempool struct {
+			TTL       time.Duration `toml:"ttl-duration"`
+			Recheck   bool          `toml:"recheck"`
+			Size      int           `toml:"size"`
+			Broadcast string        `toml:"broadcast"`
+		}
+	}{}
+	written.Mempool.TTL = 10 * time.Second
+	written.Mempool.Recheck = true
+	written.Mempool.Size = 5000
+	written.Mempool.Broadcast = "on"
+
+	want := make(map[string]any)
+	for _, key := range []string{"mempool.ttl-duration", "mempool.recheck", "mempool.size", "mempool.broadcast"} {
+		v, ok := lookupConfigField(written, key)
+		if !ok {
+			t.Fatalf("lookupConfigField(%q) did not resolve", key)
+		}
+		want[key] = v
+	}
+
+	decode := func(s string) map[string]any {
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		var live map[string]any
+		if err := dec.Decode(&live); err != nil {
+			t.Fatal(err)
+		}
+		return live
+	}
+	for name, live := range map[string]string{
+		"native":  `{"mempool":{"ttl-duration":10000000000,"recheck":true,"size":5000,"broadcast":"on"}}`,
+		"strings": `{"mempool":{"ttl-duration":"10s","recheck":"true","size":"5000","broadcast":"on"}}`,
+		"float":   `{"mempool":{"ttl_duration":"10000ms","recheck":true,"size":5000.0,"broadcast":"on"}}`,
+	} {
+		if stale := staleConfigFields(decode(live), want); len(stale) != 0 {
+			t.Errorf("%s: stale = %v, want none", name, stale)
+		}
+	}
+
+	live := decode(`{"mempool":{"ttl-duration":"5s","recheck":false,"size":5000,"broadcast":"true"}}`)
+	if stale := staleConfigFields(live, want); !slices.Equal(stale, []string{"mempool.broadcast", "mempool.recheck", "mempool.ttl-duration"}) {
+		t.Errorf("stale = %v, want broadcast, recheck and ttl-duration", stale)
+	}
+}
@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -192,7 +193,7 @@ persistent-peers = ""
 func TestConfigPatcherHandlerRejectsEmptyFiles(t *testing.T) {
 	patcher := NewConfigPatcher(t.TempDir())
 	handler := patcher.Handler()
-	_, err := handler(context.Background(), map[string]any{})
+	_, err := handler.Handle(context.Background(), map[string]any{})
 	if err == nil {
 		t.Fatal("expected error for empty files, got nil")
 	}
@@ -223,7 +224,7 @@ persistent-peers = ""
 
 	patcher := NewConfigPatcher(homeDir)
 	handler := patcher.Handler()
-	_, err := handler(context.Background(), map[string]any{
+	_, err := handler.Handle(context.Background(), map[string]any{
 		"files": map[string]any{
 			"config.toml": map[string]any{
 				"p2p": map[string]any{
@@ -243,6 +244,37 @@ persistent-peers = ""
 	}
 }
 
+func TestConfigPatcherHandlerWritesNumbersAsNumbers(t *testing.T) {
+	homeDir := t.TempDir()
+	configPath := setupConfigFile(t, homeDir, `
+[p2p]
+max-num-inbound-peers = 40
+`)
+
+	handler := NewConfigPatcher(homeDir).Handler()
+	_, err := handler.Handle(context.Background(), map[string]any{
+		"files": map[string]any{
+			"config.toml": map[string]any{
+				"p2p": map[string]any{
+					"max-num-inbound-peers": json.Number("100"),
+					"flush-throttle-ratio":  json.Number("0.5"),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+
+	p2p := readTOML(t, configPath)["p2p"].(map[string]any)
+	if p2p["max-num-inbound-peers"] != int64(100) {
+		t.Errorf("max-num-inbound-peers = %#v, want int64 100", p2p["max-num-inbound-peers"])
+	}
+	if p2p["flush-throttle-ratio"] != 0.5 {
+		t.Errorf("flush-throttle-ratio = %#v, want 0.5", p2p["flush-throttle-ratio"])
+	}
+}
+
 func TestConfigPatcherCreatesFileIfMissing(t *testing.T) {
 	homeDir := t.TempDir()
 	configDir := filepath.Join(homeDir, "config")
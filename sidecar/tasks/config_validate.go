@@ -22,8 +22,8 @@ func NewConfigValidator(homeDir string) *ConfigValidator {
 	return &ConfigValidator{homeDir: homeDir}
 }
 
-// Handler returns an engine.TaskHandler for the config-validate task type.
-func (v *ConfigValidator) Handler() engine.TaskHandler {
+// Handler returns an engine.Handler for the config-validate task type.
+func (v *ConfigValidator) Handler() engine.Handler {
 	return engine.TypedHandler(func(_ context.Context, _ struct{}) error {
 		cfg, err := seiconfig.ReadConfigFromDir(v.homeDir)
 		if err != nil {
@@ -92,7 +92,7 @@ func NewEvmLogicalDigester(factory seis3.UploaderFactory) *EvmLogicalDigester {
 	return &EvmLogicalDigester{s3UploaderFactory: factory}
 }
 
-func (d *EvmLogicalDigester) Handler() engine.TaskHandler {
+func (d *EvmLogicalDigester) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, req EvmLogicalDigestRequest) error {
 		return d.run(ctx, req)
 	})
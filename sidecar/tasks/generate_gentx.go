@@ -65,7 +65,7 @@ func NewGentxGenerator(homeDir string) *GentxGenerator {
 	return &GentxGenerator{homeDir: homeDir}
 }
 
-// Handler returns an engine.TaskHandler for the generate-gentx task type.
+// Handler returns an engine.Handler for the generate-gentx task type.
 //
 // Expected params:
 //
@@ -74,7 +74,7 @@ func NewGentxGenerator(homeDir string) *GentxGenerator {
 //	  "stakingAmount":  "1000000usei",
 //	  "accountBalance": "10000000usei"
 //	}
-func (g *GentxGenerator) Handler() engine.TaskHandler {
+func (g *GentxGenerator) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, params GenerateGentxRequest) error {
 		if markerExists(g.homeDir, gentxMarkerFile) {
 			gentxLog.Debug("already completed, skipping")
@@ -37,10 +37,10 @@ func NewIdentityGenerator(homeDir string) *IdentityGenerator {
 	return &IdentityGenerator{homeDir: homeDir}
 }
 
-// Handler returns an engine.TaskHandler for the generate-identity task type.
+// Handler returns an engine.Handler for the generate-identity task type.
 //
 // Expected params: {"chainId": "...", "moniker": "..."}
-func (g *IdentityGenerator) Handler() engine.TaskHandler {
+func (g *IdentityGenerator) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, params GenerateIdentityRequest) error {
 		if markerExists(g.homeDir, identityMarkerFile) {
 			identityLog.Debug("already completed, skipping")
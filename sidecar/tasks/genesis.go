@@ -85,9 +85,9 @@ func NewGenesisFetcher(homeDir, chainID, genesisBucket, genesisRegion string, fa
 	}
 }
 
-// Handler returns an engine.TaskHandler that resolves genesis from embedded
+// Handler returns an engine.Handler that resolves genesis from embedded
 // config or S3 fallback. No task parameters are required.
-func (g *GenesisFetcher) Handler() engine.TaskHandler {
+func (g *GenesisFetcher) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, req ConfigureGenesisRequest) error {
 		if markerExists(g.homeDir, genesisMarkerFile) {
 			genesisLog.Debug("already completed, skipping")
@@ -56,9 +56,9 @@ func NewGenesisPeersSetter(homeDir, bucket, region, chainID string, s3Factory S3
 	}
 }
 
-// Handler returns an engine.TaskHandler for the set-genesis-peers task.
+// Handler returns an engine.Handler for the set-genesis-peers task.
 // The peers.json key is derived from the chain ID: {chainID}/peers.json.
-func (g *GenesisPeersSetter) Handler() engine.TaskHandler {
+func (g *GenesisPeersSetter) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, _ SetGenesisPeersRequest) error {
 		key := g.chainID + "/peers.json"
 
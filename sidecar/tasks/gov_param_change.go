@@ -35,11 +35,10 @@ var govParamChangeLog = seilog.NewLogger("seictl", "task", "gov-param-change")
 // whatever shape the param's registered type expects (scalar, string,
 // bool, or object). It is stringified exactly ONCE — see buildParamChangeMsg.
 //
-// Integer-valued params MUST be passed as JSON strings (e.g. "100"), not
-// bare numbers: the sidecar request decode routes values through a
-// map[string]any (float64 numbers), which silently loses precision above
-// 2^53. Sei's large-integer params (durations, windows) are string-encoded
-// by convention, so this is the natural form anyway.
+// Sei's large-integer params (durations, windows) are string-encoded by
+// convention, so pass them as JSON strings (e.g. "100"). A bare number
+// still reaches Value exactly as submitted: params decode with UseNumber
+// end to end, never through float64.
 type paramChange struct {
 	Subspace string          `json:"subspace"`
 	Key      string          `json:"key"`
@@ -77,7 +76,7 @@ func NewGovParamChanger(cfg engine.ExecutionConfig) *GovParamChanger {
 // Handler delegates to SignAndBroadcast (which owns the crash-idempotency
 // marker — see the REHYDRATION note at the top of this file) and classifies
 // the outcome via classifyGovResult.
-func (g *GovParamChanger) Handler() engine.TaskHandler {
+func (g *GovParamChanger) Handler() engine.Handler {
 	return engine.TypedHandlerWithResult(func(ctx context.Context, params GovParamChangeRequest) (*wire.GovTxResult, error) {
 		msg, err := buildParamChangeMsg(g.cfg, params)
 		if err != nil {
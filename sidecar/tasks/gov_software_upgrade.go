@@ -66,7 +66,7 @@ func NewGovSoftwareUpgrader(cfg engine.ExecutionConfig) *GovSoftwareUpgrader {
 // Handler delegates to SignAndBroadcast (which owns the crash-idempotency
 // marker — see the REHYDRATION note at the top of this file) and classifies
 // the outcome via classifyGovResult.
-func (g *GovSoftwareUpgrader) Handler() engine.TaskHandler {
+func (g *GovSoftwareUpgrader) Handler() engine.Handler {
 	return engine.TypedHandlerWithResult(func(ctx context.Context, params GovSoftwareUpgradeRequest) (*wire.GovTxResult, error) {
 		msg, err := buildSoftwareUpgradeMsg(g.cfg, params)
 		if err != nil {
@@ -55,7 +55,7 @@ func NewGovVoter(cfg engine.ExecutionConfig) *GovVoter {
 //
 // Stale proposals are rejected by CheckTx and surface as Terminal. We
 // do not pre-check via chain query — that opens a TOCTOU window.
-func (g *GovVoter) Handler() engine.TaskHandler {
+func (g *GovVoter) Handler() engine.Handler {
 	return engine.TypedHandlerWithResult(func(ctx context.Context, params GovVoteRequest) (*wire.GovTxResult, error) {
 		msg, err := buildVoteMsg(g.cfg, params)
 		if err != nil {
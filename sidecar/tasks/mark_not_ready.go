@@ -33,13 +33,13 @@ func NewMarkNotReadier(purger markReadyPurger) *MarkNotReadier {
 	return &MarkNotReadier{purger: purger}
 }
 
-// Handler returns an engine.TaskHandler for the mark-not-ready task type.
+// Handler returns an engine.Handler for the mark-not-ready task type.
 // Params are empty. The handler purges mark-ready records and returns; the
 // engine's completion hook performs the readiness flip. On purge failure it
 // returns an error so the engine skips the flip (fail-safe: readiness is left
 // untouched rather than flipped over a store that still holds a releasable
 // mark-ready).
-func (m *MarkNotReadier) Handler() engine.TaskHandler {
+func (m *MarkNotReadier) Handler() engine.Handler {
 	return engine.TypedHandler(func(_ context.Context, _ struct{}) error {
 		n, err := m.purger.DeleteByType(string(engine.TaskMarkReady))
 		if err != nil {
@@ -22,7 +22,7 @@ func (p *fakePurger) DeleteByType(taskType string) (int, error) {
 
 func TestMarkNotReady_PurgesMarkReadyRecords(t *testing.T) {
 	p := &fakePurger{n: 2}
-	if _, err := NewMarkNotReadier(p).Handler()(context.Background(), nil); err != nil {
+	if _, err := NewMarkNotReadier(p).Handler().Handle(context.Background(), nil); err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
 	if len(p.deleted) != 1 || p.deleted[0] != string(engine.TaskMarkReady) {
@@ -32,7 +32,7 @@ func TestMarkNotReady_PurgesMarkReadyRecords(t *testing.T) {
 
 func TestMarkNotReady_PurgeFailurePropagates(t *testing.T) {
 	p := &fakePurger{err: fmt.Errorf("store offline")}
-	_, err := NewMarkNotReadier(p).Handler()(context.Background(), nil)
+	_, err := NewMarkNotReadier(p).Handler().Handle(context.Background(), nil)
 	if err == nil {
 		t.Fatal("expected purge failure to propagate so the engine skips the readiness flip")
 	}
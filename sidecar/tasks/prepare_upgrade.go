@@ -0,0 +1,366 @@
+package tasks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+	seis3 "github.com/sei-protocol/seictl/sidecar/s3"
+	"github.com/sei-protocol/seilog"
+)
+
+var prepareUpgradeLog = seilog.NewLogger("seictl", "task", "prepare-upgrade")
+
+// Upgrade binaries are laid out as cosmovisor lays them out under the home
+// directory, so the seid container execs cosmovisor/current/bin/seid:
+//
+//	cosmovisor/upgrades/<name>/bin/seid         one directory per upgrade plan
+//	cosmovisor/upgrades/<name>/bin/seid.sha256  its verified digest, re-checked by apply-upgrade
+//	cosmovisor/current -> upgrades/<name>       the active binary, switched by apply-upgrade
+const (
+	upgradeRootDir     = "cosmovisor"
+	upgradesDir        = "upgrades"
+	upgradeCurrentLink = "current"
+	upgradeBinaryName  = "seid"
+	upgradeDigestExt   = ".sha256"
+)
+
+// upgradeDir is the directory of upgrade name, relative to the cosmovisor
+// root, which is also the current link's target for it.
+func upgradeDir(name string) string {
+	return filepath.Join(upgradesDir, name)
+}
+
+// upgradeBinaryPath is where upgrade name's seid binary is placed.
+func upgradeBinaryPath(homeDir, name string) string {
+	return filepath.Join(homeDir, upgradeRootDir, upgradeDir(name), "bin", upgradeBinaryName)
+}
+
+// validateUpgradeName rejects names that would escape the upgrades
+// directory.
+func validateUpgradeName(name string) error {
+	switch {
+	case name == "":
+		return &engine.FieldError{Field: "upgradeName", Reason: "is required"}
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`):
+		return &engine.FieldError{Field: "upgradeName", Reason: fmt.Sprintf("%q is not a valid directory name", name)}
+	}
+	return nil
+}
+
+// PrepareUpgradeRequest holds the typed parameters for the prepare-upgrade
+// task. UpgradeName and UpgradeInfo are the software-upgrade plan's name
+// and info, as gov-software-upgrade submits them.
+type PrepareUpgradeRequest struct {
+	UpgradeName string `json:"upgradeName"`
+	UpgradeInfo string `json:"upgradeInfo"`
+}
+
+// Validate checks the name is usable as a directory and the info names a
+// checksummed binary for this platform. Whether a file:// source is
+// accepted is the preparer's to decide when the task runs.
+func (r PrepareUpgradeRequest) Validate() error {
+	if err := validateUpgradeName(r.UpgradeName); err != nil {
+		return err
+	}
+	if _, _, err := upgradeBinarySource(r.UpgradeInfo, upgradePlatform(), true); err != nil {
+		return &engine.FieldError{Field: "upgradeInfo", Reason: err.Error()}
+	}
+	return nil
+}
+
+// PrepareUpgradeResult is the prepare-upgrade task's structured result.
+// Downloaded is false when a binary with the expected digest was already in
+// place.
+type PrepareUpgradeResult struct {
+	Path       string `json:"path"`
+	Source     string `json:"source"`
+	SHA256     string `json:"sha256"`
+	Downloaded bool   `json:"downloaded"`
+}
+
+// upgradePlanInfo is the cosmovisor-style JSON in an upgrade plan's info:
+// a download URL per "os/arch" platform, or "any", each carrying its
+// checksum as a "checksum=sha256:<hex>" query parameter.
+type upgradePlanInfo struct {
+	Binaries map[string]string `json:"binaries"`
+}
+
+func upgradePlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// upgradeBinarySource picks platform's binary from info, returning its
+// download URL, stripped of the checksum parameter, and expected SHA-256.
+// A binary without a sha256 checksum is refused, as is a file:// URL unless
+// allowFile is set.
+func upgradeBinarySource(info, platform string, allowFile bool) (*url.URL, string, error) {
+	var plan upgradePlanInfo
+	if err := json.Unmarshal([]byte(info), &plan); err != nil {
+		return nil, "", fmt.Errorf("upgrade info is not a JSON object with a binaries map: %w", err)
+	}
+	raw, ok := plan.Binaries[platform]
+	if !ok {
+		raw, ok = plan.Binaries["any"]
+	}
+	if !ok {
+		return nil, "", fmt.Errorf("upgrade info has no binary for %s", platform)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("binary URL for %s: %w", platform, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "s3":
+	case "file":
+		if !allowFile {
+			return nil, "", fmt.Errorf("binary URL for %s: file:// sources are not accepted", platform)
+		}
+	default:
+		return nil, "", fmt.Errorf("binary URL for %s has unsupported scheme %q", platform, u.Scheme)
+	}
+	q := u.Query()
+	algo, sum, _ := strings.Cut(q.Get("checksum"), ":")
+	if algo != "sha256" {
+		return nil, "", fmt.Errorf("binary URL for %s must carry checksum=sha256:<hex>", platform)
+	}
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+		return nil, "", fmt.Errorf("binary URL for %s has a malformed sha256 checksum %q", platform, sum)
+	}
+	q.Del("checksum")
+	u.RawQuery = q.Encode()
+	return u, strings.ToLower(sum), nil
+}
+
+// UpgradePreparer downloads an upgrade plan's seid binary into the upgrade
+// layout and verifies its SHA-256 before placing it. It never touches the
+// current link, so a prepared binary is inert until apply-upgrade.
+//
+// Binaries come over http(s), or from S3 as s3://<bucket>/<key> (in the
+// region given by a region query parameter, else the preparer's). A plan
+// naming a local file:// path is refused: a proposal could otherwise place
+// any file on the node's disk as the next seid.
+type UpgradePreparer struct {
+	homeDir         string
+	region          string
+	httpClient      *http.Client
+	s3ClientFactory S3ClientFactory
+	// allowFile accepts file:// binary URLs. Only tests set it.
+	allowFile bool
+}
+
+// NewUpgradePreparer creates a preparer rooted at homeDir. region is the
+// default region for s3:// binaries. Pass nil for the default HTTP client
+// and S3 factory.
+func NewUpgradePreparer(homeDir, region string, httpClient *http.Client, factory S3ClientFactory) *UpgradePreparer {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if factory == nil {
+		factory = DefaultS3ClientFactory
+	}
+	return &UpgradePreparer{homeDir: homeDir, region: region, httpClient: httpClient, s3ClientFactory: factory}
+}
+
+// Handler returns an engine.Handler for the prepare-upgrade task type.
+func (p *UpgradePreparer) Handler() engine.Handler {
+	return engine.TypedHandlerWithResult(func(ctx context.Context, params PrepareUpgradeRequest) (*PrepareUpgradeResult, error) {
+		return p.prepare(ctx, params)
+	})
+}
+
+func (p *UpgradePreparer) prepare(ctx context.Context, params PrepareUpgradeRequest) (*PrepareUpgradeResult, error) {
+	src, want, err := upgradeBinarySource(params.UpgradeInfo, upgradePlatform(), p.allowFile)
+	if err != nil {
+		return nil, fmt.Errorf("prepare-upgrade: %w", err)
+	}
+	dest := upgradeBinaryPath(p.homeDir, params.UpgradeName)
+	res := &PrepareUpgradeResult{Path: dest, Source: src.Redacted(), SHA256: want}
+
+	if got, err := fileSHA256(dest); err == nil && got == want {
+		// Record the digest again in case a crash cut off the last run
+		// between placing the binary and recording it.
+		if err := writeFileSynced(dest+upgradeDigestExt, []byte(want+"\n"), 0o644); err != nil {
+			return nil, fmt.Errorf("prepare-upgrade: recording digest: %w", err)
+		}
+		prepareUpgradeLog.Info("binary already prepared", "upgrade", params.UpgradeName, "path", dest)
+		return res, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return nil, fmt.Errorf("prepare-upgrade: creating %s: %w", filepath.Dir(dest), err)
+	}
+	body, err := p.open(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	prepareUpgradeLog.Info("downloading upgrade binary", "upgrade", params.UpgradeName, "source", res.Source)
+	tmp := dest + ".download"
+	got, err := writeHashed(tmp, body)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("prepare-upgrade: downloading %s: %w", res.Source, err)
+	}
+	if got != want {
+		_ = os.Remove(tmp)
+		return nil, &engine.TaskError{
+			Task:      "prepare-upgrade",
+			Operation: "verify-checksum",
+			Message:   fmt.Sprintf("binary from %s has SHA-256 %s, expected %s", res.Source, got, want),
+			Hint:      "the binary does not match the upgrade plan's checksum; refusing to place it",
+			Retryable: false,
+		}
+	}
+	// The binary and its digest are synced to disk before they are in
+	// place, so a power loss never leaves a truncated binary at dest for
+	// apply-upgrade to swap in.
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("prepare-upgrade: placing %s: %w", dest, err)
+	}
+	if err := syncDir(filepath.Dir(dest)); err != nil {
+		return nil, fmt.Errorf("prepare-upgrade: syncing %s: %w", filepath.Dir(dest), err)
+	}
+	if err := writeFileSynced(dest+upgradeDigestExt, []byte(want+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("prepare-upgrade: recording digest: %w", err)
+	}
+	res.Downloaded = true
+	prepareUpgradeLog.Info("upgrade binary prepared", "upgrade", params.UpgradeName, "path", dest, "sha256", got)
+	return res, nil
+}
+
+// open starts the download of src.
+func (p *UpgradePreparer) open(ctx context.Context, src *url.URL) (io.ReadCloser, error) {
+	switch src.Scheme {
+	case "file":
+		f, err := os.Open(src.Path)
+		if err != nil {
+			return nil, fmt.Errorf("prepare-upgrade: %w", err)
+		}
+		return f, nil
+	case "s3":
+		bucket, key := src.Host, strings.TrimPrefix(src.Path, "/")
+		region := p.region
+		if r := src.Query().Get("region"); r != "" {
+			region = r
+		}
+		client, err := p.s3ClientFactory(ctx, region)
+		if err != nil {
+			return nil, fmt.Errorf("prepare-upgrade: building S3 client: %w", err)
+		}
+		out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+		if err != nil {
+			return nil, seis3.ClassifyS3Error("prepare-upgrade", bucket, key, region, err)
+		}
+		return out.Body, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("prepare-upgrade: %w", err)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prepare-upgrade: fetching %s: %w", src.Redacted(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, &engine.TaskError{
+			Task:      "prepare-upgrade",
+			Operation: "download",
+			Message:   fmt.Sprintf("fetching %s returned %s", src.Redacted(), resp.Status),
+			Retryable: resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests,
+		}
+	}
+	return resp.Body, nil
+}
+
+// writeHashed copies r to a new executable file at path and syncs it,
+// returning the SHA-256 hex digest of what was written.
+func writeHashed(path string, r io.Reader) (string, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeFileSynced atomically replaces path with data: it writes and syncs a
+// temporary file, renames it into place, and syncs the directory so the
+// rename itself survives a power loss.
+func writeFileSynced(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir fsyncs directory dir, persisting the entries renamed into it.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
+
+// fileSHA256 returns the SHA-256 hex digest of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,125 @@
+package tasks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// upgradeInfoFor returns plan info naming src, with data's checksum, as the
+// binary for this platform.
+func upgradeInfoFor(t *testing.T, src string, data []byte) string {
+	t.Helper()
+	sum := sha256.Sum256(data)
+	info, err := json.Marshal(upgradePlanInfo{Binaries: map[string]string{
+		upgradePlatform(): src + "?checksum=sha256:" + hex.EncodeToString(sum[:]),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(info)
+}
+
+func TestUpgradeBinarySource(t *testing.T) {
+	sum := strings.Repeat("ab", sha256.Size)
+	u, got, err := upgradeBinarySource(`{"binaries":{"any":"https://example.com/seid?checksum=sha256:`+sum+`&v=2"}}`, "linux/arm64", false)
+	if err != nil {
+		t.Fatalf("upgradeBinarySource() error = %v", err)
+	}
+	if got != sum || u.String() != "https://example.com/seid?v=2" {
+		t.Errorf("source = %s, %s; want the any binary without its checksum", u, got)
+	}
+
+	for name, info := range map[string]string{
+		"not json":       "v6.0.0",
+		"no platform":    `{"binaries":{"darwin/arm64":"https://example.com/seid?checksum=sha256:` + sum + `"}}`,
+		"no checksum":    `{"binaries":{"any":"https://example.com/seid"}}`,
+		"md5 checksum":   `{"binaries":{"any":"https://example.com/seid?checksum=md5:00"}}`,
+		"bad scheme":     `{"binaries":{"any":"ftp://example.com/seid?checksum=sha256:` + sum + `"}}`,
+		"file scheme":    `{"binaries":{"any":"file:///tmp/seid?checksum=sha256:` + sum + `"}}`,
+		"short checksum": `{"binaries":{"any":"https://example.com/seid?checksum=sha256:abcd"}}`,
+	} {
+		if _, _, err := upgradeBinarySource(info, "linux/arm64", false); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestUpgradePreparer_FromFileAndIdempotent(t *testing.T) {
+	home := t.TempDir()
+	src := filepath.Join(t.TempDir(), "seid")
+	data := []byte("#!/bin/sh\necho v6\n")
+	if err := os.WriteFile(src, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	params := map[string]any{"upgradeName": "v6.0.0", "upgradeInfo": upgradeInfoFor(t, "file://"+src, data)}
+	p := NewUpgradePreparer(home, "", nil, nil)
+	p.allowFile = true
+
+	raw, err := p.Handler().Handle(context.Background(), params)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	var res PrepareUpgradeResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(home, "cosmovisor", "upgrades", "v6.0.0", "bin", "seid")
+	if res.Path != want || !res.Downloaded {
+		t.Fatalf("result = %+v, want a download to %s", res, want)
+	}
+	if info, err := os.Stat(want); err != nil || info.Mode()&0o111 == 0 {
+		t.Fatalf("placed binary = %v, %v; want it executable", info, err)
+	}
+	if digest, _ := os.ReadFile(want + ".sha256"); strings.TrimSpace(string(digest)) != res.SHA256 {
+		t.Errorf("recorded digest = %q, want %s", digest, res.SHA256)
+	}
+
+	// A crash between placing the binary and recording its digest is
+	// repaired by the next run.
+	if err := os.Remove(want + ".sha256"); err != nil {
+		t.Fatal(err)
+	}
+	raw, err = p.Handler().Handle(context.Background(), params)
+	if err != nil {
+		t.Fatalf("second prepare: %v", err)
+	}
+	if err := json.Unmarshal(raw, &res); err != nil || res.Downloaded {
+		t.Errorf("second prepare = %+v, want the placed binary kept", res)
+	}
+	if err := verifyPreparedBinary(want); err != nil {
+		t.Errorf("verifyPreparedBinary after a repaired prepare: %v", err)
+	}
+}
+
+func TestUpgradePreparer_ChecksumMismatch(t *testing.T) {
+	home := t.TempDir()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("tampered"))
+	}))
+	t.Cleanup(srv.Close)
+	p := NewUpgradePreparer(home, "", srv.Client(), nil)
+
+	_, err := p.Handler().Handle(context.Background(), map[string]any{
+		"upgradeName": "v6.0.0",
+		"upgradeInfo": upgradeInfoFor(t, srv.URL+"/seid", []byte("genuine")),
+	})
+	var te *engine.TaskError
+	if !errors.As(err, &te) || te.Operation != "verify-checksum" || te.Retryable {
+		t.Fatalf("err = %v, want a terminal checksum error", err)
+	}
+	bin := filepath.Join(home, "cosmovisor", "upgrades", "v6.0.0", "bin")
+	if entries, _ := os.ReadDir(bin); len(entries) != 0 {
+		t.Errorf("bin dir holds %v after a mismatch, want nothing placed", entries)
+	}
+}
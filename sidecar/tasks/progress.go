@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+// byteProgress counts bytes moving through one phase of a task and reports
+// the running total to the task's engine.ProgressReporter. Its wrappers are
+// safe for concurrent use, so one counter can sit behind a parallel
+// multipart download.
+type byteProgress struct {
+	reporter engine.ProgressReporter
+	phase    string
+	total    int64
+	done     atomic.Int64
+}
+
+// newByteProgress starts a counter for phase against total bytes (zero when
+// unknown) and reports the phase's start.
+func newByteProgress(ctx context.Context, phase string, total int64) *byteProgress {
+	p := &byteProgress{reporter: engine.ProgressFromContext(ctx), phase: phase, total: total}
+	p.add(0)
+	return p
+}
+
+func (p *byteProgress) add(n int) {
+	done := p.done.Add(int64(n))
+	p.reporter.Report(engine.Progress{Phase: p.phase, BytesDone: done, BytesTotal: p.total})
+}
+
+// reader counts the bytes read through r.
+func (p *byteProgress) reader(r io.Reader) io.Reader {
+	return progressReader{r: r, p: p}
+}
+
+// writerAt counts the bytes written through w.
+func (p *byteProgress) writerAt(w io.WriterAt) io.WriterAt {
+	return progressWriterAt{w: w, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *byteProgress
+}
+
+func (r progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.p.add(n)
+	}
+	return n, err
+}
+
+type progressWriterAt struct {
+	w io.WriterAt
+	p *byteProgress
+}
+
+func (w progressWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	n, err := w.w.WriteAt(b, off)
+	if n > 0 {
+		w.p.add(n)
+	}
+	return n, err
+}
+
+// heightProgress reports heights processed in one phase of a task, counted
+// from first. total is the number of heights the phase covers, zero when it
+// is open-ended. The zero value is usable and reports nothing outside an
+// engine-run handler.
+type heightProgress struct {
+	phase string
+	first int64
+	total int64
+}
+
+// reached reports h as the latest height processed.
+func (p heightProgress) reached(ctx context.Context, h int64) {
+	engine.ProgressFromContext(ctx).Report(engine.Progress{
+		Phase:        p.phase,
+		HeightsDone:  h - p.first + 1,
+		HeightsTotal: p.total,
+		Message:      fmt.Sprintf("height %d", h),
+	})
+}
@@ -0,0 +1,104 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports []engine.Progress
+}
+
+func (r *recordingReporter) Report(p engine.Progress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, p)
+}
+
+func (r *recordingReporter) last(t *testing.T) engine.Progress {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.reports) == 0 {
+		t.Fatal("no progress reported")
+	}
+	return r.reports[len(r.reports)-1]
+}
+
+func TestByteProgressCountsThroughWrappers(t *testing.T) {
+	rec := &recordingReporter{}
+	ctx := engine.WithProgressReporter(context.Background(), rec)
+
+	p := newByteProgress(ctx, "extract", 11)
+	if got := rec.last(t); got.Phase != "extract" || got.BytesDone != 0 || got.BytesTotal != 11 {
+		t.Fatalf("start report = %+v", got)
+	}
+	if _, err := io.Copy(io.Discard, p.reader(bytes.NewReader([]byte("hello ")))); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "part"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := p.writerAt(f).WriteAt([]byte("world"), 6); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.last(t); got.BytesDone != 11 {
+		t.Fatalf("bytes done = %d, want 11", got.BytesDone)
+	}
+}
+
+func TestExtractArchiveReportsProgress(t *testing.T) {
+	rec := &recordingReporter{}
+	ctx := engine.WithProgressReporter(context.Background(), rec)
+
+	homeDir := t.TempDir()
+	setupSnapshotDirs(t, homeDir, []int64{100})
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- writeArchive(ctx, pw, filepath.Join(homeDir, "data", "snapshots"), 100)
+	}()
+	body, err := io.ReadAll(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "snap.tar.gz")
+	if err := os.WriteFile(archive, body, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.last(t); got.Phase != "upload" || got.BytesTotal == 0 || got.BytesDone != got.BytesTotal {
+		t.Fatalf("archive progress = %+v, want every byte archived", got)
+	}
+
+	if err := extractArchive(ctx, archive, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(archive)
+	if got := rec.last(t); got.Phase != "extract" || got.BytesDone != info.Size() || got.BytesTotal != info.Size() {
+		t.Fatalf("extract progress = %+v, want %d of %d", got, info.Size(), info.Size())
+	}
+}
+
+func TestHeightProgressCountsFromFirst(t *testing.T) {
+	rec := &recordingReporter{}
+	ctx := engine.WithProgressReporter(context.Background(), rec)
+
+	heightProgress{phase: "export", first: 1001, total: 1000}.reached(ctx, 1250)
+	if got := rec.last(t); got.Phase != "export" || got.HeightsDone != 250 || got.HeightsTotal != 1000 || got.Message != "height 1250" {
+		t.Fatalf("report = %+v", got)
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 // MarkReadyHandler returns a no-op TaskHandler. When it succeeds, the engine
 // marks itself as ready.
-func MarkReadyHandler() engine.TaskHandler {
+func MarkReadyHandler() engine.Handler {
 	return engine.TypedHandler(func(_ context.Context, _ struct{}) error {
 		return nil
 	})
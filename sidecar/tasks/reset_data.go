@@ -67,9 +67,9 @@ func NewResetDataer(homeDir string) *ResetDataer {
 	}
 }
 
-// Handler returns an engine.TaskHandler for the reset-data task type. Params
+// Handler returns an engine.Handler for the reset-data task type. Params
 // are empty; the result carries the pre-wipe byte count.
-func (d *ResetDataer) Handler() engine.TaskHandler {
+func (d *ResetDataer) Handler() engine.Handler {
 	return engine.TypedHandlerWithResult(func(ctx context.Context, _ struct{}) (ResetDataResult, error) {
 		return d.reset(ctx)
 	})
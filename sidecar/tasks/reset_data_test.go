@@ -52,7 +52,7 @@ func exists(path string) bool {
 
 func runReset(t *testing.T, d *ResetDataer) ResetDataResult {
 	t.Helper()
-	raw, err := d.Handler()(context.Background(), nil)
+	raw, err := d.Handler().Handle(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("reset-data: %v", err)
 	}
@@ -166,7 +166,7 @@ func TestResetData_MeasurementFailureYieldsUnknownSize(t *testing.T) {
 
 func TestResetData_RefusesWhenRPCServing(t *testing.T) {
 	home := seedHome(t)
-	_, err := newResetDataer(home, true).Handler()(context.Background(), nil)
+	_, err := newResetDataer(home, true).Handler().Handle(context.Background(), nil)
 	if err == nil {
 		t.Fatal("expected refusal when seid RPC is serving")
 	}
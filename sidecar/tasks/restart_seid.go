@@ -150,9 +150,9 @@ func seidRPCUp(ctx context.Context, c *rpc.StatusClient) bool {
 	return true
 }
 
-// Handler returns an engine.TaskHandler for the restart-seid task type.
+// Handler returns an engine.Handler for the restart-seid task type.
 // Params are empty: restart-seid is a fire-and-confirm operation.
-func (r *RestartSeider) Handler() engine.TaskHandler {
+func (r *RestartSeider) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, _ struct{}) error {
 		if err := r.stopSeid(ctx); err != nil {
 			return err
@@ -39,6 +39,7 @@ type comparisonLoop struct {
 	height       int64
 	pageBuf      []shadow.CompareResult
 	pollInterval time.Duration
+	progress     heightProgress
 }
 
 // ExportAndCompare runs a continuous comparison between the local shadow node
@@ -102,14 +103,16 @@ func (e *ResultExporter) newComparisonLoop(ctx context.Context, cfg ResultExport
 	}
 
 	last := e.readExportState()
+	start := last.LastExportedHeight + 1
 	return &comparisonLoop{
 		exporter:     e,
 		comparator:   shadow.NewComparator(cfg.RPCEndpoint, cfg.CanonicalRPC, compOpts...),
 		uploader:     uploader,
 		cfg:          cfg,
 		prefix:       normalizePrefix(cfg.Prefix),
-		height:       last.LastExportedHeight + 1,
+		height:       start,
 		pollInterval: comparePollInterval,
+		progress:     heightProgress{phase: "compare", first: start},
 	}, nil
 }
 
@@ -197,6 +200,8 @@ func (l *comparisonLoop) waitForBlocks(ctx context.Context) (int64, error) {
 }
 
 func (l *comparisonLoop) compareBlocksUpTo(ctx context.Context, latestHeight int64) (diverged bool, _ error) {
+	// The survey tails the chain, so its total grows with each batch.
+	l.progress.total = latestHeight - l.progress.first + 1
 	for l.height <= latestHeight {
 		if err := ctx.Err(); err != nil {
 			return false, err
@@ -230,6 +235,7 @@ func (l *comparisonLoop) compareBlocksUpTo(ctx context.Context, latestHeight int
 			return false, err
 		}
 
+		l.progress.reached(ctx, l.height)
 		l.height++
 	}
 	return false, nil
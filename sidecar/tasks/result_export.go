@@ -98,7 +98,7 @@ func NewResultExporter(homeDir, chainID, podName string, factory seis3.UploaderF
 	return &ResultExporter{homeDir: homeDir, chainID: chainID, podName: podName, s3UploaderFactory: factory}
 }
 
-func (e *ResultExporter) Handler() engine.TaskHandler {
+func (e *ResultExporter) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, cfg ResultExportRequest) error {
 		if cfg.Bucket == "" {
 			return fmt.Errorf("result-export: missing required param 'bucket'")
@@ -154,6 +154,7 @@ func (e *ResultExporter) Export(ctx context.Context, cfg ResultExportRequest) er
 		return nil
 	}
 
+	progress := heightProgress{phase: "export", first: startHeight, total: int64(fullPages * defaultPageSize)}
 	for page := 0; page < fullPages; page++ {
 		pageStart := startHeight + int64(page*defaultPageSize)
 		pageEnd := pageStart + int64(defaultPageSize) - 1
@@ -163,7 +164,7 @@ func (e *ResultExporter) Export(ctx context.Context, cfg ResultExportRequest) er
 			"end", pageEnd,
 			"bucket", cfg.Bucket)
 
-		if err := e.exportPage(ctx, rpcClient, uploader, cfg.Bucket, cfg.Region, prefix, pageStart, pageEnd); err != nil {
+		if err := e.exportPage(ctx, rpcClient, uploader, cfg.Bucket, cfg.Region, prefix, pageStart, pageEnd, progress); err != nil {
 			return fmt.Errorf("exporting page %d-%d: %w", pageStart, pageEnd, err)
 		}
 
@@ -191,12 +192,13 @@ func (e *ResultExporter) exportPage(
 	uploader seis3.Uploader,
 	bucket, region, prefix string,
 	start, end int64,
+	progress heightProgress,
 ) error {
 	key := fmt.Sprintf("%s%d-%d.ndjson.gz", prefix, start, end)
 
 	var collectErr error
 	_, uploadErr := seis3.StreamGzipFunc(ctx, uploader, bucket, key, func(w io.Writer) error {
-		collectErr = e.collectResults(ctx, client, w, start, end)
+		collectErr = e.collectResults(ctx, client, w, start, end, progress)
 		return collectErr
 	})
 	switch {
@@ -218,7 +220,8 @@ func (e *ResultExporter) exportPage(
 // collectResults queries block_results for each height and writes one NDJSON
 // line per block to w. Each line is a JSON object with height, time, and the
 // raw block_results response. gzip/pipe/checksum are owned by the s3 helper.
-func (e *ResultExporter) collectResults(ctx context.Context, client *rpc.Client, w io.Writer, start, end int64) error {
+// Each written height is reported to progress.
+func (e *ResultExporter) collectResults(ctx context.Context, client *rpc.Client, w io.Writer, start, end int64, progress heightProgress) error {
 	for h := start; h <= end; h++ {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -244,6 +247,7 @@ func (e *ResultExporter) collectResults(ctx context.Context, client *rpc.Client,
 		if _, err := w.Write(line); err != nil {
 			return fmt.Errorf("%w at height %d: %w", errSinkWrite, h, err)
 		}
+		progress.reached(ctx, h)
 	}
 
 	return nil
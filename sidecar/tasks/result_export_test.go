@@ -68,7 +68,7 @@ func TestExportPage_ProducerErrorNotClassifiedAsS3(t *testing.T) {
 	defer srv.Close()
 
 	e := NewResultExporter(t.TempDir(), "test-1", "pod-0", mockResultUploaderFactory())
-	err := e.exportPage(context.Background(), rpc.NewClient(srv.URL, nil), &mockResultUploader{}, "bkt", "us-east-1", "p/", 100, 100)
+	err := e.exportPage(context.Background(), rpc.NewClient(srv.URL, nil), &mockResultUploader{}, "bkt", "us-east-1", "p/", 100, 100, heightProgress{})
 	if err == nil {
 		t.Fatal("expected a producer error")
 	}
@@ -90,7 +90,7 @@ func TestExportPage_UploadErrorKeepsS3Classification(t *testing.T) {
 
 	up := drainingFailingUploader{err: errors.New("connection reset by peer")}
 	e := NewResultExporter(t.TempDir(), "test-1", "pod-0", mockResultUploaderFactory())
-	err := e.exportPage(context.Background(), rpc.NewClient(srv.URL, nil), up, "bkt", "us-east-1", "p/", 100, 100)
+	err := e.exportPage(context.Background(), rpc.NewClient(srv.URL, nil), up, "bkt", "us-east-1", "p/", 100, 100, heightProgress{})
 	if err == nil {
 		t.Fatal("expected an upload error")
 	}
@@ -274,7 +274,7 @@ func TestExportHandler_MissingParams(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := handler(context.Background(), tc.params)
+			_, err := handler.Handle(context.Background(), tc.params)
 			if err == nil {
 				t.Fatal("expected error, got nil")
 			}
@@ -323,7 +323,7 @@ func TestHandlerRouting_WithCanonicalRPC_CallsExportAndCompare(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
 
-	_, err := e.Handler()(ctx, map[string]any{
+	_, err := e.Handler().Handle(ctx, map[string]any{
 		"bucket":       "test-bucket",
 		"region":       "us-east-1",
 		"rpcEndpoint":  srv.URL,
@@ -341,7 +341,7 @@ func TestHandlerRouting_WithoutCanonicalRPC_CallsExport(t *testing.T) {
 	tmpDir := t.TempDir()
 	e := NewResultExporter(tmpDir, "test-1", "test-pod-0", mockResultUploaderFactory())
 
-	_, err := e.Handler()(context.Background(), map[string]any{
+	_, err := e.Handler().Handle(context.Background(), map[string]any{
 		"bucket":      "test-bucket",
 		"region":      "us-east-1",
 		"rpcEndpoint": srv.URL,
@@ -93,8 +93,9 @@ type SignAndBroadcastResult struct {
 }
 
 // TerminalError marks a sign-tx error as non-retryable (malformed input,
-// chain-confusion, CheckTx rejection, missing key). The engine has no
-// retry policy yet, but callers should not implement ad-hoc retry on top.
+// chain-confusion, CheckTx rejection, missing key). The engine's retry
+// policy never re-executes a task that fails with one; callers should not
+// implement ad-hoc retry on top.
 type TerminalError struct {
 	Err error
 }
@@ -102,6 +103,9 @@ type TerminalError struct {
 func (e *TerminalError) Error() string { return e.Err.Error() }
 func (e *TerminalError) Unwrap() error { return e.Err }
 
+// Terminal marks the error final for engine.Retryable.
+func (e *TerminalError) Terminal() bool { return true }
+
 // Terminal wraps err as TerminalError, or returns nil when err is nil.
 func Terminal(err error) error {
 	if err == nil {
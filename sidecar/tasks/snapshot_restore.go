@@ -72,8 +72,8 @@ func NewSnapshotRestorer(homeDir, bucket, region, chainID string, clientFactory
 	}, nil
 }
 
-// Handler returns an engine.TaskHandler for the snapshot-restore task.
-func (r *SnapshotRestorer) Handler() engine.TaskHandler {
+// Handler returns an engine.Handler for the snapshot-restore task.
+func (r *SnapshotRestorer) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, req SnapshotRestoreRequest) error {
 		return r.Restore(ctx, req.TargetHeight)
 	})
@@ -104,7 +104,7 @@ func (r *SnapshotRestorer) Restore(ctx context.Context, targetHeight int64) erro
 		return fmt.Errorf("building S3 lister: %w", err)
 	}
 
-	snapshotKey, err := resolveKeyForHeight(ctx, lister, r.bucket, prefix, r.region, targetHeight)
+	snapshotKey, snapshotSize, err := resolveKeyForHeight(ctx, lister, r.bucket, prefix, r.region, targetHeight)
 	if err != nil {
 		return err
 	}
@@ -129,7 +129,7 @@ func (r *SnapshotRestorer) Restore(ctx context.Context, targetHeight int64) erro
 	_, err = client.DownloadObject(ctx, &transfermanager.DownloadObjectInput{
 		Bucket:   aws.String(r.bucket),
 		Key:      aws.String(snapshotKey),
-		WriterAt: tmpFile,
+		WriterAt: newByteProgress(ctx, "download", snapshotSize).writerAt(tmpFile),
 	})
 	_ = tmpFile.Close()
 	if err != nil {
@@ -157,10 +157,11 @@ func (r *SnapshotRestorer) Restore(ctx context.Context, targetHeight int64) erro
 }
 
 // resolveKeyForHeight lists snapshot objects under prefix and returns the key
-// with the highest parsed height. When targetHeight > 0 it caps the search at
-// that height; targetHeight == 0 picks the highest available snapshot.
-func resolveKeyForHeight(ctx context.Context, lister seis3.ObjectLister, bucket, prefix, region string, targetHeight int64) (string, error) {
-	var bestHeight int64
+// with the highest parsed height, and its size. When targetHeight > 0 it caps
+// the search at that height; targetHeight == 0 picks the highest available
+// snapshot.
+func resolveKeyForHeight(ctx context.Context, lister seis3.ObjectLister, bucket, prefix, region string, targetHeight int64) (string, int64, error) {
+	var bestHeight, bestSize int64
 	var bestKey string
 
 	var continuationToken *string
@@ -171,7 +172,7 @@ func resolveKeyForHeight(ctx context.Context, lister seis3.ObjectLister, bucket,
 			ContinuationToken: continuationToken,
 		})
 		if err != nil {
-			return "", seis3.ClassifyS3Error("snapshot-restore", bucket, prefix, region, err)
+			return "", 0, seis3.ClassifyS3Error("snapshot-restore", bucket, prefix, region, err)
 		}
 
 		for _, obj := range output.Contents {
@@ -188,6 +189,7 @@ func resolveKeyForHeight(ctx context.Context, lister seis3.ObjectLister, bucket,
 			if h > bestHeight {
 				bestHeight = h
 				bestKey = *obj.Key
+				bestSize = aws.ToInt64(obj.Size)
 			}
 		}
 
@@ -199,14 +201,14 @@ func resolveKeyForHeight(ctx context.Context, lister seis3.ObjectLister, bucket,
 
 	if bestKey == "" {
 		if targetHeight > 0 {
-			return "", fmt.Errorf("no snapshot found at or below height %d in s3://%s/%s", targetHeight, bucket, prefix)
+			return "", 0, fmt.Errorf("no snapshot found at or below height %d in s3://%s/%s", targetHeight, bucket, prefix)
 		}
-		return "", fmt.Errorf("no snapshots found in s3://%s/%s", bucket, prefix)
+		return "", 0, fmt.Errorf("no snapshots found in s3://%s/%s", bucket, prefix)
 	}
 
 	restoreLog.Info("resolved snapshot",
 		"targetHeight", targetHeight, "snapshotHeight", bestHeight, "key", bestKey)
-	return bestKey, nil
+	return bestKey, bestSize, nil
 }
 
 func parseHeightFromKey(key string) int64 {
@@ -221,7 +223,8 @@ func parseHeightFromKey(key string) int64 {
 	return h
 }
 
-// extractArchive opens a .tar.gz file and extracts it to destDir.
+// extractArchive opens a .tar.gz file and extracts it to destDir, reporting
+// progress as compressed bytes read against the archive's size.
 func extractArchive(ctx context.Context, archivePath, destDir string) error {
 	f, err := os.Open(archivePath)
 	if err != nil {
@@ -229,7 +232,11 @@ func extractArchive(ctx context.Context, archivePath, destDir string) error {
 	}
 	defer func() { _ = f.Close() }()
 
-	return extractTarStream(ctx, f, destDir)
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	return extractTarStream(ctx, newByteProgress(ctx, "extract", size).reader(f), destDir)
 }
 
 func extractTarStream(ctx context.Context, r io.Reader, destDir string) error {
@@ -114,17 +114,17 @@ func NewSnapshotUploader(homeDir, bucket, region, chainID string, uploadInterval
 	}, nil
 }
 
-// Handler returns an engine.TaskHandler for the snapshot-upload task.
+// Handler returns an engine.Handler for the snapshot-upload task.
 // The handler runs in a loop, attempting an upload on each tick and
 // sleeping for the configured interval between attempts. It stays
 // running until the context is cancelled.
-func (u *SnapshotUploader) Handler() engine.TaskHandler {
+func (u *SnapshotUploader) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, _ SnapshotUploadRequest) error {
 		return u.runLoop(ctx)
 	})
 }
 
-// OnceHandler returns an engine.TaskHandler for the one-shot snapshot-upload
+// OnceHandler returns an engine.Handler for the one-shot snapshot-upload
 // task. It runs Upload exactly once and returns the structured result so the
 // task reaches a real terminal (completed with an outcome, or failed with the
 // error). The execution is bounded by a handler-internal deadline so a wedged
@@ -132,7 +132,7 @@ func (u *SnapshotUploader) Handler() engine.TaskHandler {
 // deadline lives on a child context: it surfaces as context.DeadlineExceeded,
 // which the engine persists as Failed (its cancellation-suppression guard keys
 // only on context.Canceled).
-func (u *SnapshotUploader) OnceHandler(timeout time.Duration) engine.TaskHandler {
+func (u *SnapshotUploader) OnceHandler(timeout time.Duration) engine.Handler {
 	if timeout <= 0 {
 		timeout = defaultUploadTimeout
 	}
@@ -330,7 +330,9 @@ func pickUploadCandidate(snapshotsDir string) (int64, error) {
 
 // writeArchive streams a tar.gz archive of the snapshot at the given height
 // into wc (typically the write half of an io.Pipe). It always closes wc when
-// done, propagating any archiving error so the reader side sees it.
+// done, propagating any archiving error so the reader side sees it. Progress
+// is reported as file bytes archived against the snapshot's size on disk;
+// the archive streams to S3 as it is written, so this tracks the upload.
 func writeArchive(ctx context.Context, wc io.WriteCloser, snapshotsDir string, height int64) (retErr error) {
 	defer func() {
 		if retErr != nil {
@@ -344,20 +346,22 @@ func writeArchive(ctx context.Context, wc io.WriteCloser, snapshotsDir string, h
 	tw := tar.NewWriter(gw)
 
 	heightDir := filepath.Join(snapshotsDir, strconv.FormatInt(height, 10))
-	if err := addDirToTar(ctx, tw, heightDir, strconv.FormatInt(height, 10)); err != nil {
+	metadataPath := filepath.Join(snapshotsDir, "metadata.db")
+	progress := newByteProgress(ctx, "upload", treeSize(heightDir)+treeSize(metadataPath))
+
+	if err := addDirToTar(ctx, tw, progress, heightDir, strconv.FormatInt(height, 10)); err != nil {
 		return err
 	}
 
 	// metadata.db has been a LevelDB directory in cosmos-sdk for several
 	// versions, but the API allows it to be a single file too. Dispatch
 	// on whichever we observe so a future revert doesn't break us either way.
-	metadataPath := filepath.Join(snapshotsDir, "metadata.db")
 	if info, err := os.Stat(metadataPath); err == nil {
 		var addErr error
 		if info.IsDir() {
-			addErr = addDirToTar(ctx, tw, metadataPath, "metadata.db")
+			addErr = addDirToTar(ctx, tw, progress, metadataPath, "metadata.db")
 		} else {
-			addErr = addFileToTar(ctx, tw, metadataPath, "metadata.db", info)
+			addErr = addFileToTar(ctx, tw, progress, metadataPath, "metadata.db", info)
 		}
 		if addErr != nil {
 			return fmt.Errorf("archiving metadata.db: %w", addErr)
@@ -373,7 +377,7 @@ func writeArchive(ctx context.Context, wc io.WriteCloser, snapshotsDir string, h
 	return nil
 }
 
-func addDirToTar(ctx context.Context, tw *tar.Writer, dir, base string) error {
+func addDirToTar(ctx context.Context, tw *tar.Writer, progress *byteProgress, dir, base string) error {
 	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -405,12 +409,25 @@ func addDirToTar(ctx context.Context, tw *tar.Writer, dir, base string) error {
 			return err
 		}
 		defer func() { _ = f.Close() }()
-		_, err = io.Copy(tw, f)
+		_, err = io.Copy(tw, progress.reader(f))
 		return err
 	})
 }
 
-func addFileToTar(ctx context.Context, tw *tar.Writer, path, name string, info os.FileInfo) error {
+// treeSize returns the total size of the regular files at or under path, or
+// zero when it cannot be read; it only sizes the progress total.
+func treeSize(path string) int64 {
+	var size int64
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+func addFileToTar(ctx context.Context, tw *tar.Writer, progress *byteProgress, path, name string, info os.FileInfo) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -427,7 +444,7 @@ func addFileToTar(ctx context.Context, tw *tar.Writer, path, name string, info o
 		return err
 	}
 	defer func() { _ = f.Close() }()
-	_, err = io.Copy(tw, f)
+	_, err = io.Copy(tw, progress.reader(f))
 	return err
 }
 
@@ -372,7 +372,7 @@ func TestOnceHandler_ReturnsDistinguishableOutcomes(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		raw, err := uploader.OnceHandler(time.Minute)(context.Background(), nil)
+		raw, err := uploader.OnceHandler(time.Minute).Handle(context.Background(), nil)
 		if err != nil {
 			t.Fatalf("handler error = %v", err)
 		}
@@ -389,7 +389,7 @@ func TestOnceHandler_ReturnsDistinguishableOutcomes(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		raw, err := uploader.OnceHandler(time.Minute)(context.Background(), nil)
+		raw, err := uploader.OnceHandler(time.Minute).Handle(context.Background(), nil)
 		if err != nil {
 			t.Fatalf("handler error = %v", err)
 		}
@@ -410,7 +410,7 @@ func TestOnceHandler_ReturnsDistinguishableOutcomes(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		raw, err := uploader.OnceHandler(time.Minute)(context.Background(), nil)
+		raw, err := uploader.OnceHandler(time.Minute).Handle(context.Background(), nil)
 		if err != nil {
 			t.Fatalf("handler error = %v", err)
 		}
@@ -435,7 +435,7 @@ func TestOnceHandler_DeadlineFailsCleanly(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = uploader.OnceHandler(50*time.Millisecond)(context.Background(), nil)
+	_, err = uploader.OnceHandler(50*time.Millisecond).Handle(context.Background(), nil)
 	if !errors.Is(err, context.DeadlineExceeded) {
 		t.Fatalf("handler error = %v, want context.DeadlineExceeded", err)
 	}
@@ -52,8 +52,8 @@ func NewStateSyncConfigurer(homeDir string, client rpc.HTTPDoer) *StateSyncConfi
 	return &StateSyncConfigurer{homeDir: homeDir, httpClient: client}
 }
 
-// Handler returns an engine.TaskHandler.
-func (s *StateSyncConfigurer) Handler() engine.TaskHandler {
+// Handler returns an engine.Handler.
+func (s *StateSyncConfigurer) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, params StateSyncRequest) error {
 		return s.Configure(ctx, params)
 	})
@@ -100,9 +100,9 @@ func NewStopSeider() *StopSeider {
 	}
 }
 
-// Handler returns an engine.TaskHandler for the stop-seid task type. Params are
+// Handler returns an engine.Handler for the stop-seid task type. Params are
 // empty: stop-seid is a fire-and-confirm operation.
-func (s *StopSeider) Handler() engine.TaskHandler {
+func (s *StopSeider) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, _ struct{}) error {
 		return s.stopper.stop(ctx)
 	})
@@ -28,7 +28,7 @@ func TestStopSeider_StopsAndDoesNotWaitForUp(t *testing.T) {
 	sig.alive.Store(false) // exits immediately after SIGTERM
 
 	// neverUp would hang restart-seid's waitForUp; stop-seid must ignore it.
-	if _, err := newStopSeider(sig, neverUp, time.Second).Handler()(context.Background(), nil); err != nil {
+	if _, err := newStopSeider(sig, neverUp, time.Second).Handler().Handle(context.Background(), nil); err != nil {
 		t.Fatalf("expected success without waiting for up, got %v", err)
 	}
 	if len(sig.signals) != 1 || sig.signals[0] != syscall.SIGTERM {
@@ -40,7 +40,7 @@ func TestStopSeider_GraceTimeoutFailsWithoutSIGKILL(t *testing.T) {
 	sig := &fakeSignaler{findPID: 42}
 	sig.alive.Store(true) // never exits
 
-	_, err := newStopSeider(sig, neverUp, 50*time.Millisecond).Handler()(context.Background(), nil)
+	_, err := newStopSeider(sig, neverUp, 50*time.Millisecond).Handler().Handle(context.Background(), nil)
 	if err == nil || !strings.Contains(err.Error(), "still alive") {
 		t.Fatalf("expected still-alive failure, got %v", err)
 	}
@@ -52,7 +52,7 @@ func TestStopSeider_GraceTimeoutFailsWithoutSIGKILL(t *testing.T) {
 func TestStopSeider_NotFoundRPCDownIsSuccess(t *testing.T) {
 	sig := &fakeSignaler{findErr: fmt.Errorf("process \"seid\" not found in /proc")}
 
-	if _, err := newStopSeider(sig, neverUp, time.Second).Handler()(context.Background(), nil); err != nil {
+	if _, err := newStopSeider(sig, neverUp, time.Second).Handler().Handle(context.Background(), nil); err != nil {
 		t.Fatalf("expected success when seid absent and RPC down, got %v", err)
 	}
 	if len(sig.signals) != 0 {
@@ -63,7 +63,7 @@ func TestStopSeider_NotFoundRPCDownIsSuccess(t *testing.T) {
 func TestStopSeider_NotFoundRPCUpRefuses(t *testing.T) {
 	sig := &fakeSignaler{findErr: fmt.Errorf("process \"seid\" not found in /proc")}
 
-	_, err := newStopSeider(sig, upAfter(0), time.Second).Handler()(context.Background(), nil)
+	_, err := newStopSeider(sig, upAfter(0), time.Second).Handler().Handle(context.Background(), nil)
 	if err == nil {
 		t.Fatal("expected refusal when RPC serves but process not found")
 	}
@@ -2,10 +2,13 @@ package tasks
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
 )
 
 // TestDeserialize_SnapshotRestore verifies that the snapshot-restore handler
@@ -24,7 +27,7 @@ func TestDeserialize_SnapshotRestore(t *testing.T) {
 	handler := restorer.Handler()
 
 	// The handler should succeed (skip via marker) without a parse error.
-	_, err = handler(context.Background(), map[string]any{})
+	_, err = handler.Handle(context.Background(), map[string]any{})
 	if err != nil {
 		t.Fatalf("snapshot-restore handler returned error: %v", err)
 	}
@@ -52,7 +55,7 @@ persistent-peers = ""
 		},
 	}
 
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err != nil {
 		t.Fatalf("config-patch handler returned error: %v", err)
 	}
@@ -81,12 +84,12 @@ func TestDeserialize_AssembleGenesis(t *testing.T) {
 	}
 
 	// This will fail at the S3 download step (no real S3), but if it gets
-	// past param parsing without a "parsing params" error, deserialization worked.
-	_, err := handler(context.Background(), params)
+	// past param parsing without an invalid-params error, deserialization worked.
+	_, err := handler.Handle(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error (no S3 client), got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 }
@@ -110,12 +113,12 @@ func TestDeserialize_AwaitCondition(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	_, err := handler(ctx, params)
+	_, err := handler.Handle(ctx, params)
 	if err == nil {
 		t.Fatal("expected context error, got nil")
 	}
-	// If we get a context error (not a parsing error), deserialization succeeded.
-	if strings.Contains(err.Error(), "parsing params") {
+	// If we get a context error (not an invalid-params error), deserialization succeeded.
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 }
@@ -135,7 +138,7 @@ func TestDeserialize_ConfigApply(t *testing.T) {
 		},
 	}
 
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err != nil {
 		t.Fatalf("config-apply handler returned error: %v", err)
 	}
@@ -153,19 +156,16 @@ func TestDeserialize_ConfigReload(t *testing.T) {
 	// config-reload needs a valid on-disk config to read.
 	// We just check that deserialization doesn't fail when fields is empty
 	// (it'll fail with a validation error, not a parse error).
-	handler := NewConfigReloader(t.TempDir()).Handler()
+	handler := NewConfigReloader(t.TempDir(), "").Handler()
 
 	params := map[string]any{
 		"fields": map[string]any{},
 	}
 
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for empty fields, got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
-		t.Fatalf("deserialization failed: %v", err)
-	}
 	if !strings.Contains(err.Error(), "at least one field") {
 		t.Errorf("expected 'at least one field' error, got: %v", err)
 	}
@@ -203,7 +203,7 @@ func TestDeserialize_ConfigureGenesis(t *testing.T) {
 	fetcher := NewGenesisFetcher(homeDir, "pacific-1", "test-bucket", "us-east-2", nil)
 	handler := fetcher.Handler()
 
-	_, err := handler(context.Background(), map[string]any{})
+	_, err := handler.Handle(context.Background(), map[string]any{})
 	if err != nil {
 		t.Fatalf("unexpected error for embedded chain: %v", err)
 	}
@@ -218,11 +218,11 @@ func TestDeserialize_UploadArtifacts(t *testing.T) {
 		"nodeName": "",
 	}
 
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for empty nodeName, got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 	if !strings.Contains(err.Error(), "missing required param 'nodeName'") {
@@ -240,11 +240,11 @@ func TestDeserialize_GenerateIdentity(t *testing.T) {
 		"moniker": "val-0",
 	}
 
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for empty chainId, got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 	if !strings.Contains(err.Error(), "missing required param 'chainId'") {
@@ -259,11 +259,11 @@ func TestDeserialize_SetGenesisPeers(t *testing.T) {
 
 	// The handler will try to download peers.json from S3 — that will fail
 	// since there's no real S3 client. But it proves deserialization worked.
-	_, err := handler(context.Background(), map[string]any{})
+	_, err := handler.Handle(context.Background(), map[string]any{})
 	if err == nil {
 		t.Fatal("expected error (no S3), got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 }
@@ -284,11 +284,11 @@ func TestDeserialize_StateSync(t *testing.T) {
 	}
 
 	// Will fail because there are no peers, but deserialization should succeed.
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error (no peers), got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 }
@@ -303,11 +303,11 @@ func TestDeserialize_ResultExport(t *testing.T) {
 		"region": "us-east-1",
 	}
 
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for empty bucket, got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 	if !strings.Contains(err.Error(), "missing required param 'bucket'") {
@@ -326,11 +326,11 @@ func TestDeserialize_GenerateGentx(t *testing.T) {
 		"accountBalance": "10000usei",
 	}
 
-	_, err := handler(context.Background(), params)
+	_, err := handler.Handle(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for empty chainId, got nil")
 	}
-	if strings.Contains(err.Error(), "parsing params") {
+	if errors.Is(err, engine.ErrInvalidParams) {
 		t.Fatalf("deserialization failed: %v", err)
 	}
 	if !strings.Contains(err.Error(), "missing required param 'chainId'") {
@@ -50,8 +50,8 @@ func NewGenesisArtifactUploader(homeDir, bucket, region, chainID string, factory
 	}
 }
 
-// Handler returns an engine.TaskHandler for the upload-genesis-artifacts task type.
-func (u *GenesisArtifactUploader) Handler() engine.TaskHandler {
+// Handler returns an engine.Handler for the upload-genesis-artifacts task type.
+func (u *GenesisArtifactUploader) Handler() engine.Handler {
 	return engine.TypedHandler(func(ctx context.Context, cfg UploadArtifactsRequest) error {
 		if markerExists(u.homeDir, artifactUploadMarkerFile) {
 			artifactLog.Debug("already completed, skipping")
@@ -0,0 +1,250 @@
+package wire
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema the sidecar publishes for task
+// params and results. It describes shapes only: which fields exist and
+// what JSON type each holds. Whether a field is required, and which
+// values it accepts, is left to the handler, since Go struct tags do not
+// say. The zero Schema accepts any value.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+var (
+	timeType        = reflect.TypeFor[time.Time]()
+	rawMessageType  = reflect.TypeFor[json.RawMessage]()
+	jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// SchemaOf derives the Schema of the JSON encoding/json produces and
+// accepts for t, following the same field names, omissions and
+// embedding rules. Types with their own JSON decoding are described as
+// accepting any value.
+func SchemaOf(t reflect.Type) *Schema {
+	return schemaOf(t, map[reflect.Type]bool{})
+}
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case reflect.PointerTo(t).Implements(jsonUnmarshaler):
+		return &Schema{}
+	case reflect.PointerTo(t).Implements(textUnmarshaler):
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		addFields(s, t, visiting)
+		return s
+	default:
+		// interface{} and anything else encoding/json handles dynamically.
+		return &Schema{}
+	}
+}
+
+// addFields adds t's JSON fields to s, flattening untagged embedded
+// structs as encoding/json does. Outer fields win over embedded ones.
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, ft)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs := schemaOf(f.Type, visiting)
+		if slices.Contains(strings.Split(opts, ","), "string") && fs.Type != "" && fs.Type != "object" && fs.Type != "array" {
+			fs = &Schema{Type: "string"}
+		}
+		s.Properties[name] = fs
+	}
+	for _, et := range embedded {
+		inner := &Schema{Properties: map[string]*Schema{}}
+		addFields(inner, et, visiting)
+		for name, fs := range inner.Properties {
+			if _, ok := s.Properties[name]; !ok {
+				s.Properties[name] = fs
+			}
+		}
+	}
+}
+
+// Validate checks v, a value decoded from JSON into any (maps, slices,
+// float64 or json.Number, string, bool, nil), against s. It reports the
+// first mismatch with the path to it, rooted at "params". Null is
+// accepted anywhere, as encoding/json accepts it into any Go value. An
+// object whose schema lists Properties rejects keys it does not list, as
+// the sidecar does when it decodes params.
+func (s *Schema) Validate(v any) error {
+	return s.validate("params", v)
+}
+
+// Property returns the schema of the named property, matching names
+// case-insensitively as encoding/json does, or nil when s has none.
+func (s *Schema) Property(name string) *Schema {
+	if fs, ok := s.Properties[name]; ok {
+		return fs
+	}
+	for k, fs := range s.Properties {
+		if strings.EqualFold(k, name) {
+			return fs
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, v any) error {
+	if s == nil || v == nil {
+		return nil
+	}
+	switch s.Type {
+	case "":
+		return nil
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return mismatch(path, s.Type, v)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return mismatch(path, s.Type, v)
+		}
+	case "number", "integer":
+		n, ok := number(v)
+		if !ok {
+			return mismatch(path, s.Type, v)
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			return fmt.Errorf("%s: want integer, got %v", path, n)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("%s: %v is below the minimum %v", path, n, *s.Minimum)
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return mismatch(path, s.Type, v)
+		}
+		for i, item := range items {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return mismatch(path, s.Type, v)
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fs := s.AdditionalProperties
+			if fs == nil && s.Properties != nil {
+				if fs = s.Property(k); fs == nil {
+					return fmt.Errorf("%s.%s: unknown field", path, k)
+				}
+			}
+			if err := fs.validate(path+"."+k, obj[k]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func mismatch(path, want string, got any) error {
+	return fmt.Errorf("%s: want %s, got %s", path, want, jsonType(got))
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64, json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
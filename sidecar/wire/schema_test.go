@@ -0,0 +1,133 @@
+package wire
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaBase struct {
+	ChainID string `json:"chainId"`
+	Shadow  int    `json:"shadowed"`
+}
+
+type schemaRequest struct {
+	schemaBase
+	Name     string            `json:"name"`
+	Count    uint64            `json:"count,omitempty"`
+	Ratio    float64           `json:"ratio"`
+	Enabled  *bool             `json:"enabled,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	At       time.Time         `json:"at"`
+	Raw      json.RawMessage   `json:"raw"`
+	Any      any               `json:"any"`
+	Quoted   int               `json:"quoted,string"`
+	Shadowed string            `json:"shadowed"`
+	Skipped  string            `json:"-"`
+	Untagged string
+	private  string
+}
+
+func TestSchemaOf(t *testing.T) {
+	s := SchemaOf(reflect.TypeFor[schemaRequest]())
+	if s.Type != "object" {
+		t.Fatalf("type = %q, want object", s.Type)
+	}
+	want := map[string]string{
+		"chainId": "string", "name": "string", "count": "integer", "ratio": "number",
+		"enabled": "boolean", "tags": "array", "labels": "object", "at": "string",
+		"raw": "", "any": "", "quoted": "string", "shadowed": "string", "Untagged": "string",
+	}
+	if len(s.Properties) != len(want) {
+		t.Errorf("properties = %v, want %d of them", keys(s.Properties), len(want))
+	}
+	for name, typ := range want {
+		p, ok := s.Properties[name]
+		if !ok {
+			t.Errorf("missing property %q", name)
+			continue
+		}
+		if p.Type != typ {
+			t.Errorf("%s: type = %q, want %q", name, p.Type, typ)
+		}
+	}
+	if m := s.Properties["count"].Minimum; m == nil || *m != 0 {
+		t.Errorf("unsigned count has no minimum 0")
+	}
+	if s.Properties["tags"].Items.Type != "string" || s.Properties["labels"].AdditionalProperties.Type != "string" {
+		t.Errorf("tags/labels element schemas wrong: %+v %+v", s.Properties["tags"], s.Properties["labels"])
+	}
+	if s.Properties["at"].Format != "date-time" {
+		t.Errorf("time.Time format = %q", s.Properties["at"].Format)
+	}
+
+	type node struct {
+		Children []node `json:"children"`
+	}
+	if s := SchemaOf(reflect.TypeFor[node]()); s.Properties["children"].Items.Type != "object" {
+		t.Errorf("recursive type: %+v", s)
+	}
+}
+
+func keys(m map[string]*Schema) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s := SchemaOf(reflect.TypeFor[schemaRequest]())
+	decode := func(raw string) map[string]any {
+		var v map[string]any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for _, ok := range []string{
+		`{}`,
+		`{"name":"a","count":3,"ratio":0.5,"tags":["x"],"labels":{"k":"v"},"raw":{"any":[1]},"any":7}`,
+		`{"enabled":null,"COUNT":2}`,
+	} {
+		if err := s.Validate(decode(ok)); err != nil {
+			t.Errorf("%s: %v", ok, err)
+		}
+	}
+	for raw, wantPath := range map[string]string{
+		`{"name":1}`:              "params.name",
+		`{"count":1.5}`:           "params.count",
+		`{"count":-1}`:            "params.count",
+		`{"tags":["x",2]}`:        "params.tags[1]",
+		`{"labels":{"k":false}}`:  "params.labels.k",
+		`{"enabled":"yes"}`:       "params.enabled",
+		`{"chainId":["sei"]}`:     "params.chainId",
+		`{"at":1700000000}`:       "params.at",
+		`{"quoted":7}`:            "params.quoted",
+		`{"ratio":{"value":0.5}}`: "params.ratio",
+		`{"unknownField":true}`:   "params.unknownField",
+		`{"nmae":"a"}`:            "params.nmae",
+	} {
+		err := s.Validate(decode(raw))
+		if err == nil || !strings.HasPrefix(err.Error(), wantPath+":") {
+			t.Errorf("%s: err = %v, want one at %s", raw, err, wantPath)
+		}
+	}
+
+	// A schema survives the JSON round trip clients read it through.
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Schema
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if err := back.Validate(decode(`{"count":-1}`)); err == nil {
+		t.Error("round-tripped schema lost the minimum")
+	}
+}
@@ -45,6 +45,11 @@ const (
 	TaskMarkNotReady TaskType = "mark-not-ready"
 	TaskStopSeid     TaskType = "stop-seid"
 	TaskResetData    TaskType = "reset-data"
+
+	// Binary upgrade tasks: prepare-upgrade places a software-upgrade
+	// plan's seid binary, apply-upgrade switches to it at the plan's height.
+	TaskPrepareUpgrade TaskType = "prepare-upgrade"
+	TaskApplyUpgrade   TaskType = "apply-upgrade"
 )
 
 // VoteOption mirrors cosmos gov v1beta1 VoteOption values so callers can parse
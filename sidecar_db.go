@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/sei-protocol/seictl/internal/patch"
+	"github.com/sei-protocol/seictl/sidecar/engine"
+)
+
+var sidecarCmd = cli.Command{
+	Name:  "sidecar",
+	Usage: "Operate on the local sidecar's state",
+	Commands: []*cli.Command{
+		&sidecarDBCmd,
+		&sidecarPolicyCmd,
+	},
+}
+
+var sidecarDBCmd = cli.Command{
+	Name:  "db",
+	Usage: "Move the sidecar's task ledger between volumes or hosts",
+	Description: "The ledger is the task history and the pre-broadcast tx markers " +
+		"that keep sign-tx tasks idempotent across a crash, held in the store " +
+		"SEI_SIDECAR_STORE selects: sidecar.db (sqlite, the default) or " +
+		"sidecar.journal (journal). Copying sidecar.db by hand is unsafe while " +
+		"its write-ahead log is live; export and import move the ledger as a " +
+		"versioned, checksummed JSON archive, between either backend.",
+	Commands: []*cli.Command{
+		&sidecarDBExportCmd,
+		&sidecarDBImportCmd,
+	},
+}
+
+var sidecarDBExportCmd = cli.Command{
+	Name:  "export",
+	Usage: "Write the sidecar's task ledger to a JSON archive",
+	Description: "Reads every task result and tx marker from the store in one " +
+		"consistent snapshot. sidecar.db is opened read-only and never migrated, " +
+		"so it is safe against a running sidecar, even an older one; the archive " +
+		"records the file's own schema version. sidecar.journal is locked by a " +
+		"running sidecar, so stop the sidecar before exporting it.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "output",
+			Aliases:     []string{"o"},
+			DefaultText: "STDOUT",
+			TakesFile:   true,
+			Usage:       "File to write the archive to (mode 0600; it holds signed tx bytes)",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		ledger, schema, err := exportLedger()
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := engine.WriteLedgerArchive(&buf, ledger, schema); err != nil {
+			return err
+		}
+		if out := cmd.String("output"); out != "" {
+			if err := patch.WriteFileAtomic(filepath.Clean(out), buf.Bytes(), 0o600); err != nil {
+				return fmt.Errorf("write %s: %w", out, err)
+			}
+		} else if _, err := io.Copy(os.Stdout, &buf); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "exported %d task results and %d tx markers\n", len(ledger.TaskResults), len(ledger.TxMarkers))
+		return nil
+	},
+}
+
+var sidecarDBImportCmd = cli.Command{
+	Name:      "import",
+	Usage:     "Load a task ledger archive into an empty sidecar store",
+	ArgsUsage: "<archive|->",
+	Description: "Verifies the archive's format, schema version and checksum, then loads " +
+		"it into the store SEI_SIDECAR_STORE selects, which must hold no ledger " +
+		"yet. Stop the sidecar first. An interrupted import into sidecar.db leaves " +
+		"it marked partial and serve refuses to start on it; rerun the import to " +
+		"finish. An import into sidecar.journal is written in one step.",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return fmt.Errorf("expected exactly one archive path (or - for stdin)")
+		}
+		in := io.Reader(os.Stdin)
+		if path := cmd.Args().First(); path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		ledger, info, err := engine.ReadLedgerArchive(in)
+		if err != nil {
+			return err
+		}
+
+		store, name, err := openLedgerStore()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		err = store.ImportLedger(ledger, info.Checksum)
+		if errors.Is(err, engine.ErrStoreNotEmpty) {
+			return fmt.Errorf("%s already holds task results or tx markers; import only into a fresh home", name)
+		}
+		if err != nil {
+			return fmt.Errorf("import ledger: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "imported %d task results and %d tx markers (exported %s from schema %d)\n",
+			len(ledger.TaskResults), len(ledger.TxMarkers), info.ExportedAt.Format(time.RFC3339), info.SchemaVersion)
+		return nil
+	},
+}
+
+// sidecarHome is the sidecar's home directory: --home, or /sei.
+func sidecarHome() string {
+	if destinations.home != "" {
+		return destinations.home
+	}
+	return "/sei"
+}
+
+// exportLedger exports the ledger of the store SEI_SIDECAR_STORE selects,
+// with the schema version it is recorded at.
+func exportLedger() (*engine.Ledger, int, error) {
+	backend, err := storeBackend()
+	if err != nil {
+		return nil, 0, err
+	}
+	path := storePath(sidecarHome(), backend)
+	if _, err := os.Stat(path); err != nil {
+		return nil, 0, err
+	}
+	if backend == "journal" {
+		// A journal holds results in this binary's shape once replayed.
+		store, name, err := openLedgerStore()
+		if err != nil {
+			return nil, 0, err
+		}
+		defer store.Close()
+		ledger, err := store.ExportLedger()
+		if err != nil {
+			return nil, 0, fmt.Errorf("export ledger from %s: %w", name, err)
+		}
+		return ledger, engine.SchemaVersion, nil
+	}
+
+	ledger, schema, err := engine.ExportLedgerFile(path)
+	if errors.Is(err, engine.ErrSchemaTooNew) {
+		return nil, 0, fmt.Errorf("sidecar.db is at schema %d, newer than this binary's %d; export with the newer seictl", schema, engine.SchemaVersion)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("export ledger: %w", err)
+	}
+	return ledger, schema, nil
+}
+
+// openLedgerStore opens the store SEI_SIDECAR_STORE selects for a ledger
+// import or export, returning it with its file name.
+func openLedgerStore() (interface {
+	engine.LedgerStore
+	Close() error
+}, string, error) {
+	backend, err := storeBackend()
+	if err != nil {
+		return nil, "", err
+	}
+	path := storePath(sidecarHome(), backend)
+	name := filepath.Base(path)
+	if backend == "journal" {
+		store, err := engine.NewJournalStore(path)
+		if errors.Is(err, engine.ErrJournalLocked) {
+			return nil, "", fmt.Errorf("%s is held by a running sidecar; stop it first", name)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("open %s: %w", path, err)
+		}
+		return store, name, nil
+	}
+	store, err := engine.NewSQLiteStore(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("open %s: %w", path, err)
+	}
+	return store, name, nil
+}
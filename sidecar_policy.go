@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/sei-protocol/seictl/sidecar/engine"
+	"github.com/sei-protocol/seictl/sidecar/server"
+)
+
+var sidecarPolicyCmd = cli.Command{
+	Name:  "policy",
+	Usage: "Work with the sidecar's authorization policy",
+	Description: "In trusted-header and mtls mode, serve loads the policy file SEI_SIDECAR_POLICY_FILE " +
+		"names and authorizes each request by the caller's X-Remote-User and " +
+		"X-Remote-Group, or client certificate common name and organizations. Rules grant read or write on task types; sign-tx task " +
+		"types are denied unless a rule allows them.",
+	Commands: []*cli.Command{
+		&sidecarPolicyCheckCmd,
+	},
+}
+
+var sidecarPolicyCheckCmd = cli.Command{
+	Name:  "check",
+	Usage: "Evaluate a policy against a sample request",
+	Description: "Loads and validates the policy, then reports whether it allows the " +
+		"described caller the given verb on the given task type, and which rule " +
+		"decided. Exits 1 when the request is denied.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "policy",
+			Sources:   cli.EnvVars("SEI_SIDECAR_POLICY_FILE"),
+			TakesFile: true,
+			Required:  true,
+			Usage:     "Policy file to evaluate",
+		},
+		&cli.StringFlag{
+			Name:  "user",
+			Usage: "Caller's X-Remote-User or certificate common name",
+		},
+		&cli.StringSliceFlag{
+			Name:  "group",
+			Usage: "Caller's X-Remote-Group or certificate organization (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:  "verb",
+			Value: server.VerbWrite,
+			Usage: "read or write",
+		},
+		&cli.StringFlag{
+			Name:  "type",
+			Usage: "Task type the request acts on; omit for reads",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		verb := cmd.String("verb")
+		if verb != server.VerbRead && verb != server.VerbWrite {
+			return fmt.Errorf("--verb must be %s or %s, got %q", server.VerbRead, server.VerbWrite, verb)
+		}
+		if verb == server.VerbRead && cmd.String("type") != "" {
+			return fmt.Errorf("--type applies only to --verb %s; reads carry no task type", server.VerbWrite)
+		}
+		policy, err := server.LoadPolicy(cmd.String("policy"))
+		if err != nil {
+			return err
+		}
+
+		d := policy.Evaluate(server.PolicyRequest{
+			Caller:   engine.Caller{User: cmd.String("user"), Groups: cmd.StringSlice("group")},
+			Verb:     verb,
+			TaskType: cmd.String("type"),
+		})
+		if !d.Allowed {
+			return cli.Exit("denied: "+d.Reason, 1)
+		}
+		fmt.Fprintln(cmd.Root().Writer, "allowed: "+d.Reason)
+		return nil
+	},
+}
@@ -0,0 +1,69 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v3"
+
+	"github.com/sei-protocol/seictl/internal/cliutil"
+	sidecar "github.com/sei-protocol/seictl/sidecar/client"
+)
+
+func cancelAction(ctx context.Context, c *cli.Command) error {
+	id, err := uuid.Parse(c.StringArg("id"))
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, cliutil.UsageError("id argument must be a task UUID: %s", err.Error()))
+		return cli.Exit("", 1)
+	}
+
+	cfg, ns, err := resolveKube(c)
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+	sc, err := newSidecarClient(cfg, ns, c.String("node"), int32(c.Int("port")))
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+
+	res, err := sc.CancelTask(ctx, id, c.String("reason"))
+	if err != nil {
+		if errors.Is(err, sidecar.ErrNotFound) {
+			cliutil.EmitStatus(os.Stderr, fmt.Errorf("task %s not found on node %s", id, c.String("node")))
+			return cli.Exit("", 1)
+		}
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+	if err := printJSON(os.Stdout, res); err != nil {
+		return fmt.Errorf("print: %w", err)
+	}
+	return nil
+}
+
+var cancelCmd = cli.Command{
+	Name:      "cancel",
+	Usage:     "Cancel a pending or running task, keeping its record",
+	ArgsUsage: "<id>",
+	Description: "POST /v0/tasks/{id}:cancel on the target node's sidecar and print " +
+		"the TaskResult as JSON. The task ends in the terminal status " +
+		"\"cancelled\" with .cancelledBy (your authenticated identity) and " +
+		".cancelReason set; unlike delete, the record is kept. A .status of " +
+		"\"running\" means the handler had not stopped yet — re-check with get.",
+	Arguments: []cli.Argument{
+		&cli.StringArg{Name: "id", UsageText: "task UUID"},
+	},
+	Flags: append([]cli.Flag{
+		nodeFlag(true),
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "Why the task is being cancelled; recorded on the task",
+		},
+	}, commonFlags()...),
+	Action: cancelAction,
+}
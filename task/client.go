@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math/rand/v2"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/urfave/cli/v3"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -59,7 +61,9 @@ func resolveKube(c *cli.Command) (*rest.Config, string, error) {
 // settings are inert against the plaintext http:// proxy; auth injection is
 // scheme-independent. requestTimeout rides through as the client's per-request
 // Timeout. The config is copied so the per-request bound does not leak onto the
-// shared cfg used by discovery.
+// shared cfg used by discovery. The event stream behind Watch gets a second,
+// unbounded client from the same config: it is long-lived by design and ends
+// with the caller's ctx instead.
 func newSidecarClient(cfg *rest.Config, ns, node string, port int32) (*sidecar.SidecarClient, error) {
 	authCfg := rest.CopyConfig(cfg)
 	authCfg.Timeout = requestTimeout
@@ -67,7 +71,13 @@ func newSidecarClient(cfg *rest.Config, ns, node string, port int32) (*sidecar.S
 	if err != nil {
 		return nil, fmt.Errorf("building authenticated HTTP client: %w", err)
 	}
-	return sidecar.NewSidecarClientFromPodDNS(node, ns, port, sidecar.WithHTTPDoer(hc))
+	streamCfg := rest.CopyConfig(cfg)
+	streamCfg.Timeout = 0
+	stream, err := rest.HTTPClientFor(streamCfg)
+	if err != nil {
+		return nil, fmt.Errorf("building authenticated HTTP client: %w", err)
+	}
+	return sidecar.NewSidecarClientFromPodDNS(node, ns, port, sidecar.WithHTTPDoer(hc), sidecar.WithStreamDoer(stream))
 }
 
 // discoverPublishNode label-selects snapshot-publish pods for the chain and
@@ -95,6 +105,33 @@ func discoverPublishNode(ctx context.Context, cfg *rest.Config, ns, chain string
 	return nodeFromPod(pod), nil
 }
 
+// discoverSelectedNodes returns the names, sorted, of the SeiNodes in ns
+// matching selector. It is the target half of `task submit --selector`.
+func discoverSelectedNodes(ctx context.Context, cfg *rest.Config, ns string, selector labels.Selector) ([]string, error) {
+	kcli, err := cliutil.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	nodes := &unstructured.UnstructuredList{}
+	nodes.SetGroupVersionKind(schema.GroupVersionKind{Group: "sei.io", Version: "v1alpha1", Kind: "SeiNodeList"})
+	err = kcli.List(ctx, nodes,
+		client.InNamespace(ns),
+		client.MatchingLabelsSelector{Selector: selector},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list SeiNodes matching %s in %s: %w", selector, ns, err)
+	}
+	if len(nodes.Items) == 0 {
+		return nil, fmt.Errorf("no SeiNodes match %s in %s", selector, ns)
+	}
+	names := make([]string, len(nodes.Items))
+	for i, n := range nodes.Items {
+		names[i] = n.GetName()
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
 // nodeFromPod derives a SeiNode / headless-service name from a StatefulSet pod
 // name by stripping the trailing ordinal. Publish nodes are single-replica, so
 // the pod is always <node>-0 and the sidecar resolves at <node>-0.<node>.<ns>.
@@ -6,19 +6,22 @@
 // SeiNodeTaskWorkflow custom resources the controller executes, task drives
 // the sidecar HTTP API on one addressed pod, with the same two-path shape:
 //
-//   - `task get|list|delete|submit` — the raw verbs: thin wrappers over the
-//     typed SidecarClient (read one/all task results, cancel-or-delete a task,
-//     or POST an arbitrary task). `submit` is the generic escape hatch, the
-//     analogue of `workflow apply`.
+//   - `task get|list|watch|cancel|delete|submit` — the raw verbs: thin wrappers
+//     over the typed SidecarClient (read one/all task results, follow a task's
+//     event stream to a terminal state, cancel a task and keep its record,
+//     cancel-or-delete a task, or POST an arbitrary task). `submit` is the
+//     generic escape hatch, the analogue of `workflow apply`.
 //   - `task snapshot-upload` — the paved road: submit one snapshot-upload-once
-//     with a fresh task ID and poll it to a terminal state with
+//     with a fresh task ID and follow it to a terminal state with
 //     kubectl-wait-compatible exit codes. The procedure a per-(network,cluster)
 //     CronJob invokes daily.
 //
 // Every verb addresses a single pod's sidecar. The target is either an explicit
 // --node (the SeiNode / headless-service name; the sidecar is reached at
 // <node>-0.<node>.<ns>.svc.cluster.local through its proxy) or, for
-// snapshot-upload, discovered by label selection. Cluster + namespace resolve
+// snapshot-upload, discovered by label selection. The one exception is
+// `submit --selector`, which fans a task out across every matching SeiNode
+// through a FleetClient and reports the per-node outcome. Cluster + namespace resolve
 // from --kubeconfig and -n exactly as the workflow and node trees do.
 package task
 
@@ -34,7 +37,9 @@ var Cmd = cli.Command{
 		&snapshotUploadCmd,
 		&getCmd,
 		&listCmd,
+		&watchCmd,
 		&submitCmd,
+		&cancelCmd,
 		&deleteCmd,
 	},
 }
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+
+	"github.com/sei-protocol/seictl/internal/cliutil"
+	sidecar "github.com/sei-protocol/seictl/sidecar/client"
+)
+
+// fleetFlagNames are the submit flags that only shape a --selector rollout.
+var fleetFlagNames = []string{"parallelism", "canary", "batch-size", "wait", "timeout", "continue-on-failure"}
+
+// fleetFlags are the `task submit --selector` rollout flags.
+func fleetFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "selector",
+			Aliases: []string{"l"},
+			Usage:   "Label selector for the SeiNodes to fan out to (mutually exclusive with --node)",
+		},
+		&cli.IntFlag{
+			Name:  "parallelism",
+			Value: 10,
+			Usage: "Most nodes submitted to (and, with --wait, waited on) at once",
+		},
+		&cli.IntFlag{
+			Name:  "canary",
+			Usage: "Nodes rolled out first as a stage of their own (0 = no canary)",
+		},
+		&cli.IntFlag{
+			Name:  "batch-size",
+			Usage: "Nodes per stage after the canary (0 = all remaining in one)",
+		},
+		&cli.BoolFlag{
+			Name:  "wait",
+			Usage: "Follow each node's task to a terminal status before its stage counts as done (implied by --canary and --batch-size)",
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "When waiting, bound on each node's submit and wait (0 = none)",
+		},
+		&cli.BoolFlag{
+			Name:  "continue-on-failure",
+			Usage: "Run later stages even after a node fails",
+		},
+	}
+}
+
+// submitFleet fans req out to every SeiNode in ns matching selector. The
+// capabilities check, unless skipped, runs against the first node's
+// sidecar.
+func submitFleet(ctx context.Context, c *cli.Command, cfg *rest.Config, ns string, selector labels.Selector, req sidecar.TaskRequest, params map[string]interface{}) error {
+	if c.Int("parallelism") < 1 || c.Int("canary") < 0 || c.Int("batch-size") < 0 || c.Duration("timeout") < 0 {
+		cliutil.EmitStatus(os.Stderr, cliutil.UsageError("--parallelism must be positive and --canary, --batch-size and --timeout not negative"))
+		return cli.Exit("", 1)
+	}
+	nodes, err := discoverSelectedNodes(ctx, cfg, ns, selector)
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+	targets := make([]sidecar.FleetTarget, len(nodes))
+	for i, node := range nodes {
+		sc, err := newSidecarClient(cfg, ns, node, int32(c.Int("port")))
+		if err != nil {
+			cliutil.EmitStatus(os.Stderr, err)
+			return cli.Exit("", 1)
+		}
+		targets[i] = sidecar.FleetTarget{Name: node, Client: sc}
+	}
+
+	if !c.Bool("no-validate") {
+		if err := validateSubmission(ctx, targets[0].Client, req.Type, params); err != nil {
+			cliutil.EmitStatus(os.Stderr, err)
+			return cli.Exit("", 1)
+		}
+	}
+
+	opts := sidecar.FleetOptions{
+		Parallelism:       c.Int("parallelism"),
+		Canary:            c.Int("canary"),
+		BatchSize:         c.Int("batch-size"),
+		ContinueOnFailure: c.Bool("continue-on-failure"),
+	}
+	// A staged rollout always waits: a stage must not pass on a task its
+	// sidecar accepted but that then fails.
+	if c.Bool("wait") || opts.Canary > 0 || opts.BatchSize > 0 {
+		opts.Wait = &sidecar.WaitOptions{Timeout: c.Duration("timeout")}
+	}
+	fc, err := sidecar.NewFleetClient(targets, opts)
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+	fmt.Fprintf(os.Stderr, "seictl: submitting %s to %d SeiNodes matching %s\n", req.Type, len(targets), selector)
+
+	res, err := fc.Submit(ctx, req)
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, err)
+	}
+	printFleetTable(os.Stderr, res)
+	if perr := printJSON(os.Stdout, res); perr != nil {
+		return fmt.Errorf("print: %w", perr)
+	}
+	if err != nil || !res.Succeeded() {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+// printFleetTable writes a rollout's per-node outcome as a table.
+func printFleetTable(w io.Writer, res *sidecar.FleetResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tSTAGE\tTASK\tSTATUS\tERROR")
+	for _, n := range res.Nodes {
+		msg := n.Error
+		if msg == "" {
+			msg = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", n.Node, n.Stage, n.ID, n.Status, msg)
+	}
+	tw.Flush()
+	if res.Halted {
+		fmt.Fprintf(w, "rollout %s halted after a failure; rerun with --id %s to resume\n", res.ID, res.ID)
+	}
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sidecar "github.com/sei-protocol/seictl/sidecar/client"
+)
+
+// submit takes exactly one of --node and --selector, and the rollout flags
+// only with --selector; each misuse is a usage error before any cluster
+// access.
+func TestSubmitAction_RejectsTargetMisuse(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantSub string
+	}{
+		{"neither", []string{"submit", "config-reload"}, "--selector"},
+		{"both", []string{"submit", "config-reload", "--node", "n", "-l", "app=x"}, "--selector"},
+		{"bad selector", []string{"submit", "config-reload", "-l", "a=(b"}, "parse --selector"},
+		{"rollout flag with node", []string{"submit", "config-reload", "--node", "n", "--canary", "1"}, "--canary"},
+		{"bad id", []string{"submit", "config-reload", "--node", "n", "--id", "nope"}, "--id"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			origExiter := cli.OsExiter
+			cli.OsExiter = func(int) {}
+			t.Cleanup(func() { cli.OsExiter = origExiter })
+			out := captureStderr(t, func() {
+				cmd := &cli.Command{
+					Name:      "submit",
+					Flags:     submitFlags(),
+					Arguments: submitCmd.Arguments,
+					Action:    submitAction,
+				}
+				_ = cmd.Run(context.Background(), tc.args)
+			})
+			var status metav1.Status
+			if err := json.Unmarshal([]byte(out), &status); err != nil {
+				t.Fatalf("stderr was not a metav1.Status (%q): %v", out, err)
+			}
+			if status.Reason != metav1.StatusReasonBadRequest || !strings.Contains(status.Message, tc.wantSub) {
+				t.Errorf("status = %q %q, want a bad request naming %q", status.Reason, status.Message, tc.wantSub)
+			}
+		})
+	}
+}
+
+func TestPrintFleetTable(t *testing.T) {
+	base := uuid.New()
+	res := &sidecar.FleetResult{ID: base, Halted: true, Nodes: []sidecar.NodeOutcome{
+		{Node: "rpc-0", Stage: 1, ID: sidecar.FleetNodeID(base, "rpc-0"), Status: sidecar.NodeFailed, Error: "disk full"},
+		{Node: "rpc-1", Stage: 2, ID: sidecar.FleetNodeID(base, "rpc-1"), Status: sidecar.NodeNotAttempted},
+	}}
+	var buf bytes.Buffer
+	printFleetTable(&buf, res)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "NODE") {
+		t.Fatalf("table = %q, want a header, two rows and the halt note", buf.String())
+	}
+	if !strings.Contains(lines[1], "failed") || !strings.Contains(lines[1], "disk full") {
+		t.Errorf("row = %q, want the failure and its error", lines[1])
+	}
+	if !strings.Contains(lines[3], "--id "+base.String()) {
+		t.Errorf("halt note = %q, want the base ID to resume with", lines[3])
+	}
+}
@@ -4,40 +4,105 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/sei-protocol/seictl/internal/cliutil"
+	sidecar "github.com/sei-protocol/seictl/sidecar/client"
 )
 
 func listAction(ctx context.Context, c *cli.Command) error {
-	cfg, ns, err := resolveKube(c)
+	params, err := listParams(c)
 	if err != nil {
 		cliutil.EmitStatus(os.Stderr, err)
 		return cli.Exit("", 1)
 	}
-	sc, err := newSidecarClient(cfg, ns, c.String("node"), int32(c.Int("port")))
+
+	cfg, ns, err := resolveKube(c)
 	if err != nil {
 		cliutil.EmitStatus(os.Stderr, err)
 		return cli.Exit("", 1)
 	}
-
-	results, err := sc.ListTasks(ctx)
+	sc, err := newSidecarClient(cfg, ns, c.String("node"), int32(c.Int("port")))
 	if err != nil {
 		cliutil.EmitStatus(os.Stderr, err)
 		return cli.Exit("", 1)
 	}
+
+	results := []sidecar.TaskResult{}
+	for {
+		page, next, err := sc.ListTasksPage(ctx, params)
+		if err != nil {
+			cliutil.EmitStatus(os.Stderr, err)
+			return cli.Exit("", 1)
+		}
+		results = append(results, page...)
+		if next == "" {
+			break
+		}
+		if !c.Bool("all") {
+			fmt.Fprintf(os.Stderr, "seictl: more results; continue with --cursor %s\n", next)
+			break
+		}
+		params.Cursor = &next
+	}
 	if err := printJSON(os.Stdout, results); err != nil {
 		return fmt.Errorf("print: %w", err)
 	}
 	return nil
 }
 
+// listParams maps the list flags onto the sidecar's query parameters.
+func listParams(c *cli.Command) (sidecar.ListTasksParams, error) {
+	var p sidecar.ListTasksParams
+	if v := c.String("type"); v != "" {
+		p.Type = &v
+	}
+	if v := c.String("status"); v != "" {
+		st := sidecar.ListTasksParamsStatus(v)
+		p.Status = &st
+	}
+	for _, f := range []struct {
+		name string
+		into **time.Time
+	}{
+		{"submitted-after", &p.SubmittedAfter},
+		{"submitted-before", &p.SubmittedBefore},
+	} {
+		if v := c.String(f.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return p, cliutil.UsageError("--%s must be an RFC 3339 timestamp: %s", f.name, err.Error())
+			}
+			*f.into = &t
+		}
+	}
+	if v := int(c.Int("limit")); v != 0 {
+		p.Limit = &v
+	}
+	if v := c.String("cursor"); v != "" {
+		p.Cursor = &v
+	}
+	return p, nil
+}
+
 var listCmd = cli.Command{
 	Name:  "list",
-	Usage: "List recent task results from a node's sidecar",
-	Description: "GET /v0/tasks on the target node's sidecar and print the recent " +
-		"TaskResults as a JSON array.",
-	Flags:  append([]cli.Flag{nodeFlag(true)}, commonFlags()...),
+	Usage: "List task results from a node's sidecar",
+	Description: "GET /v0/tasks on the target node's sidecar and print the matching " +
+		"TaskResults, newest first, as a JSON array. One page is fetched; when " +
+		"more match, the cursor to continue from prints on stderr. --all " +
+		"follows the cursor to the last page.",
+	Flags: append([]cli.Flag{
+		nodeFlag(true),
+		&cli.StringFlag{Name: "type", Usage: "Only results of this task type"},
+		&cli.StringFlag{Name: "status", Usage: "Only results in this status (pending, running, completed, failed, cancelled, skipped)"},
+		&cli.StringFlag{Name: "submitted-after", Usage: "Only results submitted at or after this RFC 3339 time"},
+		&cli.StringFlag{Name: "submitted-before", Usage: "Only results submitted before this RFC 3339 time"},
+		&cli.IntFlag{Name: "limit", Usage: "Page size (sidecar default 100, max 1000)"},
+		&cli.StringFlag{Name: "cursor", Usage: "Continue from a previous listing's cursor"},
+		&cli.BoolFlag{Name: "all", Usage: "Follow the cursor and print every matching result"},
+	}, commonFlags()...),
 	Action: listAction,
 }
@@ -86,7 +86,7 @@ func snapshotUploadAction(ctx context.Context, c *cli.Command) error {
 			cliutil.EmitStatus(os.Stderr, timeoutStatus(id, node, timeout))
 			return cli.Exit("", 1)
 		}
-		if errors.Is(err, errTaskDeleted) {
+		if errors.Is(err, sidecar.ErrNotFound) {
 			cliutil.EmitStatus(os.Stderr, deletedStatus(id, node))
 			return cli.Exit("", 1)
 		}
@@ -109,63 +109,40 @@ func snapshotUploadAction(ctx context.Context, c *cli.Command) error {
 }
 
 // runSnapshotUpload submits one snapshot-upload-once with a caller-generated,
-// fresh task ID and polls it to a terminal state. The fresh ID is load-bearing:
-// the engine coalesces a reused ID onto an existing Completed row without
-// re-running, so reusing one reads back a stale result and never uploads.
+// fresh task ID and follows it to a terminal state over the event stream,
+// polling every interval when the stream is unavailable. The fresh ID is
+// load-bearing: the engine coalesces a reused ID onto an existing Completed
+// row without re-running, so reusing one reads back a stale result and never
+// uploads.
+//
+// A transient GET failure (e.g. a brief rbac-proxy restart mid-upload) is
+// logged and the poll carries on: the sidecar keeps running the upload, so
+// aborting would only strand a multi-GB upload and let Job backoff submit a
+// redundant concurrent one. A task that settles failed or cancelled is
+// returned with a nil error for classifyUpload to report. A 404 mid-wait
+// (ErrNotFound) means the row is gone and will never settle; it returns at
+// once rather than burning the full --timeout.
 func runSnapshotUpload(ctx context.Context, sc *sidecar.SidecarClient, id uuid.UUID, interval time.Duration) (*sidecar.TaskResult, error) {
 	req := sidecar.SnapshotUploadOnceTask{}.ToTaskRequest()
 	req.Id = &id
-	if _, err := sc.SubmitTask(ctx, req); err != nil {
-		return nil, fmt.Errorf("submit: %w", err)
-	}
-	return pollUntilTerminal(ctx, sc, id, interval)
-}
-
-// errTaskDeleted signals a 404 on GET after a successful submit: the task row is
-// gone (deleted or cancelled out-of-band), so it will never reach terminal and
-// the poll returns at once rather than burning the full --timeout. Distinct from
-// a transient GET error and from a task that ran and failed.
-var errTaskDeleted = errors.New("task deleted while awaiting completion")
-
-// pollUntilTerminal GETs the task on interval until it reaches completed or
-// failed, or ctx expires. A transient GetTask error while ctx is still live
-// (e.g. a brief rbac-proxy restart mid-upload) is logged to stderr and the poll
-// continues to the next tick: the sidecar keeps running the upload, so aborting
-// here would only strand a multi-GB upload and let Job backoff submit a
-// redundant concurrent one. A 404 (ErrNotFound) is the exception — the row is
-// gone and cannot become terminal, so the poll returns errTaskDeleted at once.
-// Only ctx expiry otherwise ends the loop with an error (DeadlineExceeded on
-// timeout), which the caller maps to a distinct exit message. A persistent GET
-// failure stays visible on stderr across ticks.
-func pollUntilTerminal(ctx context.Context, sc *sidecar.SidecarClient, id uuid.UUID, interval time.Duration) (*sidecar.TaskResult, error) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-	for {
-		res, err := sc.GetTask(ctx, id)
-		if err != nil {
-			if errors.Is(err, sidecar.ErrNotFound) {
-				return nil, errTaskDeleted
-			}
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return nil, ctxErr
+	_, res, err := sidecar.SubmitAndWait[snapshotUploadResult](ctx, sc, req, sidecar.WaitOptions{
+		PollInterval: interval,
+		OnEvent: func(ev sidecar.TaskEvent) {
+			if ev.Type == "progress" {
+				printEvent(os.Stderr, ev)
 			}
-			fmt.Fprintf(os.Stderr, "seictl: polling snapshot-upload-once %s: %v (retrying next tick)\n", id, err)
-		} else {
-			switch res.Status {
-			case sidecar.Completed, sidecar.Failed:
-				return res, nil
-			}
-		}
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-		}
+		},
+		OnRetry: printRetry,
+	})
+	var failed *sidecar.TaskFailedError
+	if errors.As(err, &failed) {
+		return failed.Task, nil
 	}
+	return res, err
 }
 
 // classifyUpload maps a terminal TaskResult to (summary, err): a completed
-// upload or noop is healthy (nil err, exit 0); a failed task or a completed
+// upload or noop is healthy (nil err, exit 0); a failed or cancelled task or a completed
 // task with an unrecognized outcome is an error carrying a metav1.Status so the
 // stderr `jq -r .reason` discriminator works.
 func classifyUpload(res *sidecar.TaskResult) (string, error) {
@@ -189,6 +166,13 @@ func classifyUpload(res *sidecar.TaskResult) (string, error) {
 		}
 		return "", failStatus(metav1.StatusReasonInternalError, http.StatusInternalServerError,
 			"snapshot-upload-once failed: %s", detail)
+	case sidecar.Cancelled:
+		detail := "(no reason given)"
+		if res.CancelReason != nil && *res.CancelReason != "" {
+			detail = *res.CancelReason
+		}
+		return "", failStatus(metav1.StatusReasonInternalError, http.StatusInternalServerError,
+			"snapshot-upload-once was cancelled: %s", detail)
 	default:
 		return "", failStatus(metav1.StatusReasonInternalError, http.StatusInternalServerError,
 			"snapshot-upload-once returned non-terminal status %q", res.Status)
@@ -237,7 +221,9 @@ var snapshotUploadCmd = cli.Command{
 	Usage: "Run one snapshot-upload-once on a node and wait for it to finish",
 	Description: "The paved road a per-(network,cluster) CronJob invokes daily. " +
 		"Selects one target, submits a snapshot-upload-once with a fresh unique " +
-		"task ID, and polls it to a terminal state. " +
+		"task ID, and follows it to a terminal state over the sidecar's event " +
+		"stream (upload progress prints as it goes), polling when the stream " +
+		"is unavailable. " +
 		"\n\n" +
 		"Target: --node names one explicitly. --chain discovers a " +
 		"random pod labelled sei.io/snapshot-publish=true,sei.io/chain=<chain> — " +
@@ -267,7 +253,7 @@ var snapshotUploadCmd = cli.Command{
 		&cli.DurationFlag{
 			Name:  "poll-interval",
 			Value: 20 * time.Second,
-			Usage: "Interval between task GETs (sidecar-local and cheap)",
+			Usage: "Interval between task GETs when the event stream is unavailable (sidecar-local and cheap)",
 		},
 	}, commonFlags()...),
 	Action: snapshotUploadAction,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -100,8 +101,11 @@ func TestClassifyUpload(t *testing.T) {
 // completeAfter, then the terminal result. GET calls at or below errorGetsUntil
 // return 503 first, standing in for a transient rbac-proxy blip mid-poll. When
 // notFoundAfter is nonzero, GET calls at or beyond it return 404, standing in
-// for the task row being deleted or cancelled out-of-band mid-poll.
+// for the task row being deleted or cancelled out-of-band mid-poll. When
+// stream is set it also serves GET /v0/events, streaming the terminal result
+// as a single event; otherwise the stream 404s as on an older sidecar.
 type fakeSidecar struct {
+	stream         bool
 	completeAfter  int32
 	errorGetsUntil int32
 	notFoundAfter  int32
@@ -149,6 +153,13 @@ func (f *fakeSidecar) handler(t *testing.T) http.Handler {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(res)
 	})
+	if f.stream {
+		mux.HandleFunc("GET /v0/events", func(w http.ResponseWriter, r *http.Request) {
+			data, _ := json.Marshal(f.terminal)
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "id: 1\nevent: %s\ndata: %s\n\n", f.terminal.Status, data)
+		})
+	}
 	return mux
 }
 
@@ -184,10 +195,44 @@ func TestRunSnapshotUpload_PollsToTerminal(t *testing.T) {
 	}
 }
 
+// With the event stream available the run follows it instead of polling: one
+// GET to catch an already-settled task, then the terminal event.
+func TestRunSnapshotUpload_FollowsEventStream(t *testing.T) {
+	id := uuid.New()
+	fake := &fakeSidecar{
+		stream:        true,
+		completeAfter: 1 << 30, // polling would never see the terminal
+		terminal: sidecar.TaskResult{
+			Id:     id,
+			Status: sidecar.Completed,
+			Result: rawResult(t, snapshotUploadResult{Outcome: sidecar.OutcomeUploaded, Height: 100, Key: "snap/100.tar"}),
+		},
+	}
+	srv := httptest.NewServer(fake.handler(t))
+	t.Cleanup(srv.Close)
+	sc, err := sidecar.NewSidecarClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewSidecarClient: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := runSnapshotUpload(ctx, sc, id, time.Hour)
+	if err != nil {
+		t.Fatalf("runSnapshotUpload: %v", err)
+	}
+	if res.Status != sidecar.Completed {
+		t.Fatalf("status = %q, want completed", res.Status)
+	}
+	if got := fake.gets.Load(); got != 1 {
+		t.Errorf("GET count = %d, want 1 (the stream, not polling, should settle the run)", got)
+	}
+}
+
 // A transient GET failure (e.g. an rbac-proxy restart) must not abort the run:
 // the sidecar keeps uploading, so the poll has to survive the blip and read the
 // eventual terminal rather than fail and trigger a redundant resubmit.
-func TestPollUntilTerminal_SurvivesTransientGetError(t *testing.T) {
+func TestRunSnapshotUpload_SurvivesTransientGetError(t *testing.T) {
 	id := uuid.New()
 	fake := &fakeSidecar{
 		completeAfter:  3,
@@ -241,9 +286,8 @@ func captureStderr(t *testing.T, fn func()) string {
 }
 
 // A non-positive --poll-interval or --timeout must be rejected as a usage error
-// before the value reaches time.NewTicker, which panics on a non-positive
-// duration. The action shapes it as a metav1.Status{Reason: BadRequest} like its
-// other flag validation, not a crash.
+// rather than silently replaced by the wait's defaults. The action shapes it as
+// a metav1.Status{Reason: BadRequest} like its other flag validation.
 func TestSnapshotUploadAction_RejectsNonPositiveDurations(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -287,9 +331,9 @@ func TestSnapshotUploadAction_RejectsNonPositiveDurations(t *testing.T) {
 
 // A 404 mid-poll means the task row is gone (deleted or cancelled out-of-band)
 // after a successful submit: it can never become terminal, so the poll must exit
-// at once with errTaskDeleted rather than retry every ErrNotFound until the whole
+// at once with ErrNotFound rather than retry every 404 until the whole
 // --timeout is burned.
-func TestPollUntilTerminal_ExitsPromptlyOn404(t *testing.T) {
+func TestRunSnapshotUpload_ExitsPromptlyOn404(t *testing.T) {
 	id := uuid.New()
 	fake := &fakeSidecar{
 		completeAfter: 1 << 30, // never completes on its own
@@ -308,8 +352,8 @@ func TestPollUntilTerminal_ExitsPromptlyOn404(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	_, err = runSnapshotUpload(ctx, sc, id, time.Millisecond)
-	if !errors.Is(err, errTaskDeleted) {
-		t.Fatalf("err = %v, want errTaskDeleted", err)
+	if !errors.Is(err, sidecar.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
 	}
 	if ctx.Err() != nil {
 		t.Errorf("poll should have exited well before the timeout; ctx err = %v", ctx.Err())
@@ -3,10 +3,13 @@ package task
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/google/uuid"
 	"github.com/urfave/cli/v3"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/sei-protocol/seictl/internal/cliutil"
 	sidecar "github.com/sei-protocol/seictl/sidecar/client"
@@ -18,10 +21,38 @@ func submitAction(ctx context.Context, c *cli.Command) error {
 		cliutil.EmitStatus(os.Stderr, cliutil.UsageError("type argument required: seictl task submit <type> --node ..."))
 		return cli.Exit("", 1)
 	}
+	node, rawSelector := c.String("node"), c.String("selector")
+	if (node == "") == (rawSelector == "") {
+		cliutil.EmitStatus(os.Stderr, cliutil.UsageError("exactly one of --node or --selector is required: --node addresses one pod, --selector every matching SeiNode"))
+		return cli.Exit("", 1)
+	}
+	var selector labels.Selector
+	if rawSelector != "" {
+		var err error
+		if selector, err = labels.Parse(rawSelector); err != nil {
+			cliutil.EmitStatus(os.Stderr, cliutil.UsageError("parse --selector: %s", err.Error()))
+			return cli.Exit("", 1)
+		}
+	} else {
+		for _, name := range fleetFlagNames {
+			if c.IsSet(name) {
+				cliutil.EmitStatus(os.Stderr, cliutil.UsageError("--%s applies only with --selector", name))
+				return cli.Exit("", 1)
+			}
+		}
+	}
 
 	req := sidecar.TaskRequest{Type: taskType}
+	if raw := c.String("id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			cliutil.EmitStatus(os.Stderr, cliutil.UsageError("--id must be a UUID: %s", err.Error()))
+			return cli.Exit("", 1)
+		}
+		req.Id = &id
+	}
+	var params map[string]interface{}
 	if raw := c.String("params"); raw != "" {
-		var params map[string]interface{}
 		if err := json.Unmarshal([]byte(raw), &params); err != nil {
 			cliutil.EmitStatus(os.Stderr, cliutil.UsageError("--params must be a JSON object: %s", err.Error()))
 			return cli.Exit("", 1)
@@ -34,12 +65,22 @@ func submitAction(ctx context.Context, c *cli.Command) error {
 		cliutil.EmitStatus(os.Stderr, err)
 		return cli.Exit("", 1)
 	}
-	sc, err := newSidecarClient(cfg, ns, c.String("node"), int32(c.Int("port")))
+	if selector != nil {
+		return submitFleet(ctx, c, cfg, ns, selector, req, params)
+	}
+	sc, err := newSidecarClient(cfg, ns, node, int32(c.Int("port")))
 	if err != nil {
 		cliutil.EmitStatus(os.Stderr, err)
 		return cli.Exit("", 1)
 	}
 
+	if !c.Bool("no-validate") {
+		if err := validateSubmission(ctx, sc, taskType, params); err != nil {
+			cliutil.EmitStatus(os.Stderr, err)
+			return cli.Exit("", 1)
+		}
+	}
+
 	id, err := sc.SubmitTask(ctx, req)
 	if err != nil {
 		cliutil.EmitStatus(os.Stderr, err)
@@ -51,24 +92,67 @@ func submitAction(ctx context.Context, c *cli.Command) error {
 	return nil
 }
 
+// validateSubmission checks the task type and params against the
+// sidecar's published capabilities before POSTing, so a typo, an
+// unknown param or a mistyped one fails here with the field named. A sidecar that
+// predates GET /v0/capabilities is not checked.
+func validateSubmission(ctx context.Context, sc *sidecar.SidecarClient, taskType string, params map[string]interface{}) error {
+	caps, err := sc.Capabilities(ctx)
+	if errors.Is(err, sidecar.ErrUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := caps.ValidateTask(taskType, params); err != nil {
+		return cliutil.UsageError("%s", err.Error())
+	}
+	return nil
+}
+
 var submitCmd = cli.Command{
 	Name:      "submit",
-	Usage:     "POST a raw task to a node's sidecar (generic escape hatch)",
+	Usage:     "POST a raw task to a node's sidecar, or to every SeiNode a selector matches (generic escape hatch)",
 	ArgsUsage: "<type>",
 	Description: "POST /v0/tasks with an arbitrary task type and optional JSON " +
 		"params, printing the assigned task ID. The raw analogue of " +
-		"`workflow apply`: params are validated server-side, not by this verb. " +
+		"`workflow apply`. The type and the shape of --params are first checked " +
+		"against the sidecar's GET /v0/capabilities (skipped for sidecars that " +
+		"predate it, or with --no-validate); the handler still validates values. " +
 		"For the daily snapshot publish use `task snapshot-upload`, which owns " +
-		"fresh-ID generation and terminal polling.",
+		"fresh-ID generation and terminal polling.\n\n" +
+		"With --selector instead of --node the task fans out to every matching " +
+		"SeiNode, sorted by name: --canary nodes first, then batches of " +
+		"--batch-size, at most --parallelism at once. Each node's task ID is " +
+		"derived from --id (or a fresh base) and the node name, so rerunning " +
+		"with the printed base ID resubmits idempotently. A failed node halts " +
+		"the rollout after its stage unless --continue-on-failure. With --wait, " +
+		"implied by --canary and --batch-size, a node counts only once its task " +
+		"completes. The per-node outcome prints " +
+		"as JSON on stdout and a table on stderr; any node not submitted or " +
+		"completed exits 1.",
 	Arguments: []cli.Argument{
 		&cli.StringArg{Name: "type", UsageText: "task type identifier (e.g. config-validate)"},
 	},
-	Flags: append([]cli.Flag{
-		nodeFlag(true),
+	Flags:  submitFlags(),
+	Action: submitAction,
+}
+
+// submitFlags builds submit's flags, a fresh set per call.
+func submitFlags() []cli.Flag {
+	return append(append([]cli.Flag{
+		nodeFlag(false),
 		&cli.StringFlag{
 			Name:  "params",
-			Usage: "Task parameters as a JSON object (validated server-side)",
+			Usage: "Task parameters as a JSON object",
 		},
-	}, commonFlags()...),
-	Action: submitAction,
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "Task ID (a UUID); with --selector, the base each node's ID is derived from",
+		},
+		&cli.BoolFlag{
+			Name:  "no-validate",
+			Usage: "Skip checking the type and --params against the sidecar's capabilities",
+		},
+	}, fleetFlags()...), commonFlags()...)
 }
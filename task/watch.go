@@ -0,0 +1,129 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sei-protocol/seictl/internal/cliutil"
+	sidecar "github.com/sei-protocol/seictl/sidecar/client"
+)
+
+func watchAction(ctx context.Context, c *cli.Command) error {
+	id, err := uuid.Parse(c.StringArg("id"))
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, cliutil.UsageError("id argument must be a task UUID: %s", err.Error()))
+		return cli.Exit("", 1)
+	}
+	interval := c.Duration("poll-interval")
+	if interval <= 0 {
+		cliutil.EmitStatus(os.Stderr, cliutil.UsageError("--poll-interval must be positive (got %s)", interval))
+		return cli.Exit("", 1)
+	}
+
+	cfg, ns, err := resolveKube(c)
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+	sc, err := newSidecarClient(cfg, ns, c.String("node"), int32(c.Int("port")))
+	if err != nil {
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+
+	_, res, err := sidecar.WaitForTask[json.RawMessage](ctx, sc, id, sidecar.WaitOptions{
+		PollInterval: interval,
+		OnEvent:      func(ev sidecar.TaskEvent) { printEvent(os.Stderr, ev) },
+		OnRetry:      printRetry,
+	})
+	var failed *sidecar.TaskFailedError
+	switch {
+	case errors.As(err, &failed):
+		res = failed.Task
+	case errors.Is(err, sidecar.ErrNotFound):
+		cliutil.EmitStatus(os.Stderr, fmt.Errorf("task %s not found on node %s", id, c.String("node")))
+		return cli.Exit("", 1)
+	case err != nil:
+		cliutil.EmitStatus(os.Stderr, err)
+		return cli.Exit("", 1)
+	}
+	if err := printJSON(os.Stdout, res); err != nil {
+		return fmt.Errorf("print: %w", err)
+	}
+	if res.Status != sidecar.Completed {
+		cliutil.EmitStatus(os.Stderr, failStatus(metav1.StatusReasonInternalError, http.StatusInternalServerError,
+			"task %s ended %s", id, res.Status))
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+// printRetry reports a transient failure a wait rides out on stderr, so a
+// persistent one stays visible across polls.
+func printRetry(err error) {
+	fmt.Fprintf(os.Stderr, "seictl: %v (retrying)\n", err)
+}
+
+// printEvent writes one line per task event: the transition and, for
+// progress, how far the handler has got.
+func printEvent(w io.Writer, ev sidecar.TaskEvent) {
+	line := fmt.Sprintf("seictl: task %s %s", ev.Task.Id, ev.Type)
+	if p := ev.Task.Progress; ev.Type == "progress" && p != nil {
+		if p.Phase != nil {
+			line += " " + *p.Phase
+		}
+		switch {
+		case p.BytesDone != nil:
+			line += fmt.Sprintf(" %d/%s bytes", *p.BytesDone, progressTotal(p.BytesTotal))
+		case p.HeightsDone != nil:
+			line += fmt.Sprintf(" %d/%s heights", *p.HeightsDone, progressTotal(p.HeightsTotal))
+		}
+		if p.Message != nil {
+			line += " (" + *p.Message + ")"
+		}
+	}
+	if ev.Task.Error != nil && *ev.Task.Error != "" {
+		line += ": " + *ev.Task.Error
+	}
+	fmt.Fprintln(w, line)
+}
+
+func progressTotal(n *int64) string {
+	if n == nil {
+		return "?"
+	}
+	return fmt.Sprint(*n)
+}
+
+var watchCmd = cli.Command{
+	Name:      "watch",
+	Usage:     "Follow one task on a node's sidecar until it finishes",
+	ArgsUsage: "<id>",
+	Description: "GET /v0/events?task={id} on the target node's sidecar and print " +
+		"each lifecycle event (started, progress, completed, ...) on stderr as " +
+		"it happens, then the terminal TaskResult as JSON on stdout. A dropped " +
+		"stream resumes where it left off; a sidecar without the event stream " +
+		"is polled instead. Exits 0 only when the task completed.",
+	Arguments: []cli.Argument{
+		&cli.StringArg{Name: "id", UsageText: "task UUID"},
+	},
+	Flags: append([]cli.Flag{
+		nodeFlag(true),
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Value: 5 * time.Second,
+			Usage: "Interval between task GETs when the sidecar has no event stream",
+		},
+	}, commonFlags()...),
+	Action: watchAction,
+}